@@ -1,8 +1,6 @@
 package tradier
 
-import (
-	"encoding/json"
-)
+import "encoding/json"
 
 type Margin struct {
 	FedCall           int     `json:"fed_call"`
@@ -10,12 +8,12 @@ type Margin struct {
 	OptionBuyingPower float64 `json:"option_buying_power"`
 	StockBuyingPower  float64 `json:"stock_buying_power"`
 	StockShortValue   float64 `json:"stock_short_value"`
-	Sweep             int
+	Sweep             float64
 }
 
 type Cash struct {
 	CashAvailable  float64 `json:"cash_available"`
-	Sweep          int
+	Sweep          float64
 	UnsettledFunds float64 `json:"unsettled_funds"`
 }
 
@@ -33,14 +31,19 @@ type AccountBalances struct {
 	AccountType        string  `json:"account_type"`
 	ClosePL            float64 `json:"close_pl"`
 	CurrentRequirement float64 `json:"current_requirement"`
+	DividendBalance    float64 `json:"dividend_balance"`
 	Equity             float64
+	LongLiquidValue    float64 `json:"long_liquid_value"`
 	LongMarketValue    float64 `json:"long_market_value"`
 	MarketValue        float64 `json:"market_value"`
+	NetValue           float64 `json:"net_value"`
 	OpenPL             float64 `json:"open_pl"`
 	OptionLongValue    float64 `json:"option_long_value"`
 	OptionRequirement  float64 `json:"option_requirement"`
 	OptionShortValue   float64 `json:"option_short_value"`
+	PendingCash        float64 `json:"pending_cash"`
 	PendingOrdersCount int     `json:"pending_orders_count"`
+	ShortLiquidValue   float64 `json:"short_liquid_value"`
 	ShortMarketValue   float64 `json:"short_market_value"`
 	StockLongValue     float64 `json:"stock_long_value"`
 	TotalCash          float64 `json:"total_cash"`
@@ -79,6 +82,7 @@ type Event struct {
 	Type       string
 	Trade      Trade
 	Adjustment Adjustment
+	Option     OptionEvent
 }
 
 type ClosedPosition struct {
@@ -145,31 +149,52 @@ const (
 )
 
 type Order struct {
-	Id                int
-	Type              string
-	Symbol            string
-	OptionSymbol      string `json:"option_symbol"`
-	Side              string
-	Quantity          float64
-	Status            string
-	Duration          string
-	Price             float64
-	StopPrice         float64  `json:"stop_price"`
-	OptionType        string   `json:"option_type"`
-	ExpirationDate    DateTime `json:"expiration_date"`
-	Exchange          string   `json:"exch"`
-	AverageFillPrice  float64  `json:"avg_fill_price"`
-	ExecutedQuantity  float64  `json:"exec_quantity"`
-	ExecutionExchange string   `json:"exec_exch"`
-	LastFillPrice     float64  `json:"last_fill_price"`
-	LastFillQuantity  float64  `json:"last_fill_quantity"`
-	RemainingQuantity float64  `json:"remaining_quantity"`
-	CreateDate        DateTime `json:"create_date"`
-	TransactionDate   DateTime `json:"transaction_date"`
-	Class             string
-	NumLegs           int `json:"num_legs"`
-	Legs              []Order
-	Strategy          string
+	Id                int      `json:"id,omitempty" yaml:"id,omitempty"`
+	Type              string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Symbol            string   `json:"symbol,omitempty" yaml:"symbol,omitempty"`
+	OptionSymbol      string   `json:"option_symbol,omitempty" yaml:"option_symbol,omitempty"`
+	Side              string   `json:"side,omitempty" yaml:"side,omitempty"`
+	Quantity          float64  `json:"quantity,omitempty" yaml:"quantity,omitempty"`
+	Status            string   `json:"status,omitempty" yaml:"status,omitempty"`
+	Duration          string   `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Price             float64  `json:"price,omitempty" yaml:"price,omitempty"`
+	StopPrice         float64  `json:"stop_price,omitempty" yaml:"stop_price,omitempty"`
+	OptionType        string   `json:"option_type,omitempty" yaml:"option_type,omitempty"`
+	ExpirationDate    DateTime `json:"expiration_date" yaml:"expiration_date,omitempty"`
+	Exchange          string   `json:"exch,omitempty" yaml:"exch,omitempty"`
+	AverageFillPrice  float64  `json:"avg_fill_price,omitempty" yaml:"avg_fill_price,omitempty"`
+	ExecutedQuantity  float64  `json:"exec_quantity,omitempty" yaml:"exec_quantity,omitempty"`
+	ExecutionExchange string   `json:"exec_exch,omitempty" yaml:"exec_exch,omitempty"`
+	LastFillPrice     float64  `json:"last_fill_price,omitempty" yaml:"last_fill_price,omitempty"`
+	LastFillQuantity  float64  `json:"last_fill_quantity,omitempty" yaml:"last_fill_quantity,omitempty"`
+	RemainingQuantity float64  `json:"remaining_quantity,omitempty" yaml:"remaining_quantity,omitempty"`
+	CreateDate        DateTime `json:"create_date" yaml:"create_date,omitempty"`
+	TransactionDate   DateTime `json:"transaction_date" yaml:"transaction_date,omitempty"`
+	Class             string   `json:"class,omitempty" yaml:"class,omitempty"`
+	NumLegs           int      `json:"num_legs,omitempty" yaml:"num_legs,omitempty"`
+	Legs              []Order  `json:"legs,omitempty" yaml:"legs,omitempty"`
+	Strategy          string   `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+	Tag               string   `json:"tag,omitempty" yaml:"tag,omitempty"`
+}
+
+// UnmarshalJSON decodes an order, including numeric fields sent as strings.
+// Tradier lists the legs of multileg and advanced orders under "leg", as an
+// object if there is only one; orders encoded by this package use "legs".
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type order Order
+	if err := unmarshalNumbers(data, (*order)(o)); err != nil {
+		return err
+	}
+	var legs struct {
+		Leg oneOrMany[Order] `json:"leg"`
+	}
+	if err := json.Unmarshal(data, &legs); err != nil {
+		return err
+	}
+	if len(legs.Leg) > 0 {
+		o.Legs = legs.Leg
+	}
+	return nil
 }
 
 // If there is only a single event, then tradier sends back
@@ -178,57 +203,68 @@ type Order struct {
 type OpenOrders []*Order
 
 func (oo *OpenOrders) UnmarshalJSON(data []byte) error {
-	orders := make([]*Order, 0)
-	if err := json.Unmarshal(data, &orders); err == nil {
-		*oo = orders
-		return nil
-	}
-
-	order := Order{}
-	err := json.Unmarshal(data, &order)
-	if err == nil {
-		*oo = []*Order{&order}
-	}
+	orders, err := decodeOneOrMany[*Order](data)
+	*oo = orders
 	return err
 }
 
 // Helper struct for decoding Tradier's response to open orders request,
-// which returns null if there are no open orders, a list if there are
+// which returns "null" if there are no open orders, a list if there are
 // multiple open orders, or a single object if there is just one.
 type openOrdersResponse struct {
-	Orders struct {
+	Orders nullable[struct {
 		Order OpenOrders
-	}
+	}]
 }
 
-func (oor *openOrdersResponse) UnmarshalJSON(data []byte) error {
-	// If there are no open orders, then Tradier returns "null".
-	var noOrders struct {
-		Orders string
-	}
+// OrderPreview is Tradier's preview of an order: its estimated cost and
+// impact on the account, and the order as Tradier would place it.
+type OrderPreview struct {
+	Status string
+	// Set if the order would be accepted.
+	Result      bool
+	RequestDate DateTime `json:"request_date"`
 
-	err := json.Unmarshal(data, &noOrders)
-	if err == nil {
-		return nil
-	}
+	// The order as previewed.
+	Class        string
+	Strategy     string
+	Symbol       string
+	OptionSymbol string `json:"option_symbol"`
+	Side         string
+	Quantity     float64
+	Type         string
+	Duration     string
+	Price        float64
+	StopPrice    float64 `json:"stop_price"`
 
-	// Otherwise, unmarshal the results using the default unmarshaler.
-	var result struct {
-		Orders struct {
-			Order OpenOrders
-		}
-	}
-	err = json.Unmarshal(data, &result)
-	oor.Orders = result.Orders
-	return err
+	// Estimated commission and regulatory fees.
+	Commission float64
+	Fees       float64
+	// Estimated cost of the order, excluding and including the commission and
+	// fees. Credits are negative.
+	OrderCost float64 `json:"order_cost"`
+	Cost      float64
+	// Change of the account's margin requirement.
+	MarginChange float64 `json:"margin_change"`
+	// Buying power required to open an option position.
+	OptionRequirement float64 `json:"option_requirement"`
+	ExtendedHours     bool    `json:"extended_hours"`
+
+	// Number of day trades made in the account in the current period.
+	DayTrades int `json:"day_trades"`
+	// Set if the order would be a day trade.
+	DayTrade bool `json:"day_trade"`
+	// Set if the order would put the account in a margin call.
+	MarginCall bool `json:"margin_call"`
+	// Warning messages returned with the preview.
+	Messages previewMessages `json:"warnings"`
+	// Error messages returned with the preview, e.g. why the order would
+	// be rejected.
+	ErrorMessages previewMessages `json:"errors"`
 }
 
-type OrderPreview struct {
-	Commission    float64
-	Cost          float64
-	ExtendedHours bool `json:"extended_hours"`
-	Fees          float64
-	MarginChange  float64 `json:"margin_change"`
-	Quantity      float64
-	Status        string
+// UnmarshalJSON decodes a preview, including numeric fields sent as strings.
+func (op *OrderPreview) UnmarshalJSON(data []byte) error {
+	type orderPreview OrderPreview
+	return unmarshalNumbers(data, (*orderPreview)(op))
 }
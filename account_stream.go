@@ -0,0 +1,167 @@
+package tradier
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// AccountEventsEndpoint is the websocket endpoint for account events.
+// It is only available for live accounts.
+const AccountEventsEndpoint = "wss://ws.tradier.com/v1/accounts/events"
+
+// AccountEvent is an order status update streamed from the account events stream.
+type AccountEvent struct {
+	Id                int      `json:"id"`
+	Event             string   `json:"event"`
+	Status            string   `json:"status"`
+	Account           string   `json:"account"`
+	Type              string   `json:"type"`
+	Price             float64  `json:"price"`
+	StopPrice         float64  `json:"stop_price"`
+	AverageFillPrice  float64  `json:"avg_fill_price"`
+	ExecutedQuantity  float64  `json:"executed_quantity"`
+	LastFillQuantity  float64  `json:"last_fill_quantity"`
+	LastFillPrice     float64  `json:"last_fill_price"`
+	RemainingQuantity float64  `json:"remaining_quantity"`
+	TransactionDate   DateTime `json:"transaction_date"`
+	CreateDate        DateTime `json:"create_date"`
+}
+
+// IsFill returns true if the event reports a full or partial fill.
+func (ae *AccountEvent) IsFill() bool {
+	return ae.Status == Filled || ae.Status == PartiallyFilled
+}
+
+// IsFinal returns true if the order will receive no further updates.
+func (ae *AccountEvent) IsFinal() bool {
+	return isFinalStatus(ae.Status)
+}
+
+// AccountEventStream reads the account events websocket and decodes each
+// message into an AccountEvent.
+type AccountEventStream struct {
+	conn         *websocket.Conn
+	closeChan    chan struct{}
+	once         sync.Once
+	stallTimeout time.Duration
+	span         Span
+	metrics      MetricsRecorder
+
+	mu  sync.Mutex
+	err error
+}
+
+// StreamAccountEvents creates an account streaming session and delivers the
+// order events of the user's accounts on output, until the stream is stopped or
+// fails. The output channel is closed when the stream ends.
+// https://documentation.tradier.com/brokerage-api/streaming/wss-account-websocket
+func (tc *Client) StreamAccountEvents(output chan *AccountEvent) (*AccountEventStream, error) {
+	ctx, span := tc.startStreamSpan(context.Background(), "account", nil)
+	session, err := tc.createStreamSession(ctx, "/v1/accounts/events/session")
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	url := session.Url
+	if url == "" {
+		url = AccountEventsEndpoint
+	}
+	authorization, err := tc.authorization(ctx)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+	header := http.Header{"Authorization": authorization}
+	if tc.identityHeader != nil {
+		header[ServiceIdentityHeader] = tc.identityHeader
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	subscription := struct {
+		Events    []string `json:"events"`
+		SessionId string   `json:"sessionid"`
+	}{[]string{"order"}, session.SessionId}
+	if err := conn.WriteJSON(subscription); err != nil {
+		conn.Close()
+		span.End(err)
+		return nil, err
+	}
+
+	aes := &AccountEventStream{
+		conn:         conn,
+		closeChan:    make(chan struct{}),
+		stallTimeout: tc.streamStallTimeout,
+		span:         span,
+		metrics:      tc.metrics,
+	}
+	go aes.consumeEvents(output)
+	return aes, nil
+}
+
+// Stop closes the stream.
+func (aes *AccountEventStream) Stop() {
+	aes.once.Do(func() {
+		close(aes.closeChan)
+		aes.conn.Close()
+	})
+}
+
+// Err returns the error that ended the stream, if it was not stopped.
+func (aes *AccountEventStream) Err() error {
+	aes.mu.Lock()
+	defer aes.mu.Unlock()
+	return aes.err
+}
+
+func (aes *AccountEventStream) consumeEvents(output chan *AccountEvent) {
+	defer close(output)
+	defer func() { aes.span.End(aes.Err()) }()
+	for {
+		// Heartbeats are sent regularly, so a stall is detected by a read deadline.
+		if aes.stallTimeout > 0 {
+			aes.conn.SetReadDeadline(time.Now().Add(aes.stallTimeout))
+		}
+		var event AccountEvent
+		_, msg, err := aes.conn.ReadMessage()
+		if err == nil {
+			aes.metrics.ObserveStreamBytes("account", len(msg))
+			err = json.Unmarshal(msg, &event)
+		}
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				err = ErrStreamStalled{Timeout: aes.stallTimeout}
+			}
+			select {
+			case <-aes.closeChan:
+			default:
+				if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					aes.mu.Lock()
+					aes.err = err
+					aes.mu.Unlock()
+				}
+			}
+			return
+		}
+
+		// Heartbeats and other non-order messages are ignored.
+		if event.Event != "order" {
+			continue
+		}
+		select {
+		case output <- &event:
+		case <-aes.closeChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_StreamAccountEvents(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/events/session":
+			wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/accounts/events"
+			w.Write([]byte(`{"stream": {"url": "` + wsURL + `", "sessionid": "session-1"}}`))
+		case "/v1/accounts/events":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+
+			var subscription struct {
+				Events    []string
+				SessionId string `json:"sessionid"`
+			}
+			assert.NoError(t, conn.ReadJSON(&subscription))
+			assert.Equal(t, "session-1", subscription.SessionId)
+			for _, msg := range []string{
+				`{"event": "heartbeat"}`,
+				`{"id": 1, "event": "order", "status": "partially_filled", "executed_quantity": 50, "remaining_quantity": 50, "last_fill_price": 281.1}`,
+				`{"id": 1, "event": "order", "status": "filled", "executed_quantity": 100, "avg_fill_price": 281.15, "transaction_date": "2021-01-04T14:30:01.000Z"}`,
+				`{"id": 2, "event": "order", "status": "rejected"}`,
+			} {
+				conn.WriteMessage(websocket.TextMessage, []byte(msg))
+			}
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+
+	events := make(chan *AccountEvent)
+	stream, err := client.StreamAccountEvents(events)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer stream.Stop()
+
+	checker := NewConsistencyChecker(nil, nil)
+	var received []*AccountEvent
+	for e := range events {
+		checker.ObserveEvent(e)
+		received = append(received, e)
+	}
+	assert.NoError(t, stream.Err())
+	if assert.Len(t, received, 3) {
+		assert.True(t, received[0].IsFill())
+		assert.False(t, received[0].IsFinal())
+		assert.Equal(t, 281.15, received[1].AverageFillPrice)
+		assert.True(t, received[2].IsFinal())
+	}
+	assert.Equal(t, Filled, checker.observed[1])
+}
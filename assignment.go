@@ -0,0 +1,237 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Types of option events in account history.
+const (
+	OptionAssignment = "ASSIGNMENT"
+	OptionExercise   = "EXERCISE"
+	OptionExpiration = "OPTEXP"
+)
+
+// OptionEvent is the detail of an "option" account history event.
+type OptionEvent struct {
+	OptionType  string `json:"option_type"`
+	Description string
+	Quantity    float64
+	Symbol      string
+}
+
+var occSymbolInText = regexp.MustCompile(`[A-Z0-9.]{1,6}\d{6}[CP]\d{8}`)
+
+// AssignmentEvent is an option assignment, exercise or expiration
+// detected in the account history.
+type AssignmentEvent struct {
+	// One of OptionAssignment, OptionExercise or OptionExpiration.
+	Type     string
+	Date     time.Time
+	Symbol   string
+	Contract OptionSymbol
+	// Number of contracts removed from the position.
+	Contracts float64
+	// The option position before the event, if it was held.
+	Position *Position
+	// Shares of the underlying delivered by an assignment or exercise,
+	// negative if they were sold, at the strike price.
+	Shares float64
+	// Cash paid by the assignment or exercise of a cash-settled contract,
+	// negative if it was paid out, as recorded in the history event.
+	Cash float64
+}
+
+func (ae AssignmentEvent) String() string {
+	switch ae.Type {
+	case OptionExpiration:
+		return fmt.Sprintf("%v contracts of %v expired", ae.Contracts, ae.Symbol)
+	case OptionAssignment:
+		if ae.Contract.Style().CashSettled {
+			return fmt.Sprintf("assigned on %v contracts of %v: %+.2f cash", ae.Contracts, ae.Symbol, ae.Cash)
+		}
+		return fmt.Sprintf("assigned on %v contracts of %v: %+g shares of %v at %v",
+			ae.Contracts, ae.Symbol, ae.Shares, ae.Contract.Underlying, ae.Contract.Strike)
+	default:
+		if ae.Contract.Style().CashSettled {
+			return fmt.Sprintf("exercised %v contracts of %v: %+.2f cash", ae.Contracts, ae.Symbol, ae.Cash)
+		}
+		return fmt.Sprintf("exercised %v contracts of %v: %+g shares of %v at %v",
+			ae.Contracts, ae.Symbol, ae.Shares, ae.Contract.Underlying, ae.Contract.Strike)
+	}
+}
+
+// DetectAssignments returns the assignment, exercise and expiration events in
+// history, oldest first, linked to the option positions in book (if not nil).
+// Events whose contract cannot be determined from the event are skipped.
+func DetectAssignments(history []*Event, book *PositionBook) []AssignmentEvent {
+	var detected []AssignmentEvent
+	for _, e := range history {
+		if e.Type != "option" {
+			continue
+		}
+		eventType := strings.ToUpper(e.Option.OptionType)
+		if eventType != OptionAssignment && eventType != OptionExercise && eventType != OptionExpiration {
+			continue
+		}
+
+		symbol := e.Option.Symbol
+		if symbol == "" {
+			symbol = occSymbolInText.FindString(e.Option.Description)
+		}
+		contract, err := ParseOptionSymbol(symbol)
+		if err != nil {
+			continue
+		}
+
+		ae := AssignmentEvent{
+			Type:      eventType,
+			Date:      e.Date.Time,
+			Symbol:    symbol,
+			Contract:  contract,
+			Contracts: math.Abs(e.Option.Quantity),
+		}
+		if book != nil {
+			ae.Position = book.Position(symbol)
+		}
+		// Short positions are assigned and long positions exercised,
+		// unless the held position says otherwise.
+		short := eventType == OptionAssignment
+		if ae.Position != nil {
+			short = ae.Position.Quantity < 0
+		}
+		if eventType != OptionExpiration && contract.Style().CashSettled {
+			ae.Cash = e.Amount
+		} else if eventType != OptionExpiration {
+			// Calls deliver stock to the long side, puts to the short side.
+			shares := ae.Contracts * contractMultiplier(symbol)
+			if (contract.OptionType == Call) == short {
+				shares = -shares
+			}
+			ae.Shares = shares
+		}
+		detected = append(detected, ae)
+	}
+
+	sort.SliceStable(detected, func(i, j int) bool { return detected[i].Date.Before(detected[j].Date) })
+	return detected
+}
+
+// ApplyAssignment updates the book for the event and returns the realized gain.
+// The option lots are closed; for an assignment or exercise the premium goes
+// into the cost basis (or proceeds) of the shares delivered at the strike,
+// and for an expiration it is realized. Cash-settled contracts are closed
+// for the cash of the event.
+func (pb *PositionBook) ApplyAssignment(ae AssignmentEvent) float64 {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	// Close the side of the option position that is held.
+	contracts := ae.Contracts
+	if p := pb.position(ae.Symbol); p != nil && p.Quantity > 0 {
+		contracts = -contracts
+	}
+	if ae.Type == OptionExpiration {
+		return pb.trade(ae.Symbol, contracts, 0, ae.Date)
+	} else if ae.Contract.Style().CashSettled {
+		return pb.trade(ae.Symbol, contracts, -ae.Cash, ae.Date)
+	}
+
+	_, premium := pb.close(ae.Symbol, -contracts)
+	cost := ae.Shares*ae.Contract.Strike + premium
+	return pb.trade(ae.Contract.Underlying, ae.Shares, cost, ae.Date)
+}
+
+// AssignmentMonitor polls the account history for assignments, exercises and
+// expirations, applying them to Book and notifying each one once.
+type AssignmentMonitor struct {
+	Client   *Client
+	Book     *PositionBook
+	Interval time.Duration
+	// Number of history events fetched on each poll.
+	Limit  int
+	Notify func(event AssignmentEvent, realized float64)
+	Errors func(err error)
+
+	seen map[string]bool
+}
+
+// NewAssignmentMonitor returns a monitor of the selected account, with
+// a position book of its current positions.
+func (tc *Client) NewAssignmentMonitor(notify func(event AssignmentEvent, realized float64)) (*AssignmentMonitor, error) {
+	positions, err := tc.GetAccountPositions()
+	if err != nil {
+		return nil, err
+	}
+	am := &AssignmentMonitor{
+		Client:   tc,
+		Book:     NewPositionBook(positions),
+		Interval: time.Minute,
+		Limit:    25,
+		Notify:   notify,
+		Errors:   func(err error) { tc.logger.Error("Assignment monitor failed", "error", err) },
+		seen:     make(map[string]bool),
+	}
+
+	// Events already in the history are reflected in the current positions.
+	history, err := tc.GetAccountHistory(am.Limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, ae := range DetectAssignments(history, nil) {
+		am.seen[assignmentKey(ae)] = true
+	}
+	return am, nil
+}
+
+// Check polls the history once, returning any new events.
+func (am *AssignmentMonitor) Check() ([]AssignmentEvent, error) {
+	history, err := am.Client.GetAccountHistory(am.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if am.seen == nil {
+		am.seen = make(map[string]bool)
+	}
+	var events []AssignmentEvent
+	for _, ae := range DetectAssignments(history, am.Book) {
+		key := assignmentKey(ae)
+		if am.seen[key] {
+			continue
+		}
+		am.seen[key] = true
+
+		realized := am.Book.ApplyAssignment(ae)
+		if am.Notify != nil {
+			am.Notify(ae, realized)
+		}
+		events = append(events, ae)
+	}
+	return events, nil
+}
+
+// Run checks the history every Interval until stop is closed.
+func (am *AssignmentMonitor) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(am.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := am.Check(); err != nil && am.Errors != nil {
+			am.Errors(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func assignmentKey(ae AssignmentEvent) string {
+	return fmt.Sprintf("%s/%s/%s/%v", ae.Date.Format(time.RFC3339), ae.Type, ae.Symbol, ae.Contracts)
+}
@@ -0,0 +1,98 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectAssignments(t *testing.T) {
+	day := time.Date(2021, time.February, 19, 0, 0, 0, 0, time.UTC)
+	history := []*Event{
+		{Type: "option", Date: DateTime{day}, Option: OptionEvent{OptionType: "OPTEXP", Description: "Expired SPY210219C00400000", Quantity: 2}},
+		{Type: "trade", Date: DateTime{day}, Trade: Trade{Symbol: "SPY", Quantity: 100}},
+		{Type: "option", Date: DateTime{day}, Option: OptionEvent{OptionType: "ASSIGNMENT", Description: "Assignment SPY210219P00360000", Quantity: -1}},
+		{Type: "option", Date: DateTime{day}, Option: OptionEvent{OptionType: "EXERCISE", Symbol: "AAPL210219P00120000", Quantity: 1}},
+	}
+	book := NewPositionBook([]*Position{
+		{Symbol: "SPY210219C00400000", Quantity: -2, CostBasis: -150},
+		{Symbol: "SPY210219P00360000", Quantity: -1, CostBasis: -440},
+		{Symbol: "AAPL210219P00120000", Quantity: 1, CostBasis: 80},
+		{Symbol: "AAPL", Quantity: 100, CostBasis: 13000},
+	})
+
+	events := DetectAssignments(history, book)
+	if !assert.Len(t, events, 3) {
+		return
+	}
+
+	assert.Equal(t, OptionExpiration, events[0].Type)
+	assert.Equal(t, 2.0, events[0].Contracts)
+	assert.Equal(t, 0.0, events[0].Shares)
+	assert.Equal(t, 150.0, book.ApplyAssignment(events[0]), "premium realized on expiry")
+	assert.Nil(t, book.Position("SPY210219C00400000"))
+
+	// The short put delivers stock at the strike less the premium.
+	assert.Equal(t, OptionAssignment, events[1].Type)
+	assert.Equal(t, -1.0, events[1].Position.Quantity)
+	assert.Equal(t, 100.0, events[1].Shares)
+	assert.Equal(t, 0.0, book.ApplyAssignment(events[1]))
+	if p := book.Position("SPY"); assert.NotNil(t, p) {
+		assert.Equal(t, 100.0, p.Quantity)
+		assert.Equal(t, 35560.0, p.CostBasis)
+	}
+
+	// The long put sells stock at the strike less the premium paid.
+	assert.Equal(t, -100.0, events[2].Shares)
+	assert.Equal(t, 11920.0-13000.0, book.ApplyAssignment(events[2]))
+	assert.Nil(t, book.Position("AAPL"))
+	assert.Nil(t, book.Position("AAPL210219P00120000"))
+}
+
+func TestAssignmentMonitor(t *testing.T) {
+	history := `{"event": [{"type": "option", "date": "2021-02-12T00:00:00Z", "option": {"option_type": "OPTEXP", "description": "SPY210212C00400000", "quantity": 1}}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/VA000/positions":
+			w.Write([]byte(`{"positions": {"position": [{"symbol": "SPY210219P00360000", "quantity": -1, "cost_basis": -440}]}}`))
+		case "/v1/accounts/VA000/history":
+			w.Write([]byte(`{"history": ` + history + `}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	client := NewClient(params)
+
+	var notified []string
+	monitor, err := client.NewAssignmentMonitor(func(ae AssignmentEvent, realized float64) {
+		notified = append(notified, ae.String())
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	events, err := monitor.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, events, "events before the monitor started are ignored")
+
+	history = `{"event": [
+		{"type": "option", "date": "2021-02-12T00:00:00Z", "option": {"option_type": "OPTEXP", "description": "SPY210212C00400000", "quantity": 1}},
+		{"type": "option", "date": "2021-02-19T00:00:00Z", "option": {"option_type": "ASSIGNMENT", "description": "SPY210219P00360000", "quantity": 1}}]}`
+	events, err = monitor.Check()
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, []string{"assigned on 1 contracts of SPY210219P00360000: +100 shares of SPY at 360"}, notified)
+	if p := monitor.Book.Position("SPY"); assert.NotNil(t, p) {
+		assert.Equal(t, 35560.0, p.CostBasis)
+	}
+
+	events, err = monitor.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
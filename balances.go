@@ -0,0 +1,87 @@
+package tradier
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInsufficientSettledCash is returned when ClientParams.RequireSettledCash is
+// set and a buy order in a cash account would cost more than the settled cash,
+// which would risk a free-riding violation.
+var ErrInsufficientSettledCash = errors.New("order cost exceeds settled cash")
+
+// IsCashAccount returns true if the balances are for a cash account.
+func (ab *AccountBalances) IsCashAccount() bool {
+	return ab.AccountType == CashAccount
+}
+
+// SweepBalance returns the balance of the account's cash sweep.
+func (ab *AccountBalances) SweepBalance() float64 {
+	if ab.IsCashAccount() {
+		return ab.Cash.Sweep
+	}
+	return ab.Margin.Sweep
+}
+
+// SettledCash returns the cash that has settled and may be used to buy without
+// selling the purchase before the funds settle. It excludes proceeds of unsettled
+// sales, uncleared deposits and cash pending from unfilled orders.
+func (ab *AccountBalances) SettledCash() float64 {
+	if !ab.IsCashAccount() {
+		return ab.TotalCash - ab.UnclearedFunds - ab.PendingCash
+	}
+	return math.Max(0, ab.Cash.CashAvailable-ab.Cash.UnsettledFunds-ab.UnclearedFunds)
+}
+
+// Returns true if the order spends cash to open or add to a position.
+func isBuyOrder(order Order) bool {
+	switch order.Class {
+	case Equity, Option:
+		return order.Side == Buy || order.Side == BuyToOpen
+	case Multileg, Combo:
+		return order.Type == Debit || order.Type == MarketOrder
+	default:
+		for _, leg := range order.Legs {
+			if isBuyOrder(leg) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Check that a buy order in a cash account can be paid for with settled cash.
+// Orders whose cost cannot be estimated from their price are priced at the ask.
+func (tc *Client) checkSettledCash(order Order) error {
+	if !tc.requireSettledCash || !isBuyOrder(order) {
+		return nil
+	}
+
+	balances, err := tc.GetAccountBalances()
+	if err != nil {
+		return err
+	} else if !balances.IsCashAccount() {
+		return nil
+	}
+
+	cost, ok := orderNotional(order)
+	if !ok && (order.Class == Equity || order.Class == Option) {
+		symbol := orderSymbol(&order)
+		quotes, err := tc.GetQuotes([]string{symbol})
+		if err != nil {
+			return err
+		}
+		if len(quotes) > 0 && quotes[0].Ask > 0 {
+			cost, ok = order.Quantity*quotes[0].Ask*contractMultiplier(symbol), true
+		}
+	}
+	if !ok {
+		return errors.Wrap(ErrInsufficientSettledCash, "cannot estimate the cost of the order")
+	}
+
+	if settled := balances.SettledCash(); cost > settled {
+		return errors.Wrapf(ErrInsufficientSettledCash, "order costs %.2f with %.2f settled", cost, settled)
+	}
+	return nil
+}
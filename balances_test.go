@@ -0,0 +1,78 @@
+package tradier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const cashBalancesJSON = `{"balances": {"account_number": "VA000", "account_type": "cash",
+	"total_cash": 5000, "uncleared_funds": 500, "pending_cash": 0, "dividend_balance": 12.5, "net_value": 9000,
+	"cash": {"cash_available": 4500, "sweep": 1234.56, "unsettled_funds": 1500}}}`
+
+func TestAccountBalances_SettledCash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(cashBalancesJSON))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	balances, err := NewClient(params).GetAccountBalances()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, balances.IsCashAccount())
+	assert.Equal(t, 1234.56, balances.SweepBalance())
+	assert.Equal(t, 12.5, balances.DividendBalance)
+	assert.Equal(t, 9000.0, balances.NetValue)
+	assert.Equal(t, 2500.0, balances.SettledCash())
+}
+
+func TestClient_RequireSettledCash(t *testing.T) {
+	var placed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/VA000/balances":
+			w.Write([]byte(cashBalancesJSON))
+		case "/v1/markets/quotes":
+			w.Write([]byte(`{"quotes": {"quote": {"symbol": "SPY", "bid": 379.9, "ask": 380}}}`))
+		case "/v1/accounts/VA000/orders":
+			placed++
+			w.Write([]byte(`{"order": {"id": 1, "status": "ok"}}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.RequireSettledCash = true
+	params.Account = "VA000"
+	client := NewClient(params)
+
+	for _, test := range []struct {
+		name  string
+		order Order
+		err   error
+	}{
+		{"Limit buy within settled cash", Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 6, Type: LimitOrder, Price: 400, Duration: Day}, nil},
+		{"Limit buy exceeding settled cash", Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 7, Type: LimitOrder, Price: 400, Duration: Day}, ErrInsufficientSettledCash},
+		{"Market buy priced at the ask", Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 7, Type: MarketOrder, Duration: Day}, ErrInsufficientSettledCash},
+		{"Sales are not limited", Order{Class: Equity, Symbol: "SPY", Side: Sell, Quantity: 100, Type: MarketOrder, Duration: Day}, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := client.PlaceOrder(test.order)
+			assert.Equal(t, test.err, errors.Cause(err))
+			_, err = client.FastPlaceOrder(context.Background(), test.order)
+			assert.Equal(t, test.err, errors.Cause(err), "FastPlaceOrder")
+		})
+	}
+	assert.Equal(t, 4, placed)
+}
@@ -0,0 +1,193 @@
+package tradier
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Sources of the bars returned by BarChain.
+const (
+	BarFromStream    = "stream"
+	BarFromTimeSales = "timesales"
+	BarFromHistory   = "history"
+)
+
+// SourcedBar is a bar annotated with the source it came from.
+type SourcedBar struct {
+	TimeSale
+	// One of the BarFrom sources, e.g. BarFromStream.
+	Source string
+}
+
+type streamBarsKey struct {
+	symbol   string
+	interval time.Duration
+}
+
+// StreamBars keeps the bars built from the market stream, for BarChain.
+// Its Add method can be the callback of NewMultiBarBuilder.
+type StreamBars struct {
+	// Bars kept per symbol and interval, dropping the oldest. If zero then
+	// all bars are kept.
+	MaxBars int
+
+	mu   sync.Mutex
+	bars map[streamBarsKey][]TimeSale
+}
+
+func NewStreamBars(maxBars int) *StreamBars {
+	return &StreamBars{MaxBars: maxBars, bars: make(map[streamBarsKey][]TimeSale)}
+}
+
+// Add adds a bar of the symbol at the interval. Bars are expected in order.
+func (sb *StreamBars) Add(symbol string, interval time.Duration, bar TimeSale) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	if sb.bars == nil {
+		sb.bars = make(map[streamBarsKey][]TimeSale)
+	}
+	key := streamBarsKey{symbol, interval}
+	bars := append(sb.bars[key], bar)
+	if sb.MaxBars > 0 && len(bars) > sb.MaxBars {
+		bars = append([]TimeSale(nil), bars[len(bars)-sb.MaxBars:]...)
+	}
+	sb.bars[key] = bars
+}
+
+// Bars returns the bars of the symbol at the interval that start from start
+// to end. Zero times do not limit the range.
+func (sb *StreamBars) Bars(symbol string, interval time.Duration, start, end time.Time) []TimeSale {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	var result []TimeSale
+	for _, bar := range sb.bars[streamBarsKey{symbol, interval}] {
+		t := barTime(bar)
+		if (start.IsZero() || !t.Before(start)) && (end.IsZero() || !t.After(end)) {
+			result = append(result, bar)
+		}
+	}
+	return result
+}
+
+// BarChain returns the bars of a range from the best source that has them:
+// the bars built from the stream, then time and sales for the part of the
+// range before the first streamed bar, and then daily history for the part
+// too old for time and sales. Each bar is annotated with its source.
+type BarChain struct {
+	// Bars built from the stream. If nil then only Source is used.
+	Stream *StreamBars
+	Source BarSource
+
+	now func() time.Time
+}
+
+func NewBarChain(stream *StreamBars, source BarSource) *BarChain {
+	return &BarChain{Stream: stream, Source: source}
+}
+
+// ErrNoBarSource is returned by BarChain.GetBars if it has no Source.
+var ErrNoBarSource = errors.New("bar chain has no source")
+
+// GetBars returns the bars of the symbol at the interval from start to end,
+// in order of time. Daily, weekly and monthly bars are always requested from
+// the history. Intraday bars that are older than time and sales are
+// available for are replaced by daily bars, so the bars of a long range may
+// have two intervals; their Source tells them apart.
+func (bc *BarChain) GetBars(symbol string, interval Interval, start, end time.Time) ([]SourcedBar, error) {
+	if bc.Source == nil {
+		return nil, ErrNoBarSource
+	}
+	if interval.IsHistory() {
+		bars, err := bc.Source.GetTimeSales(symbol, interval, start, end)
+		return sourcedBars(nil, bars, BarFromHistory), err
+	}
+	duration, ok := intervalDurations[interval]
+	if !ok {
+		return nil, fmt.Errorf("bars are not available at the %v interval", interval)
+	}
+	if end.IsZero() {
+		end = bc.clock()
+	}
+
+	var streamed []TimeSale
+	if bc.Stream != nil {
+		streamed = bc.Stream.Bars(symbol, duration, start, end)
+	}
+	cutoff := end
+	if len(streamed) > 0 {
+		cutoff = barTime(streamed[0])
+	}
+
+	var result []SourcedBar
+	if start.Before(cutoff) {
+		// Time and sales are requested from the start of the first full day
+		// they are available for, the rest of the range from the history.
+		from := start
+		var rangeErr *IntervalRangeError
+		if err := interval.Validate(start, cutoff, bc.clock()); errors.As(err, &rangeErr) && rangeErr.Earliest.After(start) {
+			earliest := rangeErr.Earliest.In(marketTimezone)
+			from = time.Date(earliest.Year(), earliest.Month(), earliest.Day()+1, 0, 0, 0, 0, marketTimezone)
+			if from.After(cutoff) {
+				from = cutoff
+			}
+			history, err := bc.Source.GetTimeSales(symbol, IntervalDaily, start, from.AddDate(0, 0, -1))
+			if err != nil {
+				return nil, err
+			}
+			last := from.Format("2006-01-02")
+			for _, bar := range history {
+				if bar.Date.Format("2006-01-02") < last {
+					result = append(result, SourcedBar{TimeSale: bar, Source: BarFromHistory})
+				}
+			}
+		}
+
+		if from.Before(cutoff) {
+			backfill, err := bc.Source.GetTimeSales(symbol, interval, from, cutoff)
+			if err != nil {
+				return nil, err
+			}
+			for _, bar := range backfill {
+				if t := barTime(bar); !t.Before(from) && t.Before(cutoff) {
+					result = append(result, SourcedBar{TimeSale: bar, Source: BarFromTimeSales})
+				}
+			}
+		}
+	}
+	result = sourcedBars(result, streamed, BarFromStream)
+
+	sort.SliceStable(result, func(i, j int) bool { return barTime(result[i].TimeSale).Before(barTime(result[j].TimeSale)) })
+	return result, nil
+}
+
+func (bc *BarChain) clock() time.Time {
+	if bc.now != nil {
+		return bc.now()
+	}
+	return time.Now()
+}
+
+var intervalDurations = map[Interval]time.Duration{
+	IntervalMinute: time.Minute,
+	Interval5Min:   5 * time.Minute,
+	Interval15Min:  15 * time.Minute,
+}
+
+func sourcedBars(result []SourcedBar, bars []TimeSale, source string) []SourcedBar {
+	for _, bar := range bars {
+		result = append(result, SourcedBar{TimeSale: bar, Source: source})
+	}
+	return result
+}
+
+// Returns the start of the bar: the time of intraday bars, or the date of
+// daily bars.
+func barTime(bar TimeSale) time.Time {
+	if !bar.Time.IsZero() {
+		return bar.Time.Time
+	}
+	return bar.Date.Time
+}
@@ -0,0 +1,92 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBarSource struct {
+	requests []Interval
+}
+
+func (fb *fakeBarSource) GetTimeSales(symbol string, interval Interval, start, end time.Time) ([]TimeSale, error) {
+	fb.requests = append(fb.requests, interval)
+	var bars []TimeSale
+	if interval.IsHistory() {
+		for d := start.Truncate(24 * time.Hour); !d.After(end); d = d.AddDate(0, 0, 1) {
+			bars = append(bars, TimeSale{Date: DateTime{d}, Close: 100})
+		}
+		return bars, nil
+	}
+	for t := start; !t.After(end); t = t.Add(15 * time.Minute) {
+		bars = append(bars, TimeSale{Time: DateTime{t}, Close: 101})
+	}
+	return bars, nil
+}
+
+func TestBarChain_GetBars(t *testing.T) {
+	now := time.Date(2024, 6, 28, 15, 0, 0, 0, marketTimezone)
+	stream := NewStreamBars(3)
+	for m := 0; m < 4; m++ {
+		stream.Add("SPY", 15*time.Minute, TimeSale{Time: DateTime{now.Add(time.Duration(m-4) * 15 * time.Minute)}, Close: 102})
+	}
+	source := &fakeBarSource{}
+	chain := NewBarChain(stream, source)
+	chain.now = func() time.Time { return now }
+
+	t.Run("Recent", func(t *testing.T) {
+		source.requests = nil
+		bars, err := chain.GetBars("SPY", Interval15Min, now.Add(-2*time.Hour), now)
+		assert.NoError(t, err)
+		assert.Equal(t, []Interval{Interval15Min}, source.requests, "no history is needed")
+		if assert.Len(t, bars, 8) {
+			assert.Equal(t, BarFromTimeSales, bars[0].Source)
+			assert.Equal(t, BarFromTimeSales, bars[4].Source, "only the last 3 streamed bars are kept")
+			assert.Equal(t, BarFromStream, bars[5].Source)
+			assert.True(t, bars[5].Time.Equal(now.Add(-45*time.Minute)))
+		}
+	})
+
+	t.Run("Old", func(t *testing.T) {
+		source.requests = nil
+		start := time.Date(2024, 5, 1, 0, 0, 0, 0, marketTimezone)
+		bars, err := chain.GetBars("SPY", Interval15Min, start, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []Interval{IntervalDaily, Interval15Min}, source.requests)
+
+		backfillStart := time.Date(2024, 6, 9, 0, 0, 0, 0, marketTimezone)
+		counts := make(map[string]int)
+		for i, bar := range bars {
+			counts[bar.Source]++
+			switch bar.Source {
+			case BarFromHistory:
+				assert.True(t, bar.Date.Before(backfillStart), "daily bar of %v", bar.Date)
+			case BarFromTimeSales:
+				assert.False(t, bar.Time.Before(backfillStart))
+			}
+			if i > 0 {
+				assert.False(t, barTime(bar.TimeSale).Before(barTime(bars[i-1].TimeSale)), "bars are in order")
+			}
+		}
+		assert.Equal(t, 39, counts[BarFromHistory], "May 1 to June 8")
+		assert.Equal(t, 3, counts[BarFromStream])
+		assert.NotZero(t, counts[BarFromTimeSales])
+	})
+
+	t.Run("Daily", func(t *testing.T) {
+		source.requests = nil
+		bars, err := chain.GetBars("SPY", IntervalDaily, now.AddDate(0, 0, -3), now)
+		assert.NoError(t, err)
+		assert.Equal(t, []Interval{IntervalDaily}, source.requests)
+		if assert.NotEmpty(t, bars) {
+			assert.Equal(t, BarFromHistory, bars[0].Source)
+		}
+	})
+
+	t.Run("Tick", func(t *testing.T) {
+		_, err := chain.GetBars("SPY", IntervalTick, now.Add(-time.Hour), now)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,159 @@
+package tradier
+
+import (
+	"sync"
+	"time"
+)
+
+// BarBuilder aggregates streamed time sales into OHLCV bars.
+// Bars are emitted when the first print of the following interval arrives, or on Flush.
+//
+// Bars of several intervals may be built from the same prints by one builder,
+// which filters each print once and keeps the bars of a symbol together.
+// The intervals must not be changed once prints have been added.
+type BarBuilder struct {
+	Interval time.Duration
+	// Intervals of bars built in addition to Interval.
+	Intervals []time.Duration
+	// If set, only prints for which Filter returns true are included.
+	Filter PrintFilter
+	// Bars is called with the bars of Interval.
+	Bars func(symbol string, bar TimeSale)
+	// If set, IntervalBars is called with the bars of every interval.
+	IntervalBars func(symbol string, interval time.Duration, bar TimeSale)
+
+	mu sync.Mutex
+	// The bars in progress of each symbol, by index of the interval.
+	current map[string][]*barState
+}
+
+type barState struct {
+	start    time.Time
+	bar      TimeSale
+	notional float64
+}
+
+type closedBar struct {
+	index int
+	bar   TimeSale
+}
+
+func NewBarBuilder(interval time.Duration, filter PrintFilter, bars func(symbol string, bar TimeSale)) *BarBuilder {
+	return &BarBuilder{
+		Interval: interval,
+		Filter:   filter,
+		Bars:     bars,
+		current:  make(map[string][]*barState),
+	}
+}
+
+// NewMultiBarBuilder returns a builder of bars of each of the intervals,
+// e.g. one, five and fifteen minutes, from the same prints.
+func NewMultiBarBuilder(intervals []time.Duration, filter PrintFilter, bars func(symbol string, interval time.Duration, bar TimeSale)) *BarBuilder {
+	return &BarBuilder{
+		Intervals:    intervals,
+		Filter:       filter,
+		IntervalBars: bars,
+		current:      make(map[string][]*barState),
+	}
+}
+
+// Returns the interval with index i, Interval followed by Intervals.
+func (bb *BarBuilder) interval(i int) time.Duration {
+	if i == 0 {
+		return bb.Interval
+	}
+	return bb.Intervals[i-1]
+}
+
+// AddTimeSale adds a print to the bars of its symbol.
+func (bb *BarBuilder) AddTimeSale(tse *TimeSaleEvent) {
+	if bb.Filter != nil && !bb.Filter(tse) {
+		return
+	}
+
+	t := timeFromMs(tse.DateMs)
+	var closed []closedBar
+
+	bb.mu.Lock()
+	if bb.current == nil {
+		bb.current = make(map[string][]*barState)
+	}
+	states := bb.current[tse.Symbol]
+	if states == nil {
+		states = make([]*barState, 1+len(bb.Intervals))
+		bb.current[tse.Symbol] = states
+	}
+	for i, state := range states {
+		interval := bb.interval(i)
+		if interval <= 0 {
+			continue
+		}
+		start := t.Truncate(interval)
+		if state != nil && start.After(state.start) {
+			closed = append(closed, closedBar{index: i, bar: state.bar})
+			state = nil
+		}
+		if state == nil {
+			state = &barState{start: start}
+			state.bar = TimeSale{
+				Time:      DateTime{start},
+				Timestamp: start.Unix(),
+				Open:      FloatOrNaN(tse.Last),
+				High:      FloatOrNaN(tse.Last),
+				Low:       FloatOrNaN(tse.Last),
+			}
+			states[i] = state
+		}
+		if !start.Before(state.start) {
+			updateBar(state, tse.Last, tse.Size)
+		}
+	}
+	bb.mu.Unlock()
+
+	for _, c := range closed {
+		bb.emit(tse.Symbol, c)
+	}
+}
+
+// Flush emits all bars in progress.
+func (bb *BarBuilder) Flush() {
+	bb.mu.Lock()
+	current := bb.current
+	bb.current = make(map[string][]*barState)
+	bb.mu.Unlock()
+
+	for symbol, states := range current {
+		for i, state := range states {
+			if state != nil {
+				bb.emit(symbol, closedBar{index: i, bar: state.bar})
+			}
+		}
+	}
+}
+
+func (bb *BarBuilder) emit(symbol string, c closedBar) {
+	if c.index == 0 && bb.Bars != nil {
+		bb.Bars(symbol, c.bar)
+	}
+	if bb.IntervalBars != nil {
+		bb.IntervalBars(symbol, bb.interval(c.index), c.bar)
+	}
+}
+
+func updateBar(state *barState, price float64, size int64) {
+	bar := &state.bar
+	if FloatOrNaN(price) > bar.High {
+		bar.High = FloatOrNaN(price)
+	}
+	if FloatOrNaN(price) < bar.Low {
+		bar.Low = FloatOrNaN(price)
+	}
+	bar.Close = FloatOrNaN(price)
+	bar.Price = FloatOrNaN(price)
+	bar.Volume += size
+	state.notional += price * float64(size)
+	if bar.Volume > 0 {
+		bar.Vwap = FloatOrNaN(state.notional / float64(bar.Volume))
+	}
+}
@@ -0,0 +1,92 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarBuilder(t *testing.T) {
+	start := time.Date(2021, time.January, 4, 14, 30, 0, 0, time.UTC)
+	ms := func(d time.Duration) int64 {
+		return start.Add(d).UnixNano() / int64(time.Millisecond)
+	}
+
+	var bars []TimeSale
+	bb := NewBarBuilder(time.Minute, RegularPrints, func(symbol string, bar TimeSale) {
+		bars = append(bars, bar)
+	})
+
+	bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 370, Size: 100, DateMs: ms(time.Second)})
+	bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 380, Size: 5, Flag: "I", DateMs: ms(2 * time.Second)})
+	bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 360, Size: 100, Cancel: true, DateMs: ms(3 * time.Second)})
+	bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 372, Size: 300, DateMs: ms(30 * time.Second)})
+	assert.Empty(t, bars)
+
+	bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 371, Size: 100, DateMs: ms(61 * time.Second)})
+	if assert.Len(t, bars, 1) {
+		bar := bars[0]
+		assert.Equal(t, start.Unix(), bar.Timestamp)
+		assert.Equal(t, FloatOrNaN(370), bar.Open)
+		assert.Equal(t, FloatOrNaN(372), bar.High)
+		assert.Equal(t, FloatOrNaN(370), bar.Low)
+		assert.Equal(t, FloatOrNaN(372), bar.Close)
+		assert.Equal(t, int64(400), bar.Volume)
+		assert.Equal(t, FloatOrNaN(371.5), bar.Vwap)
+	}
+
+	bb.Flush()
+	assert.Len(t, bars, 2)
+}
+
+func TestParseConditions(t *testing.T) {
+	tse := &TimeSaleEvent{Flag: "@ I"}
+	assert.Equal(t, []Condition{ConditionRegular, ConditionOddLot}, tse.Conditions())
+	assert.True(t, tse.HasCondition(ConditionOddLot))
+	assert.False(t, tse.HasCondition(ConditionDerivativelyPriced))
+	assert.Equal(t, "Nasdaq", Exchange("Q").Name())
+}
+
+func TestBarBuilder_multipleIntervals(t *testing.T) {
+	start := time.Date(2021, time.January, 4, 14, 30, 0, 0, time.UTC)
+	ms := func(d time.Duration) int64 {
+		return start.Add(d).UnixNano() / int64(time.Millisecond)
+	}
+
+	bars := make(map[time.Duration][]TimeSale)
+	bb := NewMultiBarBuilder([]time.Duration{time.Minute, 5 * time.Minute}, nil, func(symbol string, interval time.Duration, bar TimeSale) {
+		bars[interval] = append(bars[interval], bar)
+	})
+
+	bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 370, Size: 100, DateMs: ms(time.Second)})
+	bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 375, Size: 100, DateMs: ms(2 * time.Minute)})
+	bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 365, Size: 200, DateMs: ms(4 * time.Minute)})
+	assert.Len(t, bars[time.Minute], 2)
+	assert.Empty(t, bars[5*time.Minute])
+
+	bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 368, Size: 100, DateMs: ms(5 * time.Minute)})
+	assert.Len(t, bars[time.Minute], 3)
+	if assert.Len(t, bars[5*time.Minute], 1) {
+		bar := bars[5*time.Minute][0]
+		assert.Equal(t, start.Unix(), bar.Timestamp)
+		assert.Equal(t, FloatOrNaN(370), bar.Open)
+		assert.Equal(t, FloatOrNaN(375), bar.High)
+		assert.Equal(t, FloatOrNaN(365), bar.Low)
+		assert.Equal(t, FloatOrNaN(365), bar.Close)
+		assert.Equal(t, int64(400), bar.Volume)
+	}
+
+	t.Run("Interval and Bars", func(t *testing.T) {
+		var primary []TimeSale
+		bb := NewBarBuilder(time.Minute, nil, func(symbol string, bar TimeSale) { primary = append(primary, bar) })
+		bb.Intervals = []time.Duration{5 * time.Minute}
+		var all int
+		bb.IntervalBars = func(symbol string, interval time.Duration, bar TimeSale) { all++ }
+
+		bb.AddTimeSale(&TimeSaleEvent{Symbol: "SPY", Last: 370, Size: 100, DateMs: ms(time.Second)})
+		bb.Flush()
+		assert.Len(t, primary, 1)
+		assert.Equal(t, 2, all)
+	})
+}
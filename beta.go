@@ -0,0 +1,48 @@
+package tradier
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BetaEndpoint names an endpoint that Tradier offers as a beta.
+type BetaEndpoint string
+
+const (
+	BetaCorporateCalendars BetaEndpoint = "fundamentals/calendars"
+	BetaCompanyInfo        BetaEndpoint = "fundamentals/company"
+	BetaCorporateActions   BetaEndpoint = "fundamentals/corporate_actions"
+	BetaDividends          BetaEndpoint = "fundamentals/dividends"
+	BetaRatios             BetaEndpoint = "fundamentals/ratios"
+	BetaFinancials         BetaEndpoint = "fundamentals/financials"
+	BetaPriceStatistics    BetaEndpoint = "fundamentals/statistics"
+)
+
+// ErrBetaDisabled is returned by requests to beta endpoints unless they are
+// enabled with ClientParams.EnableBeta.
+var ErrBetaDisabled = errors.New("beta endpoints are not enabled for this client")
+
+// DefaultPath returns the path of the endpoint under /beta.
+func (be BetaEndpoint) DefaultPath() string {
+	return "/beta/markets/" + string(be)
+}
+
+// Returns the path of the beta endpoint, or an error if it cannot be
+// requested. Endpoints that have been moved out of /beta, e.g. promoted to
+// v1 with ClientParams.BetaPaths, are no longer treated as betas.
+func (tc *Client) betaPath(endpoint BetaEndpoint) (string, error) {
+	path, ok := tc.betaPaths[endpoint]
+	if !ok {
+		path = endpoint.DefaultPath()
+	}
+	if !strings.HasPrefix(path, "/beta/") {
+		return path, nil
+	}
+	if tc.sandbox {
+		return "", errors.Wrapf(ErrUnsupportedInSandbox, "%s", endpoint)
+	} else if !tc.betaEnabled {
+		return "", errors.Wrapf(ErrBetaDisabled, "%s", endpoint)
+	}
+	return path, nil
+}
@@ -0,0 +1,50 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_BetaEndpoints(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.RetryLimit = 0
+
+	_, err := NewClient(params).GetRatios([]string{"AAPL"})
+	assert.Equal(t, ErrBetaDisabled, errors.Cause(err))
+	assert.Contains(t, err.Error(), "fundamentals/ratios")
+	assert.Empty(t, paths)
+
+	t.Run("Enabled", func(t *testing.T) {
+		paths = nil
+		params := params
+		params.EnableBeta = true
+		_, err := NewClient(params).GetRatios([]string{"AAPL"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/beta/markets/fundamentals/ratios"}, paths)
+	})
+
+	t.Run("Promoted", func(t *testing.T) {
+		paths = nil
+		params := params
+		params.BetaPaths = map[BetaEndpoint]string{BetaRatios: "/v1/markets/fundamentals/ratios"}
+		client := NewClient(params)
+		_, err := client.GetRatios([]string{"AAPL"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/v1/markets/fundamentals/ratios"}, paths)
+		_, err = client.GetDividends([]string{"AAPL"})
+		assert.Equal(t, ErrBetaDisabled, errors.Cause(err), "other endpoints are still betas")
+	})
+}
@@ -0,0 +1,118 @@
+package tradier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Environment is a consistent snapshot of the market and account state
+// that strategies can start from.
+type Environment struct {
+	Clock MarketStatus
+	// Market calendar for the current and next month.
+	Calendar     []MarketCalendar
+	Profile      *UserProfile
+	EasyToBorrow []Security
+	FetchedAt    time.Time
+}
+
+// BootstrapProgress is called after each step of Bootstrap completes.
+type BootstrapProgress func(step string, completed, total int)
+
+type bootstrapCache struct {
+	mu  sync.Mutex
+	env *Environment
+}
+
+// Bootstrap fetches the market calendar for the current and next month,
+// the market clock, the user profile and the easy-to-borrow list in sequence,
+// waiting for the rate limit window to renew if it is exhausted between steps.
+// The resulting Environment is cached and returned by Environment().
+func (tc *Client) Bootstrap(ctx context.Context, progress BootstrapProgress) (*Environment, error) {
+	now := time.Now()
+	next := nextMonth(now)
+	env := &Environment{}
+
+	steps := []struct {
+		name     string
+		category EndpointCategory
+		run      func() error
+	}{
+		{"calendar", CategoryMarketData, func() error {
+			days, err := tc.getMarketCalendar(ctx, now.Year(), now.Month())
+			env.Calendar = append(env.Calendar, days...)
+			return err
+		}},
+		{"next calendar", CategoryMarketData, func() error {
+			days, err := tc.getMarketCalendar(ctx, next.Year(), next.Month())
+			env.Calendar = append(env.Calendar, days...)
+			return err
+		}},
+		{"clock", CategoryMarketData, func() (err error) {
+			env.Clock, err = tc.getMarketState(ctx)
+			return err
+		}},
+		{"profile", CategoryStandard, func() (err error) {
+			env.Profile, err = tc.getUserProfile(ctx)
+			return err
+		}},
+		{"easy to borrow", CategoryMarketData, func() (err error) {
+			env.EasyToBorrow, err = tc.getEasyToBorrow(ctx)
+			return err
+		}},
+	}
+
+	for i, step := range steps {
+		if err := tc.waitForRateLimit(ctx, step.category); err != nil {
+			return nil, err
+		}
+		if err := step.run(); err != nil {
+			return nil, errors.Wrapf(err, "bootstrap %s", step.name)
+		}
+		if progress != nil {
+			progress(step.name, i+1, len(steps))
+		}
+	}
+	env.FetchedAt = time.Now()
+
+	tc.bootstrap.mu.Lock()
+	tc.bootstrap.env = env
+	tc.bootstrap.mu.Unlock()
+	return env, nil
+}
+
+// Environment returns the snapshot from the last successful Bootstrap, or nil.
+func (tc *Client) Environment() *Environment {
+	tc.bootstrap.mu.Lock()
+	defer tc.bootstrap.mu.Unlock()
+	return tc.bootstrap.env
+}
+
+// Wait until the rate limit window of the category renews, if the last
+// response reported that no requests are available.
+func (tc *Client) waitForRateLimit(ctx context.Context, category EndpointCategory) error {
+	w, ok := tc.rateLimits.snapshot()[category]
+	if !ok || w.Available > 0 {
+		return nil
+	}
+
+	wait := time.Until(w.Expiry)
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Returns the first day of the month after t. Adding a month to t itself
+// would skip February from the 29th to the 31st of January.
+func nextMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,65 @@
+package tradier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Bootstrap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/markets/calendar":
+			w.Write([]byte(`{"calendar": {"days": {"day": [{"date": "2021-01-04", "status": "open"}]}}}`))
+		case "/v1/markets/clock":
+			w.Write([]byte(`{"clock": {"date": "2021-01-04", "state": "open"}}`))
+		case "/v1/user/profile":
+			w.Write([]byte(`{"profile": {"id": "id-test", "name": "Test", "account": {"account_number": "VA000", "type": "margin"}}}`))
+		case "/v1/markets/etb":
+			w.Write([]byte(`{"securities": {"security": [{"symbol": "SPY"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+
+	var steps []string
+	env, err := client.Bootstrap(context.Background(), func(step string, completed, total int) {
+		steps = append(steps, step)
+		assert.Equal(t, 5, total)
+	})
+	assert.NoError(t, err)
+	assert.Len(t, steps, 5)
+	assert.Len(t, env.Calendar, 2)
+	assert.Equal(t, MarketOpen, env.Clock.State)
+	assert.Equal(t, "VA000", env.Profile.Account[0].AccountNumber)
+	assert.Equal(t, "SPY", env.EasyToBorrow[0].Symbol)
+	assert.Equal(t, env, client.Environment())
+}
+
+func TestNextMonth(t *testing.T) {
+	tests := map[string]string{
+		"2021-01-15": "2021-02-01",
+		"2021-01-29": "2021-02-01",
+		"2021-01-30": "2021-02-01",
+		"2021-01-31": "2021-02-01",
+		"2024-01-31": "2024-02-01",
+		"2021-03-31": "2021-04-01",
+		"2021-08-31": "2021-09-01",
+		"2021-12-31": "2022-01-01",
+	}
+	for day, want := range tests {
+		now, err := time.ParseInLocation("2006-01-02", day, marketTimezone)
+		if assert.NoError(t, err) {
+			assert.Equal(t, want, nextMonth(now.Add(15*time.Hour)).Format("2006-01-02"), day)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package tradier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BulkClient is the subset of the Client used by bulk operations.
+type BulkClient interface {
+	GetOpenOrders() ([]*Order, error)
+	GetOrders(params OrdersParams) ([]*Order, error)
+	GetOrderStatus(orderId int) (*Order, error)
+	GetAccountPositions() ([]*Position, error)
+	CancelOrder(orderId int) error
+	PlaceOrder(order Order) (*OrderResult, error)
+}
+
+const (
+	// Bulk item actions.
+	BulkCancel = "cancel"
+	BulkClose  = "close"
+
+	// Bulk item states.
+	BulkPending = "pending"
+	BulkDone    = "done"
+	BulkFailed  = "failed"
+
+	bulkKeyPrefix = "bulk/"
+)
+
+// BulkItem is a single step of a bulk operation.
+type BulkItem struct {
+	Action string `json:"action"`
+	// Order to cancel.
+	OrderId int `json:"order_id,omitempty"`
+	// Closing order to place.
+	Order         *Order `json:"order,omitempty"`
+	PlacedOrderId int    `json:"placed_order_id,omitempty"`
+	State         string `json:"state"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"last_error,omitempty"`
+}
+
+// BulkOperation is the persisted work list of a cancel-all or flatten-all.
+type BulkOperation struct {
+	Id      string     `json:"id"`
+	Created time.Time  `json:"created"`
+	Items   []BulkItem `json:"items"`
+}
+
+// Complete returns true if every item of the operation is done.
+func (op *BulkOperation) Complete() bool {
+	for _, item := range op.Items {
+		if item.State != BulkDone {
+			return false
+		}
+	}
+	return true
+}
+
+// BulkReconciliation reports the outcome of running a bulk operation,
+// checked against the account state after the run.
+type BulkReconciliation struct {
+	Operation *BulkOperation
+	Failed    []BulkItem
+	// Orders still open that the operation canceled or should have canceled.
+	OpenOrders []*Order
+	// Positions of symbols the operation closed or should have closed.
+	OpenPositions []*Position
+}
+
+// BulkRunner executes bulk operations, persisting the work list to Store
+// after every step so that an operation interrupted by an outage can be
+// resumed without repeating completed steps.
+type BulkRunner struct {
+	Client BulkClient
+	Store  Store
+	// Delays between attempts of a failing item. After len(Retries)+1
+	// attempts the item is marked failed and the operation moves on.
+	Retries []time.Duration
+}
+
+func NewBulkRunner(client BulkClient, store Store) *BulkRunner {
+	return &BulkRunner{
+		Client:  client,
+		Store:   store,
+		Retries: []time.Duration{time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second},
+	}
+}
+
+// CancelAll cancels all open orders as the operation with the given id.
+func (br *BulkRunner) CancelAll(ctx context.Context, id string) (*BulkReconciliation, error) {
+	op := &BulkOperation{Id: id, Created: time.Now()}
+	if err := br.addCancels(op); err != nil {
+		return nil, err
+	}
+	return br.start(ctx, op)
+}
+
+// FlattenAll cancels all open orders and then closes all positions
+// with market orders, as the operation with the given id. Closing orders
+// are tagged with the id, so it should contain only letters, digits and dashes.
+func (br *BulkRunner) FlattenAll(ctx context.Context, id string) (*BulkReconciliation, error) {
+	op := &BulkOperation{Id: id, Created: time.Now()}
+	if err := br.addCancels(op); err != nil {
+		return nil, err
+	}
+
+	positions, err := br.Client.GetAccountPositions()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range positions {
+		if p.Quantity == 0 {
+			continue
+		}
+		op.Items = append(op.Items, BulkItem{
+			Action: BulkClose,
+			Order:  closingOrder(p),
+			State:  BulkPending,
+		})
+	}
+	for i := range op.Items {
+		if op.Items[i].Order != nil {
+			op.Items[i].Order.Tag = bulkTag(id, i)
+		}
+	}
+	return br.start(ctx, op)
+}
+
+// Resume continues the operation with the given id, retrying steps that
+// are pending or previously failed.
+func (br *BulkRunner) Resume(ctx context.Context, id string) (*BulkReconciliation, error) {
+	op, err := br.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	return br.run(ctx, op)
+}
+
+// Load returns the last persisted state of the operation with the given id.
+func (br *BulkRunner) Load(id string) (*BulkOperation, error) {
+	records, err := br.Store.Range(bulkKeyPrefix+id, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no bulk operation %q", id)
+	}
+
+	var op BulkOperation
+	err = json.Unmarshal(records[len(records)-1].Data, &op)
+	return &op, err
+}
+
+// Incomplete returns the ids of persisted operations that have not completed.
+func (br *BulkRunner) Incomplete() ([]string, error) {
+	keys, err := br.Store.Keys(bulkKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, key := range keys {
+		id := strings.TrimPrefix(key, bulkKeyPrefix)
+		op, err := br.Load(id)
+		if err != nil {
+			return nil, err
+		}
+		if !op.Complete() {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (br *BulkRunner) addCancels(op *BulkOperation) error {
+	orders, err := br.Client.GetOpenOrders()
+	if err != nil {
+		return err
+	}
+	for _, o := range orders {
+		if isOpenStatus(o.Status) {
+			op.Items = append(op.Items, BulkItem{Action: BulkCancel, OrderId: o.Id, State: BulkPending})
+		}
+	}
+	return nil
+}
+
+func (br *BulkRunner) start(ctx context.Context, op *BulkOperation) (*BulkReconciliation, error) {
+	if err := br.save(op); err != nil {
+		return nil, err
+	}
+	return br.run(ctx, op)
+}
+
+func (br *BulkRunner) run(ctx context.Context, op *BulkOperation) (*BulkReconciliation, error) {
+	for i := range op.Items {
+		item := &op.Items[i]
+		if item.State == BulkDone {
+			continue
+		}
+		if err := br.runItem(ctx, op, item); err != nil {
+			br.save(op)
+			return nil, err
+		}
+
+		if err := br.save(op); err != nil {
+			return nil, err
+		}
+	}
+
+	return br.reconcile(op)
+}
+
+// runItem attempts the item according to the retry schedule. It only returns
+// an error if the context is done or an attempt cannot be persisted; item
+// failures are recorded on the item.
+func (br *BulkRunner) runItem(ctx context.Context, op *BulkOperation, item *BulkItem) error {
+	for attempt := 0; ; attempt++ {
+		// Persist the attempt before making it, so that if the process dies
+		// before its outcome is saved, a resumed run looks for the order it
+		// may have placed instead of placing another.
+		item.Attempts++
+		if err := br.save(op); err != nil {
+			return err
+		}
+		err := br.attempt(item)
+		if err == nil {
+			item.State = BulkDone
+			item.LastError = ""
+			return nil
+		}
+		item.LastError = err.Error()
+
+		if attempt >= len(br.Retries) {
+			item.State = BulkFailed
+			return nil
+		}
+		select {
+		case <-time.After(br.Retries[attempt]):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (br *BulkRunner) attempt(item *BulkItem) error {
+	switch item.Action {
+	case BulkCancel:
+		err := br.Client.CancelOrder(item.OrderId)
+		if err == nil {
+			return nil
+		}
+		// The order may have been canceled by a previous attempt whose
+		// response was lost, or have been filled in the meantime.
+		if status, statusErr := br.Client.GetOrderStatus(item.OrderId); statusErr == nil && status != nil && !isOpenStatus(status.Status) {
+			return nil
+		}
+		return err
+	case BulkClose:
+		// A previous attempt may have placed the order but not recorded it,
+		// so look for an order with the item's tag before placing another.
+		// Tradier leaves the tags out of the orders unless they are requested.
+		if item.Attempts > 1 {
+			orders, err := br.Client.GetOrders(OrdersParams{IncludeTags: true})
+			if err != nil {
+				return err
+			}
+			for _, o := range orders {
+				if o.Tag == item.Order.Tag && o.Status != Rejected && o.Status != Canceled && o.Status != Expired {
+					item.PlacedOrderId = o.Id
+					return nil
+				}
+			}
+		}
+
+		result, err := br.Client.PlaceOrder(*item.Order)
+		if err == nil {
+			item.PlacedOrderId = result.Id
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown bulk action: %v", item.Action)
+	}
+}
+
+func (br *BulkRunner) reconcile(op *BulkOperation) (*BulkReconciliation, error) {
+	rec := &BulkReconciliation{Operation: op}
+	canceled := make(map[int]bool)
+	closed := make(map[string]bool)
+	for _, item := range op.Items {
+		if item.State == BulkFailed {
+			rec.Failed = append(rec.Failed, item)
+		}
+		switch item.Action {
+		case BulkCancel:
+			canceled[item.OrderId] = true
+		case BulkClose:
+			closed[orderSymbol(item.Order)] = true
+		}
+	}
+
+	orders, err := br.Client.GetOpenOrders()
+	if err != nil {
+		return rec, err
+	}
+	for _, o := range orders {
+		if canceled[o.Id] && isOpenStatus(o.Status) {
+			rec.OpenOrders = append(rec.OpenOrders, o)
+		}
+	}
+
+	if len(closed) > 0 {
+		positions, err := br.Client.GetAccountPositions()
+		if err != nil {
+			return rec, err
+		}
+		for _, p := range positions {
+			if closed[p.Symbol] && p.Quantity != 0 {
+				rec.OpenPositions = append(rec.OpenPositions, p)
+			}
+		}
+	}
+	return rec, nil
+}
+
+func (br *BulkRunner) save(op *BulkOperation) error {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	return br.Store.Append(bulkKeyPrefix+op.Id, Record{Time: time.Now(), Data: data})
+}
+
+// closingOrder returns a market order closing the position.
+func closingOrder(p *Position) *Order {
+	order := &Order{
+		Class:    Equity,
+		Symbol:   p.Symbol,
+		Quantity: p.Quantity,
+		Type:     MarketOrder,
+		Duration: Day,
+		Side:     Sell,
+	}
+	if p.Quantity < 0 {
+		order.Quantity = -p.Quantity
+		order.Side = BuyToCover
+	}
+
+	if os, err := ParseOptionSymbol(p.Symbol); err == nil {
+		order.Class = Option
+		order.Symbol = os.Underlying
+		order.OptionSymbol = p.Symbol
+		order.Side = SellToClose
+		if p.Quantity < 0 {
+			order.Side = BuyToClose
+		}
+	}
+	return order
+}
+
+func bulkTag(id string, item int) string {
+	return fmt.Sprintf("bulk-%s-%d", id, item)
+}
+
+func isOpenStatus(status string) bool {
+	return status == Open || status == PartiallyFilled || status == Pending || status == Submitted
+}
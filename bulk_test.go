@@ -0,0 +1,206 @@
+package tradier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBulkClient struct {
+	orders    []*Order
+	positions []*Position
+	down      bool
+	placed    []Order
+	// If set, onPlace is called after an order is placed.
+	onPlace func()
+}
+
+var errOutage = errors.New("connection refused")
+
+func (fc *fakeBulkClient) GetOpenOrders() ([]*Order, error) {
+	return fc.GetOrders(OrdersParams{})
+}
+
+// GetOrders returns the orders without their tags unless they are
+// included, as Tradier does.
+func (fc *fakeBulkClient) GetOrders(params OrdersParams) ([]*Order, error) {
+	if fc.down {
+		return nil, errOutage
+	}
+	if params.IncludeTags {
+		return fc.orders, nil
+	}
+	orders := make([]*Order, len(fc.orders))
+	for i, o := range fc.orders {
+		order := *o
+		order.Tag = ""
+		orders[i] = &order
+	}
+	return orders, nil
+}
+
+func (fc *fakeBulkClient) GetOrderStatus(orderId int) (*Order, error) {
+	if fc.down {
+		return nil, errOutage
+	}
+	for _, o := range fc.orders {
+		if o.Id == orderId {
+			return o, nil
+		}
+	}
+	return nil, errors.New("no such order")
+}
+
+func (fc *fakeBulkClient) GetAccountPositions() ([]*Position, error) {
+	if fc.down {
+		return nil, errOutage
+	}
+	return fc.positions, nil
+}
+
+func (fc *fakeBulkClient) CancelOrder(orderId int) error {
+	if fc.down {
+		return errOutage
+	}
+	for _, o := range fc.orders {
+		if o.Id == orderId {
+			o.Status = Canceled
+		}
+	}
+	return nil
+}
+
+func (fc *fakeBulkClient) PlaceOrder(order Order) (*OrderResult, error) {
+	if fc.down {
+		return nil, errOutage
+	}
+	fc.placed = append(fc.placed, order)
+	order.Id = 100 + len(fc.placed)
+	order.Status = Filled
+	fc.orders = append(fc.orders, &order)
+
+	var remaining []*Position
+	for _, p := range fc.positions {
+		if p.Symbol != orderSymbol(&order) {
+			remaining = append(remaining, p)
+		}
+	}
+	fc.positions = remaining
+	if fc.onPlace != nil {
+		fc.onPlace()
+	}
+	return &OrderResult{Id: order.Id, Status: StatusOK}, nil
+}
+
+func TestBulkRunner_FlattenAll(t *testing.T) {
+	client := &fakeBulkClient{
+		orders: []*Order{
+			{Id: 1, Symbol: "SPY", Status: Open},
+			{Id: 2, Symbol: "AAPL", Status: Filled},
+		},
+		positions: []*Position{
+			{Symbol: "SPY", Quantity: 100},
+			{Symbol: "SPY210219P00360000", Quantity: -2},
+		},
+	}
+	runner := NewBulkRunner(client, NewMemoryStore())
+	runner.Retries = nil
+
+	t.Run("Work list", func(t *testing.T) {
+		op := &BulkOperation{Id: "flatten1"}
+		assert.NoError(t, runner.addCancels(op))
+		assert.Len(t, op.Items, 1)
+		assert.Equal(t, Sell, closingOrder(client.positions[0]).Side)
+		closeOption := closingOrder(client.positions[1])
+		assert.Equal(t, BuyToClose, closeOption.Side)
+		assert.Equal(t, "SPY", closeOption.Symbol)
+		assert.Equal(t, 2.0, closeOption.Quantity)
+	})
+
+	t.Run("Flatten", func(t *testing.T) {
+		rec, err := runner.FlattenAll(context.Background(), "flatten1")
+		assert.NoError(t, err)
+		assert.Empty(t, rec.Failed)
+		assert.Empty(t, rec.OpenPositions)
+		assert.Len(t, client.placed, 2)
+
+		// Re-running a complete operation does nothing.
+		rec, err = runner.Resume(context.Background(), "flatten1")
+		assert.NoError(t, err)
+		assert.True(t, rec.Operation.Complete())
+		assert.Len(t, client.placed, 2)
+	})
+
+	t.Run("Partial outage and resume", func(t *testing.T) {
+		client.down = true
+		op := &BulkOperation{Id: "flatten3", Items: []BulkItem{
+			{Action: BulkClose, Order: closingOrder(&Position{Symbol: "IWM", Quantity: 5}), State: BulkPending},
+		}}
+		op.Items[0].Order.Tag = bulkTag("flatten3", 0)
+		client.positions = []*Position{{Symbol: "IWM", Quantity: 5}}
+		rec, err := runner.start(context.Background(), op)
+		assert.Error(t, err, "reconciliation fails while the API is down")
+		assert.Len(t, rec.Failed, 1)
+
+		ids, err := runner.Incomplete()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"flatten3"}, ids)
+
+		client.down = false
+		rec, err = runner.Resume(context.Background(), "flatten3")
+		assert.NoError(t, err)
+		assert.Empty(t, rec.Failed)
+		assert.Empty(t, rec.OpenPositions)
+		assert.Equal(t, 2, rec.Operation.Items[0].Attempts)
+
+		// A tagged order already placed is not placed again.
+		placed := len(client.placed)
+		op, err = runner.Load("flatten3")
+		assert.NoError(t, err)
+		op.Items[0].State = BulkFailed
+		rec, err = runner.run(context.Background(), op)
+		assert.NoError(t, err)
+		assert.Len(t, client.placed, placed)
+	})
+}
+
+// crashStore discards appends once crashed, like a process that died.
+type crashStore struct {
+	Store
+	crashed bool
+}
+
+func (cs *crashStore) Append(key string, rec Record) error {
+	if cs.crashed {
+		return nil
+	}
+	return cs.Store.Append(key, rec)
+}
+
+func TestBulkRunner_crashAfterPlacing(t *testing.T) {
+	store := &crashStore{Store: NewMemoryStore()}
+	client := &fakeBulkClient{positions: []*Position{{Symbol: "IWM", Quantity: 5}}}
+	client.onPlace = func() { store.crashed = true }
+	runner := NewBulkRunner(client, store)
+	runner.Retries = nil
+
+	_, err := runner.FlattenAll(context.Background(), "crash1")
+	assert.NoError(t, err)
+	assert.Len(t, client.placed, 1)
+
+	// The outcome of the placed order was never persisted.
+	op, err := runner.Load("crash1")
+	assert.NoError(t, err)
+	assert.Equal(t, BulkPending, op.Items[0].State)
+	assert.Equal(t, 1, op.Items[0].Attempts)
+
+	client.onPlace = nil
+	resumed := NewBulkRunner(client, store.Store)
+	rec, err := resumed.Resume(context.Background(), "crash1")
+	assert.NoError(t, err)
+	assert.True(t, rec.Operation.Complete())
+	assert.Len(t, client.placed, 1, "the closing order is not placed twice")
+	assert.Equal(t, 101, rec.Operation.Items[0].PlacedOrderId)
+}
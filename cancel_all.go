@@ -0,0 +1,65 @@
+package tradier
+
+import (
+	"context"
+	"sync"
+)
+
+const cancelConcurrency = 4
+
+// CancelResult is the outcome of canceling an open order.
+type CancelResult struct {
+	Order *Order
+	// Error canceling the order, or nil if it was canceled.
+	Err error
+}
+
+// CancelAllOrders cancels the open orders of the account concurrently. It
+// returns the result of each cancel, in the order of the open orders. Orders
+// not yet canceled when ctx is done fail with its error.
+func (tc *Client) CancelAllOrders(ctx context.Context) ([]CancelResult, error) {
+	return tc.cancelOrders(ctx, func(order *Order) bool { return true })
+}
+
+// CancelOrdersBySymbol cancels the open orders of the account for the symbol,
+// including multileg orders with a leg for it, as CancelAllOrders does. The
+// symbol may be an underlying or an option symbol.
+func (tc *Client) CancelOrdersBySymbol(ctx context.Context, symbol string) ([]CancelResult, error) {
+	return tc.cancelOrders(ctx, func(order *Order) bool { return orderHasSymbol(order, symbol) })
+}
+
+func (tc *Client) cancelOrders(ctx context.Context, match func(order *Order) bool) ([]CancelResult, error) {
+	orders, err := tc.GetOpenOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CancelResult
+	for _, o := range orders {
+		if isOpenStatus(o.Status) && match(o) {
+			results = append(results, CancelResult{Order: o})
+		}
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < cancelConcurrency && w < len(results); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].Err = tc.CancelOrder(results[i].Order.Id)
+			}
+		}()
+	}
+	for i := range results {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results, nil
+}
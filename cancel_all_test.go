@@ -0,0 +1,86 @@
+package tradier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_CancelAllOrders(t *testing.T) {
+	var mu sync.Mutex
+	var canceled []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"orders": {"order": [
+				{"id": 1, "symbol": "SPY", "status": "open"},
+				{"id": 2, "symbol": "AAPL", "status": "partially_filled"},
+				{"id": 3, "symbol": "SPY", "status": "filled"},
+				{"id": 4, "symbol": "SPY", "class": "multileg", "status": "pending", "leg": [
+					{"option_symbol": "SPY210219C00360000"}, {"option_symbol": "SPY210219C00370000"}]},
+				{"id": 5, "symbol": "MSFT", "status": "open"}
+			]}}`))
+			return
+		}
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		mu.Lock()
+		canceled = append(canceled, id)
+		mu.Unlock()
+		if id == "5" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": {"error": "Order is not cancelable"}}`))
+			return
+		}
+		w.Write([]byte(`{"order": {"id": ` + id + `, "status": "ok"}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.RetryLimit = 0
+	client := NewClient(params)
+
+	results, err := client.CancelAllOrders(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, results, 4, "filled orders are not canceled") {
+		for i, id := range []int{1, 2, 4, 5} {
+			assert.Equal(t, id, results[i].Order.Id)
+		}
+		assert.NoError(t, results[0].Err)
+		assert.Error(t, results[3].Err)
+	}
+	assert.ElementsMatch(t, []string{"1", "2", "4", "5"}, canceled)
+
+	t.Run("By symbol", func(t *testing.T) {
+		canceled = nil
+		results, err := client.CancelOrdersBySymbol(context.Background(), "SPY")
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.ElementsMatch(t, []string{"1", "4"}, canceled)
+
+		canceled = nil
+		results, err = client.CancelOrdersBySymbol(context.Background(), "SPY210219C00370000")
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, []string{"4"}, canceled)
+	})
+
+	t.Run("Context done", func(t *testing.T) {
+		canceled = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := client.CancelAllOrders(ctx)
+		assert.NoError(t, err)
+		for _, result := range results {
+			assert.Equal(t, context.Canceled, result.Err)
+		}
+		assert.Empty(t, canceled)
+	})
+}
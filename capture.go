@@ -0,0 +1,220 @@
+package tradier
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Key prefixes of stream captures in a Store. Raw ticks are stored under
+// CaptureTicksPrefix+symbol and one minute bars under CaptureBarsPrefix+symbol.
+const (
+	CaptureTicksPrefix = "capture/ticks/"
+	CaptureBarsPrefix  = "capture/bars/"
+)
+
+// PrunableStore is a Store from which records can be deleted.
+type PrunableStore interface {
+	Store
+	// Delete removes the records stored under key with start <= Time < end.
+	// A zero end is unbounded.
+	Delete(key string, start, end time.Time) error
+}
+
+func (ms *MemoryStore) Delete(key string, start, end time.Time) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	var kept []Record
+	for _, rec := range ms.records[key] {
+		if !rec.Time.Before(start) && (end.IsZero() || rec.Time.Before(end)) {
+			continue
+		}
+		kept = append(kept, rec)
+	}
+	if len(kept) == 0 {
+		delete(ms.records, key)
+	} else {
+		ms.records[key] = kept
+	}
+	return nil
+}
+
+// StreamRecorder captures the raw events of a market stream to a Store,
+// timestamped by the time of the event where it has one.
+type StreamRecorder struct {
+	Store Store
+	// If set, Errors is called with each error storing an event.
+	Errors func(err error)
+}
+
+func NewStreamRecorder(store Store) *StreamRecorder {
+	return &StreamRecorder{Store: store}
+}
+
+// Handle records the event, if it is a market event.
+func (sr *StreamRecorder) Handle(event *StreamEvent) {
+	if event.Symbol == "" || event.Error != nil {
+		return
+	}
+	t := time.Now()
+	if me, err := DecodeMarketEvent(event); err == nil {
+		if dated, ok := me.(interface{ Date() time.Time }); ok && dated.Date().Unix() > 0 {
+			t = dated.Date()
+		}
+	}
+
+	rec := Record{Time: t, Data: event.Message}
+	if err := sr.Store.Append(CaptureTicksPrefix+event.Symbol, rec); err != nil && sr.Errors != nil {
+		sr.Errors(err)
+	}
+}
+
+// HandleChan records the events until the channel is closed.
+func (sr *StreamRecorder) HandleChan(events <-chan *StreamEvent) {
+	for event := range events {
+		sr.Handle(event)
+	}
+}
+
+// RetentionPolicy limits how long stream captures are kept.
+type RetentionPolicy struct {
+	// Raw ticks older than this many days are downsampled into one minute
+	// bars. If zero then ticks are kept.
+	TickDays int
+	// One minute bars older than this many months are deleted.
+	// If zero then bars are kept.
+	BarMonths int
+}
+
+// CompactionStats summarizes a compaction of stream captures.
+type CompactionStats struct {
+	TicksCompacted int
+	BarsWritten    int
+	BarsDeleted    int
+}
+
+// CaptureCompactor applies a retention policy to the stream captures in a store.
+type CaptureCompactor struct {
+	Store    PrunableStore
+	Policy   RetentionPolicy
+	Interval time.Duration
+	// If set, Errors is called with each error of a compaction by Run.
+	Errors func(err error)
+}
+
+func NewCaptureCompactor(store PrunableStore, policy RetentionPolicy) *CaptureCompactor {
+	return &CaptureCompactor{
+		Store:    store,
+		Policy:   policy,
+		Interval: time.Hour,
+	}
+}
+
+// Compact downsamples the ticks and deletes the bars that are older than
+// the policy allows at now.
+func (cc *CaptureCompactor) Compact(now time.Time) (CompactionStats, error) {
+	var stats CompactionStats
+	if cc.Policy.TickDays > 0 {
+		// Compact whole minutes, so that bars are never split between compactions.
+		cutoff := now.AddDate(0, 0, -cc.Policy.TickDays).Truncate(time.Minute)
+		keys, err := cc.Store.Keys(CaptureTicksPrefix)
+		if err != nil {
+			return stats, err
+		}
+		for _, key := range keys {
+			symbol := strings.TrimPrefix(key, CaptureTicksPrefix)
+			ticks, bars, err := cc.compactTicks(symbol, cutoff)
+			stats.TicksCompacted += ticks
+			stats.BarsWritten += bars
+			if err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	if cc.Policy.BarMonths > 0 {
+		cutoff := now.AddDate(0, -cc.Policy.BarMonths, 0)
+		keys, err := cc.Store.Keys(CaptureBarsPrefix)
+		if err != nil {
+			return stats, err
+		}
+		for _, key := range keys {
+			expired, err := cc.Store.Range(key, time.Time{}, cutoff)
+			if err != nil {
+				return stats, err
+			}
+			if len(expired) == 0 {
+				continue
+			}
+			if err := cc.Store.Delete(key, time.Time{}, cutoff); err != nil {
+				return stats, err
+			}
+			stats.BarsDeleted += len(expired)
+		}
+	}
+	return stats, nil
+}
+
+// Replace the ticks of symbol before cutoff with one minute bars of its trades
+// and time sales. Quotes and summaries are dropped.
+func (cc *CaptureCompactor) compactTicks(symbol string, cutoff time.Time) (int, int, error) {
+	key := CaptureTicksPrefix + symbol
+	ticks, err := cc.Store.Range(key, time.Time{}, cutoff)
+	if err != nil || len(ticks) == 0 {
+		return 0, 0, err
+	}
+
+	var bars []TimeSale
+	bb := NewBarBuilder(time.Minute, nil, func(_ string, bar TimeSale) { bars = append(bars, bar) })
+	for _, tick := range ticks {
+		event := &StreamEvent{}
+		if err := UnmarshalStreamEvent(tick.Data, event); err != nil {
+			continue
+		}
+		me, err := DecodeMarketEvent(event)
+		if err != nil {
+			continue
+		}
+		switch e := me.(type) {
+		case *TimeSaleEvent:
+			if !e.Cancel {
+				bb.AddTimeSale(e)
+			}
+		case *TradeEvent:
+			bb.AddTimeSale(&TimeSaleEvent{Symbol: e.Symbol, Last: e.Price, Size: e.Size, DateMs: e.DateMs})
+		}
+	}
+	bb.Flush()
+
+	for _, bar := range bars {
+		data, err := json.Marshal(bar)
+		if err != nil {
+			return 0, 0, err
+		}
+		if err := cc.Store.Append(CaptureBarsPrefix+symbol, Record{Time: bar.Time.Time, Data: data}); err != nil {
+			return 0, 0, err
+		}
+	}
+	if err := cc.Store.Delete(key, time.Time{}, cutoff); err != nil {
+		return 0, len(bars), err
+	}
+	return len(ticks), len(bars), nil
+}
+
+// Run compacts the captures every Interval until stop is closed.
+func (cc *CaptureCompactor) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(cc.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := cc.Compact(time.Now()); err != nil && cc.Errors != nil {
+			cc.Errors(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
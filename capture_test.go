@@ -0,0 +1,61 @@
+package tradier
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func captureTimeSale(symbol string, t time.Time, last float64, size int) *StreamEvent {
+	event := &StreamEvent{}
+	UnmarshalStreamEvent([]byte(fmt.Sprintf(
+		`{"type": "timesale", "symbol": %q, "last": "%v", "size": "%d", "date": "%d"}`,
+		symbol, last, size, t.UnixNano()/int64(time.Millisecond))), event)
+	return event
+}
+
+func TestCaptureCompactor_Compact(t *testing.T) {
+	store := NewMemoryStore()
+	recorder := NewStreamRecorder(store)
+
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -10)
+	recorder.Handle(captureTimeSale("SPY", old, 400, 10))
+	recorder.Handle(captureTimeSale("SPY", old.Add(20*time.Second), 402, 30))
+	recorder.Handle(captureTimeSale("SPY", old.Add(time.Minute), 401, 5))
+	recorder.Handle(captureTimeSale("SPY", now.Add(-time.Hour), 410, 1))
+
+	ticks, _ := store.Range(CaptureTicksPrefix+"SPY", time.Time{}, time.Time{})
+	assert.Len(t, ticks, 4)
+	assert.Equal(t, old, ticks[0].Time.UTC())
+
+	compactor := NewCaptureCompactor(store, RetentionPolicy{TickDays: 7, BarMonths: 1})
+	stats, err := compactor.Compact(now)
+	assert.NoError(t, err)
+	assert.Equal(t, CompactionStats{TicksCompacted: 3, BarsWritten: 2}, stats)
+
+	ticks, _ = store.Range(CaptureTicksPrefix+"SPY", time.Time{}, time.Time{})
+	assert.Len(t, ticks, 1)
+	bars, _ := store.Range(CaptureBarsPrefix+"SPY", time.Time{}, time.Time{})
+	if assert.Len(t, bars, 2) {
+		var bar TimeSale
+		assert.NoError(t, json.Unmarshal(bars[0].Data, &bar))
+		assert.Equal(t, FloatOrNaN(400), bar.Open)
+		assert.Equal(t, FloatOrNaN(402), bar.Close)
+		assert.Equal(t, int64(40), bar.Volume)
+	}
+
+	// Compacting again changes nothing until the bars expire.
+	stats, err = compactor.Compact(now)
+	assert.NoError(t, err)
+	assert.Equal(t, CompactionStats{}, stats)
+
+	stats, err = compactor.Compact(old.AddDate(0, 1, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, CompactionStats{TicksCompacted: 1, BarsWritten: 1, BarsDeleted: 2}, stats)
+	keys, _ := store.Keys(CaptureTicksPrefix)
+	assert.Empty(t, keys)
+}
@@ -0,0 +1,118 @@
+package tradier
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ContractDelta is the change in one option contract between chain snapshots.
+// For added contracts the changes are relative to zero.
+type ContractDelta struct {
+	Symbol       string  `json:"symbol"`
+	Added        bool    `json:"added,omitempty"`
+	Removed      bool    `json:"removed,omitempty"`
+	Bid          float64 `json:"bid,omitempty"`
+	Ask          float64 `json:"ask,omitempty"`
+	Last         float64 `json:"last,omitempty"`
+	Volume       int     `json:"volume,omitempty"`
+	OpenInterest float64 `json:"open_interest,omitempty"`
+	// Change in mid implied volatility, if the chains include greeks.
+	MidIV float64 `json:"mid_iv,omitempty"`
+	// The current quote, or nil if the contract was removed.
+	Quote *Quote `json:"-"`
+}
+
+// DiffChains returns the deltas of contracts that changed between two
+// snapshots of a chain, ordered by symbol.
+func DiffChains(previous, current []*Quote) []ContractDelta {
+	prev := make(map[string]*Quote, len(previous))
+	for _, q := range previous {
+		prev[q.Symbol] = q
+	}
+
+	var deltas []ContractDelta
+	for _, q := range current {
+		p, ok := prev[q.Symbol]
+		delete(prev, q.Symbol)
+		if !ok {
+			p = &Quote{}
+		}
+
+		d := ContractDelta{
+			Symbol:       q.Symbol,
+			Added:        !ok,
+			Bid:          q.Bid - p.Bid,
+			Ask:          q.Ask - p.Ask,
+			Last:         q.Last - p.Last,
+			Volume:       q.Volume - p.Volume,
+			OpenInterest: q.OpenInterest - p.OpenInterest,
+			MidIV:        midIV(q) - midIV(p),
+			Quote:        q,
+		}
+		if d.Added || d.Bid != 0 || d.Ask != 0 || d.Last != 0 || d.Volume != 0 || d.OpenInterest != 0 || d.MidIV != 0 {
+			deltas = append(deltas, d)
+		}
+	}
+	for symbol := range prev {
+		deltas = append(deltas, ContractDelta{Symbol: symbol, Removed: true})
+	}
+
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i].Symbol < deltas[j].Symbol })
+	return deltas
+}
+
+func midIV(q *Quote) float64 {
+	if q.Greeks == nil {
+		return 0
+	}
+	return q.Greeks.MidIV
+}
+
+type chainKey struct {
+	symbol     string
+	expiration string
+}
+
+// ChainCache keeps the latest snapshot of each option chain so that
+// repeated snapshots can be reported as deltas.
+type ChainCache struct {
+	mu      sync.RWMutex
+	chains  map[chainKey][]*Quote
+	updated map[chainKey]time.Time
+}
+
+func NewChainCache() *ChainCache {
+	return &ChainCache{
+		chains:  make(map[chainKey][]*Quote),
+		updated: make(map[chainKey]time.Time),
+	}
+}
+
+// Update replaces the cached chain and returns the deltas from the previous snapshot.
+// The first snapshot of a chain reports every contract as added.
+func (cc *ChainCache) Update(symbol string, expiration time.Time, chain []*Quote) []ContractDelta {
+	key := chainKey{symbol, expiration.Format("2006-01-02")}
+	cc.mu.Lock()
+	previous := cc.chains[key]
+	cc.chains[key] = chain
+	cc.updated[key] = time.Now()
+	cc.mu.Unlock()
+	return DiffChains(previous, chain)
+}
+
+// Snapshot returns the cached chain, or nil if it has not been fetched.
+func (cc *ChainCache) Snapshot(symbol string, expiration time.Time) []*Quote {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.chains[chainKey{symbol, expiration.Format("2006-01-02")}]
+}
+
+// Refresh fetches the chain with greeks and updates the cache.
+func (cc *ChainCache) Refresh(chains ChainProvider, symbol string, expiration time.Time) ([]ContractDelta, error) {
+	chain, err := chains.GetOptionChainWithGreeks(symbol, expiration)
+	if err != nil {
+		return nil, err
+	}
+	return cc.Update(symbol, expiration, chain), nil
+}
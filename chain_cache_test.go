@@ -0,0 +1,47 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainCache(t *testing.T) {
+	expiration := time.Date(2021, time.February, 19, 0, 0, 0, 0, time.UTC)
+	chains := &fakeChains{chain: []*Quote{
+		{Symbol: "SPY210219P00360000", Bid: 3.00, Ask: 3.20, OpenInterest: 1000, Greeks: &Greeks{MidIV: 0.20}},
+		{Symbol: "SPY210219P00355000", Bid: 2.00, Ask: 2.20, OpenInterest: 500, Greeks: &Greeks{MidIV: 0.22}},
+	}}
+	cc := NewChainCache()
+
+	deltas, err := cc.Refresh(chains, "SPY", expiration)
+	assert.NoError(t, err)
+	if assert.Len(t, deltas, 2) {
+		assert.True(t, deltas[0].Added)
+		assert.Equal(t, 2.00, deltas[0].Bid)
+	}
+
+	chains.chain = []*Quote{
+		{Symbol: "SPY210219P00360000", Bid: 3.00, Ask: 3.20, OpenInterest: 1000, Greeks: &Greeks{MidIV: 0.20}},
+		{Symbol: "SPY210219P00350000", Bid: 1.50, Ask: 1.60, OpenInterest: 10, Greeks: &Greeks{MidIV: 0.25}},
+	}
+	deltas, err = cc.Refresh(chains, "SPY", expiration)
+	assert.NoError(t, err)
+	assert.Equal(t, []ContractDelta{
+		{Symbol: "SPY210219P00350000", Added: true, Bid: 1.50, Ask: 1.60, OpenInterest: 10, MidIV: 0.25, Quote: chains.chain[1]},
+		{Symbol: "SPY210219P00355000", Removed: true},
+	}, deltas)
+
+	chains.chain = []*Quote{
+		{Symbol: "SPY210219P00360000", Bid: 3.00, Ask: 3.20, Volume: 5000, OpenInterest: 1000, Greeks: &Greeks{MidIV: 0.35}},
+		{Symbol: "SPY210219P00350000", Bid: 1.50, Ask: 1.60, OpenInterest: 10, Greeks: &Greeks{MidIV: 0.25}},
+	}
+	deltas, err = cc.Refresh(chains, "SPY", expiration)
+	assert.NoError(t, err)
+	if assert.Len(t, deltas, 1) {
+		assert.Equal(t, 5000, deltas[0].Volume)
+		assert.InDelta(t, 0.15, deltas[0].MidIV, 1e-9)
+	}
+	assert.Equal(t, chains.chain, cc.Snapshot("SPY", expiration))
+}
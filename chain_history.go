@@ -0,0 +1,206 @@
+package tradier
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ChainSnapshot is an option chain as of a point in time.
+type ChainSnapshot struct {
+	Time  time.Time
+	Chain []*Quote
+}
+
+// ChainSnapshots is a source of snapshots of option chains, such as the
+// snapshots persisted by a ChainSnapshotter or the live ChainCache.
+type ChainSnapshots interface {
+	// ChainSnapshots returns the snapshots of the chain taken with
+	// start <= Time < end, ordered by time. A zero end is unbounded.
+	ChainSnapshots(symbol string, expiration time.Time, start, end time.Time) ([]ChainSnapshot, error)
+}
+
+// ChainSnapshots returns the cached snapshot of the chain, if it was taken in the range.
+func (cc *ChainCache) ChainSnapshots(symbol string, expiration time.Time, start, end time.Time) ([]ChainSnapshot, error) {
+	key := chainKey{symbol, expiration.Format("2006-01-02")}
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	chain, ok := cc.chains[key]
+	updated := cc.updated[key]
+	if !ok || updated.Before(start) || (!end.IsZero() && !updated.Before(end)) {
+		return nil, nil
+	}
+	return []ChainSnapshot{{Time: updated, Chain: chain}}, nil
+}
+
+func chainSnapshotKey(symbol string, expiration time.Time) string {
+	return "chains/" + symbol + "/" + expiration.Format("2006-01-02")
+}
+
+// StoredChainSnapshots reads the chain snapshots persisted in a Store.
+type StoredChainSnapshots struct {
+	Store Store
+}
+
+func (scs StoredChainSnapshots) ChainSnapshots(symbol string, expiration time.Time, start, end time.Time) ([]ChainSnapshot, error) {
+	records, err := scs.Store.Range(chainSnapshotKey(symbol, expiration), start, end)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]ChainSnapshot, 0, len(records))
+	for _, rec := range records {
+		var chain []*Quote
+		if err := json.Unmarshal(rec.Data, &chain); err != nil {
+			return nil, errors.Wrapf(err, "error decoding chain snapshot of %v at %v", symbol, rec.Time)
+		}
+		snapshots = append(snapshots, ChainSnapshot{Time: rec.Time, Chain: chain})
+	}
+	return snapshots, nil
+}
+
+// ChainSnapshotter periodically snapshots option chains into a ChainCache,
+// and persists each snapshot in a Store.
+type ChainSnapshotter struct {
+	Chains   ChainProvider
+	Cache    *ChainCache
+	Store    Store
+	Interval time.Duration
+	// If set, Deltas is called with the changes of each snapshot.
+	Deltas func(symbol string, expiration time.Time, deltas []ContractDelta)
+	// If set, Errors is called with each error of a snapshot by Run.
+	Errors func(err error)
+
+	mu      sync.Mutex
+	watched map[chainKey]time.Time
+}
+
+func NewChainSnapshotter(chains ChainProvider, store Store) *ChainSnapshotter {
+	return &ChainSnapshotter{
+		Chains:   chains,
+		Cache:    NewChainCache(),
+		Store:    store,
+		Interval: 15 * time.Minute,
+		watched:  make(map[chainKey]time.Time),
+	}
+}
+
+// Watch adds a chain to those snapshotted by Run.
+func (cs *ChainSnapshotter) Watch(symbol string, expiration time.Time) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.watched == nil {
+		cs.watched = make(map[chainKey]time.Time)
+	}
+	cs.watched[chainKey{symbol, expiration.Format("2006-01-02")}] = expiration
+}
+
+// Unwatch removes a chain from those snapshotted by Run.
+func (cs *ChainSnapshotter) Unwatch(symbol string, expiration time.Time) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	delete(cs.watched, chainKey{symbol, expiration.Format("2006-01-02")})
+}
+
+// Snapshot fetches the chain, updates the cache and persists the snapshot.
+func (cs *ChainSnapshotter) Snapshot(symbol string, expiration time.Time) ([]ContractDelta, error) {
+	now := time.Now()
+	deltas, err := cs.Cache.Refresh(cs.Chains, symbol, expiration)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(cs.Cache.Snapshot(symbol, expiration))
+	if err != nil {
+		return deltas, err
+	}
+	if err := cs.Store.Append(chainSnapshotKey(symbol, expiration), Record{Time: now, Data: data}); err != nil {
+		return deltas, err
+	}
+	if cs.Deltas != nil {
+		cs.Deltas(symbol, expiration, deltas)
+	}
+	return deltas, nil
+}
+
+// Run snapshots the watched chains every Interval until stop is closed.
+func (cs *ChainSnapshotter) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(cs.Interval)
+	defer ticker.Stop()
+	for {
+		cs.mu.Lock()
+		watched := make([]chainKey, 0, len(cs.watched))
+		expirations := make(map[chainKey]time.Time, len(cs.watched))
+		for key, expiration := range cs.watched {
+			watched = append(watched, key)
+			expirations[key] = expiration
+		}
+		cs.mu.Unlock()
+		sort.Slice(watched, func(i, j int) bool {
+			if watched[i].symbol != watched[j].symbol {
+				return watched[i].symbol < watched[j].symbol
+			}
+			return watched[i].expiration < watched[j].expiration
+		})
+
+		for _, key := range watched {
+			if _, err := cs.Snapshot(key.symbol, expirations[key]); err != nil && cs.Errors != nil {
+				cs.Errors(err)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// ContractPoint is the state of an option contract in a chain snapshot.
+type ContractPoint struct {
+	Time         time.Time
+	Bid          float64
+	Ask          float64
+	Last         float64
+	Volume       int
+	OpenInterest float64
+	// Mid implied volatility, if the snapshot includes greeks.
+	MidIV float64
+}
+
+// ContractHistory returns the time series of an option contract, identified
+// by its OCC symbol, in the snapshots taken with start <= Time < end.
+// Snapshots in which the contract is not listed are skipped.
+func ContractHistory(snapshots ChainSnapshots, occSymbol string, start, end time.Time) ([]ContractPoint, error) {
+	contract, err := ParseOptionSymbol(occSymbol)
+	if err != nil {
+		return nil, err
+	}
+	chains, err := snapshots.ChainSnapshots(contract.Underlying, contract.Expiration, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []ContractPoint
+	for _, snapshot := range chains {
+		for _, q := range snapshot.Chain {
+			if q.Symbol != occSymbol {
+				continue
+			}
+			points = append(points, ContractPoint{
+				Time:         snapshot.Time,
+				Bid:          q.Bid,
+				Ask:          q.Ask,
+				Last:         q.Last,
+				Volume:       q.Volume,
+				OpenInterest: q.OpenInterest,
+				MidIV:        midIV(q),
+			})
+			break
+		}
+	}
+	return points, nil
+}
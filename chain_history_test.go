@@ -0,0 +1,52 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractHistory(t *testing.T) {
+	expiration := time.Date(2021, time.February, 19, 0, 0, 0, 0, time.UTC)
+	chains := &fakeChains{}
+	store := NewMemoryStore()
+	snapshotter := NewChainSnapshotter(chains, store)
+	var deltas [][]ContractDelta
+	snapshotter.Deltas = func(symbol string, expiration time.Time, d []ContractDelta) { deltas = append(deltas, d) }
+
+	start := time.Now()
+	for i, oi := range []float64{1000, 1200, 900} {
+		chains.chain = []*Quote{
+			{Symbol: "SPY210219P00360000", Bid: 3.00, Volume: 100 * (i + 1), OpenInterest: oi, Greeks: &Greeks{MidIV: 0.20 + float64(i)/100}},
+			{Symbol: "SPY210219P00355000", Bid: 2.00, OpenInterest: 500},
+		}
+		_, err := snapshotter.Snapshot("SPY", expiration)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, deltas, 3)
+
+	points, err := ContractHistory(StoredChainSnapshots{store}, "SPY210219P00360000", start, time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, points, 3) {
+		assert.Equal(t, []float64{1000, 1200, 900},
+			[]float64{points[0].OpenInterest, points[1].OpenInterest, points[2].OpenInterest})
+		assert.Equal(t, 300, points[2].Volume)
+		assert.InDelta(t, 0.22, points[2].MidIV, 1e-9)
+		assert.False(t, points[1].Time.Before(points[0].Time))
+	}
+
+	// The live cache has only the latest snapshot.
+	points, err = ContractHistory(snapshotter.Cache, "SPY210219P00360000", start, time.Time{})
+	assert.NoError(t, err)
+	if assert.Len(t, points, 1) {
+		assert.Equal(t, 900.0, points[0].OpenInterest)
+	}
+
+	points, err = ContractHistory(StoredChainSnapshots{store}, "SPY210219C00360000", start, time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, points)
+
+	_, err = ContractHistory(snapshotter.Cache, "SPY", start, time.Time{})
+	assert.Error(t, err)
+}
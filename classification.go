@@ -0,0 +1,143 @@
+package tradier
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Sector is a Morningstar sector code.
+type Sector int64
+
+const (
+	SectorBasicMaterials        Sector = 101
+	SectorConsumerCyclical      Sector = 102
+	SectorFinancialServices     Sector = 103
+	SectorRealEstate            Sector = 104
+	SectorConsumerDefensive     Sector = 205
+	SectorHealthcare            Sector = 206
+	SectorUtilities             Sector = 207
+	SectorCommunicationServices Sector = 308
+	SectorEnergy                Sector = 309
+	SectorIndustrials           Sector = 310
+	SectorTechnology            Sector = 311
+)
+
+var sectorNames = map[Sector]string{
+	SectorBasicMaterials:        "Basic Materials",
+	SectorConsumerCyclical:      "Consumer Cyclical",
+	SectorFinancialServices:     "Financial Services",
+	SectorRealEstate:            "Real Estate",
+	SectorConsumerDefensive:     "Consumer Defensive",
+	SectorHealthcare:            "Healthcare",
+	SectorUtilities:             "Utilities",
+	SectorCommunicationServices: "Communication Services",
+	SectorEnergy:                "Energy",
+	SectorIndustrials:           "Industrials",
+	SectorTechnology:            "Technology",
+}
+
+func (s Sector) String() string {
+	if name, ok := sectorNames[s]; ok {
+		return name
+	}
+	return "Sector(" + strconv.FormatInt(int64(s), 10) + ")"
+}
+
+// Classification is the sector/industry classification and size of a company.
+type Classification struct {
+	Symbol        string
+	Sector        Sector
+	IndustryGroup int64
+	Industry      int64
+	MarketCap     int64
+}
+
+// NewClassification extracts the classification of a company info result.
+func NewClassification(symbol string, result CompanyInfoResult) Classification {
+	c := Classification{Symbol: symbol}
+	if ac := result.Tables.AssetClassification; ac != nil {
+		if ac.MorningstarSectorCode != nil {
+			c.Sector = Sector(*ac.MorningstarSectorCode)
+		}
+		if ac.MorningstarIndustryGroupCode != nil {
+			c.IndustryGroup = *ac.MorningstarIndustryGroupCode
+		}
+		if ac.MorningstarIndustryCode != nil {
+			c.Industry = *ac.MorningstarIndustryCode
+		}
+	}
+	if p := result.Tables.ShareClassProfile; p != nil && p.MarketCap != nil {
+		c.MarketCap = *p.MarketCap
+	}
+	return c
+}
+
+// CompanyInfoSource provides company fundamentals. It is implemented by Client.
+type CompanyInfoSource interface {
+	GetCompanyInfo(symbols []string) (GetCompanyInfoResponse, error)
+}
+
+// ClassificationCache caches symbol classifications, fetching uncached
+// (or expired) symbols from the company fundamentals endpoint in one request.
+type ClassificationCache struct {
+	Source CompanyInfoSource
+	// How long classifications are cached. If zero they never expire.
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]classificationEntry
+}
+
+type classificationEntry struct {
+	Classification
+	fetched time.Time
+}
+
+func NewClassificationCache(source CompanyInfoSource, ttl time.Duration) *ClassificationCache {
+	return &ClassificationCache{
+		Source:  source,
+		TTL:     ttl,
+		entries: make(map[string]classificationEntry),
+	}
+}
+
+// Lookup returns the classifications of the given symbols. Symbols without
+// company info are omitted from the result.
+func (cc *ClassificationCache) Lookup(symbols []string) (map[string]Classification, error) {
+	now := time.Now()
+	result := make(map[string]Classification, len(symbols))
+	var missing []string
+
+	cc.mu.Lock()
+	for _, s := range symbols {
+		e, ok := cc.entries[s]
+		if ok && (cc.TTL == 0 || now.Sub(e.fetched) < cc.TTL) {
+			result[s] = e.Classification
+		} else {
+			missing = append(missing, s)
+		}
+	}
+	cc.mu.Unlock()
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	resp, err := cc.Source.GetCompanyInfo(missing)
+	if err != nil {
+		return result, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	for _, r := range resp {
+		if len(r.Results) == 0 {
+			continue
+		}
+		c := NewClassification(r.Request, r.Results[0])
+		cc.entries[r.Request] = classificationEntry{Classification: c, fetched: now}
+		result[r.Request] = c
+	}
+	return result, nil
+}
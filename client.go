@@ -1,6 +1,7 @@
 package tradier
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff"
@@ -18,6 +20,10 @@ import (
 const (
 	defaultRetries = 3
 
+	// Header indicating the number of requests allowed per interval.
+	rateLimitAllowed = "X-Ratelimit-Allowed"
+	// Header indicating the number of requests used in the current interval.
+	rateLimitUsed = "X-Ratelimit-Used"
 	// Header indicating the number of requests remaining.
 	rateLimitAvailable = "X-Ratelimit-Available"
 	// Header indicating the time at which our rate limit will renew.
@@ -31,6 +37,12 @@ var (
 	// ErrNoAccountSelected is returned if account-specific methods
 	// are attempted to be used without selecting an account first.
 	ErrNoAccountSelected = errors.New("no account selected")
+	// ErrTradingDisabled is returned by methods that place, change or cancel
+	// orders unless trading was enabled with ClientParams.EnableTrading.
+	ErrTradingDisabled = errors.New("trading is not enabled for this client")
+
+	acceptHeader          = []string{"application/json"}
+	formContentTypeHeader = []string{"application/x-www-form-urlencoded"}
 )
 
 type ClientParams struct {
@@ -40,43 +52,263 @@ type ClientParams struct {
 	Backoff    backoff.BackOff
 	RetryLimit int
 	Account    string
+	// If set, identical GET requests made within this window of each other
+	// share a single response, to avoid burning quota on redundant calls.
+	MemoizeWindow time.Duration
+	// Environment labels the client as live or sandbox. Orders are refused
+	// if the label contradicts the endpoint.
+	Environment TradingEnvironment
+	// If positive, orders above this notional are refused when the
+	// environment is unspecified or the endpoint is not a known Tradier endpoint.
+	MaxUnverifiedNotional float64
+	// Clients are read-only unless trading is explicitly enabled.
+	EnableTrading bool
+	// A warning is logged if the local clock is measured to be off from
+	// Tradier's by more than this. Defaults to 2s.
+	MaxClockSkew time.Duration
+	// If positive, OrderLatencyAlert is invoked when this many consecutive
+	// orders (default 1) take longer than the budget to be acknowledged.
+	// If the alert is nil then a warning is logged.
+	OrderLatencyBudget     time.Duration
+	OrderLatencyAlertAfter int
+	OrderLatencyAlert      func(OrderLatencyAlert)
+	// Response bodies larger than this are refused with ErrResponseTooLarge.
+	// If zero then responses are not limited.
+	MaxResponseSize int64
+	// Memoized responses from endpoints that are known to be large, such as
+	// option chains and history, are decoded as they are read rather than
+	// buffered if they exceed this size. Defaults to 1MiB.
+	StreamingThreshold int64
+	// If positive, streams are closed with ErrStreamStalled when no data
+	// is received for this long. Managed streams then reconnect.
+	StreamStallTimeout time.Duration
+	// If set, PlaceOrder refuses buy orders in cash accounts that cost more
+	// than the settled cash, with ErrInsufficientSettledCash. Checking costs
+	// a balances request (and a quote for market orders) per order.
+	RequireSettledCash bool
+	// Sets how PlaceOrder handles sales in cash accounts of positions bought
+	// with unsettled funds, which would be good faith violations:
+	// GoodFaithIgnore (the default), GoodFaithWarn, which calls
+	// GoodFaithWarning (or logs a warning if it is nil), or GoodFaithBlock,
+	// which refuses the order with ErrGoodFaithViolation. Checking costs
+	// a balances and a history request per sale.
+	GoodFaithPolicy  string
+	GoodFaithWarning func(ErrGoodFaithViolation)
+	// If set, orders that require a higher option level than the account is
+	// approved for are refused with ErrOptionLevel, as by RequiredOptionLevel.
+	// Checking costs a profile request per account, and a positions request
+	// per order that sells calls to open without buying calls.
+	CheckOptionLevel bool
+	// Logger receives the client's warnings and retry messages, and those of
+	// the streams and monitors it creates. If nil then they are discarded.
+	Logger Logger
+	// If set, requests are authorized with the token it supplies instead of
+	// AuthToken, such as an OAuth token that is refreshed before it expires.
+	TokenSource TokenSource
+	// If set, Credentials is consulted for the token of each request, and
+	// takes precedence over TokenSource and AuthToken.
+	Credentials CredentialsProvider
+	// If set, the client uses the sandbox: the live endpoint and environment
+	// are replaced by the sandbox's, and endpoints the sandbox does not
+	// support fail with ErrUnsupportedInSandbox. Clients of SandboxEndpoint
+	// are in the sandbox regardless.
+	Sandbox bool
+	// Once no more than RateLimitReserve requests remain available in the
+	// rate limit window of a category, further requests wait for the window
+	// to renew rather than fail with a quota violation. If negative then
+	// requests are never delayed.
+	RateLimitReserve int
+	// Decides which failed requests are retried, and when. Defaults to
+	// a DefaultRetryPolicy using Backoff.
+	RetryPolicy RetryPolicy
+	// If set, requests and streams are traced with spans started by Tracer.
+	Tracer Tracer
+	// If set, measurements of the client's requests and streams are
+	// recorded, e.g. by a shared Metrics.
+	Metrics MetricsRecorder
+	// Deadlines of requests by category, applied to each attempt in addition
+	// to those of the request's context. Streams have no deadline, so the
+	// Timeout of Client should be left zero if streams are used.
+	Timeouts RequestTimeouts
+	// If positive, the last JournalSize requests are kept in memory for
+	// RecentRequests, e.g. to dump for a postmortem.
+	JournalSize int
+	// Beta endpoints, such as the fundamentals, fail with ErrBetaDisabled
+	// unless they are enabled.
+	EnableBeta bool
+	// Paths of beta endpoints that replace their DefaultPath, e.g. to follow
+	// an endpoint that Tradier has promoted to v1 before this package does:
+	// {BetaRatios: "/v1/markets/fundamentals/ratios"}. Endpoints with paths
+	// outside of /beta do not need EnableBeta.
+	BetaPaths map[BetaEndpoint]string
+	// If set, identifies the service using the client to proxies in front
+	// of Tradier, with the ServiceIdentityHeader of each request, including
+	// those creating stream sessions, and in Usage.
+	ServiceIdentity string
+	// If set, the requests of the client are recorded in Usage by its
+	// ServiceIdentity, e.g. to attribute the use of a shared token.
+	Usage *UsageLedger
+	// Decimal places of the prices of orders. If zero, prices of $1 or more
+	// have two and lower prices four, as Tradier accepts. Orders with prices
+	// that have more places are refused rather than rounded.
+	PriceDecimals int
 }
 
 // DefaultParams returns ClientParams initialized with default values.
 func DefaultParams(authToken string) ClientParams {
 	return ClientParams{
-		Endpoint:   APIEndpoint,
-		AuthToken:  authToken,
-		Client:     &http.Client{},
-		Backoff:    backoff.NewExponentialBackOff(),
-		RetryLimit: defaultRetries,
+		Endpoint:    APIEndpoint,
+		AuthToken:   authToken,
+		Client:      &http.Client{},
+		Backoff:     backoff.NewExponentialBackOff(),
+		RetryLimit:  defaultRetries,
+		Environment: EnvironmentLive,
+
+		MaxResponseSize: defaultMaxResponseSize,
 	}
 }
 
+// SandboxParams returns ClientParams initialized with default values for the sandbox.
+func SandboxParams(authToken string) ClientParams {
+	params := DefaultParams(authToken)
+	params.Endpoint = SandboxEndpoint
+	params.Environment = EnvironmentSandbox
+	params.Sandbox = true
+	return params
+}
+
 // Client provides methods for making requests to the Tradier API.
 type Client struct {
-	client     *http.Client
-	endpoint   string
-	authHeader string
-	backoff    backoff.BackOff
-	retryLimit int
-
-	account string
+	client        *http.Client
+	endpoint      string
+	auth          *atomic.Pointer[authState]
+	retry         RetryPolicy
+	retryLimit    int
+	priceDecimals int
+	memo          *getMemo
+	rateLimits    *rateLimitTracker
+	reserve       int
+	clockSkew     *skewTracker
+	bootstrap     *bootstrapCache
+	debug         *debugDumper
+	logger        Logger
+	tracer        Tracer
+	metrics       MetricsRecorder
+	journal       *requestJournal
+	timeouts      RequestTimeouts
+
+	orderLatency *orderLatencyTracker
+
+	maxResponseSize    int64
+	streamingThreshold int64
+	streamStallTimeout time.Duration
+
+	environment           TradingEnvironment
+	sandbox               bool
+	maxUnverifiedNotional float64
+	tradingEnabled        bool
+	requireSettledCash    bool
+	goodFaithPolicy       string
+	goodFaithWarning      func(ErrGoodFaithViolation)
+	checkOptionLevels     bool
+	optionLevels          *optionLevelCache
+	betaEnabled           bool
+	betaPaths             map[BetaEndpoint]string
+	identity              string
+	identityHeader        []string
+	usage                 *UsageLedger
+
+	account   string
+	ordersURL string
 }
 
 func NewClient(params ClientParams) *Client {
-	return &Client{
-		client:     params.Client,
-		endpoint:   params.Endpoint,
-		authHeader: fmt.Sprintf("Bearer %s", params.AuthToken),
-		backoff:    params.Backoff,
-		retryLimit: params.RetryLimit,
-		account:    params.Account,
+	if params.Logger == nil {
+		params.Logger = discardLogger{}
+	}
+	if params.Tracer == nil {
+		params.Tracer = noopTracer{}
+	}
+	if params.Metrics == nil {
+		params.Metrics = noopMetrics{}
 	}
+	if params.Sandbox {
+		if params.Endpoint == "" || params.Endpoint == APIEndpoint {
+			params.Endpoint = SandboxEndpoint
+		}
+		if params.Environment == EnvironmentLive {
+			params.Environment = EnvironmentSandbox
+		}
+	}
+	if params.RetryPolicy == nil {
+		params.RetryPolicy = NewRetryPolicy(params.Backoff)
+	}
+	if params.Credentials == nil && params.TokenSource != nil {
+		params.Credentials = tokenSourceCredentials{params.TokenSource}
+	}
+	tc := &Client{
+		client:        params.Client,
+		endpoint:      params.Endpoint,
+		auth:          newAuth(params.AuthToken, params.Credentials),
+		retry:         params.RetryPolicy,
+		retryLimit:    params.RetryLimit,
+		priceDecimals: params.PriceDecimals,
+		rateLimits:    newRateLimitTracker(),
+		reserve:       params.RateLimitReserve,
+		clockSkew:     newSkewTracker(params.MaxClockSkew, params.Logger),
+		bootstrap:     &bootstrapCache{},
+		debug:         &debugDumper{},
+		logger:        params.Logger,
+		tracer:        params.Tracer,
+		metrics:       params.Metrics,
+		journal:       newRequestJournal(params.JournalSize),
+		timeouts:      params.Timeouts,
+
+		environment:           params.Environment,
+		sandbox:               params.Sandbox || endpointEnvironment(params.Endpoint) == EnvironmentSandbox,
+		maxUnverifiedNotional: params.MaxUnverifiedNotional,
+		tradingEnabled:        params.EnableTrading,
+		requireSettledCash:    params.RequireSettledCash,
+		goodFaithPolicy:       params.GoodFaithPolicy,
+		goodFaithWarning:      params.GoodFaithWarning,
+		checkOptionLevels:     params.CheckOptionLevel,
+		optionLevels:          &optionLevelCache{},
+		betaEnabled:           params.EnableBeta,
+		betaPaths:             params.BetaPaths,
+		identity:              params.ServiceIdentity,
+		usage:                 params.Usage,
+		orderLatency:          newOrderLatencyTracker(params),
+
+		maxResponseSize:    params.MaxResponseSize,
+		streamingThreshold: params.StreamingThreshold,
+		streamStallTimeout: params.StreamStallTimeout,
+	}
+	if tc.streamingThreshold <= 0 {
+		tc.streamingThreshold = defaultStreamingThreshold
+	}
+	if params.MemoizeWindow > 0 {
+		tc.memo = newGetMemo(params.MemoizeWindow)
+	}
+	if tc.identity != "" {
+		tc.identityHeader = []string{tc.identity}
+	}
+	tc.SelectAccount(params.Account)
+	return tc
 }
 
 func (tc *Client) SelectAccount(account string) {
 	tc.account = account
+	tc.ordersURL = tc.endpoint + "/v1/accounts/" + account + "/orders"
+}
+
+// WithAccount returns a copy of the client bound to account, leaving the
+// account selected in this client unchanged. The copy shares this client's
+// connections, rate limits, caches and settings, so a process trading several
+// accounts concurrently should use a copy per account rather than SelectAccount.
+func (tc *Client) WithAccount(account string) *Client {
+	clone := *tc
+	clone.SelectAccount(account)
+	return &clone
 }
 
 func (tc *Client) GetAccountBalances() (*AccountBalances, error) {
@@ -100,12 +332,12 @@ func (tc *Client) GetAccountPositions() ([]*Position, error) {
 
 	url := tc.endpoint + "/v1/accounts/" + tc.account + "/positions"
 	var result struct {
-		Positions struct {
-			Position []*Position
-		}
+		Positions nullable[struct {
+			Position oneOrMany[*Position]
+		}]
 	}
 	err := tc.getJSON(url, &result)
-	return result.Positions.Position, err
+	return result.Positions.Value.Position, err
 }
 
 func (tc *Client) GetAccountHistory(limit int) ([]*Event, error) {
@@ -118,12 +350,12 @@ func (tc *Client) GetAccountHistory(limit int) ([]*Event, error) {
 		url += fmt.Sprintf("?limit=%d", limit)
 	}
 	var result struct {
-		History struct {
-			Event []*Event
-		}
+		History nullable[struct {
+			Event oneOrMany[*Event]
+		}]
 	}
 	err := tc.getJSON(url, &result)
-	return result.History.Event, err
+	return result.History.Value.Event, err
 }
 
 func (tc *Client) GetAccountCostBasis() ([]*ClosedPosition, error) {
@@ -133,12 +365,12 @@ func (tc *Client) GetAccountCostBasis() ([]*ClosedPosition, error) {
 
 	url := tc.endpoint + "/v1/accounts/" + tc.account + "/gainloss"
 	var result struct {
-		GainLoss struct {
-			ClosedPosition []*ClosedPosition `json:"closed_position"`
-		} `json:"gainloss"`
+		GainLoss nullable[struct {
+			ClosedPosition oneOrMany[*ClosedPosition] `json:"closed_position"`
+		}] `json:"gainloss"`
 	}
 	err := tc.getJSON(url, &result)
-	return result.GainLoss.ClosedPosition, err
+	return result.GainLoss.Value.ClosedPosition, err
 }
 
 func (tc *Client) GetOpenOrders() ([]*Order, error) {
@@ -146,10 +378,10 @@ func (tc *Client) GetOpenOrders() ([]*Order, error) {
 		return nil, ErrNoAccountSelected
 	}
 
-	url := tc.endpoint + "/v1/accounts/" + tc.account + "/orders"
+	url := tc.ordersURL
 	var result openOrdersResponse
 	err := tc.getJSON(url, &result)
-	return []*Order(result.Orders.Order), err
+	return []*Order(result.Orders.Value.Order), err
 }
 
 func (tc *Client) GetOrderStatus(orderId int) (*Order, error) {
@@ -165,50 +397,120 @@ func (tc *Client) GetOrderStatus(orderId int) (*Order, error) {
 	return result.Order, err
 }
 
-func (tc *Client) PlaceOrder(order Order) (int, error) {
+// PlaceOrder places the order, and returns the result that Tradier responds
+// with. The result is returned with the error if Tradier did not accept the
+// order. Invalid orders are rejected by Order.Validate without a request.
+func (tc *Client) PlaceOrder(order Order) (*OrderResult, error) {
+	if err := tc.checkPlaceOrder(order); err != nil {
+		return nil, err
+	}
+	form, err := orderToParams(order, tc.priceDecimals)
+	if err != nil {
+		return nil, err
+	}
+	// The retry policy only retries the order if it cannot have been placed.
+	return tc.submitOrder(context.Background(), form)
+}
+
+// Applies the guards of placing an order.
+func (tc *Client) checkPlaceOrder(order Order) error {
 	if tc.account == "" {
-		return 0, ErrNoAccountSelected
+		return ErrNoAccountSelected
+	} else if !tc.tradingEnabled {
+		return ErrTradingDisabled
 	}
 
-	url := tc.endpoint + "/v1/accounts/" + tc.account + "/orders"
-	form, err := orderToParams(order)
-	if err != nil {
-		return 0, err
+	if err := order.Validate(); err != nil {
+		return err
+	}
+	if err := tc.checkEnvironment(order); err != nil {
+		return err
+	}
+	if err := tc.checkSettledCash(order); err != nil {
+		return err
 	}
+	if err := tc.checkGoodFaith(order); err != nil {
+		return err
+	}
+	return tc.checkOptionLevel(order)
+}
 
-	resp, err := tc.do("POST", url, form, 0)
+// Posts the order form, and decodes Tradier's result.
+func (tc *Client) submitOrder(ctx context.Context, form url.Values) (*OrderResult, error) {
+	start := time.Now()
+	resp, err := tc.doContext(ctx, "POST", tc.ordersURL, form, tc.retryLimit)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return 0, errors.New(resp.Status + ": " + string(body))
+		return nil, newTradierError(resp, body)
+	} else if err != nil {
+		return nil, err
 	}
 
-	var result struct {
+	result, err := decodeOrderResult(body)
+	if err != nil {
+		return result, err
+	}
+	if result.Id != 0 {
+		tc.orderLatency.observe(start)
+	}
+	return result, result.err()
+}
+
+// OrderResult is Tradier's response to placing an order.
+type OrderResult struct {
+	Id        int
+	Status    string
+	PartnerId string
+	// Warning messages returned with the order.
+	Warnings []string
+	// Body of the response, for diagnostics.
+	Body []byte
+	// True if PlaceOrderIdempotent found the order among the account's
+	// orders after an ambiguous failure, rather than in a response. Only
+	// Id and Status are set then.
+	Recovered bool
+}
+
+func (r *OrderResult) err() error {
+	if r.Status != StatusOK {
+		return fmt.Errorf("received order status: %v", r.Status)
+	}
+	return nil
+}
+
+func decodeOrderResult(body []byte) (*OrderResult, error) {
+	var response struct {
 		Order struct {
-			Id     int
-			Status string
+			Id        int
+			Status    string
+			PartnerId string          `json:"partner_id"`
+			Warnings  previewMessages `json:"warnings"`
 		}
 	}
-	dec := json.NewDecoder(resp.Body)
-	err = dec.Decode(&result)
-	if err != nil {
-		return result.Order.Id, err
-	} else if result.Order.Status != StatusOK {
-		err = fmt.Errorf("received order status: %v", result.Order.Status)
-	}
-	return result.Order.Id, err
+	err := json.Unmarshal(body, &response)
+	return &OrderResult{
+		Id:        response.Order.Id,
+		Status:    response.Order.Status,
+		PartnerId: response.Order.PartnerId,
+		Warnings:  response.Order.Warnings,
+		Body:      body,
+	}, err
 }
 
 func (tc *Client) PreviewOrder(order Order) (*OrderPreview, error) {
 	if tc.account == "" {
 		return nil, ErrNoAccountSelected
 	}
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
 
-	url := tc.endpoint + "/v1/accounts/" + tc.account + "/orders"
-	form, err := orderToParams(order)
+	url := tc.ordersURL
+	form, err := orderToParams(order, tc.priceDecimals)
 	if err != nil {
 		return nil, err
 	}
@@ -221,7 +523,7 @@ func (tc *Client) PreviewOrder(order Order) (*OrderPreview, error) {
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, errors.New(resp.Status + ": " + string(body))
+		return nil, newTradierError(resp, body)
 	}
 
 	var result struct {
@@ -233,6 +535,8 @@ func (tc *Client) PreviewOrder(order Order) (*OrderPreview, error) {
 		return result.Order, err
 	} else if result.Order == nil {
 		err = fmt.Errorf("didn't receive order preview")
+	} else if result.Order.Status != StatusOK && len(result.Order.ErrorMessages) > 0 {
+		err = fmt.Errorf("received order status: %v: %v", result.Order.Status, strings.Join(result.Order.ErrorMessages, "; "))
 	} else if result.Order.Status != StatusOK {
 		err = fmt.Errorf("received order status: %v", result.Order.Status)
 	}
@@ -241,70 +545,126 @@ func (tc *Client) PreviewOrder(order Order) (*OrderPreview, error) {
 
 // Convert the given order to URL parameters for a create order request.
 // We also do some sanity checking to prevent placing orders with unset fields.
-func orderToParams(order Order) (url.Values, error) {
-	form := url.Values{}
+// Prices are formatted with priceDecimals places, as by formatPrice.
+func orderToParams(order Order, priceDecimals int) (url.Values, error) {
+	form := newOrderForm(priceDecimals)
 	form.Add("class", order.Class)
 	form.Add("duration", order.Duration)
+	if order.Tag != "" {
+		form.Add("tag", order.Tag)
+	}
 
 	switch order.Class {
 	case Equity, Option:
-		form.Add("symbol", order.Symbol)
+		symbol := order.Symbol
+		if order.Class == Option {
+			optionSymbol := order.OptionSymbol
+			// Accept the contract in Symbol, as for the legs of other orders.
+			if contract, err := ParseOptionSymbol(symbol); optionSymbol == "" && err == nil {
+				optionSymbol, symbol = symbol, contract.Underlying
+			}
+			if optionSymbol == "" {
+				return form.Values, fmt.Errorf("option order for %v without an option symbol", symbol)
+			}
+			form.Add("option_symbol", optionSymbol)
+		}
+		form.Add("symbol", symbol)
 		form.Add("side", order.Side)
-		form.Add("quantity", strconv.FormatFloat(order.Quantity, 'f', 0, 64))
+		form.quantity("quantity", order.Quantity)
 		form.Add("type", order.Type)
 		if order.Type == LimitOrder || order.Type == StopLimitOrder {
-			form.Add("price", strconv.FormatFloat(order.Price, 'f', 2, 64))
+			form.price("price", order.Price)
 		}
 		if order.Type == StopOrder || order.Type == StopLimitOrder {
-			form.Add("stop", strconv.FormatFloat(order.StopPrice, 'f', 2, 64))
+			form.price("stop", order.StopPrice)
 		}
 	case Multileg, Combo:
 		form.Add("symbol", order.Symbol)
 		form.Add("type", order.Type)
-		if order.Type == LimitOrder || order.Type == StopLimitOrder {
-			form.Add("price", strconv.FormatFloat(order.Price, 'f', 2, 64))
+		// Credit and debit orders are limit orders at the net price.
+		if order.Type == LimitOrder || order.Type == StopLimitOrder || order.Type == Credit || order.Type == Debit {
+			form.price("price", order.Price)
 		}
 		if order.Type == StopOrder || order.Type == StopLimitOrder {
-			form.Add("stop", strconv.FormatFloat(order.StopPrice, 'f', 2, 64))
+			form.price("stop", order.StopPrice)
 		}
 
 		for i, leg := range order.Legs {
 			form.Add(fmt.Sprintf("option_symbol[%d]", i), leg.OptionSymbol)
 			form.Add(fmt.Sprintf("side[%d]", i), leg.Side)
-			form.Add(fmt.Sprintf("quantity[%dd]", i), strconv.FormatFloat(leg.Quantity, 'f', 0, 64))
+			form.quantity(fmt.Sprintf("quantity[%d]", i), leg.Quantity)
 		}
 	case OneTriggersOther, OneCancelsOther, OneTriggersOneCancelsOther:
 		for i, leg := range order.Legs {
 			form.Add(fmt.Sprintf("symbol[%d]", i), leg.Symbol)
-			form.Add(fmt.Sprintf("quantity[%d]", i), strconv.FormatFloat(leg.Quantity, 'f', 0, 64))
+			form.quantity(fmt.Sprintf("quantity[%d]", i), leg.Quantity)
 			form.Add(fmt.Sprintf("type[%d]", i), leg.Type)
 			form.Add(fmt.Sprintf("side[%d]", i), leg.Side)
 			if leg.OptionSymbol != "" {
 				form.Add(fmt.Sprintf("option_symbol[%d]", i), leg.OptionSymbol)
 			}
 			if leg.Type == LimitOrder || leg.Type == StopLimitOrder {
-				form.Add(fmt.Sprintf("price[%d]", i), strconv.FormatFloat(leg.Price, 'f', 2, 64))
+				form.price(fmt.Sprintf("price[%d]", i), leg.Price)
 			}
 			if leg.Type == StopOrder || leg.Type == StopLimitOrder {
-				form.Add(fmt.Sprintf("stop[%d]", i), strconv.FormatFloat(leg.StopPrice, 'f', 2, 64))
+				form.price(fmt.Sprintf("stop[%d]", i), leg.StopPrice)
 			}
 		}
 	default:
-		return form, fmt.Errorf("unknown order class: %v", order.Class)
+		return form.Values, fmt.Errorf("unknown order class: %v", order.Class)
 	}
-	return form, nil
+	return form.Values, form.err
 }
 
+// ChangeOrder changes the type, duration and prices of an order to those of
+// order, which must be a complete, valid order. Use ModifyOrder to change only
+// some of them.
 func (tc *Client) ChangeOrder(orderId int, order Order) error {
 	if tc.account == "" {
 		return ErrNoAccountSelected
+	} else if !tc.tradingEnabled {
+		return ErrTradingDisabled
 	}
 
-	url := tc.endpoint + "/v1/accounts/" + tc.account + "/orders/" + strconv.Itoa(orderId)
-	form, err := updateOrderParams(order)
+	form, err := updateOrderParams(order, tc.priceDecimals)
+	if err != nil {
+		return err
+	}
+	return tc.changeOrder(orderId, form)
+}
+
+// ChangeOrderRequest is a partial modification of an order. Only the fields
+// that are set are changed.
+type ChangeOrderRequest struct {
+	// New type of the order, e.g. LimitOrder.
+	Type string
+	// New duration of the order, Day or GTC.
+	Duration string
+	// New limit price, of limit and stop limit orders.
+	Price float64
+	// New stop price, of stop and stop limit orders.
+	StopPrice float64
+}
+
+// ModifyOrder changes only the fields of the order that are set in change,
+// e.g. just its limit price. Tradier rejects changes that leave the order
+// invalid, such as a change to a limit order without a price.
+func (tc *Client) ModifyOrder(orderId int, change ChangeOrderRequest) error {
+	if tc.account == "" {
+		return ErrNoAccountSelected
+	} else if !tc.tradingEnabled {
+		return ErrTradingDisabled
+	}
+
+	form, err := changeOrderParams(change, tc.priceDecimals)
 	if err != nil {
 		return err
 	}
+	return tc.changeOrder(orderId, form)
+}
+
+func (tc *Client) changeOrder(orderId int, form url.Values) error {
+	url := tc.endpoint + "/v1/accounts/" + tc.account + "/orders/" + strconv.Itoa(orderId)
 	resp, err := tc.do("PUT", url, form, tc.retryLimit)
 	if err != nil {
 		return err
@@ -312,7 +672,7 @@ func (tc *Client) ChangeOrder(orderId int, order Order) error {
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		body, _ := ioutil.ReadAll(resp.Body)
-		return errors.New(resp.Status + ": " + string(body))
+		return newTradierError(resp, body)
 	}
 
 	var result struct {
@@ -333,34 +693,68 @@ func (tc *Client) ChangeOrder(orderId int, order Order) error {
 	return nil
 }
 
-func updateOrderParams(order Order) (url.Values, error) {
-	form := url.Values{}
+func updateOrderParams(order Order, priceDecimals int) (url.Values, error) {
+	form := newOrderForm(priceDecimals)
 	if order.Type != MarketOrder && order.Type != LimitOrder && order.Type != StopOrder && order.Type != StopLimitOrder {
-		return form, fmt.Errorf("unknown order type: %v", order.Type)
+		return form.Values, fmt.Errorf("unknown order type: %v", order.Type)
 	}
 	form.Add("type", order.Type)
 	if order.Duration != GTC && order.Duration != Day {
-		return form, fmt.Errorf("unknown order duration: %v", order.Duration)
+		return form.Values, fmt.Errorf("unknown order duration: %v", order.Duration)
 	}
 	form.Add("duration", order.Duration)
 	if order.Type == LimitOrder || order.Type == StopLimitOrder {
 		if order.Price <= 0 {
-			return form, fmt.Errorf("cannot place limit order without limit price")
+			return form.Values, fmt.Errorf("cannot place limit order without limit price")
 		}
-		form.Add("price", strconv.FormatFloat(order.Price, 'f', 2, 64))
+		form.price("price", order.Price)
 	}
 	if order.Type == StopOrder || order.Type == StopLimitOrder {
 		if order.StopPrice <= 0 {
-			return form, fmt.Errorf("cannot place stop order without stop price")
+			return form.Values, fmt.Errorf("cannot place stop order without stop price")
 		}
-		form.Add("stop", strconv.FormatFloat(order.StopPrice, 'f', 2, 64))
+		form.price("stop", order.StopPrice)
+	}
+	return form.Values, form.err
+}
+
+// Convert a partial modification to URL parameters for a change order request.
+func changeOrderParams(change ChangeOrderRequest, priceDecimals int) (url.Values, error) {
+	form := newOrderForm(priceDecimals)
+	switch change.Type {
+	case "":
+	case MarketOrder, LimitOrder, StopOrder, StopLimitOrder:
+		form.Add("type", change.Type)
+	default:
+		return form.Values, fmt.Errorf("unknown order type: %v", change.Type)
+	}
+	switch change.Duration {
+	case "":
+	case GTC, Day:
+		form.Add("duration", change.Duration)
+	default:
+		return form.Values, fmt.Errorf("unknown order duration: %v", change.Duration)
+	}
+	if change.Price < 0 || change.StopPrice < 0 {
+		return form.Values, fmt.Errorf("prices must be positive")
+	}
+	if change.Price > 0 {
+		form.price("price", change.Price)
+	}
+	if change.StopPrice > 0 {
+		form.price("stop", change.StopPrice)
 	}
-	return form, nil
+	if form.err == nil && len(form.Values) == 0 {
+		return form.Values, fmt.Errorf("no change to the order")
+	}
+	return form.Values, form.err
 }
 
 func (tc *Client) CancelOrder(orderId int) error {
 	if tc.account == "" {
 		return ErrNoAccountSelected
+	} else if !tc.tradingEnabled {
+		return ErrTradingDisabled
 	}
 
 	url := tc.endpoint + "/v1/accounts/" + tc.account + "/orders/" + strconv.Itoa(orderId)
@@ -371,7 +765,7 @@ func (tc *Client) CancelOrder(orderId int) error {
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		body, _ := ioutil.ReadAll(resp.Body)
-		return errors.New(resp.Status + ": " + string(body))
+		return newTradierError(resp, body)
 	}
 
 	var result struct {
@@ -415,26 +809,28 @@ func (tc *Client) LookupSecurities(
 	}
 
 	var result struct {
-		Securities struct {
-			// TODO: If there is only one data point, then Tradier returns
-			// a single object (not a list) and this fails to parse it.
-			Security []Security
-		}
+		Securities nullable[struct {
+			Security oneOrMany[Security]
+		}]
 	}
 	err := tc.getJSON(url, &result)
-	return result.Securities.Security, err
+	return result.Securities.Value.Security, err
 }
 
 // Get the securities on the Easy-to-Borrow list.
 func (tc *Client) GetEasyToBorrow() ([]Security, error) {
+	return tc.getEasyToBorrow(context.Background())
+}
+
+func (tc *Client) getEasyToBorrow(ctx context.Context) ([]Security, error) {
 	url := tc.endpoint + "/v1/markets/etb"
 	var result struct {
-		Securities struct {
-			Security []Security
-		}
+		Securities nullable[struct {
+			Security oneOrMany[Security]
+		}]
 	}
-	err := tc.getJSON(url, &result)
-	return result.Securities.Security, err
+	err := tc.getJSONContext(ctx, url, &result)
+	return result.Securities.Value.Security, err
 }
 
 // Get an option's expiration dates.
@@ -442,14 +838,14 @@ func (tc *Client) GetOptionExpirationDates(symbol string) ([]time.Time, error) {
 	params := "?symbol=" + symbol
 	url := tc.endpoint + "/v1/markets/options/expirations" + params
 	var result struct {
-		Expirations struct {
-			Date []DateTime
-		}
+		Expirations nullable[struct {
+			Date oneOrMany[DateTime]
+		}]
 	}
 	err := tc.getJSON(url, &result)
 
-	times := make([]time.Time, len(result.Expirations.Date))
-	for i, dt := range result.Expirations.Date {
+	times := make([]time.Time, len(result.Expirations.Value.Date))
+	for i, dt := range result.Expirations.Value.Date {
 		times[i] = dt.Time
 	}
 
@@ -461,52 +857,110 @@ func (tc *Client) GetOptionStrikes(symbol string, expiration time.Time) ([]float
 	params := "?symbol=" + symbol + "&expiration=" + expiration.Format("2006-01-02")
 	url := tc.endpoint + "/v1/markets/options/strikes" + params
 	var result struct {
-		Strikes struct {
-			Strike []float64
-		}
+		Strikes nullable[struct {
+			Strike oneOrMany[float64]
+		}]
 	}
 	err := tc.getJSON(url, &result)
-	return result.Strikes.Strike, err
+	return result.Strikes.Value.Strike, err
 }
 
 // Get an option chain.
 func (tc *Client) GetOptionChain(symbol string, expiration time.Time) ([]*Quote, error) {
+	return tc.getOptionChain(symbol, expiration, false)
+}
+
+// Get an option chain with greeks and implied volatilities.
+func (tc *Client) GetOptionChainWithGreeks(symbol string, expiration time.Time) ([]*Quote, error) {
+	return tc.getOptionChain(symbol, expiration, true)
+}
+
+func (tc *Client) getOptionChain(symbol string, expiration time.Time, greeks bool) ([]*Quote, error) {
 	params := "?symbol=" + symbol + "&expiration=" + expiration.Format("2006-01-02")
+	if greeks {
+		params += "&greeks=true"
+	}
 	url := tc.endpoint + "/v1/markets/options/chains" + params
 	var result struct {
-		Options struct {
-			Option []*Quote
-		}
+		Options nullable[struct {
+			Option oneOrMany[*Quote]
+		}]
 	}
 	err := tc.getJSON(url, &result)
-	return result.Options.Option, err
+	return result.Options.Value.Option, err
 }
 
 func (tc *Client) GetQuotes(symbols []string) ([]*Quote, error) {
 	url := tc.endpoint + "/v1/markets/quotes?symbols=" + strings.Join(symbols, ",")
+	var result struct {
+		Quotes nullable[struct {
+			Quote quoteList
+		}]
+	}
+	err := tc.getJSON(url, &result)
+	return []*Quote(result.Quotes.Value.Quote), err
+}
+
+// GetOptionQuote returns the quote of a single option contract, identified by
+// its OCC symbol, optionally with greeks. This is much cheaper than fetching
+// the whole chain to price one contract.
+func (tc *Client) GetOptionQuote(occSymbol string, greeks bool) (*OptionQuote, error) {
+	contract, err := ParseOptionSymbol(occSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	url := tc.endpoint + "/v1/markets/quotes?symbols=" + occSymbol
+	if greeks {
+		url += "&greeks=true"
+	}
 	var result struct {
 		Quotes struct {
-			Quote []*Quote
+			Quote quoteList
 		}
 	}
-	err := tc.getJSON(url, &result)
-	return result.Quotes.Quote, err
+	if err := tc.getJSON(url, &result); err != nil {
+		return nil, err
+	}
+	for _, q := range result.Quotes.Quote {
+		if q.Symbol == occSymbol {
+			return &OptionQuote{Quote: q, Contract: contract}, nil
+		}
+	}
+	return nil, fmt.Errorf("no quote for option %v", occSymbol)
+}
+
+// If there is only a single quote, then tradier sends back
+// an object, but if there are multiple quotes, then it sends
+// a list of objects...
+type quoteList []*Quote
+
+func (ql *quoteList) UnmarshalJSON(data []byte) error {
+	quotes, err := decodeOneOrMany[*Quote](data)
+	*ql = quotes
+	return err
 }
 
+// Time sales are requested in the exchange's time zone. If the zone database
+// is not available then fall back to Eastern Standard Time rather than failing.
+var marketTimezone = func() *time.Location {
+	tz, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return tz
+}()
+
 func (tc *Client) getTimeSalesUrl(symbol string, interval Interval, start, end time.Time) string {
 	url := tc.endpoint
 	timeFormat := "2006-01-02T15:04:05"
 	tz := time.UTC
-	var err error
-	if interval == IntervalDaily || interval == IntervalWeekly || interval == IntervalMonthly {
+	if interval.IsHistory() {
 		url = url + "/v1/markets/history"
 		timeFormat = "2006-01-02"
 	} else {
 		url = url + "/v1/markets/timesales"
-		tz, err = time.LoadLocation("America/New_York")
-		if err != nil {
-			panic(err)
-		}
+		tz = marketTimezone
 	}
 	url = url + "?symbol=" + symbol
 	if interval != "" {
@@ -527,45 +981,36 @@ func (tc *Client) getTimeSalesUrl(symbol string, interval Interval, start, end t
 type timeSaleList []TimeSale
 
 func (tsl *timeSaleList) UnmarshalJSON(data []byte) error {
-	tss := make([]TimeSale, 0)
-	if err := json.Unmarshal(data, &tss); err == nil {
-		*tsl = tss
-		return nil
-	}
-
-	ts := TimeSale{}
-	err := json.Unmarshal(data, &ts)
-	if err == nil {
-		*tsl = []TimeSale{ts}
-	}
+	tss, err := decodeOneOrMany[TimeSale](data)
+	*tsl = tss
 	return err
 }
 
 func decodeTimeSales(reader io.Reader, interval Interval) ([]TimeSale, error) {
 	dec := json.NewDecoder(reader)
 	var timeSales []TimeSale
-	if interval == IntervalDaily || interval == IntervalWeekly || interval == IntervalMonthly {
+	if interval.IsHistory() {
 		var result struct {
-			History struct {
+			History nullable[struct {
 				Day timeSaleList
-			}
+			}]
 		}
 		err := dec.Decode(&result)
 		if err != nil {
 			return nil, err
 		}
-		timeSales = result.History.Day
+		timeSales = result.History.Value.Day
 	} else {
 		var result struct {
-			Series struct {
+			Series nullable[struct {
 				Data timeSaleList
-			}
+			}]
 		}
 		err := dec.Decode(&result)
 		if err != nil {
 			return nil, err
 		}
-		timeSales = result.Series.Data
+		timeSales = result.Series.Value.Data
 	}
 
 	return timeSales, nil
@@ -583,7 +1028,10 @@ func bisect(start, end time.Time) time.Time {
 
 // Return daily, minute, or tick price bars for the given symbol.
 // Tick data is available for the past 5 days, minute data for the past 20 days,
-// and daily data since 1980-01-01.
+// and daily data since 1980-01-01. Requests outside of these ranges are
+// rejected with an IntervalRangeError before being sent.
+// Ranges with more data than Tradier will return in one response are split
+// in half until each part fits; use GetTimeSalesWithOptions to limit this.
 // NOTE: The results are split, but not dividend-adjusted.
 // https://developer.tradier.com/documentation/markets/get-history
 // https://developer.tradier.com/documentation/markets/get-timesales
@@ -591,11 +1039,53 @@ func (tc *Client) GetTimeSales(
 	symbol string, interval Interval,
 	start, end time.Time) ([]TimeSale, error) {
 
+	timeSales, _, err := tc.GetTimeSalesWithOptions(symbol, interval, start, end, TimeSalesOptions{})
+	return timeSales, err
+}
+
+// TimeSalesOptions controls how GetTimeSalesWithOptions splits ranges that
+// are too big for a single response.
+type TimeSalesOptions struct {
+	// If set, a range that is too big fails with the ErrBodyBufferOverflow
+	// TradierError instead of being split.
+	DisableSplit bool
+	// If positive, splitting fails with ErrTimeSalesRequestLimit rather than
+	// send more than this many requests in total.
+	MaxRequests int
+}
+
+// ErrTimeSalesRequestLimit is returned by GetTimeSalesWithOptions if the range
+// cannot be requested within TimeSalesOptions.MaxRequests.
+var ErrTimeSalesRequestLimit = errors.New("time sales range needs more requests than allowed")
+
+// GetTimeSalesWithOptions returns the bars of the symbol as GetTimeSales
+// does, splitting the range according to opts. It also returns the number of
+// requests that were sent, not counting retries, including if it fails.
+func (tc *Client) GetTimeSalesWithOptions(
+	symbol string, interval Interval,
+	start, end time.Time, opts TimeSalesOptions) ([]TimeSale, int, error) {
+
+	if err := interval.Validate(start, end, time.Now()); err != nil {
+		return nil, 0, err
+	}
+	requests := 0
+	timeSales, err := tc.getTimeSales(symbol, interval, start, end, opts, &requests)
+	return timeSales, requests, err
+}
+
+func (tc *Client) getTimeSales(
+	symbol string, interval Interval,
+	start, end time.Time, opts TimeSalesOptions, requests *int) ([]TimeSale, error) {
+
+	if opts.MaxRequests > 0 && *requests >= opts.MaxRequests {
+		return nil, ErrTimeSalesRequestLimit
+	}
 	url := tc.getTimeSalesUrl(symbol, interval, start, end)
 
+	*requests++
 	resp, err := tc.do("GET", url, nil, tc.retryLimit)
 	if err != nil {
-		if err, ok := err.(TradierError); ok {
+		if err, ok := err.(TradierError); ok && !opts.DisableSplit {
 			if err.Fault.Detail.ErrorCode == ErrBodyBufferOverflow {
 				// Too much data for a single request!
 				// Split the requested time interval in half and recurse.
@@ -605,11 +1095,11 @@ func (tc *Client) GetTimeSales(
 					return nil, err
 				}
 
-				firstHalf, err := tc.GetTimeSales(symbol, interval, start, middle)
+				firstHalf, err := tc.getTimeSales(symbol, interval, start, middle, opts, requests)
 				if err != nil {
 					return nil, err
 				}
-				secondHalf, err := tc.GetTimeSales(symbol, interval, middle, end)
+				secondHalf, err := tc.getTimeSales(symbol, interval, middle, end, opts, requests)
 				if err != nil {
 					return nil, err
 				}
@@ -632,33 +1122,38 @@ func (tc *Client) GetTimeSales(
 // Filter restricts the type of events streamed and can include:
 // summary, trade, quote, timesale. If nil then all events are streamed.
 // https://developer.tradier.com/documentation/streaming/get-markets-events
+//
+// If the streaming session limit has been reached, ErrStreamRateLimited
+// is returned with the time at which a session may be created.
 func (tc *Client) StreamMarketEvents(
 	symbols []string, filter []Filter) (io.ReadCloser, error) {
+	return tc.openMarketStream(context.Background(), symbols, filter, tc.createStreamSession)
+}
+
+// openMarketStream creates a streaming session with createSession and
+// opens the stream. The stream is closed if ctx is canceled.
+func (tc *Client) openMarketStream(ctx context.Context, symbols []string, filter []Filter,
+	createSession func(ctx context.Context, path string) (streamSession, error)) (io.ReadCloser, error) {
 	if len(symbols) == 0 {
 		return nil, errors.New("list of symbols is required")
 	}
 
-	// First create a streaming session.
-	createSessionUrl := tc.endpoint + "/v1/markets/events/session"
-
-	createSessionResp, err := tc.do("POST", createSessionUrl, nil, tc.retryLimit)
+	ctx, span := tc.startStreamSpan(ctx, "market", symbols)
+	stream, err := tc.openTracedMarketStream(ctx, symbols, filter, createSession)
 	if err != nil {
+		span.End(err)
 		return nil, err
 	}
-	defer createSessionResp.Body.Close()
-	if createSessionResp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(createSessionResp.Body)
-		return nil, errors.New(createSessionResp.Status + ": " + string(body))
-	}
+	stream = countingReader{ReadCloser: stream, metrics: tc.metrics, stream: "market"}
+	return &spanReader{ReadCloser: stream, span: span}, nil
+}
 
-	dec := json.NewDecoder(createSessionResp.Body)
-	var sessionResp struct {
-		Stream struct {
-			SessionId string
-			Url       string
-		}
-	}
-	err = dec.Decode(&sessionResp)
+// openTracedMarketStream opens the stream for openMarketStream, within the
+// stream's span.
+func (tc *Client) openTracedMarketStream(ctx context.Context, symbols []string, filter []Filter,
+	createSession func(ctx context.Context, path string) (streamSession, error)) (io.ReadCloser, error) {
+	// First create a streaming session.
+	session, err := createSession(ctx, "/v1/markets/events/session")
 	if err != nil {
 		return nil, err
 	}
@@ -666,7 +1161,7 @@ func (tc *Client) StreamMarketEvents(
 	// Now open the stream.
 	form := url.Values{}
 	form.Add("linebreak", "true")
-	form.Add("sessionid", sessionResp.Stream.SessionId)
+	form.Add("sessionid", session.SessionId)
 	form.Add("symbols", strings.Join(symbols, ","))
 	if len(filter) > 0 {
 		strFilters := make([]string, len(filter))
@@ -680,21 +1175,28 @@ func (tc *Client) StreamMarketEvents(
 	// If we fail here then just make a new session rather than retrying.
 	// This prevents repeated failures to a session that doesn't exist for
 	// some reason.
-	resp, err := tc.do("POST", sessionResp.Stream.Url, form, 0)
+	resp, err := tc.doContext(ctx, "POST", session.Url, form, 0)
 	if err != nil {
 		return nil, err
 	} else if resp == nil {
 		return nil, errors.New("nil response with no error")
 	} else if resp.StatusCode != http.StatusOK {
 		body, _ := ioutil.ReadAll(resp.Body)
-		return nil, errors.New(resp.Status + ": " + string(body))
+		return nil, newTradierError(resp, body)
 	}
 
+	if tc.streamStallTimeout > 0 {
+		return newStallReader(resp.Body, tc.streamStallTimeout), nil
+	}
 	return resp.Body, nil
 }
 
 // Get the market calendar for a given month.
 func (tc *Client) GetMarketCalendar(year int, month time.Month) ([]MarketCalendar, error) {
+	return tc.getMarketCalendar(context.Background(), year, month)
+}
+
+func (tc *Client) getMarketCalendar(ctx context.Context, year int, month time.Month) ([]MarketCalendar, error) {
 	params := fmt.Sprintf("?year=%d&month=%d", year, month)
 	url := tc.endpoint + "/v1/markets/calendar" + params
 	var result struct {
@@ -705,166 +1207,321 @@ func (tc *Client) GetMarketCalendar(year int, month time.Month) ([]MarketCalenda
 		}
 	}
 
-	err := tc.getJSON(url, &result)
+	err := tc.getJSONContext(ctx, url, &result)
 	return result.Calendar.Days.Day, err
 }
 
 // Get the current state of the market (open/closed/etc.)
 func (tc *Client) GetMarketState() (MarketStatus, error) {
+	return tc.getMarketState(context.Background())
+}
+
+func (tc *Client) getMarketState(ctx context.Context) (MarketStatus, error) {
 	url := tc.endpoint + "/v1/markets/clock"
 	var result struct {
 		Clock MarketStatus
 	}
-	err := tc.getJSON(url, &result)
+	err := tc.getJSONContext(ctx, url, &result)
 	return result.Clock, err
 }
 
 // Get corporate calendars.
 func (tc *Client) GetCorporateCalendars(symbols []string) (
 	GetCorporateCalendarsResponse, error) {
-	params := "?symbols=" + strings.Join(symbols, ",")
-	url := tc.endpoint + "/beta/markets/fundamentals/calendars" + params
 	var result GetCorporateCalendarsResponse
-	err := tc.getJSON(url, &result)
+	err := tc.getFundamentals(BetaCorporateCalendars, symbols, &result)
 	return result, err
 }
 
 // Get company fundamentals.
 func (tc *Client) GetCompanyInfo(symbols []string) (GetCompanyInfoResponse, error) {
-	params := "?symbols=" + strings.Join(symbols, ",")
-	url := tc.endpoint + "/beta/markets/fundamentals/company" + params
 	var result GetCompanyInfoResponse
-	err := tc.getJSON(url, &result)
+	err := tc.getFundamentals(BetaCompanyInfo, symbols, &result)
 	return result, err
 }
 
 // Get corporate actions.
 func (tc *Client) GetCorporateActions(symbols []string) (GetCorporateActionsResponse, error) {
-	params := "?symbols=" + strings.Join(symbols, ",")
-	url := tc.endpoint + "/beta/markets/fundamentals/corporate_actions" + params
 	var result GetCorporateActionsResponse
-	err := tc.getJSON(url, &result)
+	err := tc.getFundamentals(BetaCorporateActions, symbols, &result)
 	return result, err
 }
 
 // Get dividends.
 func (tc *Client) GetDividends(symbols []string) (GetDividendsResponse, error) {
-	params := "?symbols=" + strings.Join(symbols, ",")
-	url := tc.endpoint + "/beta/markets/fundamentals/dividends" + params
 	var result GetDividendsResponse
-	err := tc.getJSON(url, &result)
+	err := tc.getFundamentals(BetaDividends, symbols, &result)
 	return result, err
 }
 
 // Get corporate ratios.
 func (tc *Client) GetRatios(symbols []string) (GetRatiosResponse, error) {
-	params := "?symbols=" + strings.Join(symbols, ",")
-	url := tc.endpoint + "/beta/markets/fundamentals/ratios" + params
 	var result GetRatiosResponse
-	err := tc.getJSON(url, &result)
+	err := tc.getFundamentals(BetaRatios, symbols, &result)
 	return result, err
 }
 
 // Get financial reports.
 func (tc *Client) GetFinancials(symbols []string) (GetFinancialsResponse, error) {
-	params := "?symbols=" + strings.Join(symbols, ",")
-	url := tc.endpoint + "/beta/markets/fundamentals/financials" + params
 	var result GetFinancialsResponse
-	err := tc.getJSON(url, &result)
+	err := tc.getFundamentals(BetaFinancials, symbols, &result)
 	return result, err
 }
 
 // Get price statistics.
 func (tc *Client) GetPriceStatistics(symbols []string) (GetPriceStatisticsResponse, error) {
-	params := "?symbols=" + strings.Join(symbols, ",")
-	url := tc.endpoint + "/beta/markets/fundamentals/statistics" + params
 	var result GetPriceStatisticsResponse
-	err := tc.getJSON(url, &result)
+	err := tc.getFundamentals(BetaPriceStatistics, symbols, &result)
 	return result, err
 }
 
+// Fundamentals are a beta API that the sandbox does not support.
+func (tc *Client) getFundamentals(endpoint BetaEndpoint, symbols []string, result interface{}) error {
+	path, err := tc.betaPath(endpoint)
+	if err != nil {
+		return err
+	}
+	url := tc.endpoint + path + "?symbols=" + strings.Join(symbols, ",")
+	return tc.getJSON(url, result)
+}
+
 func (tc *Client) getJSON(url string, result interface{}) error {
-	resp, err := tc.do("GET", url, nil, tc.retryLimit)
+	return tc.getJSONContext(context.Background(), url, result)
+}
+
+func (tc *Client) getJSONContext(ctx context.Context, url string, result interface{}) error {
+	if tc.memo != nil {
+		// Large responses are decoded as they are read rather than memoized.
+		var streamed bool
+		var decodeErr error
+		body, err := tc.memo.get(url, func() ([]byte, error) {
+			return tc.getBody(ctx, url, func(r io.Reader) {
+				streamed = true
+				decodeErr = json.NewDecoder(r).Decode(result)
+			})
+		})
+		if streamed {
+			return decodeErr
+		} else if err == nil {
+			return json.Unmarshal(body, result)
+		} else if err != errResponseStreamed {
+			return err
+		}
+		// A concurrent request for the same url was streamed, so make our own.
+	}
+	return tc.getJSONUnmemoized(ctx, url, result)
+}
+
+// getJSONUnmemoized is getJSONContext without the memo, for requests whose
+// responses must be current.
+func (tc *Client) getJSONUnmemoized(ctx context.Context, url string, result interface{}) error {
+	resp, err := tc.doContext(ctx, "GET", url, nil, tc.retryLimit)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		body, _ := ioutil.ReadAll(resp.Body)
-		return errors.New(resp.Status + ": " + string(body))
+		return newTradierError(resp, body)
 	}
 
 	dec := json.NewDecoder(resp.Body)
 	return dec.Decode(result)
 }
 
+func (tc *Client) getBody(ctx context.Context, url string, overflow func(r io.Reader)) ([]byte, error) {
+	resp, err := tc.doContext(ctx, "GET", url, nil, tc.retryLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, newTradierError(resp, body)
+	}
+	return tc.readBody(resp, overflow)
+}
+
 func (tc *Client) do(method, url string, body url.Values, maxRetries int) (*http.Response, error) {
+	return tc.doContext(context.Background(), method, url, body, maxRetries)
+}
+
+func (tc *Client) doContext(ctx context.Context, method, url string, body url.Values, maxRetries int) (*http.Response, error) {
+	rr := newRequestRecord(method, url, body)
+	ctx, span := tc.tracer.Start(ctx, method+" "+rr.Endpoint,
+		"http.method", method, "tradier.endpoint", rr.Endpoint, "tradier.category", rr.Category,
+		"tradier.account", tc.account, "tradier.symbols", rr.Symbols)
+	start := time.Now()
+	resp, err := tc.doAttempts(ctx, &rr, url, body, maxRetries)
+	tc.metrics.ObserveRequest(method, rr.Endpoint, rr.StatusCode, rr.retries(), time.Since(start))
+	tc.journal.record(&rr, url, body, start, err)
+	span.SetAttributes("tradier.attempts", rr.Attempts, "http.status_code", rr.StatusCode)
+	if rr.RateLimitAvailable >= 0 {
+		span.SetAttributes("tradier.rate_limit.available", rr.RateLimitAvailable)
+	}
+	span.End(err)
+	return resp, err
+}
+
+// requestRecord describes a request made by doContext.
+type requestRecord struct {
+	Method   string
+	Endpoint string
+	Category EndpointCategory
+	Symbols  int
+	// Attempts made, and the status of the last response, if any.
+	Attempts   int
+	StatusCode int
+	// Available requests reported by the last response, or -1.
+	RateLimitAvailable int
+}
+
+func (rr *requestRecord) retries() int {
+	if rr.Attempts > 1 {
+		return rr.Attempts - 1
+	}
+	return 0
+}
+
+func newRequestRecord(method, rawURL string, body url.Values) requestRecord {
+	rr := requestRecord{Method: method, RateLimitAvailable: -1}
+	if u, err := url.Parse(rawURL); err == nil {
+		rr.Endpoint = endpointName(u.Path)
+		rr.Category = endpointCategory(method, u.Path)
+		rr.Symbols = requestSymbols(u, body)
+	}
+	return rr
+}
+
+// doAttempts makes the request, retrying as allowed by the retry policy,
+// and records the attempts in rr.
+func (tc *Client) doAttempts(ctx context.Context, rr *requestRecord, url string, body url.Values, maxRetries int) (*http.Response, error) {
+	method := rr.Method
 	var req *http.Request
 	var resp *http.Response
 	var err error
-	var sleep time.Duration
+	// Placing an order is the only request that changes state if repeated.
+	idempotent := method != http.MethodPost || body.Get("preview") == "true"
+	category := rr.Category
+	timeout := tc.timeouts.timeout(category, rr.Endpoint)
 	for i := 0; i <= maxRetries; i++ {
+		if wait := tc.rateLimits.take(category, tc.reserve, time.Now()); wait > 0 {
+			tc.logger.Info("Waiting for rate limit", "category", category, "wait", wait)
+			tc.metrics.ObserveRateLimitWait(category, wait)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		// Each attempt has its own deadline, which is canceled once the
+		// response has been read.
+		attemptCtx, cancel := withTimeout(ctx, timeout)
 		// Request must be made within retry loop, because body will be re-read each time.
-		req, err = tc.makeSignedRequest(method, url, body)
+		req, err = tc.makeSignedRequest(attemptCtx, method, url, body)
 		if err != nil {
+			cancel()
 			return nil, err
 		}
 
+		tc.debug.dumpRequest(req)
+		rr.Attempts++
 		resp, err = tc.client.Do(req)
+		if tc.usage != nil {
+			if err == nil {
+				tc.usage.record(tc.identity, category, resp)
+			} else {
+				tc.usage.record(tc.identity, category, nil)
+			}
+		}
+		if err == nil {
+			decompressResponse(resp)
+			rr.StatusCode = resp.StatusCode
+			if available, err := strconv.Atoi(resp.Header.Get(rateLimitAvailable)); err == nil {
+				rr.RateLimitAvailable = available
+			}
+			if err := tc.limitResponse(resp); err != nil {
+				cancel()
+				return nil, err
+			}
+			tc.debug.dumpResponse(resp)
+			tc.rateLimits.update(category, resp.Header)
+			tc.clockSkew.observeHeader(resp.Header, time.Now())
+		}
 		if err == nil && resp.StatusCode == http.StatusOK {
+			tc.retry.Reset(category)
+			if timeout > 0 {
+				resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			}
 			break // Successful request
 		}
 
+		attempt := RetryAttempt{Method: method, Category: category, Idempotent: idempotent, Attempt: i + 1}
 		if err != nil {
-			Logger.Println(err)
-			sleep = tc.backoff.NextBackOff()
-		} else if resp.StatusCode != http.StatusOK {
+			cancel()
+			tc.logger.Warn("Request failed", "method", method, "path", req.URL.Path, "error", err)
+			attempt.Err = err
+		} else {
 			var respBody []byte
 			respBody, err = ioutil.ReadAll(resp.Body)
 			resp.Body.Close()
-			tradierErr := TradierError{
-				HttpStatusCode: resp.StatusCode,
-			}
-			if jsonErr := json.Unmarshal(respBody, &tradierErr); jsonErr == nil {
+			cancel()
+			tradierErr, parsed := parseTradierError(resp, respBody)
+			if parsed {
 				// We extracted an error message, don't retry.
 				return resp, tradierErr
-			} else {
-				tradierErr.Fault.FaultString = string(respBody)
 			}
 			// Assign an error since we have read the body. If this is the last retry,
 			// we need to return a non-nil error.
 			err = tradierErr
-			rateLimitExpiry := parseQuotaViolationExpiration(tradierErr.Fault.FaultString)
-			if rateLimitExpiry.After(time.Now().Add(sleep)) {
-				sleep = rateLimitExpiry.Sub(time.Now()) + (1 * time.Second)
-			} else {
-				sleep = tc.backoff.NextBackOff()
-			}
+			attempt.StatusCode, attempt.Header, attempt.Err = resp.StatusCode, resp.Header, err
 		}
 
-		if i+1 <= maxRetries && sleep != backoff.Stop {
-			Logger.Printf("Retrying after %v\n", sleep)
-			time.Sleep(sleep)
+		if i+1 > maxRetries {
+			break
+		}
+		sleep, retry := tc.retry.Retry(attempt)
+		if !retry {
+			break
+		}
+		tc.logger.Warn("Retrying request", "method", method, "path", req.URL.Path,
+			"category", category, "attempt", i+1, "status", attempt.StatusCode, "after", sleep)
+		select {
+		case <-time.After(sleep):
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
 	return resp, err
 }
 
-func (tc *Client) makeSignedRequest(method, url string, body url.Values) (*http.Request, error) {
+func (tc *Client) makeSignedRequest(ctx context.Context, method, url string, body url.Values) (*http.Request, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		bodyReader = strings.NewReader(body.Encode())
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", tc.authHeader)
+	authorization, err := tc.authorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Header values are shared between requests to avoid allocating them each time.
+	req.Header["Accept"] = acceptHeader
+	if isCompressedPath(req.URL.Path) {
+		req.Header["Accept-Encoding"] = gzipEncodingHeader
+	}
+	req.Header["Authorization"] = authorization
+	if tc.identityHeader != nil {
+		req.Header[ServiceIdentityHeader] = tc.identityHeader
+	}
 	if method != http.MethodDelete {
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header["Content-Type"] = formContentTypeHeader
 	}
 
 	return req, nil
@@ -0,0 +1,298 @@
+package tradier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetOptionQuote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.URL.Query().Get("symbols")
+		if symbol != "SPY210219P00360000" {
+			w.Write([]byte(`{"quotes": {"unmatched_symbols": {"symbol": "` + symbol + `"}}}`))
+			return
+		}
+		assert.Equal(t, "true", r.URL.Query().Get("greeks"))
+		w.Write([]byte(`{"quotes": {"quote": {"symbol": "SPY210219P00360000", "bid": 3.0, "ask": 3.2,
+			"strike": 360, "option_type": "put", "greeks": {"delta": -0.25, "mid_iv": 0.21}}}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+
+	t.Run("Single contract with greeks", func(t *testing.T) {
+		quote, err := client.GetOptionQuote("SPY210219P00360000", true)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "SPY", quote.Contract.Underlying)
+			assert.Equal(t, 360.0, quote.Strike)
+			assert.InDelta(t, 3.1, quote.Mid(), 1e-9)
+			if assert.NotNil(t, quote.Greeks) {
+				assert.Equal(t, -0.25, quote.Greeks.Delta)
+			}
+		}
+	})
+
+	t.Run("Unmatched symbol", func(t *testing.T) {
+		_, err := client.GetOptionQuote("SPY210219P00350000", false)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid symbol", func(t *testing.T) {
+		_, err := client.GetOptionQuote("SPY", false)
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_EnableTrading(t *testing.T) {
+	params := DefaultParams("token")
+	params.Endpoint = "http://localhost:0"
+	params.Account = "VA000"
+	client := NewClient(params)
+
+	order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day}
+	_, err := client.PlaceOrder(order)
+	assert.Equal(t, ErrTradingDisabled, err)
+	_, err = client.FastPlaceOrder(context.Background(), order)
+	assert.Equal(t, ErrTradingDisabled, err)
+	assert.Equal(t, ErrTradingDisabled, client.ChangeOrder(1, order))
+	assert.Equal(t, ErrTradingDisabled, client.ModifyOrder(1, ChangeOrderRequest{Price: 1}))
+	assert.Equal(t, ErrTradingDisabled, client.CancelOrder(1))
+}
+
+func TestClient_PlaceOrder(t *testing.T) {
+	response := `{"order": {"id": 257459, "status": "ok", "partner_id": "c4998eb7-06e8-4820-a7ab-55d9760065fb",
+		"warnings": ["Order will execute in extended hours"]}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	client := NewClient(params)
+	order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: LimitOrder, Price: 500, Duration: PostMarket}
+
+	result, err := client.PlaceOrder(order)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 257459, result.Id)
+		assert.Equal(t, StatusOK, result.Status)
+		assert.Equal(t, "c4998eb7-06e8-4820-a7ab-55d9760065fb", result.PartnerId)
+		assert.Equal(t, []string{"Order will execute in extended hours"}, result.Warnings)
+		assert.Equal(t, response, string(result.Body))
+	}
+
+	response = `{"order": {"id": 257460, "status": "rejected"}}`
+	result, err = client.PlaceOrder(order)
+	assert.EqualError(t, err, "received order status: rejected")
+	if assert.NotNil(t, result, "the result is returned with the error") {
+		assert.Equal(t, 257460, result.Id)
+	}
+}
+
+func TestClient_ModifyOrder(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		w.Write([]byte(`{"order": {"id": 1, "status": "ok"}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.EnableTrading = true
+	client := NewClient(params)
+
+	assert.NoError(t, client.ModifyOrder(1, ChangeOrderRequest{Price: 401.5}))
+	assert.Equal(t, url.Values{"price": {"401.50"}}, form)
+	assert.NoError(t, client.Orders().ModifyOrder(1, ChangeOrderRequest{Duration: GTC}))
+	assert.Equal(t, url.Values{"duration": {"gtc"}}, form)
+	assert.NoError(t, client.ModifyOrder(1, ChangeOrderRequest{Type: StopLimitOrder, Price: 399, StopPrice: 400}))
+	assert.Equal(t, url.Values{"type": {"stop_limit"}, "price": {"399.00"}, "stop": {"400.00"}}, form)
+
+	for _, test := range []struct {
+		change ChangeOrderRequest
+		err    string
+	}{
+		{ChangeOrderRequest{}, "no change to the order"},
+		{ChangeOrderRequest{Type: "trailing"}, "unknown order type: trailing"},
+		{ChangeOrderRequest{Duration: PreMarket}, "unknown order duration: pre"},
+		{ChangeOrderRequest{Price: -1}, "prices must be positive"},
+		{ChangeOrderRequest{Price: 400.125}, "price: 400.125 has more than 2 decimal places"},
+	} {
+		assert.EqualError(t, client.ModifyOrder(1, test.change), test.err)
+	}
+}
+
+func TestClient_GetTimeSalesWithOptions(t *testing.T) {
+	// Ranges of more than 8 days are too big for a single response.
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, _ := time.Parse("2006-01-02", r.FormValue("start"))
+		end, _ := time.Parse("2006-01-02", r.FormValue("end"))
+		if end.Sub(start) > 8*24*time.Hour {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"fault": {"faultstring": "Body buffer overflow", "detail": {"errorcode": "protocol.http.TooBigBody"}}}`))
+			return
+		}
+		w.Write([]byte(`{"history": {"day": {"date": "` + r.FormValue("start") + `", "close": 100}}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.RetryLimit = 0
+	client := NewClient(params)
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 32)
+
+	bars, n, err := client.GetTimeSalesWithOptions("SPY", IntervalDaily, start, end, TimeSalesOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, bars, 4)
+	assert.Equal(t, 7, n)
+	assert.Equal(t, 7, requests)
+
+	t.Run("Split disabled", func(t *testing.T) {
+		requests = 0
+		_, n, err := client.GetTimeSalesWithOptions("SPY", IntervalDaily, start, end, TimeSalesOptions{DisableSplit: true})
+		if assert.IsType(t, TradierError{}, err) {
+			assert.Equal(t, ErrBodyBufferOverflow, err.(TradierError).Fault.Detail.ErrorCode)
+		}
+		assert.Equal(t, 1, n)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("Max requests", func(t *testing.T) {
+		requests = 0
+		_, n, err := client.GetTimeSalesWithOptions("SPY", IntervalDaily, start, end, TimeSalesOptions{MaxRequests: 5})
+		assert.Equal(t, ErrTimeSalesRequestLimit, err)
+		assert.Equal(t, 5, n)
+		assert.Equal(t, 5, requests)
+	})
+}
+
+func TestClient_TradierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(rateLimitAvailable, "0")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusBadRequest)
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"errors": {"error": "Backoffice rejected override of the order."}}`))
+		} else {
+			w.Write([]byte(`{"fault": {"faultstring": "Invalid symbol", "detail": {"errorcode": "invalid"}}}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	client := NewClient(params)
+
+	_, err := client.PlaceOrder(Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day})
+	if assert.IsType(t, TradierError{}, err) {
+		te := err.(TradierError)
+		assert.Equal(t, http.StatusBadRequest, te.HttpStatusCode)
+		assert.Equal(t, "0", te.Header.Get(rateLimitAvailable))
+		assert.Empty(t, te.Header.Get("Set-Cookie"))
+		assert.Contains(t, te.Error(), "Backoffice rejected")
+	}
+
+	_, err = client.GetQuotes([]string{"XYZ"})
+	if assert.IsType(t, TradierError{}, err) {
+		assert.Equal(t, "invalid", err.(TradierError).Fault.Detail.ErrorCode)
+	}
+}
+
+func TestClient_WithAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/VA111/positions":
+			w.Write([]byte(`{"positions": {"position": [{"symbol": "SPY", "quantity": 1}]}}`))
+		case "/v1/accounts/VA222/positions":
+			w.Write([]byte(`{"positions": {"position": [{"symbol": "QQQ", "quantity": 2}]}}`))
+		case "/v1/accounts/VA222/orders":
+			w.Write([]byte(`{"order": {"id": 2, "status": "ok"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	client := NewClient(params)
+	client.SelectAccount("VA111")
+
+	other := client.WithAccount("VA222")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		positions, err := other.GetAccountPositions()
+		if assert.NoError(t, err) && assert.Len(t, positions, 1) {
+			assert.Equal(t, "QQQ", positions[0].Symbol)
+		}
+		result, err := other.PlaceOrder(Order{Class: Equity, Symbol: "QQQ", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, result.Id)
+		}
+	}()
+
+	positions, err := client.GetAccountPositions()
+	if assert.NoError(t, err) && assert.Len(t, positions, 1) {
+		assert.Equal(t, "SPY", positions[0].Symbol)
+	}
+	<-done
+	// The copy shares the client's trackers.
+	assert.Equal(t, 1, client.Stats().OrderLatency.Count)
+}
+
+func Test_orderToParams(t *testing.T) {
+	t.Run("Equity", func(t *testing.T) {
+		form, err := orderToParams(Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 400, Duration: Day}, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "SPY", form.Get("symbol"))
+		assert.Equal(t, "400.00", form.Get("price"))
+		assert.NotContains(t, form, "option_symbol")
+	})
+
+	t.Run("Option", func(t *testing.T) {
+		order := *closingOrder(&Position{Symbol: "SPY210219P00360000", Quantity: -2})
+		form, err := orderToParams(order, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "option", form.Get("class"))
+		assert.Equal(t, "SPY", form.Get("symbol"))
+		assert.Equal(t, "SPY210219P00360000", form.Get("option_symbol"))
+		assert.Equal(t, BuyToClose, form.Get("side"))
+		assert.Equal(t, "2", form.Get("quantity"))
+	})
+
+	t.Run("Option contract in symbol", func(t *testing.T) {
+		form, err := orderToParams(Order{Class: Option, Symbol: "SPY210219P00360000", Side: SellToOpen, Quantity: 1, Type: MarketOrder, Duration: Day}, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "SPY", form.Get("symbol"))
+		assert.Equal(t, "SPY210219P00360000", form.Get("option_symbol"))
+	})
+
+	t.Run("Option without contract", func(t *testing.T) {
+		_, err := orderToParams(Order{Class: Option, Symbol: "SPY", Side: SellToOpen, Quantity: 1, Type: MarketOrder, Duration: Day}, 0)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package tradier
+
+import (
+	"sync"
+	"time"
+)
+
+// MarketClock reports the state of the market. It is implemented by Client.
+type MarketClock interface {
+	GetMarketState() (MarketStatus, error)
+}
+
+// MarketTransition is a change of the market state seen by a ClockWatcher.
+type MarketTransition struct {
+	From, To MarketStatus
+}
+
+// Opened returns whether the regular session opened.
+func (mt MarketTransition) Opened() bool {
+	return mt.To.State == MarketOpen && mt.From.State != MarketOpen
+}
+
+// Closed returns whether the regular session closed.
+func (mt MarketTransition) Closed() bool {
+	return mt.From.State == MarketOpen && mt.To.State != MarketOpen
+}
+
+func (mt MarketTransition) String() string {
+	return string(mt.From.State) + " -> " + string(mt.To.State)
+}
+
+// ClockWatcher polls the market clock and reports each change of the market
+// state, e.g. the close when the state changes from MarketOpen.
+type ClockWatcher struct {
+	Clock MarketClock
+	// How often Run polls the clock.
+	Interval time.Duration
+	// Called with the previous and the new status when the state changes.
+	// The first poll only records the current status.
+	Changes func(from, to MarketStatus)
+	// If set, each change is also sent on Transitions, after Changes is
+	// called. Polling waits for the send, so the channel should be drained.
+	Transitions chan<- MarketTransition
+	Errors      func(err error)
+
+	mu     sync.Mutex
+	status MarketStatus
+	polled bool
+}
+
+func NewClockWatcher(clock MarketClock, changes func(from, to MarketStatus)) *ClockWatcher {
+	return &ClockWatcher{
+		Clock:    clock,
+		Interval: time.Minute,
+		Changes:  changes,
+	}
+}
+
+// Status returns the status from the last poll, or false if the clock has
+// not been polled.
+func (cw *ClockWatcher) Status() (MarketStatus, bool) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	return cw.status, cw.polled
+}
+
+// Poll polls the clock once, reporting a change of state.
+func (cw *ClockWatcher) Poll() error {
+	status, err := cw.Clock.GetMarketState()
+	if err != nil {
+		return err
+	}
+
+	cw.mu.Lock()
+	from, changed := cw.status, cw.polled && cw.status.State != status.State
+	cw.status, cw.polled = status, true
+	cw.mu.Unlock()
+
+	if !changed {
+		return nil
+	}
+	if cw.Changes != nil {
+		cw.Changes(from, status)
+	}
+	if cw.Transitions != nil {
+		cw.Transitions <- MarketTransition{From: from, To: status}
+	}
+	return nil
+}
+
+// Run polls the clock every Interval until stop is closed.
+func (cw *ClockWatcher) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(cw.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := cw.Poll(); err != nil && cw.Errors != nil {
+			cw.Errors(err)
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package tradier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Default threshold above which measured clock skew is logged.
+const defaultMaxClockSkew = 2 * time.Second
+
+// ClockSkew is a measurement of the local clock against Tradier's servers.
+type ClockSkew struct {
+	// Local time minus server time: positive if the local clock is ahead.
+	Offset time.Duration
+	// Bound on the measurement error.
+	Uncertainty time.Duration
+	// Whether the measurement was made against the market clock endpoint,
+	// or estimated from the Date header of an ordinary response.
+	FromClock  bool
+	MeasuredAt time.Time
+}
+
+// Exceeds returns true if the skew is larger than the threshold,
+// even allowing for the measurement uncertainty.
+func (cs ClockSkew) Exceeds(threshold time.Duration) bool {
+	offset := cs.Offset
+	if offset < 0 {
+		offset = -offset
+	}
+	return offset-cs.Uncertainty > threshold
+}
+
+type skewTracker struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	clock     *ClockSkew
+	header    *ClockSkew
+	logger    Logger
+}
+
+func newSkewTracker(threshold time.Duration, logger Logger) *skewTracker {
+	if threshold == 0 {
+		threshold = defaultMaxClockSkew
+	}
+	return &skewTracker{threshold: threshold, logger: logger}
+}
+
+// Estimate the skew from the Date header of a response received at now.
+func (st *skewTracker) observeHeader(header http.Header, now time.Time) {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	// The header has a resolution of one second, and was set before the
+	// response was received.
+	skew := ClockSkew{
+		Offset:      now.Sub(date) - 500*time.Millisecond,
+		Uncertainty: time.Second,
+		MeasuredAt:  now,
+	}
+	st.mu.Lock()
+	st.header = &skew
+	st.mu.Unlock()
+	st.warn(skew)
+}
+
+func (st *skewTracker) observeClock(skew ClockSkew) {
+	st.mu.Lock()
+	st.clock = &skew
+	st.mu.Unlock()
+	st.warn(skew)
+}
+
+func (st *skewTracker) warn(skew ClockSkew) {
+	if skew.Exceeds(st.threshold) {
+		st.logger.Warn("Local clock is off from Tradier", "offset", skew.Offset,
+			"uncertainty", skew.Uncertainty, "max", st.threshold)
+	}
+}
+
+// latest returns the measurement against the market clock if there is one,
+// since it is more precise, and otherwise the latest header estimate.
+func (st *skewTracker) latest() (ClockSkew, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.clock != nil {
+		return *st.clock, true
+	} else if st.header != nil {
+		return *st.header, true
+	}
+	return ClockSkew{}, false
+}
+
+// MeasureClockSkew compares the local clock with the market clock endpoint's
+// server timestamp. A warning is logged if the skew exceeds ClientParams.MaxClockSkew.
+func (tc *Client) MeasureClockSkew(ctx context.Context) (ClockSkew, error) {
+	sent := time.Now()
+	resp, err := tc.doContext(ctx, "GET", tc.endpoint+"/v1/markets/clock", nil, 0)
+	if err != nil {
+		return ClockSkew{}, err
+	}
+	received := time.Now()
+	defer resp.Body.Close()
+
+	var result struct {
+		Clock MarketStatus
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return ClockSkew{}, err
+	}
+
+	// Assume the timestamp was taken halfway through the round trip. It has a
+	// resolution of one second.
+	rtt := received.Sub(sent)
+	server := time.Unix(result.Clock.Timestamp, 0).Add(500 * time.Millisecond)
+	skew := ClockSkew{
+		Offset:      sent.Add(rtt / 2).Sub(server),
+		Uncertainty: rtt/2 + 500*time.Millisecond,
+		FromClock:   true,
+		MeasuredAt:  received,
+	}
+	tc.clockSkew.observeClock(skew)
+	return skew, nil
+}
+
+// ClockSkew returns the latest measurement of the local clock's skew,
+// from MeasureClockSkew or else estimated from response headers.
+func (tc *Client) ClockSkew() (ClockSkew, bool) {
+	return tc.clockSkew.latest()
+}
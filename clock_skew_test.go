@@ -0,0 +1,61 @@
+package tradier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_MeasureClockSkew(t *testing.T) {
+	// The server's clock runs an hour behind local time.
+	offset := time.Hour
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().Add(-offset)
+		w.Header().Set("Date", now.UTC().Format(http.TimeFormat))
+		fmt.Fprintf(w, `{"clock": {"date": "2021-01-04", "state": "open", "timestamp": %d}}`, now.Unix())
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+
+	t.Run("No measurement", func(t *testing.T) {
+		_, ok := client.ClockSkew()
+		assert.False(t, ok)
+		assert.Nil(t, client.Stats().ClockSkew)
+	})
+
+	t.Run("Date header", func(t *testing.T) {
+		_, err := client.GetMarketState()
+		assert.NoError(t, err)
+		skew, ok := client.ClockSkew()
+		if assert.True(t, ok) {
+			assert.False(t, skew.FromClock)
+			assert.InDelta(t, float64(offset), float64(skew.Offset), float64(skew.Uncertainty))
+			assert.True(t, skew.Exceeds(time.Minute))
+		}
+	})
+
+	t.Run("Market clock", func(t *testing.T) {
+		skew, err := client.MeasureClockSkew(context.Background())
+		if assert.NoError(t, err) {
+			assert.True(t, skew.FromClock)
+			assert.InDelta(t, float64(offset), float64(skew.Offset), float64(skew.Uncertainty))
+		}
+		latest, _ := client.ClockSkew()
+		assert.Equal(t, skew, latest)
+		assert.Equal(t, &skew, client.Stats().ClockSkew)
+	})
+}
+
+func TestClockSkew_Exceeds(t *testing.T) {
+	skew := ClockSkew{Offset: -3 * time.Second, Uncertainty: time.Second}
+	assert.True(t, skew.Exceeds(time.Second))
+	assert.False(t, skew.Exceeds(2*time.Second))
+}
@@ -0,0 +1,92 @@
+package tradier
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMarketClock struct {
+	states []MarketState
+}
+
+func (fc *fakeMarketClock) GetMarketState() (MarketStatus, error) {
+	state := fc.states[0]
+	if len(fc.states) > 1 {
+		fc.states = fc.states[1:]
+	}
+	return MarketStatus{State: state}, nil
+}
+
+func TestClockWatcher_Poll(t *testing.T) {
+	clock := &fakeMarketClock{states: []MarketState{MarketOpen, MarketOpen, MarketPostmarket, MarketClosed}}
+	var changes [][2]MarketState
+	cw := NewClockWatcher(clock, func(from, to MarketStatus) {
+		changes = append(changes, [2]MarketState{from.State, to.State})
+	})
+
+	_, polled := cw.Status()
+	assert.False(t, polled)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, cw.Poll())
+	}
+	assert.Equal(t, [][2]MarketState{{MarketOpen, MarketPostmarket}, {MarketPostmarket, MarketClosed}}, changes,
+		"the first poll is the baseline")
+	status, polled := cw.Status()
+	assert.True(t, polled)
+	assert.Equal(t, MarketClosed, status.State)
+}
+
+func TestClockWatcher_Transitions(t *testing.T) {
+	clock := &fakeMarketClock{states: []MarketState{MarketPremarket, MarketOpen, MarketPostmarket}}
+	transitions := make(chan MarketTransition, 2)
+	cw := NewClockWatcher(clock, nil)
+	cw.Transitions = transitions
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, cw.Poll())
+	}
+	close(transitions)
+
+	var seen []MarketTransition
+	for mt := range transitions {
+		seen = append(seen, mt)
+	}
+	if assert.Len(t, seen, 2) {
+		assert.True(t, seen[0].Opened())
+		assert.False(t, seen[0].Closed())
+		assert.True(t, seen[1].Closed())
+		assert.Equal(t, "open -> postmarket", seen[1].String())
+	}
+}
+
+func TestMarketStatus_NextChange(t *testing.T) {
+	// Monday 2019-05-06 at 11:36:28 EDT.
+	var status MarketStatus
+	err := json.Unmarshal([]byte(`{"date": "2019-05-06", "description": "Market is open from 09:30 to 16:00",
+		"state": "open", "timestamp": 1557156988, "next_change": "16:00", "next_state": "postmarket"}`), &status)
+	assert.NoError(t, err)
+	assert.Equal(t, MarketOpen, status.State)
+	assert.Equal(t, MarketPostmarket, status.NextState)
+	assert.True(t, time.Date(2019, 5, 6, 20, 0, 0, 0, time.UTC).Equal(status.NextChange.Time), status.NextChange)
+
+	now := time.Unix(status.Timestamp, 0)
+	assert.Equal(t, 4*time.Hour+23*time.Minute+32*time.Second, status.timeUntilNextChange(now))
+	assert.Zero(t, status.timeUntilNextChange(now.Add(5*time.Hour)), "passed")
+	assert.Zero(t, MarketStatus{}.TimeUntilNextChange())
+
+	t.Run("After the session", func(t *testing.T) {
+		// After the postmarket the next change is the premarket of the next day.
+		var status MarketStatus
+		err := json.Unmarshal([]byte(`{"date": "2019-05-06", "state": "postmarket",
+			"timestamp": 1557183600, "next_change": "20:00", "next_state": "closed"}`), &status)
+		assert.NoError(t, err)
+		assert.True(t, time.Date(2019, 5, 7, 0, 0, 0, 0, time.UTC).Equal(status.NextChange.Time), status.NextChange)
+
+		err = json.Unmarshal([]byte(`{"date": "2019-05-06", "state": "closed",
+			"timestamp": 1557187300, "next_change": "07:00", "next_state": "premarket"}`), &status)
+		assert.NoError(t, err)
+		assert.True(t, time.Date(2019, 5, 7, 11, 0, 0, 0, time.UTC).Equal(status.NextChange.Time), status.NextChange)
+	})
+}
@@ -0,0 +1,149 @@
+// Package codec provides serialization codecs for forwarding go-tradier
+// events and API types to external systems.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/gnagel/go-tradier"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Codec encodes and decodes values for transport.
+type Codec interface {
+	// Name identifies the codec, e.g. for a content-type header.
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	JSON    Codec = jsonCodec{}
+	Msgpack Codec = msgpackCodec{}
+	Proto   Codec = protoCodec{}
+)
+
+// ByName returns the codec with the given name.
+func ByName(name string) (Codec, bool) {
+	for _, c := range []Codec{JSON, Msgpack, Proto} {
+		if c.Name() == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return "json" }
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// msgpackCodec uses the json struct tags, so that field names
+// are the same as in the JSON encoding.
+type msgpackCodec struct{}
+
+// Times are decoded in UTC, as they are from JSON.
+func init() {
+	msgpack.Register(time.Time{},
+		func(e *msgpack.Encoder, v reflect.Value) error {
+			return e.EncodeTime(v.Interface().(time.Time))
+		},
+		func(d *msgpack.Decoder, v reflect.Value) error {
+			t, err := d.DecodeTime()
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(t.UTC()))
+			return nil
+		})
+	msgpack.Register(tradier.DateTime{},
+		func(e *msgpack.Encoder, v reflect.Value) error {
+			dt := v.Interface().(tradier.DateTime)
+			if dt.IsZero() {
+				return e.EncodeNil()
+			}
+			return e.EncodeTime(dt.Time)
+		},
+		func(d *msgpack.Decoder, v reflect.Value) error {
+			t, err := d.DecodeInterface()
+			if err != nil {
+				return err
+			}
+			if t, ok := t.(time.Time); ok {
+				v.Set(reflect.ValueOf(tradier.DateTime{Time: t.UTC()}))
+			}
+			return nil
+		})
+}
+
+func (msgpackCodec) Name() string { return "msgpack" }
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	err := enc.Encode(v)
+	return buf.Bytes(), err
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// protoCodec encodes proto.Message values natively. All other values are
+// encoded as a google.protobuf.Value (Struct/ListValue) of their JSON form,
+// so they can be decoded by any consumer with the well-known types.
+// Integers larger than 2^53 lose precision in this form.
+type protoCodec struct{}
+
+func (protoCodec) Name() string { return "proto" }
+
+func (protoCodec) Marshal(v interface{}) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Marshal(m)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	value, err := structpb.NewValue(generic)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(value)
+}
+
+func (protoCodec) Unmarshal(data []byte, v interface{}) error {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, m)
+	}
+
+	value := &structpb.Value{}
+	if err := proto.Unmarshal(data, value); err != nil {
+		return err
+	}
+	js, err := value.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(js, v)
+}
@@ -0,0 +1,153 @@
+package codec
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gnagel/go-tradier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	date := tradier.DateTime{Time: time.Date(2021, time.January, 4, 14, 30, 0, 0, time.UTC)}
+	values := []interface{}{
+		&tradier.QuoteEvent{Symbol: "SPY", Bid: 370.1, BidSize: 100, BidExchange: "Q", BidDateMs: 1609770600000,
+			Ask: 370.2, AskSize: 200, AskExchange: "P", AskDateMs: 1609770600001},
+		&tradier.TradeEvent{Symbol: "SPY", Exchange: "Q", Price: 370.15, Last: 370.15, Size: 100,
+			CumulativeVolume: 1000000, DateMs: 1609770600000},
+		&tradier.TimeSaleEvent{Symbol: "SPY", Exchange: "Q", Bid: 370.1, Ask: 370.2, Last: 370.15, Size: 100,
+			DateMs: 1609770600000, Seq: 42, Flag: "I", Cancel: true, Correction: true, Session: "normal"},
+		&tradier.SummaryEvent{Symbol: "SPY", Open: 369, High: 372, Low: 368.5, PreviousClose: 368},
+		&tradier.TimeSale{Date: date, Time: date, Timestamp: 1609770600, Open: 1, High: 2, Low: 0.5,
+			Close: 1.5, Price: 1.5, Vwap: 1.25, Volume: 100},
+		&tradier.Position{CostBasis: 37000, DateAcquired: date, Id: 1, Quantity: 100, Symbol: "SPY"},
+		&tradier.Order{Id: 1, Class: tradier.Multileg, Type: tradier.Credit, Symbol: "SPY", Price: 1.4,
+			Duration: tradier.Day, CreateDate: date, Legs: []tradier.Order{
+				{OptionSymbol: "SPY210219P00365000", Side: tradier.SellToOpen, Quantity: 1},
+				{OptionSymbol: "SPY210219P00360000", Side: tradier.BuyToOpen, Quantity: 1},
+			}},
+		&tradier.Quote{Symbol: "SPY210219P00365000", Bid: 4.4, Ask: 4.6, Strike: 365, OptionType: tradier.Put,
+			ExpirationDate: date, Greeks: &tradier.Greeks{Delta: -0.31, MidIV: 0.22, UpdatedAt: date}},
+		&tradier.AccountBalances{AccountNumber: "VA000", TotalEquity: 100000,
+			Margin: tradier.Margin{OptionBuyingPower: 50000}, Cash: tradier.Cash{CashAvailable: 1000}},
+	}
+
+	for _, c := range []Codec{JSON, Msgpack, Proto} {
+		for _, v := range values {
+			name := c.Name() + "/" + reflect.TypeOf(v).Elem().Name()
+			t.Run(name, func(t *testing.T) {
+				data, err := c.Marshal(v)
+				assert.NoError(t, err)
+
+				output := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+				assert.NoError(t, c.Unmarshal(data, output))
+				assert.Equal(t, v, output)
+			})
+		}
+	}
+}
+
+func TestByName(t *testing.T) {
+	c, ok := ByName("msgpack")
+	assert.True(t, ok)
+	assert.Equal(t, Msgpack, c)
+
+	_, ok = ByName("xml")
+	assert.False(t, ok)
+}
+
+// Exported API and event types, which must survive a round trip through
+// every codec with all of their fields set.
+var apiTypes = []interface{}{
+	tradier.AccountBalances{}, tradier.Margin{}, tradier.Cash{}, tradier.PDT{},
+	tradier.Position{}, tradier.ClosedPosition{}, tradier.TaxLot{},
+	tradier.Event{}, tradier.Trade{}, tradier.Adjustment{}, tradier.OptionEvent{},
+	tradier.Order{}, tradier.OrderPreview{}, tradier.PreviewWarning{}, tradier.AccountEvent{},
+	tradier.Quote{}, tradier.Greeks{}, tradier.TimeSale{}, tradier.Security{},
+	tradier.MarketCalendar{}, tradier.MarketStatus{},
+	tradier.QuoteEvent{}, tradier.TradeEvent{}, tradier.TimeSaleEvent{}, tradier.SummaryEvent{},
+	tradier.UserAccount{}, tradier.UserProfile{},
+	tradier.CorporateEvent{}, tradier.CompanyInfoResult{}, tradier.CompanyProfile{},
+	tradier.CompanyHeadquarter{}, tradier.AssetClassification{}, tradier.HistoricalAssetClassification{},
+	tradier.ShareClass{}, tradier.ShareClassProfile{}, tradier.OwnershipDetail{}, tradier.OwnershipSummary{},
+	tradier.MergerAndAcquisition{}, tradier.StockSplit{}, tradier.CashDividend{},
+	tradier.BalanceSheet{}, tradier.CashFlowStatement{}, tradier.IncomeStatement{},
+	tradier.FinancialStatementsRestate{}, tradier.Segmentation{}, tradier.EarningReport{},
+	tradier.HistoricalReturns{}, tradier.OperationRatio{}, tradier.AlphaBeta{},
+	tradier.EarningsRatiosRestate{}, tradier.ValuationRatios{}, tradier.PriceStatistics{},
+	tradier.TrailingReturns{},
+	tradier.BulkItem{}, tradier.BulkOperation{}, tradier.ContractDelta{}, tradier.Record{},
+}
+
+func TestRoundTrip_allFields(t *testing.T) {
+	for _, c := range []Codec{JSON, Msgpack, Proto} {
+		for _, zero := range apiTypes {
+			typ := reflect.TypeOf(zero)
+			t.Run(c.Name()+"/"+typ.Name(), func(t *testing.T) {
+				v := reflect.New(typ)
+				fill(v.Elem(), 0)
+				data, err := c.Marshal(v.Interface())
+				assert.NoError(t, err)
+
+				output := reflect.New(typ)
+				assert.NoError(t, c.Unmarshal(data, output.Interface()))
+				assert.Equal(t, v.Interface(), output.Interface())
+			})
+		}
+	}
+}
+
+// Set every serialized field of v to a value other than its zero value.
+// Nested slices of a type are left empty beyond the second level.
+func fill(v reflect.Value, depth int) {
+	switch v.Interface().(type) {
+	case tradier.DateTime:
+		v.Set(reflect.ValueOf(tradier.DateTime{Time: time.Date(2021, time.January, 4, 14, 30, 15, 0, time.UTC)}))
+		return
+	case time.Time:
+		v.Set(reflect.ValueOf(time.Date(2021, time.January, 4, 14, 30, 15, 0, time.UTC)))
+		return
+	case json.RawMessage:
+		v.Set(reflect.ValueOf(json.RawMessage(`{"symbol":"SPY"}`)))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(7)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), depth)
+	case reflect.Slice:
+		if depth >= 2 {
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0), depth+1)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		fill(key, depth)
+		value := reflect.New(v.Type().Elem()).Elem()
+		fill(value, depth+1)
+		v.SetMapIndex(key, value)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+				continue
+			}
+			fill(v.Field(i), depth)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+module github.com/gnagel/go-tradier/codec
+
+go 1.23
+
+require (
+	github.com/gnagel/go-tradier v0.0.0
+	github.com/stretchr/testify v1.6.1
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	google.golang.org/protobuf v1.36.12
+)
+
+require (
+	github.com/cenkalti/backoff v2.0.0+incompatible // indirect
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/pkg/errors v0.8.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/net v0.0.0-20201224014010-6772e930b67b // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The codec module is developed alongside the tradier package.
+replace github.com/gnagel/go-tradier => ../
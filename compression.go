@@ -0,0 +1,53 @@
+package tradier
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var gzipEncodingHeader = []string{"gzip"}
+
+// Responses from endpoints with large responses, and from the fundamentals,
+// are requested compressed even if the client's transport disables
+// compression: a full option chain is several megabytes of JSON.
+func isCompressedPath(path string) bool {
+	return isLargeResponsePath(path) || strings.Contains(path, "/markets/fundamentals/")
+}
+
+// decompressResponse transparently decompresses a gzipped response, which the
+// transport leaves compressed if the request set Accept-Encoding itself.
+// The response is then limited and dumped as decompressed.
+func decompressResponse(resp *http.Response) {
+	if resp.Uncompressed || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return
+	}
+	resp.Body = &gzipBody{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// gzipBody decompresses a response body, reading the gzip header on the first
+// read so that empty bodies can be closed without being read.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (gb *gzipBody) Read(p []byte) (int, error) {
+	if gb.zr == nil && gb.err == nil {
+		gb.zr, gb.err = gzip.NewReader(gb.body)
+	}
+	if gb.err != nil {
+		return 0, gb.err
+	}
+	return gb.zr.Read(p)
+}
+
+func (gb *gzipBody) Close() error {
+	return gb.body.Close()
+}
@@ -0,0 +1,55 @@
+package tradier
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_compression(t *testing.T) {
+	chain := `{"options": {"option": [{"symbol": "SPY210219P00360000", "bid": 3.1}, {"symbol": "SPY210219C00360000", "bid": 4.2}]}}`
+	var encodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Accept-Encoding"))
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.Write([]byte(`{"clock": {"state": "open"}}`))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(chain))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	params := DefaultParams("secret-token")
+	params.Endpoint = server.URL
+	// Without compression in the transport, only the requests the client
+	// compresses itself are compressed.
+	params.Client = &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	client := NewClient(params)
+
+	var buf bytes.Buffer
+	client.WithDebugDump(&buf, "/chains")
+	quotes, err := client.GetOptionChain("SPY", time.Date(2021, 2, 19, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	if assert.Len(t, quotes, 2) {
+		assert.Equal(t, 4.2, quotes[1].Bid)
+	}
+	assert.Contains(t, buf.String(), "SPY210219C00360000", "dumps are decompressed")
+
+	_, err = client.GetMarketState()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gzip", ""}, encodings)
+
+	t.Run("Size limit", func(t *testing.T) {
+		params.MaxResponseSize = 32
+		_, err := NewClient(params).GetOptionChain("SPY", time.Date(2021, 2, 19, 0, 0, 0, 0, time.UTC))
+		assert.IsType(t, ErrResponseTooLarge{}, err, "limits apply to the decompressed size")
+	})
+}
@@ -0,0 +1,111 @@
+package tradier
+
+// Condition is a trade (sale) condition code reported in the flag of a time sale.
+type Condition byte
+
+const (
+	ConditionRegular                    Condition = '@'
+	ConditionCashSale                   Condition = 'C'
+	ConditionIntermarketSweep           Condition = 'F'
+	ConditionOddLot                     Condition = 'I'
+	ConditionSoldLast                   Condition = 'L'
+	ConditionNextDay                    Condition = 'N'
+	ConditionPriorReferencePrice        Condition = 'P'
+	ConditionSeller                     Condition = 'R'
+	ConditionFormT                      Condition = 'T'
+	ConditionExtendedHoursOutOfSequence Condition = 'U'
+	ConditionAveragePrice               Condition = 'W'
+	ConditionSoldOutOfSequence          Condition = 'Z'
+	ConditionDerivativelyPriced         Condition = '4'
+	ConditionQualifiedContingentTrade   Condition = '7'
+)
+
+// ParseConditions parses a time sale flag, which contains one character per condition.
+func ParseConditions(flag string) []Condition {
+	conditions := make([]Condition, 0, len(flag))
+	for i := 0; i < len(flag); i++ {
+		if flag[i] != ' ' {
+			conditions = append(conditions, Condition(flag[i]))
+		}
+	}
+	return conditions
+}
+
+// Conditions returns the trade conditions of the time sale.
+func (tse *TimeSaleEvent) Conditions() []Condition {
+	return ParseConditions(tse.Flag)
+}
+
+// HasCondition returns true if the time sale has any of the given conditions.
+func (tse *TimeSaleEvent) HasCondition(conditions ...Condition) bool {
+	for _, c := range tse.Conditions() {
+		for _, want := range conditions {
+			if c == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Exchange is a single letter exchange code, as reported in the exch fields.
+type Exchange string
+
+var exchangeNames = map[Exchange]string{
+	"A": "NYSE American",
+	"B": "Nasdaq BX",
+	"C": "NYSE National",
+	"D": "FINRA ADF",
+	"H": "MIAX Pearl",
+	"I": "International Securities Exchange",
+	"J": "Cboe EDGA",
+	"K": "Cboe EDGX",
+	"L": "Long-Term Stock Exchange",
+	"M": "NYSE Chicago",
+	"N": "NYSE",
+	"P": "NYSE Arca",
+	"Q": "Nasdaq",
+	"U": "Members Exchange",
+	"V": "IEX",
+	"W": "CBOE",
+	"X": "Nasdaq PSX",
+	"Y": "Cboe BYX",
+	"Z": "Cboe BZX",
+}
+
+// Name returns the name of the exchange, or the code if it is unknown.
+func (e Exchange) Name() string {
+	if name, ok := exchangeNames[e]; ok {
+		return name
+	}
+	return string(e)
+}
+
+// IsOffExchange returns true for trades reported to the FINRA facility rather than an exchange.
+func (e Exchange) IsOffExchange() bool {
+	return e == "D"
+}
+
+// PrintFilter returns true if a time sale should be included, e.g. when building bars.
+type PrintFilter func(tse *TimeSaleEvent) bool
+
+// ExcludeConditions excludes canceled prints and prints with any of the given conditions.
+func ExcludeConditions(conditions ...Condition) PrintFilter {
+	return func(tse *TimeSaleEvent) bool {
+		return !tse.Cancel && !tse.HasCondition(conditions...)
+	}
+}
+
+// RegularPrints excludes canceled prints and prints that should not update
+// the high, low or last price of a bar: odd lots, derivatively priced,
+// average price, out of sequence and prior reference price trades.
+var RegularPrints = ExcludeConditions(
+	ConditionOddLot,
+	ConditionDerivativelyPriced,
+	ConditionAveragePrice,
+	ConditionSoldOutOfSequence,
+	ConditionExtendedHoursOutOfSequence,
+	ConditionPriorReferencePrice,
+	ConditionNextDay,
+	ConditionCashSale,
+)
@@ -0,0 +1,141 @@
+package tradier
+
+import (
+	"sync"
+	"time"
+)
+
+// OrderLister lists the orders of the selected account. It is implemented by Client.
+type OrderLister interface {
+	GetOpenOrders() ([]*Order, error)
+}
+
+// StreamGap reports an order whose status in a polled snapshot does not match
+// the last status received from the account stream, indicating a missed event.
+type StreamGap struct {
+	OrderId int
+	Symbol  string
+	// Last status received from the stream, or empty if the order was never streamed.
+	StreamedStatus string
+	SnapshotStatus string
+	DetectedAt     time.Time
+}
+
+// ConsistencyChecker cross-verifies streamed order events against periodic
+// order snapshots so that silently dropped stream events are detected.
+type ConsistencyChecker struct {
+	Orders OrderLister
+	// How often Run polls for a snapshot.
+	Interval time.Duration
+	// Orders updated more recently than this are not checked, since their
+	// events may still be in flight.
+	Grace time.Duration
+	// Called for each detected gap, and with errors from polling.
+	Gaps   func(gap StreamGap)
+	Errors func(err error)
+
+	mu        sync.Mutex
+	observed  map[int]string
+	baselined bool
+}
+
+func NewConsistencyChecker(orders OrderLister, gaps func(gap StreamGap)) *ConsistencyChecker {
+	return &ConsistencyChecker{
+		Orders:   orders,
+		Interval: time.Minute,
+		Grace:    5 * time.Second,
+		Gaps:     gaps,
+		observed: make(map[int]string),
+	}
+}
+
+// Observe records an order status received from the stream.
+func (cc *ConsistencyChecker) Observe(orderId int, status string) {
+	cc.mu.Lock()
+	cc.observed[orderId] = status
+	cc.mu.Unlock()
+}
+
+// ObserveEvent records the order status of an event from StreamAccountEvents.
+func (cc *ConsistencyChecker) ObserveEvent(event *AccountEvent) {
+	cc.Observe(event.Id, event.Status)
+}
+
+// Check polls a snapshot of orders and returns the gaps found. Orders that exist
+// in the first snapshot are taken as the baseline rather than reported.
+// Reported gaps are reconciled so that they are only reported once.
+func (cc *ConsistencyChecker) Check() ([]StreamGap, error) {
+	orders, err := cc.Orders.GetOpenOrders()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	var gaps []StreamGap
+	for _, o := range orders {
+		streamed, seen := cc.observed[o.Id]
+		if streamed == o.Status {
+			continue
+		}
+		if !cc.baselined && !seen {
+			cc.observed[o.Id] = o.Status
+			continue
+		}
+		if now.Sub(orderUpdateTime(o)) < cc.Grace {
+			continue
+		}
+
+		gaps = append(gaps, StreamGap{
+			OrderId:        o.Id,
+			Symbol:         o.Symbol,
+			StreamedStatus: streamed,
+			SnapshotStatus: o.Status,
+			DetectedAt:     now,
+		})
+		cc.observed[o.Id] = o.Status
+	}
+	cc.baselined = true
+
+	return gaps, nil
+}
+
+// Run checks for gaps every Interval until stop is closed.
+func (cc *ConsistencyChecker) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(cc.Interval)
+	defer ticker.Stop()
+
+	for {
+		cc.runOnce()
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (cc *ConsistencyChecker) runOnce() {
+	gaps, err := cc.Check()
+	if err != nil {
+		if cc.Errors != nil {
+			cc.Errors(err)
+		}
+		return
+	}
+
+	if cc.Gaps != nil {
+		for _, gap := range gaps {
+			cc.Gaps(gap)
+		}
+	}
+}
+
+func orderUpdateTime(o *Order) time.Time {
+	if o.TransactionDate.After(o.CreateDate.Time) {
+		return o.TransactionDate.Time
+	}
+	return o.CreateDate.Time
+}
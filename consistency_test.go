@@ -0,0 +1,45 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeOrderLister struct {
+	orders []*Order
+}
+
+func (fl *fakeOrderLister) GetOpenOrders() ([]*Order, error) {
+	return fl.orders, nil
+}
+
+func TestConsistencyChecker_Check(t *testing.T) {
+	orders := &fakeOrderLister{orders: []*Order{
+		{Id: 1, Symbol: "SPY", Status: Open},
+	}}
+	cc := NewConsistencyChecker(orders, nil)
+	cc.Grace = 0
+
+	gaps, err := cc.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, gaps, "first snapshot is the baseline")
+
+	// The fill of order 1 was streamed, but order 2 was never seen.
+	cc.Observe(1, Filled)
+	orders.orders = []*Order{
+		{Id: 1, Symbol: "SPY", Status: Filled},
+		{Id: 2, Symbol: "AAPL", Status: Canceled},
+	}
+	gaps, err = cc.Check()
+	assert.NoError(t, err)
+	if assert.Len(t, gaps, 1) {
+		assert.Equal(t, 2, gaps[0].OrderId)
+		assert.Equal(t, "", gaps[0].StreamedStatus)
+		assert.Equal(t, Canceled, gaps[0].SnapshotStatus)
+	}
+
+	gaps, err = cc.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, gaps, "gaps are only reported once")
+}
@@ -0,0 +1,361 @@
+package tradier
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// SlowConsumerPolicy determines what happens when a consumer's queue is full.
+type SlowConsumerPolicy int
+
+const (
+	// DropNewest discards the event that does not fit in the queue.
+	DropNewest SlowConsumerPolicy = iota
+	// DropOldest discards the oldest queued event to make room.
+	DropOldest
+	// Block waits for the consumer, delaying all other consumers.
+	Block
+	// Disconnect closes the consumer's queue, with Err set to ErrSlowConsumer.
+	Disconnect
+)
+
+// ErrSlowConsumer is the error of a consumer disconnected by the Disconnect policy.
+var ErrSlowConsumer = errors.New("consumer disconnected for falling behind")
+
+// Consumer is a member of a ConsumerGroup with its own queue of events.
+type Consumer struct {
+	Name string
+	// Events receives the events for the consumer's symbols. It is closed when
+	// the consumer is closed, disconnected or the group's input ends.
+	Events <-chan *StreamEvent
+
+	group   *ConsumerGroup
+	filter  map[string]bool // Guarded by the group. Nil accepts all types.
+	queue   chan *StreamEvent
+	policy  SlowConsumerPolicy
+	dropped int64
+	done    chan struct{}
+
+	// Guards sending to and closing queue.
+	sendMu sync.Mutex
+	closed bool
+	err    error
+	once   sync.Once
+}
+
+// Dropped returns the number of events discarded because the consumer was too slow.
+func (c *Consumer) Dropped() int64 {
+	return atomic.LoadInt64(&c.dropped)
+}
+
+// Err returns the reason the consumer was disconnected, if any.
+func (c *Consumer) Err() error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return c.err
+}
+
+// Subscribe adds symbols to the consumer's subscription.
+func (c *Consumer) Subscribe(symbols ...string) {
+	c.group.subscribe(c, symbols, true)
+}
+
+// Unsubscribe removes symbols from the consumer's subscription. A consumer
+// without any symbols receives no events.
+func (c *Consumer) Unsubscribe(symbols ...string) {
+	c.group.subscribe(c, symbols, false)
+}
+
+// SetFilter restricts the consumer to events of the given types.
+// With no filters the consumer receives all types of events.
+func (c *Consumer) SetFilter(filter ...Filter) {
+	c.group.setFilter(c, filter)
+}
+
+// Close leaves the group and closes the consumer's queue.
+func (c *Consumer) Close() {
+	c.close(nil)
+}
+
+func (c *Consumer) close(err error) {
+	c.once.Do(func() {
+		c.group.leave(c)
+		close(c.done)
+		c.sendMu.Lock()
+		c.closed = true
+		c.err = err
+		close(c.queue)
+		c.sendMu.Unlock()
+	})
+}
+
+// send queues the event according to the policy. It returns false if the
+// consumer should be disconnected.
+func (c *Consumer) send(event *StreamEvent) bool {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	if c.closed {
+		return true
+	}
+
+	select {
+	case c.queue <- event:
+		return true
+	default:
+	}
+
+	switch c.policy {
+	case DropOldest:
+		for {
+			select {
+			case c.queue <- event:
+				return true
+			default:
+			}
+			select {
+			case <-c.queue:
+				atomic.AddInt64(&c.dropped, 1)
+			default:
+			}
+		}
+	case Block:
+		select {
+		case c.queue <- event:
+		case <-c.done:
+		}
+		return true
+	case Disconnect:
+		atomic.AddInt64(&c.dropped, 1)
+		return false
+	default:
+		atomic.AddInt64(&c.dropped, 1)
+		return true
+	}
+}
+
+// ConsumerGroup fans out the events of a single stream to multiple
+// independent consumers, each subscribed to its own set of symbols.
+type ConsumerGroup struct {
+	// If set, called with the union of all consumers' symbols whenever it changes,
+	// so that the underlying stream can be resubscribed.
+	SymbolsChanged func(symbols []string)
+	// If set, called with the union of all consumers' filters whenever it
+	// changes. It is nil if any consumer accepts all types of events.
+	FiltersChanged func(filter []Filter)
+
+	mu        sync.RWMutex
+	consumers map[*Consumer]map[string]bool
+	bySymbol  map[string]map[*Consumer]bool
+	filter    []Filter
+}
+
+func NewConsumerGroup() *ConsumerGroup {
+	return &ConsumerGroup{
+		consumers: make(map[*Consumer]map[string]bool),
+		bySymbol:  make(map[string]map[*Consumer]bool),
+	}
+}
+
+// Join adds a consumer for the given symbols, with a queue of the given size.
+func (g *ConsumerGroup) Join(name string, symbols []string, buffer int, policy SlowConsumerPolicy) *Consumer {
+	queue := make(chan *StreamEvent, buffer)
+	c := &Consumer{
+		Name:   name,
+		Events: queue,
+		group:  g,
+		queue:  queue,
+		policy: policy,
+		done:   make(chan struct{}),
+	}
+
+	g.mu.Lock()
+	g.consumers[c] = make(map[string]bool)
+	g.mu.Unlock()
+	g.subscribe(c, symbols, true)
+	return c
+}
+
+// Filters returns the union of the filters of consumers with symbols,
+// or nil if any of them accepts all types of events.
+func (g *ConsumerGroup) Filters() []Filter {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.filters()
+}
+
+func (g *ConsumerGroup) filters() []Filter {
+	union := make(map[string]bool)
+	for c, symbols := range g.consumers {
+		if len(symbols) == 0 {
+			continue
+		} else if c.filter == nil {
+			return nil
+		}
+		for f := range c.filter {
+			union[f] = true
+		}
+	}
+	if len(union) == 0 {
+		return nil
+	}
+
+	filter := make([]Filter, 0, len(union))
+	for f := range union {
+		filter = append(filter, Filter(f))
+	}
+	sort.Slice(filter, func(i, j int) bool { return filter[i] < filter[j] })
+	return filter
+}
+
+func (g *ConsumerGroup) setFilter(c *Consumer, filter []Filter) {
+	g.mu.Lock()
+	if _, ok := g.consumers[c]; !ok {
+		g.mu.Unlock()
+		return
+	}
+	c.filter = nil
+	if len(filter) > 0 {
+		c.filter = make(map[string]bool, len(filter))
+		for _, f := range filter {
+			c.filter[string(f)] = true
+		}
+	}
+	g.notify(false)
+}
+
+// Symbols returns the union of all consumers' symbols.
+func (g *ConsumerGroup) Symbols() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.symbols()
+}
+
+func (g *ConsumerGroup) symbols() []string {
+	symbols := make([]string, 0, len(g.bySymbol))
+	for s := range g.bySymbol {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func (g *ConsumerGroup) subscribe(c *Consumer, symbols []string, add bool) {
+	g.mu.Lock()
+	subscribed, ok := g.consumers[c]
+	if !ok {
+		g.mu.Unlock()
+		return
+	}
+
+	changed := false
+	for _, s := range symbols {
+		if add && !subscribed[s] {
+			subscribed[s] = true
+			if g.bySymbol[s] == nil {
+				g.bySymbol[s] = make(map[*Consumer]bool)
+				changed = true
+			}
+			g.bySymbol[s][c] = true
+		} else if !add && subscribed[s] {
+			delete(subscribed, s)
+			changed = g.removeSymbol(c, s) || changed
+		}
+	}
+	g.notify(changed)
+}
+
+func (g *ConsumerGroup) leave(c *Consumer) {
+	g.mu.Lock()
+	changed := false
+	for s := range g.consumers[c] {
+		changed = g.removeSymbol(c, s) || changed
+	}
+	delete(g.consumers, c)
+	g.notify(changed)
+}
+
+// Returns true if no consumers remain for the symbol.
+func (g *ConsumerGroup) removeSymbol(c *Consumer, symbol string) bool {
+	delete(g.bySymbol[symbol], c)
+	if len(g.bySymbol[symbol]) == 0 {
+		delete(g.bySymbol, symbol)
+		return true
+	}
+	return false
+}
+
+// notify unlocks the group and reports a change of symbols or filters.
+func (g *ConsumerGroup) notify(changed bool) {
+	var symbols []string
+	if changed && g.SymbolsChanged != nil {
+		symbols = g.symbols()
+	}
+	filter := g.filters()
+	filterChanged := !equalFilters(filter, g.filter)
+	g.filter = filter
+	g.mu.Unlock()
+
+	if symbols != nil {
+		g.SymbolsChanged(symbols)
+	}
+	if filterChanged && g.FiltersChanged != nil {
+		g.FiltersChanged(filter)
+	}
+}
+
+func equalFilters(a, b []Filter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Dispatch delivers the event to each consumer subscribed to its symbol
+// and type. Events without a symbol (e.g. errors) are delivered to all consumers.
+func (g *ConsumerGroup) Dispatch(event *StreamEvent) {
+	g.mu.RLock()
+	var targets []*Consumer
+	if event.Symbol == "" {
+		for c := range g.consumers {
+			targets = append(targets, c)
+		}
+	} else {
+		for c := range g.bySymbol[event.Symbol] {
+			if c.filter == nil || c.filter[event.Type] {
+				targets = append(targets, c)
+			}
+		}
+	}
+	g.mu.RUnlock()
+
+	for _, c := range targets {
+		if !c.send(event) {
+			c.close(ErrSlowConsumer)
+		}
+	}
+}
+
+// Run dispatches events until the channel is closed, and then closes all consumers.
+func (g *ConsumerGroup) Run(events <-chan *StreamEvent) {
+	for event := range events {
+		g.Dispatch(event)
+	}
+
+	g.mu.RLock()
+	consumers := make([]*Consumer, 0, len(g.consumers))
+	for c := range g.consumers {
+		consumers = append(consumers, c)
+	}
+	g.mu.RUnlock()
+	for _, c := range consumers {
+		c.Close()
+	}
+}
@@ -0,0 +1,82 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumerGroup(t *testing.T) {
+	event := func(symbol string) *StreamEvent {
+		return &StreamEvent{Type: "quote", Symbol: symbol}
+	}
+
+	var subscribed []string
+	g := NewConsumerGroup()
+	g.SymbolsChanged = func(symbols []string) { subscribed = symbols }
+
+	a := g.Join("a", []string{"SPY", "QQQ"}, 2, DropNewest)
+	b := g.Join("b", []string{"SPY", "IWM"}, 2, DropOldest)
+	c := g.Join("c", []string{"IWM"}, 1, Disconnect)
+	assert.Equal(t, []string{"IWM", "QQQ", "SPY"}, subscribed)
+
+	t.Run("Overlapping subscriptions", func(t *testing.T) {
+		g.Dispatch(event("SPY"))
+		g.Dispatch(event("IWM"))
+		assert.Equal(t, "SPY", (<-a.Events).Symbol)
+		assert.Equal(t, "SPY", (<-b.Events).Symbol)
+		assert.Equal(t, "IWM", (<-b.Events).Symbol)
+		assert.Equal(t, "IWM", (<-c.Events).Symbol)
+	})
+
+	t.Run("Slow consumer policies", func(t *testing.T) {
+		for _, s := range []string{"SPY", "QQQ", "SPY"} {
+			g.Dispatch(event(s))
+		}
+		assert.Equal(t, int64(1), a.Dropped())
+		assert.Equal(t, "SPY", (<-a.Events).Symbol)
+		assert.Equal(t, "QQQ", (<-a.Events).Symbol)
+
+		g.Dispatch(event("IWM"))
+		g.Dispatch(event("IWM"))
+		// b kept the newest two events.
+		assert.Equal(t, int64(2), b.Dropped())
+		assert.Equal(t, "IWM", (<-b.Events).Symbol)
+		assert.Equal(t, "IWM", (<-b.Events).Symbol)
+
+		// c fell behind and was disconnected.
+		<-c.Events
+		_, ok := <-c.Events
+		assert.False(t, ok)
+		assert.Equal(t, ErrSlowConsumer, c.Err())
+		assert.Equal(t, []string{"IWM", "QQQ", "SPY"}, subscribed, "b is still subscribed to IWM")
+	})
+
+	t.Run("Resubscribe", func(t *testing.T) {
+		b.Unsubscribe("IWM")
+		assert.Equal(t, []string{"QQQ", "SPY"}, subscribed)
+		a.Close()
+		assert.Equal(t, []string{"SPY"}, subscribed)
+		a.Subscribe("AAPL")
+		assert.Equal(t, []string{"SPY"}, g.Symbols())
+	})
+
+	t.Run("Run closes consumers", func(t *testing.T) {
+		blocking := g.Join("blocking", []string{"SPY"}, 0, Block)
+		events := make(chan *StreamEvent, 1)
+		events <- event("SPY")
+		close(events)
+
+		done := make(chan struct{})
+		go func() {
+			g.Run(events)
+			close(done)
+		}()
+		assert.Equal(t, "SPY", (<-blocking.Events).Symbol)
+		<-done
+		_, ok := <-b.Events
+		assert.True(t, ok)
+		_, ok = <-b.Events
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,75 @@
+package tradier
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// TokenSource supplies the access token with which each request is authorized.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// CredentialsProvider supplies the access token with which each request is
+// authorized. It is consulted for every request and stream connection, so
+// tokens can be rotated, e.g. from a secrets manager, without recreating the
+// client or its managed streams.
+type CredentialsProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticCredentials is a CredentialsProvider of a fixed token.
+type StaticCredentials string
+
+func (sc StaticCredentials) Token(ctx context.Context) (string, error) {
+	return string(sc), nil
+}
+
+type tokenSourceCredentials struct {
+	source TokenSource
+}
+
+func (tsc tokenSourceCredentials) Token(ctx context.Context) (string, error) {
+	return tsc.source.Token()
+}
+
+// The means of authorizing requests, which is replaced as a whole when the
+// token is rotated. Copies of a client made by WithAccount share it.
+type authState struct {
+	// Shared between requests to avoid allocating it each time.
+	header      []string
+	credentials CredentialsProvider
+}
+
+func newAuth(token string, credentials CredentialsProvider) *atomic.Pointer[authState] {
+	auth := &atomic.Pointer[authState]{}
+	auth.Store(&authState{header: []string{"Bearer " + token}, credentials: credentials})
+	return auth
+}
+
+// SetToken replaces the token with which requests are authorized, and any
+// CredentialsProvider. Requests already sent complete with the previous
+// token, and subsequent requests use the new one.
+func (tc *Client) SetToken(token string) {
+	tc.auth.Store(&authState{header: []string{"Bearer " + token}})
+}
+
+// SetCredentials replaces the provider consulted for the token of each request.
+func (tc *Client) SetCredentials(credentials CredentialsProvider) {
+	tc.auth.Store(&authState{credentials: credentials})
+}
+
+// Returns the Authorization header value for a request.
+func (tc *Client) authorization(ctx context.Context) ([]string, error) {
+	auth := tc.auth.Load()
+	if auth.credentials == nil {
+		return auth.header, nil
+	}
+	token, err := auth.credentials.Token(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting access token")
+	}
+	return []string{"Bearer " + token}, nil
+}
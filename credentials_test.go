@@ -0,0 +1,107 @@
+package tradier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type rotatingCredentials struct {
+	mu    sync.Mutex
+	token string
+	err   error
+}
+
+func (rc *rotatingCredentials) Token(ctx context.Context) (string, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.token, rc.err
+}
+
+func (rc *rotatingCredentials) set(token string, err error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.token, rc.err = token, err
+}
+
+func TestClient_Credentials(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"clock": {"state": "open"}}`))
+	}))
+	defer server.Close()
+
+	credentials := &rotatingCredentials{token: "first"}
+	params := DefaultParams("ignored")
+	params.Endpoint = server.URL
+	params.Credentials = credentials
+	client := NewClient(params)
+
+	_, err := client.GetMarketState()
+	assert.NoError(t, err)
+	credentials.set("second", nil)
+	_, err = client.GetMarketState()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bearer first", "Bearer second"}, tokens)
+
+	failure := errors.New("vault unavailable")
+	credentials.set("", failure)
+	_, err = client.GetMarketState()
+	assert.Equal(t, failure, errors.Cause(err))
+	assert.Len(t, tokens, 2)
+
+	t.Run("Static", func(t *testing.T) {
+		params.Credentials = StaticCredentials("static")
+		_, err := NewClient(params).GetMarketState()
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer static", tokens[len(tokens)-1])
+	})
+}
+
+func TestClient_SetToken(t *testing.T) {
+	received := make(chan string, 10)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("Authorization")
+		if r.Header.Get("Authorization") == "Bearer old" {
+			<-release
+		}
+		w.Write([]byte(`{"clock": {"state": "open"}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("old")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+	other := client.WithAccount("VA000")
+
+	done := make(chan error)
+	go func() {
+		_, err := client.GetMarketState()
+		done <- err
+	}()
+	// Rotate while the first request is in flight.
+	assert.Equal(t, "Bearer old", <-received)
+	client.SetToken("new")
+
+	_, err := client.GetMarketState()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer new", <-received)
+	_, err = other.GetMarketState()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer new", <-received)
+
+	close(release)
+	assert.NoError(t, <-done)
+
+	client.SetCredentials(StaticCredentials("provided"))
+	_, err = client.GetMarketState()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer provided", <-received)
+}
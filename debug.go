@@ -0,0 +1,138 @@
+package tradier
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultDebugDumpBodyLimit = 4096
+	redacted                  = "[REDACTED]"
+	redactedAuthorization     = "Bearer " + redacted
+	// Shorter tokens are not redacted from bodies, where they would match
+	// ordinary text.
+	minRedactedTokenLength = 8
+)
+
+// debugDumper writes sanitized request and response dumps for debugging.
+type debugDumper struct {
+	mu        sync.RWMutex
+	w         io.Writer
+	endpoints []string
+	bodyLimit int
+}
+
+// WithDebugDump enables dumping requests and responses to w, with the
+// Authorization header redacted and bodies truncated. If endpoints are given,
+// only requests whose path contains one of them are dumped
+// (e.g. "/orders"). It may be called at any time; a nil writer disables dumping.
+func (tc *Client) WithDebugDump(w io.Writer, endpoints ...string) {
+	tc.debug.set(w, endpoints, defaultDebugDumpBodyLimit)
+}
+
+// WithFullDebugDump is like WithDebugDump, but dumps full requests and
+// responses without truncating their bodies. The token is still redacted,
+// from the bodies as well as the Authorization header.
+func (tc *Client) WithFullDebugDump(w io.Writer, endpoints ...string) {
+	tc.debug.set(w, endpoints, -1)
+}
+
+func (dd *debugDumper) set(w io.Writer, endpoints []string, bodyLimit int) {
+	dd.mu.Lock()
+	defer dd.mu.Unlock()
+	dd.w = w
+	dd.endpoints = endpoints
+	dd.bodyLimit = bodyLimit
+}
+
+func (dd *debugDumper) writer(path string) io.Writer {
+	dd.mu.RLock()
+	defer dd.mu.RUnlock()
+	if dd.w == nil {
+		return nil
+	}
+	if len(dd.endpoints) == 0 {
+		return dd.w
+	}
+	for _, e := range dd.endpoints {
+		if strings.Contains(path, e) {
+			return dd.w
+		}
+	}
+	return nil
+}
+
+func (dd *debugDumper) dumpRequest(req *http.Request) {
+	w := dd.writer(req.URL.Path)
+	if w == nil {
+		return
+	}
+
+	sanitized := req.Clone(req.Context())
+	if sanitized.Header.Get("Authorization") != "" {
+		sanitized.Header.Set("Authorization", redactedAuthorization)
+	}
+	dumpBody := false
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			sanitized.Body = body
+			dumpBody = true
+		}
+	}
+
+	dump, err := httputil.DumpRequestOut(sanitized, dumpBody)
+	dd.write(w, ">>>", redactToken(dump, requestToken(req)), err)
+}
+
+func (dd *debugDumper) dumpResponse(resp *http.Response) {
+	w := dd.writer(resp.Request.URL.Path)
+	if w == nil {
+		return
+	}
+
+	// Streaming responses would never finish being read.
+	dumpBody := !isStreamPath(resp.Request.URL.Path)
+	dump, err := httputil.DumpResponse(resp, dumpBody)
+	dd.write(w, "<<<", redactToken(dump, requestToken(resp.Request)), err)
+}
+
+func (dd *debugDumper) write(w io.Writer, prefix string, dump []byte, err error) {
+	if err != nil {
+		fmt.Fprintf(w, "%s error dumping: %v\n", prefix, err)
+		return
+	}
+
+	dd.mu.RLock()
+	limit := dd.bodyLimit
+	dd.mu.RUnlock()
+	if limit >= 0 && len(dump) > limit {
+		dump = append(dump[:limit:limit], fmt.Sprintf("... [truncated %d bytes]", len(dump)-limit)...)
+	}
+	fmt.Fprintf(w, "%s\n%s\n", prefix, dump)
+}
+
+func isStreamPath(path string) bool {
+	return strings.Contains(path, "/events") && !strings.HasSuffix(path, "/session")
+}
+
+// requestToken returns the bearer token that authorized req, if any.
+func requestToken(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+}
+
+// redactToken replaces each occurrence of token in data, e.g. in the body of
+// a proxy's error that echoes the request.
+func redactToken(data []byte, token string) []byte {
+	if len(token) < minRedactedTokenLength || !bytes.Contains(data, []byte(token)) {
+		return data
+	}
+	return bytes.ReplaceAll(data, []byte(token), []byte(redacted))
+}
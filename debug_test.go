@@ -0,0 +1,71 @@
+package tradier
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_WithDebugDump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"order": {"id": 1, "status": "ok", "padding": "` + strings.Repeat("x", 8192) + `"}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("secret-token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.Account = "VA000"
+	client := NewClient(params)
+
+	var buf bytes.Buffer
+	client.WithDebugDump(&buf, "/orders")
+
+	_, err := client.GetMarketState()
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String(), "only selected endpoints are dumped")
+
+	_, err = client.PlaceOrder(Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day})
+	assert.NoError(t, err)
+	dump := buf.String()
+	assert.NotContains(t, dump, "secret-token")
+	assert.Contains(t, dump, "[REDACTED]")
+	assert.Contains(t, dump, "symbol=SPY")
+	assert.Contains(t, dump, "[truncated")
+
+	buf.Reset()
+	client.WithDebugDump(nil)
+	client.PlaceOrder(Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day})
+	assert.Empty(t, buf.String())
+}
+
+func TestClient_redactsToken(t *testing.T) {
+	// A proxy that echoes the request in its errors.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("upstream failed for Authorization: " + r.Header.Get("Authorization") + strings.Repeat(".", 8192)))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("secret-token")
+	params.Endpoint = server.URL
+	params.RetryLimit = 0
+	client := NewClient(params)
+	var buf bytes.Buffer
+	client.WithFullDebugDump(&buf)
+
+	_, err := client.GetMarketState()
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "secret-token")
+		assert.Contains(t, err.Error(), "Bearer [REDACTED]")
+	}
+	dump := buf.String()
+	assert.NotContains(t, dump, "secret-token")
+	assert.Contains(t, dump, "Bearer [REDACTED]")
+	assert.NotContains(t, dump, "[truncated", "full dumps are not truncated")
+}
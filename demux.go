@@ -1,6 +1,8 @@
 package tradier
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 )
 
@@ -11,6 +13,8 @@ type StreamDemuxer struct {
 	Summaries func(summary *SummaryEvent)
 	TimeSales func(timeSale *TimeSaleEvent)
 	Errors    func(err error)
+	// If set, the receive latency of timestamped events is recorded.
+	Latency *LatencyTracker
 }
 
 func (sd *StreamDemuxer) Handle(event *StreamEvent) {
@@ -35,6 +39,7 @@ func (sd *StreamDemuxer) HandleChan(events <-chan *StreamEvent) {
 func (sd *StreamDemuxer) handleQuote(m *StreamEvent) {
 	if sd.Quotes != nil {
 		if q, err := DecodeQuote(m); err == nil {
+			sd.observeLatency(q.Symbol, q.Date())
 			sd.Quotes(q)
 		} else {
 			sd.Errors(errors.Wrapf(err, "error decoding quote: %v", string(m.Message)))
@@ -45,6 +50,7 @@ func (sd *StreamDemuxer) handleQuote(m *StreamEvent) {
 func (sd *StreamDemuxer) handleTrade(m *StreamEvent) {
 	if sd.Trades != nil {
 		if t, err := DecodeTrade(m); err == nil {
+			sd.observeLatency(t.Symbol, t.Date())
 			sd.Trades(t)
 		} else {
 			sd.Errors(errors.Wrapf(err, "error decoding trade: %v", string(m.Message)))
@@ -65,9 +71,16 @@ func (sd *StreamDemuxer) handleSummary(m *StreamEvent) {
 func (sd *StreamDemuxer) handleTimeSale(m *StreamEvent) {
 	if sd.TimeSales != nil {
 		if ts, err := DecodeTimeSale(m); err == nil {
+			sd.observeLatency(ts.Symbol, ts.Date())
 			sd.TimeSales(ts)
 		} else {
 			sd.Errors(errors.Wrapf(err, "error decoding time sale: %v", string(m.Message)))
 		}
 	}
 }
+
+func (sd *StreamDemuxer) observeLatency(symbol string, eventTime time.Time) {
+	if sd.Latency != nil {
+		sd.Latency.Observe(symbol, eventTime)
+	}
+}
@@ -0,0 +1,109 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TradingEnvironment labels whether a client is meant to trade live or in the sandbox.
+type TradingEnvironment string
+
+const (
+	EnvironmentUnspecified TradingEnvironment = ""
+	EnvironmentLive        TradingEnvironment = "live"
+	EnvironmentSandbox     TradingEnvironment = "sandbox"
+)
+
+// ErrEnvironmentMismatch is returned when placing an order with a client whose
+// environment label does not match its endpoint (e.g. a sandbox-labeled client
+// pointed at the live API), or whose environment cannot be verified and the
+// order exceeds ClientParams.MaxUnverifiedNotional.
+var ErrEnvironmentMismatch = errors.New("trading environment mismatch")
+
+// ErrUnsupportedInSandbox is returned by requests to endpoints that the
+// sandbox does not support, such as the fundamentals beta.
+var ErrUnsupportedInSandbox = errors.New("endpoint not supported in the sandbox")
+
+// Returns the environment implied by the endpoint, or unspecified if the
+// endpoint is not a known Tradier endpoint (e.g. a proxy).
+func endpointEnvironment(endpoint string) TradingEnvironment {
+	switch strings.TrimRight(endpoint, "/") {
+	case APIEndpoint:
+		return EnvironmentLive
+	case SandboxEndpoint:
+		return EnvironmentSandbox
+	default:
+		return EnvironmentUnspecified
+	}
+}
+
+// TradingEnvironment returns the environment label of the client.
+func (tc *Client) TradingEnvironment() TradingEnvironment {
+	return tc.environment
+}
+
+// Sandbox returns true if the client uses the sandbox.
+func (tc *Client) Sandbox() bool {
+	return tc.sandbox
+}
+
+// Check that the order may be placed in the client's environment.
+func (tc *Client) checkEnvironment(order Order) error {
+	actual := endpointEnvironment(tc.endpoint)
+	if tc.environment != EnvironmentUnspecified && actual != EnvironmentUnspecified && tc.environment != actual {
+		return errors.Wrapf(ErrEnvironmentMismatch,
+			"client is labeled %v but endpoint %v is %v", tc.environment, tc.endpoint, actual)
+	}
+
+	if tc.maxUnverifiedNotional <= 0 {
+		return nil
+	}
+	if tc.environment != EnvironmentUnspecified && actual != EnvironmentUnspecified {
+		return nil
+	}
+
+	notional, ok := orderNotional(order)
+	if !ok || notional > tc.maxUnverifiedNotional {
+		return errors.Wrap(ErrEnvironmentMismatch, fmt.Sprintf(
+			"cannot verify environment of endpoint %v for order with notional %.2f (limit %.2f)",
+			tc.endpoint, notional, tc.maxUnverifiedNotional))
+	}
+	return nil
+}
+
+// Estimate the notional value of an order. Returns false if it cannot be
+// estimated, e.g. for market orders which have no price.
+func orderNotional(order Order) (float64, bool) {
+	switch order.Class {
+	case Equity:
+		price := math.Max(order.Price, order.StopPrice)
+		return order.Quantity * price, price > 0
+	case Option:
+		price := math.Max(order.Price, order.StopPrice)
+		return 100 * order.Quantity * price, price > 0
+	case Multileg, Combo:
+		if len(order.Legs) == 0 || order.Type == MarketOrder {
+			return 0, false
+		}
+		return 100 * order.Legs[0].Quantity * order.Price, true
+	case OneTriggersOther, OneCancelsOther, OneTriggersOneCancelsOther:
+		var total float64
+		for _, leg := range order.Legs {
+			price := math.Max(leg.Price, leg.StopPrice)
+			if price <= 0 {
+				return 0, false
+			}
+			multiplier := 1.0
+			if leg.OptionSymbol != "" {
+				multiplier = 100
+			}
+			total += multiplier * leg.Quantity * price
+		}
+		return total, true
+	default:
+		return 0, false
+	}
+}
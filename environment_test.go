@@ -0,0 +1,91 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_checkEnvironment(t *testing.T) {
+	order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 400}
+
+	t.Run("Sandbox label with live endpoint", func(t *testing.T) {
+		params := DefaultParams("token")
+		params.Environment = EnvironmentSandbox
+		err := NewClient(params).checkEnvironment(order)
+		assert.Equal(t, ErrEnvironmentMismatch, errors.Cause(err))
+	})
+
+	t.Run("Matching label", func(t *testing.T) {
+		params := DefaultParams("token")
+		params.MaxUnverifiedNotional = 1
+		assert.NoError(t, NewClient(params).checkEnvironment(order))
+	})
+
+	t.Run("Unverified endpoint above notional", func(t *testing.T) {
+		params := DefaultParams("token")
+		params.Endpoint = "https://tradier-proxy.internal"
+		params.MaxUnverifiedNotional = 1000
+		client := NewClient(params)
+		err := client.checkEnvironment(order)
+		assert.Equal(t, ErrEnvironmentMismatch, errors.Cause(err))
+
+		order.Quantity = 1
+		assert.NoError(t, client.checkEnvironment(order))
+
+		order.Type = MarketOrder
+		order.Price = 0
+		assert.Error(t, client.checkEnvironment(order), "market orders have unknown notional")
+	})
+}
+
+func TestSandboxParams(t *testing.T) {
+	t.Run("Sandbox params", func(t *testing.T) {
+		client := NewClient(SandboxParams("token"))
+		assert.True(t, client.Sandbox())
+		assert.Equal(t, SandboxEndpoint, client.endpoint)
+		assert.Equal(t, EnvironmentSandbox, client.TradingEnvironment())
+	})
+
+	t.Run("Sandbox flag replaces the live endpoint", func(t *testing.T) {
+		params := DefaultParams("token")
+		params.Sandbox = true
+		client := NewClient(params)
+		assert.Equal(t, SandboxEndpoint, client.endpoint)
+		assert.Equal(t, EnvironmentSandbox, client.TradingEnvironment())
+	})
+
+	t.Run("Sandbox endpoint without the flag", func(t *testing.T) {
+		params := DefaultParams("token")
+		params.Endpoint = SandboxEndpoint
+		params.Environment = EnvironmentSandbox
+		assert.True(t, NewClient(params).Sandbox())
+		assert.False(t, NewClient(DefaultParams("token")).Sandbox())
+	})
+
+	t.Run("Fundamentals are unsupported", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request to %v", r.URL)
+		}))
+		defer server.Close()
+
+		params := SandboxParams("token")
+		params.Endpoint = server.URL
+		client := NewClient(params)
+		_, err := client.GetCompanyInfo([]string{"AAPL"})
+		assert.Equal(t, ErrUnsupportedInSandbox, errors.Cause(err))
+		_, err = client.GetDividends([]string{"AAPL"})
+		assert.Equal(t, ErrUnsupportedInSandbox, errors.Cause(err))
+	})
+
+	t.Run("Known rate limits", func(t *testing.T) {
+		live := NewClient(DefaultParams("token"))
+		sandbox := NewClient(SandboxParams("token"))
+		assert.Equal(t, 120, live.KnownRateLimit(CategoryMarketData))
+		assert.Equal(t, 60, sandbox.KnownRateLimit(CategoryMarketData))
+		assert.Equal(t, 60, sandbox.KnownRateLimit(CategoryTrading))
+	})
+}
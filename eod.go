@@ -0,0 +1,233 @@
+package tradier
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EODAccount provides the account endpoints used by the built-in end of day
+// jobs. It is implemented by Client.
+type EODAccount interface {
+	GetOpenOrders() ([]*Order, error)
+	GetAccountPositions() ([]*Position, error)
+	GetAccountBalances() (*AccountBalances, error)
+	GetQuotes(symbols []string) ([]*Quote, error)
+	CancelOrder(orderId int) error
+}
+
+// EODJob is a job run after the market closes. Run is called with the date
+// of the session that closed.
+type EODJob struct {
+	Name string
+	Run  func(ctx context.Context, session time.Time) error
+}
+
+// EODResult is the outcome of one job of a run.
+type EODResult struct {
+	Job      string
+	Session  time.Time
+	Err      error
+	Duration time.Duration
+}
+
+// EODRunner runs a sequence of jobs once per session when the market closes.
+// Jobs run in order, and a failed job does not prevent later jobs from
+// running.
+//
+// The runner is driven by a ClockWatcher:
+//
+//	watcher := NewClockWatcher(client, runner.MarketChanged)
+//	go watcher.Run(stop)
+type EODRunner struct {
+	Jobs []EODJob
+	// If set, Failures is called with each job that fails.
+	Failures func(job string, err error)
+	// If set, Done is called with the results of each run.
+	Done func(results []EODResult)
+
+	mu          sync.Mutex
+	lastSession time.Time
+}
+
+func NewEODRunner(jobs ...EODJob) *EODRunner {
+	return &EODRunner{Jobs: jobs}
+}
+
+// Add appends a job to the sequence.
+func (er *EODRunner) Add(job EODJob) {
+	er.mu.Lock()
+	er.Jobs = append(er.Jobs, job)
+	er.mu.Unlock()
+}
+
+// MarketChanged runs the jobs when the state changes from MarketOpen, unless
+// they have already run for the session. Its signature matches
+// ClockWatcher.Changes.
+func (er *EODRunner) MarketChanged(from, to MarketStatus) {
+	if !(MarketTransition{From: from, To: to}).Closed() {
+		return
+	}
+	session := sessionDate(from.Time.Time)
+
+	er.mu.Lock()
+	if er.lastSession.Equal(session) {
+		er.mu.Unlock()
+		return
+	}
+	er.lastSession = session
+	er.mu.Unlock()
+
+	er.RunJobs(context.Background(), session)
+}
+
+// RunJobs runs each job in order for the session, and returns their results.
+// Jobs not yet started when ctx is done fail with its error.
+func (er *EODRunner) RunJobs(ctx context.Context, session time.Time) []EODResult {
+	er.mu.Lock()
+	jobs := append([]EODJob(nil), er.Jobs...)
+	er.mu.Unlock()
+
+	results := make([]EODResult, len(jobs))
+	for i, job := range jobs {
+		start := time.Now()
+		err := ctx.Err()
+		if err == nil {
+			err = job.Run(ctx, session)
+		}
+		results[i] = EODResult{Job: job.Name, Session: session, Err: err, Duration: time.Since(start)}
+		if err != nil && er.Failures != nil {
+			er.Failures(job.Name, err)
+		}
+	}
+
+	if er.Done != nil {
+		er.Done(results)
+	}
+	return results
+}
+
+// Returns the date of the session at t in the market timezone, or of today
+// if t is zero.
+func sessionDate(t time.Time) time.Time {
+	if t.IsZero() {
+		t = time.Now().In(marketTimezone)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// PositionMismatch is a symbol whose position in a PositionBook differs from
+// the position reported by the broker.
+type PositionMismatch struct {
+	Symbol string
+	Book   float64
+	Broker float64
+}
+
+// ReconcilePositions compares the quantities of the positions in the book
+// with the positions reported by the broker, ordered by symbol.
+func ReconcilePositions(book *PositionBook, positions []*Position) []PositionMismatch {
+	broker := make(map[string]float64)
+	for _, p := range positions {
+		broker[p.Symbol] += p.Quantity
+	}
+
+	var mismatches []PositionMismatch
+	for _, p := range book.Positions() {
+		if math.Abs(p.Quantity-broker[p.Symbol]) > 1e-9 {
+			mismatches = append(mismatches, PositionMismatch{Symbol: p.Symbol, Book: p.Quantity, Broker: broker[p.Symbol]})
+		}
+		delete(broker, p.Symbol)
+	}
+	for symbol, quantity := range broker {
+		if quantity != 0 {
+			mismatches = append(mismatches, PositionMismatch{Symbol: symbol, Broker: quantity})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].Symbol < mismatches[j].Symbol
+	})
+	return mismatches
+}
+
+// ReconcilePositionsJob compares the book with the account's positions,
+// calling mismatches (if set) with each difference. The job fails if any
+// position differs.
+func ReconcilePositionsJob(account EODAccount, book *PositionBook, mismatches func(m PositionMismatch)) EODJob {
+	return EODJob{Name: "reconcile positions", Run: func(ctx context.Context, session time.Time) error {
+		positions, err := account.GetAccountPositions()
+		if err != nil {
+			return err
+		}
+		found := ReconcilePositions(book, positions)
+		if mismatches != nil {
+			for _, m := range found {
+				mismatches(m)
+			}
+		}
+		if len(found) > 0 {
+			return errors.Errorf("%d positions differ from the book", len(found))
+		}
+		return nil
+	}}
+}
+
+// EquitySnapshotJob saves a snapshot of the account's total equity to store
+// with SaveEquitySnapshot.
+func EquitySnapshotJob(account EODAccount, store Store, accountId string) EODJob {
+	return EODJob{Name: "equity snapshot", Run: func(ctx context.Context, session time.Time) error {
+		balances, err := account.GetAccountBalances()
+		if err != nil {
+			return err
+		}
+		return SaveEquitySnapshot(store, accountId, balances, time.Now())
+	}}
+}
+
+// PnLReportJob builds the P&L report of the session, as by
+// Client.GetDailyPnL, and passes it to write.
+func PnLReportJob(account EODAccount, fees func(order *Order) float64,
+	write func(session time.Time, report *PnLReport) error) EODJob {
+	return EODJob{Name: "pnl report", Run: func(ctx context.Context, session time.Time) error {
+		report, err := dailyPnL(account, fees, time.Now())
+		if err != nil {
+			return err
+		}
+		return write(session, report)
+	}}
+}
+
+// StaleOrdersJob cancels working orders created more than maxAge ago, e.g.
+// GTC orders left behind by a strategy. Canceled (if set) is called with
+// each canceled order. The job fails with the first error canceling an
+// order, after attempting to cancel the others.
+func StaleOrdersJob(account EODAccount, maxAge time.Duration, canceled func(order *Order)) EODJob {
+	return EODJob{Name: "stale orders", Run: func(ctx context.Context, session time.Time) error {
+		orders, err := account.GetOpenOrders()
+		if err != nil {
+			return err
+		}
+
+		var firstErr error
+		now := time.Now()
+		for _, o := range orders {
+			if !isOpenStatus(o.Status) || now.Sub(o.CreateDate.Time) <= maxAge {
+				continue
+			}
+			if err := account.CancelOrder(o.Id); err != nil {
+				if firstErr == nil {
+					firstErr = errors.Wrapf(err, "error canceling order %d", o.Id)
+				}
+				continue
+			}
+			if canceled != nil {
+				canceled(o)
+			}
+		}
+		return firstErr
+	}}
+}
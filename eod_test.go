@@ -0,0 +1,150 @@
+package tradier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEODAccount struct {
+	orders    []*Order
+	positions []*Position
+	balances  *AccountBalances
+	quotes    []*Quote
+	canceled  []int
+	cancelErr error
+}
+
+func (fa *fakeEODAccount) GetOpenOrders() ([]*Order, error)          { return fa.orders, nil }
+func (fa *fakeEODAccount) GetAccountPositions() ([]*Position, error) { return fa.positions, nil }
+func (fa *fakeEODAccount) GetAccountBalances() (*AccountBalances, error) {
+	return fa.balances, nil
+}
+func (fa *fakeEODAccount) GetQuotes(symbols []string) ([]*Quote, error) { return fa.quotes, nil }
+
+func (fa *fakeEODAccount) CancelOrder(orderId int) error {
+	if fa.cancelErr != nil {
+		return fa.cancelErr
+	}
+	fa.canceled = append(fa.canceled, orderId)
+	return nil
+}
+
+func TestEODRunner_MarketChanged(t *testing.T) {
+	var ran []string
+	job := func(name string, err error) EODJob {
+		return EODJob{Name: name, Run: func(ctx context.Context, session time.Time) error {
+			ran = append(ran, name+" "+session.Format("2006-01-02"))
+			return err
+		}}
+	}
+	var failed []string
+	var results []EODResult
+	runner := NewEODRunner(job("first", errors.New("failed")), job("second", nil))
+	runner.Failures = func(job string, err error) { failed = append(failed, job) }
+	runner.Done = func(r []EODResult) { results = r }
+
+	day := DateTime{time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC)}
+	runner.MarketChanged(MarketStatus{State: "premarket", Time: day}, MarketStatus{State: "open", Time: day})
+	assert.Empty(t, ran, "not a close")
+
+	runner.MarketChanged(MarketStatus{State: "open", Time: day}, MarketStatus{State: "postmarket", Time: day})
+	assert.Equal(t, []string{"first 2021-01-04", "second 2021-01-04"}, ran, "a failed job does not stop the sequence")
+	assert.Equal(t, []string{"first"}, failed)
+	if assert.Len(t, results, 2) {
+		assert.Error(t, results[0].Err)
+		assert.NoError(t, results[1].Err)
+	}
+
+	runner.MarketChanged(MarketStatus{State: "open", Time: day}, MarketStatus{State: "closed", Time: day})
+	assert.Len(t, ran, 2, "jobs run once per session")
+}
+
+func TestEODRunner_RunJobs_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := NewEODRunner()
+	runner.Add(EODJob{Name: "cancel", Run: func(ctx context.Context, session time.Time) error {
+		cancel()
+		return nil
+	}})
+	runner.Add(EODJob{Name: "skipped", Run: func(ctx context.Context, session time.Time) error {
+		t.Error("job run after cancellation")
+		return nil
+	}})
+
+	results := runner.RunJobs(ctx, time.Now())
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, context.Canceled, results[1].Err)
+}
+
+func TestReconcilePositions(t *testing.T) {
+	book := NewPositionBook([]*Position{{Symbol: "SPY", Quantity: 100}, {Symbol: "AAPL", Quantity: 10}})
+	mismatches := ReconcilePositions(book, []*Position{
+		{Symbol: "SPY", Quantity: 100},
+		{Symbol: "AAPL", Quantity: 5},
+		{Symbol: "MSFT", Quantity: 1},
+	})
+	assert.Equal(t, []PositionMismatch{
+		{Symbol: "AAPL", Book: 10, Broker: 5},
+		{Symbol: "MSFT", Broker: 1},
+	}, mismatches)
+
+	t.Run("Job", func(t *testing.T) {
+		account := &fakeEODAccount{positions: []*Position{{Symbol: "SPY", Quantity: 100}}}
+		var reported []PositionMismatch
+		job := ReconcilePositionsJob(account, book, func(m PositionMismatch) { reported = append(reported, m) })
+		assert.Error(t, job.Run(context.Background(), time.Now()))
+		assert.Equal(t, []PositionMismatch{{Symbol: "AAPL", Book: 10}}, reported)
+	})
+}
+
+func TestEquitySnapshotJob(t *testing.T) {
+	store := NewMemoryStore()
+	account := &fakeEODAccount{balances: &AccountBalances{TotalEquity: 1234.5}}
+	assert.NoError(t, EquitySnapshotJob(account, store, "VA000001").Run(context.Background(), time.Now()))
+
+	snapshots, err := LoadEquitySnapshots(store, "VA000001")
+	assert.NoError(t, err)
+	if assert.Len(t, snapshots, 1) {
+		assert.Equal(t, 1234.5, snapshots[0].TotalEquity)
+	}
+}
+
+func TestPnLReportJob(t *testing.T) {
+	account := &fakeEODAccount{
+		positions: []*Position{{Symbol: "SPY", Quantity: 10, CostBasis: 3000}},
+		quotes:    []*Quote{{Symbol: "SPY", Last: 310, PreviousClose: 300}},
+	}
+	var report *PnLReport
+	job := PnLReportJob(account, nil, func(session time.Time, r *PnLReport) error {
+		report = r
+		return nil
+	})
+	assert.NoError(t, job.Run(context.Background(), time.Now()))
+	if assert.NotNil(t, report) {
+		assert.Len(t, report.Rows, 1)
+	}
+}
+
+func TestStaleOrdersJob(t *testing.T) {
+	old := DateTime{time.Now().Add(-48 * time.Hour)}
+	recent := DateTime{time.Now().Add(-time.Hour)}
+	account := &fakeEODAccount{orders: []*Order{
+		{Id: 1, Status: Open, CreateDate: old},
+		{Id: 2, Status: Open, CreateDate: recent},
+		{Id: 3, Status: Filled, CreateDate: old},
+		{Id: 4, Status: PartiallyFilled, CreateDate: old},
+	}}
+
+	var canceled []int
+	job := StaleOrdersJob(account, 24*time.Hour, func(o *Order) { canceled = append(canceled, o.Id) })
+	assert.NoError(t, job.Run(context.Background(), time.Now()))
+	assert.Equal(t, []int{1, 4}, account.canceled)
+	assert.Equal(t, []int{1, 4}, canceled)
+
+	account.cancelErr = errors.New("rejected")
+	assert.Error(t, job.Run(context.Background(), time.Now()))
+}
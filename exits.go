@@ -0,0 +1,140 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+)
+
+// BuildExitOCO builds a GTC one-cancels-other order exiting quantity units
+// of the position with a limit order at takeProfit and a stop order at stopLoss.
+func BuildExitOCO(pos *Position, quantity, takeProfit, stopLoss float64) (Order, error) {
+	if quantity <= 0 {
+		return Order{}, fmt.Errorf("exit quantity must be positive, got %v", quantity)
+	}
+	if pos.Quantity == 0 || quantity > math.Abs(pos.Quantity) {
+		return Order{}, fmt.Errorf("cannot exit %v of %v position of %v", quantity, pos.Symbol, pos.Quantity)
+	}
+
+	long := pos.Quantity > 0
+	if long && takeProfit <= stopLoss {
+		return Order{}, fmt.Errorf("take profit %v must be above stop loss %v for a long position", takeProfit, stopLoss)
+	} else if !long && takeProfit >= stopLoss {
+		return Order{}, fmt.Errorf("take profit %v must be below stop loss %v for a short position", takeProfit, stopLoss)
+	}
+
+	exit := closingOrder(&Position{Symbol: pos.Symbol, Quantity: math.Copysign(quantity, pos.Quantity)})
+	profit, stop := *exit, *exit
+	profit.Class, stop.Class = "", ""
+	profit.Duration, stop.Duration = "", ""
+	profit.Type, profit.Price = LimitOrder, takeProfit
+	stop.Type, stop.StopPrice = StopOrder, stopLoss
+
+	return Order{
+		Class:    OneCancelsOther,
+		Duration: GTC,
+		Legs:     []Order{profit, stop},
+	}, nil
+}
+
+// AttachExitOCO places a protective OCO exit pair for quantity units of the
+// existing position in symbol: a limit order at takeProfit and a stop order at
+// stopLoss. The quantity is validated against the account's positions.
+// It returns the result of placing the order.
+func (tc *Client) AttachExitOCO(symbol string, quantity, takeProfit, stopLoss float64) (*OrderResult, error) {
+	positions, err := tc.GetAccountPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	pos := &Position{Symbol: symbol}
+	for _, p := range positions {
+		if p.Symbol == symbol {
+			pos.Quantity += p.Quantity
+		}
+	}
+	order, err := BuildExitOCO(pos, quantity, takeProfit, stopLoss)
+	if err != nil {
+		return nil, err
+	}
+	return tc.PlaceOrder(order)
+}
+
+// BuildBracketOrder builds a one-triggers-one-cancels-other order of the
+// entry, an equity or option order opening a position, followed by its exits:
+// a limit order at takeProfit and a stop order at stopLoss, for the quantity
+// of the entry. The exits are only placed once the entry fills, and the fill
+// of either cancels the other. The duration of the entry, Day or GTC
+// (the default), applies to all three legs, as Tradier requires.
+func BuildBracketOrder(entry Order, takeProfit, stopLoss float64) (Order, error) {
+	if entry.Quantity <= 0 {
+		return Order{}, fmt.Errorf("entry quantity must be positive, got %v", entry.Quantity)
+	}
+	duration := entry.Duration
+	if duration == "" {
+		duration = GTC
+	} else if duration != Day && duration != GTC {
+		return Order{}, fmt.Errorf("bracket orders must be day or gtc, got %v", duration)
+	}
+	switch entry.Type {
+	case MarketOrder, LimitOrder, StopOrder, StopLimitOrder:
+	default:
+		return Order{}, fmt.Errorf("unknown entry order type: %v", entry.Type)
+	}
+
+	symbol, optionSymbol := entry.Symbol, entry.OptionSymbol
+	if contract, err := ParseOptionSymbol(symbol); optionSymbol == "" && err == nil {
+		optionSymbol, symbol = symbol, contract.Underlying
+	}
+	if symbol == "" {
+		return Order{}, fmt.Errorf("entry order without a symbol")
+	}
+
+	var exitSide string
+	switch entry.Side {
+	case Buy:
+		exitSide = Sell
+	case SellShort:
+		exitSide = BuyToCover
+	case BuyToOpen:
+		exitSide = SellToClose
+	case SellToOpen:
+		exitSide = BuyToClose
+	default:
+		return Order{}, fmt.Errorf("entry side %v does not open a position", entry.Side)
+	}
+	if optionSymbol == "" && (entry.Side == BuyToOpen || entry.Side == SellToOpen) {
+		return Order{}, fmt.Errorf("option entry for %v without an option symbol", symbol)
+	} else if optionSymbol != "" && (entry.Side == Buy || entry.Side == SellShort) {
+		return Order{}, fmt.Errorf("entry side %v is not an option side", entry.Side)
+	}
+
+	long := isBuySide(entry.Side)
+	if long && takeProfit <= stopLoss {
+		return Order{}, fmt.Errorf("take profit %v must be above stop loss %v for a long entry", takeProfit, stopLoss)
+	} else if !long && takeProfit >= stopLoss {
+		return Order{}, fmt.Errorf("take profit %v must be below stop loss %v for a short entry", takeProfit, stopLoss)
+	}
+	if stopLoss <= 0 {
+		return Order{}, fmt.Errorf("stop loss must be positive, got %v", stopLoss)
+	}
+	if entry.Type == LimitOrder || entry.Type == StopLimitOrder {
+		if entry.Price <= math.Min(takeProfit, stopLoss) || entry.Price >= math.Max(takeProfit, stopLoss) {
+			return Order{}, fmt.Errorf("entry price %v must be between the take profit %v and stop loss %v",
+				entry.Price, takeProfit, stopLoss)
+		}
+	}
+
+	leg := Order{Symbol: symbol, OptionSymbol: optionSymbol, Quantity: entry.Quantity}
+	open, profit, stop := leg, leg, leg
+	open.Side, open.Type, open.Price, open.StopPrice = entry.Side, entry.Type, entry.Price, entry.StopPrice
+	profit.Side, profit.Type, profit.Price = exitSide, LimitOrder, takeProfit
+	stop.Side, stop.Type, stop.StopPrice = exitSide, StopOrder, stopLoss
+
+	order := Order{
+		Class:    OneTriggersOneCancelsOther,
+		Duration: duration,
+		Tag:      entry.Tag,
+		Legs:     []Order{open, profit, stop},
+	}
+	return order, checkOrderFields(order, "")
+}
@@ -0,0 +1,106 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildExitOCO(t *testing.T) {
+	t.Run("Long equity", func(t *testing.T) {
+		order, err := BuildExitOCO(&Position{Symbol: "SPY", Quantity: 100}, 50, 400, 350)
+		assert.NoError(t, err)
+		assert.Equal(t, Order{
+			Class:    OneCancelsOther,
+			Duration: GTC,
+			Legs: []Order{
+				{Symbol: "SPY", Side: Sell, Quantity: 50, Type: LimitOrder, Price: 400},
+				{Symbol: "SPY", Side: Sell, Quantity: 50, Type: StopOrder, StopPrice: 350},
+			},
+		}, order)
+		assert.NoError(t, checkOrderFields(order, ""))
+	})
+
+	t.Run("Short option", func(t *testing.T) {
+		order, err := BuildExitOCO(&Position{Symbol: "SPY210219P00360000", Quantity: -2}, 2, 1.00, 6.00)
+		assert.NoError(t, err)
+		if assert.Len(t, order.Legs, 2) {
+			assert.Equal(t, BuyToClose, order.Legs[0].Side)
+			assert.Equal(t, "SPY", order.Legs[0].Symbol)
+			assert.Equal(t, "SPY210219P00360000", order.Legs[1].OptionSymbol)
+		}
+	})
+
+	t.Run("Invalid exits", func(t *testing.T) {
+		_, err := BuildExitOCO(&Position{Symbol: "SPY", Quantity: 100}, 150, 400, 350)
+		assert.Error(t, err)
+		_, err = BuildExitOCO(&Position{Symbol: "SPY"}, 1, 400, 350)
+		assert.Error(t, err)
+		_, err = BuildExitOCO(&Position{Symbol: "SPY", Quantity: 100}, 100, 350, 400)
+		assert.Error(t, err)
+		_, err = BuildExitOCO(&Position{Symbol: "SPY", Quantity: -100}, 100, 400, 350)
+		assert.Error(t, err)
+	})
+}
+
+func TestBuildBracketOrder(t *testing.T) {
+	t.Run("Long equity", func(t *testing.T) {
+		entry := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 380}
+		order, err := BuildBracketOrder(entry, 400, 350)
+		assert.NoError(t, err)
+		assert.Equal(t, Order{
+			Class:    OneTriggersOneCancelsOther,
+			Duration: GTC,
+			Legs: []Order{
+				{Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 380},
+				{Symbol: "SPY", Side: Sell, Quantity: 10, Type: LimitOrder, Price: 400},
+				{Symbol: "SPY", Side: Sell, Quantity: 10, Type: StopOrder, StopPrice: 350},
+			},
+		}, order)
+
+		form, err := orderToParams(order, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "otoco", form.Get("class"))
+		assert.Equal(t, "10", form.Get("quantity[0]"))
+		assert.Equal(t, "10", form.Get("quantity[2]"))
+		assert.Equal(t, "400.00", form.Get("price[1]"))
+		assert.Equal(t, "350.00", form.Get("stop[2]"))
+		for key := range form {
+			assert.NotContains(t, key, "d]", "malformed key %v", key)
+		}
+	})
+
+	t.Run("Short option", func(t *testing.T) {
+		entry := Order{Symbol: "SPY210219P00360000", Side: SellToOpen, Quantity: 2, Type: MarketOrder, Duration: Day}
+		order, err := BuildBracketOrder(entry, 1.00, 6.00)
+		assert.NoError(t, err)
+		assert.Equal(t, Day, order.Duration)
+		if assert.Len(t, order.Legs, 3) {
+			assert.Equal(t, "SPY", order.Legs[0].Symbol)
+			assert.Equal(t, "SPY210219P00360000", order.Legs[0].OptionSymbol)
+			assert.Equal(t, BuyToClose, order.Legs[1].Side)
+			assert.Equal(t, BuyToClose, order.Legs[2].Side)
+		}
+	})
+
+	t.Run("Invalid brackets", func(t *testing.T) {
+		entry := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 380}
+		_, err := BuildBracketOrder(entry, 350, 400)
+		assert.Error(t, err)
+		_, err = BuildBracketOrder(entry, 400, 385)
+		assert.Error(t, err, "the entry is below the stop loss")
+
+		for _, change := range []func(o *Order){
+			func(o *Order) { o.Quantity = 0 },
+			func(o *Order) { o.Duration = PreMarket },
+			func(o *Order) { o.Side = Sell },
+			func(o *Order) { o.Side = BuyToOpen },
+			func(o *Order) { o.Type = Debit },
+		} {
+			invalid := entry
+			change(&invalid)
+			_, err := BuildBracketOrder(invalid, 400, 350)
+			assert.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package tradier
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// The exporters (PnLReport.WriteCSV, WriteStatementsCSV, WriteStatementsJSON,
+// TimelineRecorder.WriteJSON and the dumps of RecentRequests) produce the
+// same output for the same input, so that it can be diffed and compared with
+// golden files, e.g. by tradiertest.AssertGolden: rows are written in a
+// stable order, amounts with two decimal places, and times in UTC.
+
+// Write v as indented JSON.
+func writeExportJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Format an exported amount with two decimal places, without the sign of
+// amounts that round to zero.
+func formatExportAmount(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	if s == "-0.00" {
+		return "0.00"
+	}
+	return s
+}
+
+// Normalize an exported time to UTC, without its monotonic clock reading.
+func exportTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.UTC().Round(0)
+}
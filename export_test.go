@@ -0,0 +1,61 @@
+package tradier
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportDeterminism(t *testing.T) {
+	tz := time.FixedZone("EDT", -4*60*60)
+
+	t.Run("Amounts", func(t *testing.T) {
+		report := &PnLReport{Date: time.Date(2024, 6, 3, 0, 0, 0, 0, tz), Rows: []PnLAttribution{
+			{Symbol: "SPY", Trading: 0.1 + 0.2 - 0.3 - 0.001, Net: -0.004},
+		}}
+		var buf bytes.Buffer
+		assert.NoError(t, report.WriteCSV(&buf))
+		assert.Equal(t, "date,symbol,tag,position,trading,fees,net\n"+
+			"2024-06-03,SPY,,0.00,0.00,0.00,0.00\n"+
+			"2024-06-03,TOTAL,,0.00,0.00,0.00,0.00\n", buf.String())
+	})
+
+	t.Run("Statements", func(t *testing.T) {
+		statements := []MonthlyStatement{
+			{Month: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), PnL: 2},
+			{Month: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), PnL: 1},
+		}
+		var buf bytes.Buffer
+		assert.NoError(t, WriteStatementsCSV(&buf, statements))
+		lines := strings.Split(buf.String(), "\n")
+		assert.True(t, strings.HasPrefix(lines[1], "2024-01,"))
+		assert.True(t, strings.HasPrefix(lines[2], "2024-02,"))
+		assert.Equal(t, 2.0, statements[0].PnL, "the statements are not reordered")
+	})
+
+	t.Run("Times", func(t *testing.T) {
+		tr := NewTimelineRecorder()
+		at := time.Date(2024, 6, 3, 10, 0, 0, 0, tz)
+		tr.Submitted(Order{Symbol: "SPY", Quantity: 1}, &OrderResult{Id: 1}, at)
+		var first, second bytes.Buffer
+		assert.NoError(t, tr.WriteJSON(&first))
+		assert.NoError(t, tr.WriteJSON(&second))
+		assert.Equal(t, first.String(), second.String())
+		assert.Contains(t, first.String(), `"time": "2024-06-03T14:00:00Z"`)
+
+		client := NewClient(ClientParams{JournalSize: 2})
+		client.journal.record(&requestRecord{Method: "GET", Endpoint: "/v1/markets/clock", StatusCode: 200},
+			"https://example.com/v1/markets/clock", nil, time.Now(), nil)
+		var buf bytes.Buffer
+		assert.NoError(t, client.WriteRecentRequestsJSON(&buf))
+		var entries []JournalEntry
+		if assert.NoError(t, json.Unmarshal(buf.Bytes(), &entries)) && assert.Len(t, entries, 1) {
+			assert.Equal(t, time.UTC, entries[0].Time.Location())
+			assert.Equal(t, "/v1/markets/clock", entries[0].Endpoint)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package tradier
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var responseBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// FastPlaceOrder places an order with as little overhead as possible, for
+// callers with tight latency budgets. Unlike PlaceOrder it accepts a
+// context, and it skips debug dumps, tracing and metrics. The request still
+// counts against the trading rate limit, the usage ledger and the journal,
+// but it never waits for the rate limit window to renew. The same guards as
+// PlaceOrder are applied, so clients that require settled cash, block good
+// faith violations or check option levels make their further requests before
+// placing orders. The request is never retried, even when PlaceOrder would
+// retry it.
+func (tc *Client) FastPlaceOrder(ctx context.Context, order Order) (*OrderResult, error) {
+	if err := tc.checkPlaceOrder(order); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	form, err := orderToParams(order, tc.priceDecimals)
+	if err != nil {
+		return nil, err
+	}
+	req, err := tc.makeSignedRequest(ctx, http.MethodPost, tc.ordersURL, form)
+	if err != nil {
+		return nil, err
+	}
+	tc.rateLimits.take(CategoryTrading, tc.reserve, start)
+	resp, err := tc.client.Do(req)
+	if tc.usage != nil {
+		tc.usage.record(tc.identity, CategoryTrading, resp)
+	}
+	if err != nil {
+		tc.journalFastOrder(form, start, 0, err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	tc.rateLimits.update(CategoryTrading, resp.Header)
+
+	buf := responseBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer responseBuffers.Put(buf)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		tc.journalFastOrder(form, start, resp.StatusCode, err)
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := newTradierError(resp, buf.Bytes())
+		tc.journalFastOrder(form, start, resp.StatusCode, err)
+		return nil, err
+	}
+	tc.journalFastOrder(form, start, resp.StatusCode, nil)
+
+	// The buffer is reused, so the result keeps a copy of the body.
+	result, err := decodeOrderResult(append([]byte(nil), buf.Bytes()...))
+	if err != nil {
+		return result, err
+	}
+	if result.Id != 0 {
+		tc.orderLatency.observe(start)
+	}
+	return result, result.err()
+}
+
+// Records an order placed by FastPlaceOrder in the journal, if it is kept.
+func (tc *Client) journalFastOrder(form url.Values, start time.Time, statusCode int, err error) {
+	if tc.journal == nil {
+		return
+	}
+	rr := newRequestRecord(http.MethodPost, tc.ordersURL, form)
+	rr.Attempts, rr.StatusCode = 1, statusCode
+	tc.journal.record(&rr, tc.ordersURL, form, start, err)
+}
@@ -0,0 +1,120 @@
+package tradier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrderServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/accounts/VA000/orders" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("Invalid Access Token"))
+			return
+		}
+		if r.FormValue("symbol") == "HALTED" {
+			w.Write([]byte(`{"order": {"id": 2, "status": "rejected"}}`))
+			return
+		}
+		w.Write([]byte(`{"order": {"id": 1, "status": "ok"}}`))
+	}))
+}
+
+func TestClient_FastPlaceOrder(t *testing.T) {
+	server := newOrderServer()
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	client := NewClient(params)
+	order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day}
+
+	_, err := client.FastPlaceOrder(context.Background(), order)
+	assert.Equal(t, ErrNoAccountSelected, err)
+
+	client.SelectAccount("VA000")
+	result, err := client.FastPlaceOrder(context.Background(), order)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, result.Id)
+		assert.JSONEq(t, `{"order": {"id": 1, "status": "ok"}}`, string(result.Body))
+	}
+
+	order.Symbol = "HALTED"
+	result, err = client.FastPlaceOrder(context.Background(), order)
+	assert.EqualError(t, err, "received order status: rejected")
+	if assert.NotNil(t, result) {
+		assert.Equal(t, 2, result.Id)
+	}
+
+	client.SelectAccount("VA001")
+	_, err = client.FastPlaceOrder(context.Background(), order)
+	if assert.IsType(t, TradierError{}, err) {
+		assert.Equal(t, http.StatusUnauthorized, err.(TradierError).HttpStatusCode)
+		assert.Equal(t, "Invalid Access Token", err.(TradierError).Fault.FaultString)
+	}
+}
+
+func TestClient_FastPlaceOrder_accounting(t *testing.T) {
+	expiry := time.Now().Add(time.Minute)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(rateLimitAllowed, "60")
+		w.Header().Set(rateLimitUsed, "3")
+		w.Header().Set(rateLimitAvailable, "57")
+		w.Header().Set(rateLimitExpiry, strconv.FormatInt(expiry.UnixNano()/int64(time.Millisecond), 10))
+		w.Write([]byte(`{"order": {"id": 1, "status": "ok"}}`))
+	}))
+	defer server.Close()
+
+	ledger := NewUsageLedger()
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.Account = "VA000"
+	params.ServiceIdentity = "scalper"
+	params.Usage = ledger
+	params.JournalSize = 4
+	client := NewClient(params)
+	order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day}
+
+	_, err := client.FastPlaceOrder(context.Background(), order)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 57, client.Stats().RateLimits[CategoryTrading].Available)
+	usage := ledger.Usage()["scalper"][CategoryTrading]
+	assert.Equal(t, 1, usage.Requests)
+	assert.Equal(t, 1, usage.WindowRequests)
+	if requests := client.RecentRequests(); assert.Len(t, requests, 1) {
+		assert.Equal(t, http.MethodPost, requests[0].Method)
+		assert.Equal(t, http.StatusOK, requests[0].StatusCode)
+		assert.Empty(t, requests[0].Err)
+	}
+}
+
+func BenchmarkClient_FastPlaceOrder(b *testing.B) {
+	server := newOrderServer()
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.Account = "VA000"
+	client := NewClient(params)
+	order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.FastPlaceOrder(context.Background(), order); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,7 +1,5 @@
 package tradier
 
-import "encoding/json"
-
 type CorporateEvent struct {
 	BeginDateTime *string `json:"begin_date_time"`
 	CompanyID     *string `json:"company_id"`
@@ -17,17 +15,8 @@ type CorporateEvent struct {
 type CorporateCalendar []CorporateEvent
 
 func (cc *CorporateCalendar) UnmarshalJSON(data []byte) error {
-	events := make([]CorporateEvent, 0)
-	if err := json.Unmarshal(data, &events); err == nil {
-		*cc = events
-		return nil
-	}
-
-	event := CorporateEvent{}
-	err := json.Unmarshal(data, &event)
-	if err == nil {
-		*cc = []CorporateEvent{event}
-	}
+	events, err := decodeOneOrMany[CorporateEvent](data)
+	*cc = events
 	return err
 }
 
@@ -47,34 +36,16 @@ type GetCorporateCalendarsResponse []struct {
 type NAICS []int64
 
 func (n *NAICS) UnmarshalJSON(data []byte) error {
-	ids := make([]int64, 0)
-	if err := json.Unmarshal(data, &ids); err == nil {
-		*n = ids
-		return nil
-	}
-
-	var id int64
-	err := json.Unmarshal(data, &id)
-	if err == nil {
-		*n = []int64{id}
-	}
+	ids, err := decodeOneOrMany[int64](data)
+	*n = ids
 	return err
 }
 
 type SIC []int64
 
 func (n *SIC) UnmarshalJSON(data []byte) error {
-	ids := make([]int64, 0)
-	if err := json.Unmarshal(data, &ids); err == nil {
-		*n = ids
-		return nil
-	}
-
-	var id int64
-	err := json.Unmarshal(data, &id)
-	if err == nil {
-		*n = []int64{id}
-	}
+	ids, err := decodeOneOrMany[int64](data)
+	*n = ids
 	return err
 }
 
@@ -194,17 +165,8 @@ type OwnershipDetail struct {
 type OwnershipDetails []OwnershipDetail
 
 func (ods *OwnershipDetails) UnmarshalJSON(data []byte) error {
-	details := make([]OwnershipDetail, 0)
-	if err := json.Unmarshal(data, &details); err == nil {
-		*ods = details
-		return nil
-	}
-
-	detail := OwnershipDetail{}
-	err := json.Unmarshal(data, &detail)
-	if err == nil {
-		*ods = []OwnershipDetail{detail}
-	}
+	details, err := decodeOneOrMany[OwnershipDetail](data)
+	*ods = details
 	return err
 }
 
@@ -269,17 +231,8 @@ type MergerAndAcquisition struct {
 type MergersAndAcquisitions []MergerAndAcquisition
 
 func (maq *MergersAndAcquisitions) UnmarshalJSON(data []byte) error {
-	events := make([]MergerAndAcquisition, 0)
-	if err := json.Unmarshal(data, &events); err == nil {
-		*maq = events
-		return nil
-	}
-
-	event := MergerAndAcquisition{}
-	err := json.Unmarshal(data, &event)
-	if err == nil {
-		*maq = []MergerAndAcquisition{event}
-	}
+	events, err := decodeOneOrMany[MergerAndAcquisition](data)
+	*maq = events
 	return err
 }
 
@@ -323,17 +276,8 @@ type CashDividend struct {
 type CashDividends []CashDividend
 
 func (cds *CashDividends) UnmarshalJSON(data []byte) error {
-	dividends := make([]CashDividend, 0)
-	if err := json.Unmarshal(data, &dividends); err == nil {
-		*cds = dividends
-		return nil
-	}
-
-	d := CashDividend{}
-	err := json.Unmarshal(data, &d)
-	if err == nil {
-		*cds = []CashDividend{d}
-	}
+	dividends, err := decodeOneOrMany[CashDividend](data)
+	*cds = dividends
 	return err
 }
 
@@ -372,7 +316,7 @@ type BalanceSheet struct {
 	FiscalYearEnd                                 *string  `json:"fiscal_year_end"`
 	GainsLossesNotAffectingRetainedEarnings       *float64 `json:"gains_losses_not_affecting_retained_earnings"`
 	Goodwill                                      *float64 `json:"goodwill"`
-	GoodwillAndOtherIntangibleAssets              *float64 `json:"goodwill_and_other_int64angible_assets"`
+	GoodwillAndOtherIntangibleAssets              *float64 `json:"goodwill_and_other_intangible_assets"`
 	GrossPPE                                      *float64 `json:"gross_p_p_e"`
 	Inventory                                     *float64 `json:"inventory"`
 	InvestedCapital                               *float64 `json:"invested_capital"`
@@ -392,7 +336,7 @@ type BalanceSheet struct {
 	OrdinarySharesNumber                          *float64 `json:"ordinary_shares_number"`
 	OtherCurrentAssets                            *float64 `json:"other_current_assets"`
 	OtherCurrentBorrowings                        *float64 `json:"other_current_borrowings"`
-	OtherIntangibleAssets                         *float64 `json:"other_int64angible_assets"`
+	OtherIntangibleAssets                         *float64 `json:"other_intangible_assets"`
 	OtherNonCurrentAssets                         *float64 `json:"other_non_current_assets"`
 	OtherNonCurrentLiabilities                    *float64 `json:"other_non_current_liabilities"`
 	OtherReceivables                              *float64 `json:"other_receivables"`
@@ -411,12 +355,12 @@ type BalanceSheet struct {
 	TotalCapitalization                           *float64 `json:"total_capitalization"`
 	TotalDebt                                     *float64 `json:"total_debt"`
 	TotalEquity                                   *float64 `json:"total_equity"`
-	TotalEquityGrossMinorityInterest              *float64 `json:"total_equity_gross_minority_int64erest"`
+	TotalEquityGrossMinorityInterest              *float64 `json:"total_equity_gross_minority_interest"`
 	TotalLiabilities                              *float64 `json:"total_liabilities"`
-	TotalLiabilitiesNetMinorityInterest           *float64 `json:"total_liabilities_net_minority_int64erest"`
+	TotalLiabilitiesNetMinorityInterest           *float64 `json:"total_liabilities_net_minority_interest"`
 	TotalNonCurrentAssets                         *float64 `json:"total_non_current_assets"`
 	TotalNonCurrentLiabilities                    *float64 `json:"total_non_current_liabilities"`
-	TotalNonCurrentLiabilitiesNetMinorityInterest *float64 `json:"total_non_current_liabilities_net_minority_int64erest"`
+	TotalNonCurrentLiabilitiesNetMinorityInterest *float64 `json:"total_non_current_liabilities_net_minority_interest"`
 	WorkingCapital                                *float64 `json:"working_capital"`
 }
 
@@ -448,14 +392,14 @@ type CashFlowStatement struct {
 	ForeignSales                      *float64 `json:"foreign_sales"`
 	FreeCashFlow                      *float64 `json:"free_cash_flow"`
 	IncomeTaxPaidSupplementalData     *float64 `json:"income_tax_paid_supplemental_data"`
-	InterestPaidSupplementalData      *float64 `json:"int64erest_paid_supplemental_data"`
+	InterestPaidSupplementalData      *float64 `json:"interest_paid_supplemental_data"`
 	InvestingCashFlow                 *float64 `json:"investing_cash_flow"`
 	IssuanceOfCapitalStock            *float64 `json:"issuance_of_capital_stock"`
 	NetBusinessPurchaseAndSale        *float64 `json:"net_business_purchase_and_sale"`
 	NetCommonStockIssuance            *float64 `json:"net_common_stock_issuance"`
 	NetIncome                         *float64 `json:"net_income"`
 	NetIncomeFromContinuingOperations *float64 `json:"net_income_from_continuing_operations"`
-	NetIntangiblesPurchaseAndSale     *float64 `json:"net_int64angibles_purchase_and_sale"`
+	NetIntangiblesPurchaseAndSale     *float64 `json:"net_intangibles_purchase_and_sale"`
 	NetInvestmentPurchaseAndSale      *float64 `json:"net_investment_purchase_and_sale"`
 	NetIssuancePaymentsOfDebt         *float64 `json:"net_issuance_payments_of_debt"`
 	NetOtherFinancingCharges          *float64 `json:"net_other_financing_charges"`
@@ -468,7 +412,7 @@ type CashFlowStatement struct {
 	Period                            *string  `json:"period"`
 	PeriodEndingDate                  *string  `json:"period_ending_date"`
 	PurchaseOfBusiness                *float64 `json:"purchase_of_business"`
-	PurchaseOfIntangibles             *float64 `json:"purchase_of_int64angibles"`
+	PurchaseOfIntangibles             *float64 `json:"purchase_of_intangibles"`
 	PurchaseOfInvestment              *float64 `json:"purchase_of_investment"`
 	PurchaseOfPPE                     *float64 `json:"purchase_of_p_p_e"`
 	ReportType                        *string  `json:"report_type"`
@@ -487,19 +431,19 @@ type IncomeStatement struct {
 	FiscalYearEnd                                       *string  `json:"fiscal_year_end"`
 	FormType                                            *string  `json:"form_type"`
 	GrossProfit                                         *float64 `json:"gross_profit"`
-	InterestExpense                                     *float64 `json:"int64erest_expense"`
-	InterestExpenseNonOperating                         *float64 `json:"int64erest_expense"`
-	InterestIncome                                      *float64 `json:"int64erest_income"`
-	InterestIncomeNonOperating                          *float64 `json:"int64erest_income_non_operating"`
-	InterestAndSimilarIncome                            *float64 `json:"int64erestand_similar_income"`
+	InterestExpense                                     *float64 `json:"interest_expense"`
+	InterestExpenseNonOperating                         *float64 `json:"interest_expense_non_operating"`
+	InterestIncome                                      *float64 `json:"interest_income"`
+	InterestIncomeNonOperating                          *float64 `json:"interest_income_non_operating"`
+	InterestAndSimilarIncome                            *float64 `json:"interest_and_similar_income"`
 	NetIncome                                           *float64 `json:"net_income"`
 	NetIncomeCommonStockholders                         *float64 `json:"net_income_common_stockholders"`
 	NetIncomeContinuousOperations                       *float64 `json:"net_income_continuous_operations"`
 	NetIncomeFromContinuingAndDiscontinuedOperation     *float64 `json:"net_income_from_continuing_and_discontinued_operation"`
-	NetIncomeFromContinuingOperationNetMinorityInterest *float64 `json:"net_income_from_continuing_operation_net_minority_int64erest"`
-	NetIncomeIncludingNoncontrollingInterests           *float64 `json:"net_income_including_noncontrolling_int64erests"`
-	NetInterestIncome                                   *float64 `json:"net_int64erest_income"`
-	NetNonOperatingInterestIncomeExpense                *float64 `json:"net_non_operating_int64erest_income_expense"`
+	NetIncomeFromContinuingOperationNetMinorityInterest *float64 `json:"net_income_from_continuing_operation_net_minority_interest"`
+	NetIncomeIncludingNoncontrollingInterests           *float64 `json:"net_income_including_noncontrolling_interests"`
+	NetInterestIncome                                   *float64 `json:"net_interest_income"`
+	NetNonOperatingInterestIncomeExpense                *float64 `json:"net_non_operating_interest_income_expense"`
 	NonOperatingExpenses                                *float64 `json:"non_operating_expenses"`
 	NonOperatingIncome                                  *float64 `json:"non_operating_income"`
 	NormalizedEBITDA                                    *float64 `json:"normalized_e_b_i_t_d_a"`
@@ -527,51 +471,24 @@ type IncomeStatement struct {
 type BalanceSheetResults []map[string]BalanceSheet
 
 func (bsr *BalanceSheetResults) UnmarshalJSON(data []byte) error {
-	results := make([]map[string]BalanceSheet, 0)
-	if err := json.Unmarshal(data, &results); err == nil {
-		*bsr = results
-		return nil
-	}
-
-	r := make(map[string]BalanceSheet)
-	err := json.Unmarshal(data, &r)
-	if err == nil {
-		*bsr = []map[string]BalanceSheet{r}
-	}
+	results, err := decodeOneOrMany[map[string]BalanceSheet](data)
+	*bsr = results
 	return err
 }
 
 type CashFlowStatements []map[string]CashFlowStatement
 
 func (cfs *CashFlowStatements) UnmarshalJSON(data []byte) error {
-	results := make([]map[string]CashFlowStatement, 0)
-	if err := json.Unmarshal(data, &results); err == nil {
-		*cfs = results
-		return nil
-	}
-
-	r := make(map[string]CashFlowStatement)
-	err := json.Unmarshal(data, &r)
-	if err == nil {
-		*cfs = []map[string]CashFlowStatement{r}
-	}
+	results, err := decodeOneOrMany[map[string]CashFlowStatement](data)
+	*cfs = results
 	return err
 }
 
 type IncomeStatements []map[string]IncomeStatement
 
 func (is *IncomeStatements) UnmarshalJSON(data []byte) error {
-	results := make([]map[string]IncomeStatement, 0)
-	if err := json.Unmarshal(data, &results); err == nil {
-		*is = results
-		return nil
-	}
-
-	r := make(map[string]IncomeStatement)
-	err := json.Unmarshal(data, &r)
-	if err == nil {
-		*is = []map[string]IncomeStatement{r}
-	}
+	results, err := decodeOneOrMany[map[string]IncomeStatement](data)
+	*is = results
 	return err
 }
 
@@ -627,17 +544,8 @@ type HistoricalReturns struct {
 type EarningReports []map[string]EarningReport
 
 func (ers *EarningReports) UnmarshalJSON(data []byte) error {
-	results := make([]map[string]EarningReport, 0)
-	if err := json.Unmarshal(data, &results); err == nil {
-		*ers = results
-		return nil
-	}
-
-	r := make(map[string]EarningReport)
-	err := json.Unmarshal(data, &r)
-	if err == nil {
-		*ers = []map[string]EarningReport{r}
-	}
+	results, err := decodeOneOrMany[map[string]EarningReport](data)
+	*ers = results
 	return err
 }
 
@@ -678,7 +586,7 @@ type OperationRatio struct {
 	FiscalYearEnd                 *string  `json:"fiscal_year_end"`
 	FixAssetsTurnover             *float64 `json:"fix_assets_turonver"`
 	GrossMargin                   *float64 `json:"gross_margin"`
-	InterestCoverage              *float64 `json:"int64erest_coverage"`
+	InterestCoverage              *float64 `json:"interest_coverage"`
 	InventoryTurnover             *float64 `json:"inventory_turnover"`
 	LongTermDebtEquityRatio       *float64 `json:"long_term_debt_equity_ratio"`
 	LongTermDebtTotalCapitalRatio *float64 `json:"long_term_debt_total_capital_ratio"`
@@ -769,17 +677,8 @@ type ValuationRatios struct {
 type OperationRatios []map[string]OperationRatio
 
 func (ors *OperationRatios) UnmarshalJSON(data []byte) error {
-	results := make([]map[string]OperationRatio, 0)
-	if err := json.Unmarshal(data, &results); err == nil {
-		*ors = results
-		return nil
-	}
-
-	r := make(map[string]OperationRatio)
-	err := json.Unmarshal(data, &r)
-	if err == nil {
-		*ors = []map[string]OperationRatio{r}
-	}
+	results, err := decodeOneOrMany[map[string]OperationRatio](data)
+	*ors = results
 	return err
 }
 
@@ -0,0 +1,249 @@
+package tradier
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FundamentalsSource provides the fundamentals endpoints. It is implemented by Client.
+type FundamentalsSource interface {
+	GetCompanyInfo(symbols []string) (GetCompanyInfoResponse, error)
+	GetCorporateCalendars(symbols []string) (GetCorporateCalendarsResponse, error)
+	GetRatios(symbols []string) (GetRatiosResponse, error)
+	GetPriceStatistics(symbols []string) (GetPriceStatisticsResponse, error)
+}
+
+// FundamentalsSnapshot holds the fundamentals of a single symbol at a point in time.
+// Each response contains only the entry for Symbol.
+type FundamentalsSnapshot struct {
+	Symbol     string
+	Time       time.Time
+	Company    GetCompanyInfoResponse
+	Calendars  GetCorporateCalendarsResponse
+	Ratios     GetRatiosResponse
+	Statistics GetPriceStatisticsResponse
+}
+
+// SharesOutstanding returns the shares outstanding reported in the company info.
+func (fs *FundamentalsSnapshot) SharesOutstanding() (int64, bool) {
+	for _, r := range fs.Company {
+		for _, result := range r.Results {
+			if p := result.Tables.ShareClassProfile; p != nil && p.SharesOutstanding != nil {
+				return *p.SharesOutstanding, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// NextEarningsDate returns the earliest upcoming earnings event on the corporate calendar.
+func (fs *FundamentalsSnapshot) NextEarningsDate() (string, bool) {
+	today := fs.Time.Format("2006-01-02")
+	var dates []string
+	for _, r := range fs.Calendars {
+		for _, result := range r.Results {
+			if result.Tables.CorporateCalendars == nil {
+				continue
+			}
+			for _, event := range *result.Tables.CorporateCalendars {
+				if event.Event == nil || event.BeginDateTime == nil {
+					continue
+				}
+				if strings.Contains(strings.ToLower(*event.Event), "earnings") && *event.BeginDateTime >= today {
+					dates = append(dates, *event.BeginDateTime)
+				}
+			}
+		}
+	}
+	if len(dates) == 0 {
+		return "", false
+	}
+	sort.Strings(dates)
+	return dates[0], true
+}
+
+// FundamentalsChange is emitted when a tracked field changes between snapshots.
+type FundamentalsChange struct {
+	Symbol   string
+	Field    string
+	Previous string
+	Current  string
+	Time     time.Time
+}
+
+// FundamentalsRefresher periodically refreshes the fundamentals of a universe
+// of symbols, persists each snapshot to a Store under "fundamentals/<symbol>",
+// and emits changes to key fields (next earnings date, shares outstanding).
+type FundamentalsRefresher struct {
+	Source   FundamentalsSource
+	Store    Store
+	Interval time.Duration
+	Changes  func(change FundamentalsChange)
+	Errors   func(err error)
+
+	mu      sync.Mutex
+	symbols map[string]bool
+	last    map[string]*FundamentalsSnapshot
+}
+
+func NewFundamentalsRefresher(source FundamentalsSource, store Store) *FundamentalsRefresher {
+	return &FundamentalsRefresher{
+		Source:   source,
+		Store:    store,
+		Interval: 24 * time.Hour,
+		symbols:  make(map[string]bool),
+		last:     make(map[string]*FundamentalsSnapshot),
+	}
+}
+
+// Track adds symbols to the refreshed universe.
+func (fr *FundamentalsRefresher) Track(symbols ...string) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	for _, s := range symbols {
+		fr.symbols[s] = true
+	}
+}
+
+// Untrack removes symbols from the refreshed universe.
+func (fr *FundamentalsRefresher) Untrack(symbols ...string) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	for _, s := range symbols {
+		delete(fr.symbols, s)
+		delete(fr.last, s)
+	}
+}
+
+// Latest returns the most recent snapshot of the symbol, if any.
+func (fr *FundamentalsRefresher) Latest(symbol string) *FundamentalsSnapshot {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	return fr.last[symbol]
+}
+
+// Refresh fetches the fundamentals of all tracked symbols once.
+func (fr *FundamentalsRefresher) Refresh() error {
+	fr.mu.Lock()
+	symbols := make([]string, 0, len(fr.symbols))
+	for s := range fr.symbols {
+		symbols = append(symbols, s)
+	}
+	fr.mu.Unlock()
+	if len(symbols) == 0 {
+		return nil
+	}
+	sort.Strings(symbols)
+
+	company, err := fr.Source.GetCompanyInfo(symbols)
+	if err != nil {
+		return err
+	}
+	calendars, err := fr.Source.GetCorporateCalendars(symbols)
+	if err != nil {
+		return err
+	}
+	ratios, err := fr.Source.GetRatios(symbols)
+	if err != nil {
+		return err
+	}
+	statistics, err := fr.Source.GetPriceStatistics(symbols)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	snapshots := make(map[string]*FundamentalsSnapshot, len(symbols))
+	for _, s := range symbols {
+		snapshots[s] = &FundamentalsSnapshot{Symbol: s, Time: now}
+	}
+	for _, r := range company {
+		if fs, ok := snapshots[r.Request]; ok {
+			fs.Company = append(fs.Company, r)
+		}
+	}
+	for _, r := range calendars {
+		if fs, ok := snapshots[r.Request]; ok {
+			fs.Calendars = append(fs.Calendars, r)
+		}
+	}
+	for _, r := range ratios {
+		if fs, ok := snapshots[r.Request]; ok {
+			fs.Ratios = append(fs.Ratios, r)
+		}
+	}
+	for _, r := range statistics {
+		if fs, ok := snapshots[r.Request]; ok {
+			fs.Statistics = append(fs.Statistics, r)
+		}
+	}
+
+	for _, s := range symbols {
+		if err := fr.record(snapshots[s]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fr *FundamentalsRefresher) record(fs *FundamentalsSnapshot) error {
+	if fr.Store != nil {
+		data, err := json.Marshal(fs)
+		if err != nil {
+			return err
+		}
+		if err := fr.Store.Append("fundamentals/"+fs.Symbol, Record{Time: fs.Time, Data: data}); err != nil {
+			return err
+		}
+	}
+
+	fr.mu.Lock()
+	prev := fr.last[fs.Symbol]
+	fr.last[fs.Symbol] = fs
+	fr.mu.Unlock()
+
+	if prev == nil || fr.Changes == nil {
+		return nil
+	}
+
+	prevEarnings, _ := prev.NextEarningsDate()
+	earnings, _ := fs.NextEarningsDate()
+	if prevEarnings != earnings {
+		fr.Changes(FundamentalsChange{
+			Symbol: fs.Symbol, Field: "next_earnings_date",
+			Previous: prevEarnings, Current: earnings, Time: fs.Time,
+		})
+	}
+
+	prevShares, prevOk := prev.SharesOutstanding()
+	shares, ok := fs.SharesOutstanding()
+	if prevOk != ok || prevShares != shares {
+		fr.Changes(FundamentalsChange{
+			Symbol: fs.Symbol, Field: "shares_outstanding",
+			Previous: fmt.Sprint(prevShares), Current: fmt.Sprint(shares), Time: fs.Time,
+		})
+	}
+	return nil
+}
+
+// Run refreshes every Interval until stop is closed.
+func (fr *FundamentalsRefresher) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(fr.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fr.Refresh(); err != nil && fr.Errors != nil {
+			fr.Errors(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
@@ -1,10 +1,17 @@
 module github.com/gnagel/go-tradier
 
-go 1.15
+go 1.19
 
 require (
 	github.com/cenkalti/backoff v2.0.0+incompatible
+	github.com/gorilla/websocket v1.5.3
 	github.com/pkg/errors v0.8.0
 	github.com/stretchr/testify v1.6.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/net v0.0.0-20201224014010-6772e930b67b // indirect
 )
@@ -0,0 +1,238 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Policies for sales that would be good faith violations.
+const (
+	GoodFaithIgnore = ""
+	GoodFaithWarn   = "warn"
+	GoodFaithBlock  = "block"
+)
+
+// Number of history events replayed to find purchases made with unsettled funds.
+const goodFaithHistoryLimit = 100
+
+// ErrGoodFaithViolation is returned (or passed to ClientParams.GoodFaithWarning)
+// when a sale in a cash account would sell a position bought with funds that
+// have not yet settled.
+type ErrGoodFaithViolation struct {
+	Symbol string
+	// Number of the units sold that were bought with unsettled funds.
+	Quantity  float64
+	SettlesOn time.Time
+}
+
+func (e ErrGoodFaithViolation) Error() string {
+	return fmt.Sprintf("selling %v of %v bought with funds that settle on %v would be a good faith violation",
+		e.Quantity, e.Symbol, e.SettlesOn.Format("2006-01-02"))
+}
+
+// UnsettledPurchase is a purchase paid for, at least in part, with the
+// proceeds of sales that have not yet settled. Selling it before SettlesOn
+// is a good faith violation.
+type UnsettledPurchase struct {
+	Symbol    string
+	TradeDate time.Time
+	// Number of the units purchased that were paid for with unsettled funds,
+	// and are still held.
+	Quantity  float64
+	SettlesOn time.Time
+}
+
+// Proceeds of a sale, of which remaining have not been spent.
+type unsettledProceeds struct {
+	remaining float64
+	settlesOn time.Time
+}
+
+// UnsettledPurchases replays the history of a cash account, whose total cash
+// is now cash, and returns the purchases still held that were paid for with
+// funds that are unsettled at now, ordered by trade date.
+//
+// Purchases are paid for with settled cash first, and then with the proceeds
+// of sales, earliest settling first. Trades settle on the next business day;
+// market holidays are not accounted for. Cash from before the first event
+// is assumed to be settled, so the history should cover at least the
+// settlement period.
+func UnsettledPurchases(history []*Event, cash float64, now time.Time) []UnsettledPurchase {
+	events := append([]*Event(nil), history...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date.Time) })
+
+	settled := cash
+	for _, e := range events {
+		settled -= e.Amount
+	}
+
+	var proceeds []unsettledProceeds
+	var purchases []UnsettledPurchase
+	for _, e := range events {
+		date := e.Date.Time
+		// Settle the proceeds due by the event.
+		for len(proceeds) > 0 && !proceeds[0].settlesOn.After(date) {
+			settled += proceeds[0].remaining
+			proceeds = proceeds[1:]
+		}
+
+		if e.Type != "trade" || e.Amount == 0 {
+			settled += e.Amount
+			continue
+		}
+
+		quantity := math.Abs(e.Trade.Quantity)
+		if e.Amount > 0 {
+			purchases = sellUnsettledPurchases(purchases, e.Trade.Symbol, quantity, date)
+			proceeds = append(proceeds, unsettledProceeds{remaining: e.Amount, settlesOn: settlementDate(date)})
+			continue
+		}
+
+		cost := -e.Amount
+		fromSettled := math.Min(cost, math.Max(settled, 0))
+		settled -= fromSettled
+		unpaid := cost - fromSettled
+
+		var fromUnsettled float64
+		var settlesOn time.Time
+		for i := 0; i < len(proceeds) && unpaid > 0; i++ {
+			used := math.Min(unpaid, proceeds[i].remaining)
+			if used <= 0 {
+				continue
+			}
+			proceeds[i].remaining -= used
+			unpaid -= used
+			fromUnsettled += used
+			settlesOn = proceeds[i].settlesOn
+		}
+		// Anything else is paid for by funds not in the history.
+		settled -= unpaid
+
+		if fromUnsettled > 0 {
+			purchases = append(purchases, UnsettledPurchase{
+				Symbol:    e.Trade.Symbol,
+				TradeDate: date,
+				Quantity:  quantity * fromUnsettled / cost,
+				SettlesOn: settlesOn,
+			})
+		}
+	}
+
+	var unsettled []UnsettledPurchase
+	for _, p := range purchases {
+		if p.SettlesOn.After(now) {
+			unsettled = append(unsettled, p)
+		}
+	}
+	return unsettled
+}
+
+// Remove quantity units of symbol sold on date from the purchases, first in first out.
+func sellUnsettledPurchases(purchases []UnsettledPurchase, symbol string, quantity float64, date time.Time) []UnsettledPurchase {
+	remaining := purchases[:0]
+	for _, p := range purchases {
+		if p.Symbol == symbol && quantity > 0 {
+			sold := math.Min(quantity, p.Quantity)
+			p.Quantity -= sold
+			quantity -= sold
+		}
+		if p.Quantity > 0 && p.SettlesOn.After(date) {
+			remaining = append(remaining, p)
+		}
+	}
+	return remaining
+}
+
+// Trades settle on the next business day.
+func settlementDate(tradeDate time.Time) time.Time {
+	date := tradeDate.AddDate(0, 0, 1)
+	for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// GetUnsettledPurchases returns the positions in the selected cash account that
+// were bought with unsettled funds, which cannot be sold until they settle.
+// Margin accounts have none.
+func (tc *Client) GetUnsettledPurchases() ([]UnsettledPurchase, error) {
+	balances, err := tc.GetAccountBalances()
+	if err != nil {
+		return nil, err
+	} else if !balances.IsCashAccount() {
+		return nil, nil
+	}
+
+	history, err := tc.GetAccountHistory(goodFaithHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	return UnsettledPurchases(history, balances.TotalCash, time.Now()), nil
+}
+
+// Returns the quantity of each symbol the order sells to close a position.
+func orderSales(order Order) map[string]float64 {
+	sales := make(map[string]float64)
+	switch order.Side {
+	case Sell, SellToClose:
+		sales[orderSymbol(&order)] += order.Quantity
+	}
+	for _, leg := range order.Legs {
+		for symbol, quantity := range orderSales(leg) {
+			sales[symbol] += quantity
+		}
+	}
+	return sales
+}
+
+// Check that a sale in a cash account does not sell a position bought with
+// unsettled funds, according to the good faith policy.
+func (tc *Client) checkGoodFaith(order Order) error {
+	if tc.goodFaithPolicy == GoodFaithIgnore {
+		return nil
+	}
+	sales := orderSales(order)
+	if len(sales) == 0 {
+		return nil
+	}
+
+	purchases, err := tc.GetUnsettledPurchases()
+	if err != nil {
+		return err
+	}
+
+	// Sales of several purchases of a symbol are a single violation,
+	// until the last of them settles.
+	var violations []*ErrGoodFaithViolation
+	bySymbol := make(map[string]*ErrGoodFaithViolation)
+	for _, p := range purchases {
+		if _, ok := sales[p.Symbol]; !ok {
+			continue
+		}
+		v := bySymbol[p.Symbol]
+		if v == nil {
+			v = &ErrGoodFaithViolation{Symbol: p.Symbol}
+			bySymbol[p.Symbol] = v
+			violations = append(violations, v)
+		}
+		v.Quantity += p.Quantity
+		if p.SettlesOn.After(v.SettlesOn) {
+			v.SettlesOn = p.SettlesOn
+		}
+	}
+
+	for _, v := range violations {
+		v.Quantity = math.Min(v.Quantity, sales[v.Symbol])
+		if tc.goodFaithPolicy == GoodFaithBlock {
+			return *v
+		}
+		if tc.goodFaithWarning != nil {
+			tc.goodFaithWarning(*v)
+		} else {
+			tc.logger.Warn("Good faith violation", "symbol", v.Symbol, "quantity", v.Quantity, "settles_on", v.SettlesOn.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package tradier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tradeEvent(date string, symbol string, quantity, amount float64) *Event {
+	t, _ := time.Parse("2006-01-02", date)
+	return &Event{Type: "trade", Date: DateTime{t}, Amount: amount,
+		Trade: Trade{Symbol: symbol, Quantity: quantity}}
+}
+
+func TestUnsettledPurchases(t *testing.T) {
+	// 2024-06-06 is a Thursday.
+	history := []*Event{
+		tradeEvent("2024-06-06", "SPY", -10, 5000),
+		tradeEvent("2024-06-06", "AAPL", 20, -4000),
+		tradeEvent("2024-06-07", "MSFT", 5, -2000),
+	}
+	// Starting with 3000 of settled cash, AAPL is paid for with 3000 settled
+	// and 1000 from the unsettled SPY sale, and MSFT entirely with SPY proceeds,
+	// which settle on Friday.
+	cash := 3000.0 + 5000 - 4000 - 2000
+
+	t.Run("Before settlement", func(t *testing.T) {
+		now := time.Date(2024, 6, 6, 15, 0, 0, 0, time.UTC)
+		purchases := UnsettledPurchases(history[:2], cash+2000, now)
+		settles := time.Date(2024, 6, 7, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, []UnsettledPurchase{
+			{Symbol: "AAPL", TradeDate: history[1].Date.Time, Quantity: 5, SettlesOn: settles},
+		}, purchases)
+	})
+
+	t.Run("After settlement", func(t *testing.T) {
+		now := time.Date(2024, 6, 7, 15, 0, 0, 0, time.UTC)
+		assert.Empty(t, UnsettledPurchases(history, cash, now))
+	})
+
+	t.Run("Settles after weekend", func(t *testing.T) {
+		history := []*Event{
+			tradeEvent("2024-06-07", "SPY", -10, 5000),
+			tradeEvent("2024-06-07", "MSFT", 5, -2000),
+		}
+		now := time.Date(2024, 6, 8, 12, 0, 0, 0, time.UTC)
+		purchases := UnsettledPurchases(history, 3000, now)
+		if assert.Len(t, purchases, 1) {
+			assert.Equal(t, "MSFT", purchases[0].Symbol)
+			assert.Equal(t, 5.0, purchases[0].Quantity)
+			assert.Equal(t, time.Monday, purchases[0].SettlesOn.Weekday())
+		}
+	})
+
+	t.Run("Sold purchases are removed", func(t *testing.T) {
+		history := append(history[:2:2], tradeEvent("2024-06-06", "AAPL", -20, 4100))
+		now := time.Date(2024, 6, 6, 15, 0, 0, 0, time.UTC)
+		assert.Empty(t, UnsettledPurchases(history, cash+2000+4100, now))
+	})
+}
+
+func TestClient_GoodFaithPolicy(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	today := time.Now().Format("2006-01-02")
+	var placed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/VA000/balances":
+			w.Write([]byte(`{"balances": {"account_type": "cash", "total_cash": 1000}}`))
+		case "/v1/accounts/VA000/history":
+			w.Write([]byte(`{"history": {"event": [
+				{"amount": 5000, "date": "` + today + `T00:00:00Z", "type": "trade", "trade": {"symbol": "SPY", "quantity": -10}},
+				{"amount": -4000, "date": "` + today + `T00:00:00Z", "type": "trade", "trade": {"symbol": "AAPL", "quantity": 20}},
+				{"amount": -1000, "date": "` + yesterday + `T00:00:00Z", "type": "trade", "trade": {"symbol": "MSFT", "quantity": 5}}]}}`))
+		case "/v1/accounts/VA000/orders":
+			placed++
+			w.Write([]byte(`{"order": {"id": 1, "status": "ok"}}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.Account = "VA000"
+	sellAAPL := Order{Class: Equity, Symbol: "AAPL", Side: Sell, Quantity: 20, Type: MarketOrder, Duration: Day}
+	sellMSFT := Order{Class: Equity, Symbol: "MSFT", Side: Sell, Quantity: 5, Type: MarketOrder, Duration: Day}
+
+	t.Run("Block", func(t *testing.T) {
+		params.GoodFaithPolicy = GoodFaithBlock
+		client := NewClient(params)
+		_, err := client.PlaceOrder(sellAAPL)
+		if assert.IsType(t, ErrGoodFaithViolation{}, err) {
+			assert.Equal(t, "AAPL", err.(ErrGoodFaithViolation).Symbol)
+			assert.Equal(t, 20.0, err.(ErrGoodFaithViolation).Quantity)
+		}
+		_, err = client.FastPlaceOrder(context.Background(), sellAAPL)
+		assert.IsType(t, ErrGoodFaithViolation{}, err, "FastPlaceOrder")
+
+		_, err = client.PlaceOrder(sellMSFT)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, placed)
+	})
+
+	t.Run("Warn", func(t *testing.T) {
+		var warnings []ErrGoodFaithViolation
+		params.GoodFaithPolicy = GoodFaithWarn
+		params.GoodFaithWarning = func(v ErrGoodFaithViolation) { warnings = append(warnings, v) }
+		_, err := NewClient(params).PlaceOrder(sellAAPL)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, placed)
+		if assert.Len(t, warnings, 1) {
+			assert.Equal(t, "AAPL", warnings[0].Symbol)
+		}
+	})
+}
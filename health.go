@@ -0,0 +1,101 @@
+package tradier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Health checks.
+const (
+	HealthProfile   = "profile"
+	HealthClock     = "clock"
+	HealthStreaming = "streaming"
+)
+
+// HealthCheckResult is the outcome of a single health check.
+type HealthCheckResult struct {
+	Name    string        `json:"name"`
+	Ok      bool          `json:"ok"`
+	Err     error         `json:"-"`
+	Error   string        `json:"error,omitempty"`
+	Latency time.Duration `json:"latency_ns"`
+}
+
+// HealthReport reports whether the client is ready to serve requests.
+type HealthReport struct {
+	Ready     bool                `json:"ready"`
+	Checks    []HealthCheckResult `json:"checks"`
+	CheckedAt time.Time           `json:"checked_at"`
+}
+
+// Err returns the error of the first failed check, if any.
+func (hr *HealthReport) Err() error {
+	for _, check := range hr.Checks {
+		if check.Err != nil {
+			return check.Err
+		}
+	}
+	return nil
+}
+
+// HealthCheck verifies that the client can reach Tradier: that its token is
+// valid, by requesting the user profile, and that the market clock can be
+// requested. If streaming is set, it also verifies that a market streaming
+// session can be created; such sessions count against the session creation
+// limit, so it should not be checked too often. The checks are cut short
+// when ctx is done, so it should have a deadline shorter than the probe's.
+func (tc *Client) HealthCheck(ctx context.Context, streaming bool) *HealthReport {
+	type check struct {
+		name string
+		run  func() error
+	}
+	checks := []check{
+		{HealthProfile, func() error {
+			_, err := tc.getUserProfile(ctx)
+			return err
+		}},
+		{HealthClock, func() error {
+			_, err := tc.getMarketState(ctx)
+			return err
+		}},
+	}
+	if streaming {
+		checks = append(checks, check{HealthStreaming, func() error {
+			_, err := tc.createStreamSession(ctx, "/v1/markets/events/session")
+			return err
+		}})
+	}
+
+	report := &HealthReport{Ready: true, CheckedAt: time.Now()}
+	for _, c := range checks {
+		start := time.Now()
+		err := c.run()
+		result := HealthCheckResult{Name: c.name, Ok: err == nil, Err: err, Latency: time.Since(start)}
+		if err != nil {
+			result.Error = err.Error()
+			report.Ready = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+// HealthHandler returns an http.Handler for readiness probes, e.g. of
+// Kubernetes. It runs HealthCheck with the given timeout on every request,
+// and responds with the report as JSON, with status 200 if the client is
+// ready and 503 otherwise.
+func (tc *Client) HealthHandler(timeout time.Duration, streaming bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := withTimeout(r.Context(), timeout)
+		defer cancel()
+		report := tc.HealthCheck(ctx, streaming)
+
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
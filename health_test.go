@@ -0,0 +1,65 @@
+package tradier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_HealthCheck(t *testing.T) {
+	streamingDown := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/user/profile":
+			w.Write([]byte(`{"profile": {"id": "id-1", "name": "Jane", "account": {"account_number": "VA000"}}}`))
+		case "/v1/markets/clock":
+			w.Write([]byte(`{"clock": {"date": "2021-01-04", "state": "open"}}`))
+		case "/v1/markets/events/session":
+			if streamingDown {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`{"stream": {"url": "wss://example.com", "sessionid": "abc"}}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.RetryLimit = 0
+	client := NewClient(params)
+
+	report := client.HealthCheck(context.Background(), true)
+	assert.True(t, report.Ready)
+	assert.NoError(t, report.Err())
+	if assert.Len(t, report.Checks, 3) {
+		assert.Equal(t, HealthStreaming, report.Checks[2].Name)
+	}
+	assert.Len(t, client.HealthCheck(context.Background(), false).Checks, 2)
+
+	t.Run("Not ready", func(t *testing.T) {
+		streamingDown = true
+		defer func() { streamingDown = false }()
+
+		rec := httptest.NewRecorder()
+		client.HealthHandler(time.Second, true).ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+		var decoded HealthReport
+		if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &decoded)) && assert.Len(t, decoded.Checks, 3) {
+			assert.False(t, decoded.Ready)
+			assert.True(t, decoded.Checks[0].Ok)
+			assert.False(t, decoded.Checks[2].Ok)
+			assert.NotEmpty(t, decoded.Checks[2].Error)
+		}
+
+		rec = httptest.NewRecorder()
+		client.HealthHandler(time.Second, false).ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
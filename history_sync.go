@@ -0,0 +1,130 @@
+package tradier
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+)
+
+// Events requested per page by SyncHistory.
+const historySyncPageSize = 1000
+
+// HistoryCursor records how far the account history has been synced.
+// Tradier's events have no ids, and the history can only be requested from
+// a date, so the events of the last date are identified by their contents.
+type HistoryCursor struct {
+	// Date of the last synced events, or zero if none have been synced.
+	Date time.Time
+	// Number of events synced on Date with each key, as by HistoryEventKey.
+	// Identical events on the same day, such as two fills of the same size
+	// at the same price, are counted separately.
+	Seen map[string]int
+}
+
+// HistoryStore persists the events synced by SyncHistory, and the cursor
+// from which to continue.
+type HistoryStore interface {
+	// HistoryCursor returns the cursor saved with the last events, or the
+	// zero cursor if none have been saved.
+	HistoryCursor() (HistoryCursor, error)
+	// SaveHistory saves new events, oldest first, with the cursor after them.
+	// It should save both or neither, so that events are not synced twice.
+	SaveHistory(events []*Event, cursor HistoryCursor) error
+}
+
+// HistoryEventKey returns a key identifying the contents of an event.
+func HistoryEventKey(event *Event) string {
+	data, _ := json.Marshal(event)
+	h := fnv.New64a()
+	h.Write(data)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// SyncHistory saves the events of the selected account that have not been
+// saved in store yet, and returns the number saved. Only events on or after
+// the date of the cursor are downloaded, and those already counted by the
+// cursor are skipped.
+func (tc *Client) SyncHistory(store HistoryStore) (int, error) {
+	if tc.account == "" {
+		return 0, ErrNoAccountSelected
+	}
+	cursor, err := store.HistoryCursor()
+	if err != nil {
+		return 0, err
+	}
+
+	var events []*Event
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/v1/accounts/%s/history?limit=%d&page=%d", tc.endpoint, tc.account, historySyncPageSize, page)
+		if !cursor.Date.IsZero() {
+			url += "&start=" + cursor.Date.Format("2006-01-02")
+		}
+		var result struct {
+			History nullable[struct {
+				Event oneOrMany[*Event]
+			}]
+		}
+		if err := tc.getJSON(url, &result); err != nil {
+			return 0, err
+		}
+		events = append(events, result.History.Value.Event...)
+		if len(result.History.Value.Event) < historySyncPageSize {
+			break
+		}
+	}
+
+	events, cursor = newHistoryEvents(events, cursor)
+	if len(events) == 0 {
+		return 0, nil
+	}
+	if err := store.SaveHistory(events, cursor); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// newHistoryEvents returns the events not yet synced by cursor, oldest first,
+// and the cursor after them.
+func newHistoryEvents(events []*Event, cursor HistoryCursor) ([]*Event, HistoryCursor) {
+	// Tradier lists the newest events first, so they are sorted newest first
+	// and reversed, keeping the order of the events of each day.
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventDate(events[i]).After(eventDate(events[j]))
+	})
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	seen := make(map[string]int, len(cursor.Seen))
+	for key, n := range cursor.Seen {
+		seen[key] = n
+	}
+	next := HistoryCursor{Date: cursor.Date, Seen: make(map[string]int)}
+	var fresh []*Event
+	for _, event := range events {
+		date := eventDate(event)
+		if !cursor.Date.IsZero() && date.Before(cursor.Date) {
+			continue
+		}
+		key := HistoryEventKey(event)
+		if date.Equal(cursor.Date) && seen[key] > 0 {
+			seen[key]--
+			next.Seen[key]++
+			continue
+		}
+		if !date.Equal(next.Date) {
+			next = HistoryCursor{Date: date, Seen: make(map[string]int)}
+		}
+		next.Seen[key]++
+		fresh = append(fresh, event)
+	}
+	return fresh, next
+}
+
+// The day of an event, which is the precision of the history's start date.
+func eventDate(event *Event) time.Time {
+	y, m, d := event.Date.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,95 @@
+package tradier
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryHistoryStore keeps synced events in memory.
+type memoryHistoryStore struct {
+	events []*Event
+	cursor HistoryCursor
+}
+
+func (s *memoryHistoryStore) HistoryCursor() (HistoryCursor, error) {
+	return s.cursor, nil
+}
+
+func (s *memoryHistoryStore) SaveHistory(events []*Event, cursor HistoryCursor) error {
+	s.events = append(s.events, events...)
+	s.cursor = cursor
+	return nil
+}
+
+func TestClient_SyncHistory(t *testing.T) {
+	// Events newest first, as Tradier lists them.
+	events := []string{
+		`{"amount": -500, "date": "2021-03-02T00:00:00Z", "type": "trade", "trade": {"symbol": "SPY", "quantity": 1, "price": 500}}`,
+		`{"amount": -500, "date": "2021-03-02T00:00:00Z", "type": "trade", "trade": {"symbol": "SPY", "quantity": 1, "price": 500}}`,
+		`{"amount": 1000, "date": "2021-03-01T00:00:00Z", "type": "ach"}`,
+	}
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		starts = append(starts, r.FormValue("start"))
+		var listed []string
+		for _, e := range events {
+			if start := r.FormValue("start"); start == "" || e[strings.Index(e, `"date": "`)+9:][:10] >= start {
+				listed = append(listed, e)
+			}
+		}
+		fmt.Fprintf(w, `{"history": {"event": [%s]}}`, strings.Join(listed, ","))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("secret-token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	client := NewClient(params)
+	store := &memoryHistoryStore{}
+
+	n, err := client.SyncHistory(store)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "ach", store.events[0].Type, "oldest first")
+	assert.Equal(t, time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), store.cursor.Date)
+	assert.Equal(t, []string{""}, starts)
+
+	n, err = client.SyncHistory(store)
+	assert.NoError(t, err)
+	assert.Zero(t, n, "identical events already synced are skipped")
+	assert.Equal(t, "2021-03-02", starts[1])
+
+	// A third identical fill on the same day, and an event on a later day.
+	events = append([]string{
+		`{"amount": 12.5, "date": "2021-03-03T00:00:00Z", "type": "dividend"}`,
+		`{"amount": -500, "date": "2021-03-02T00:00:00Z", "type": "trade", "trade": {"symbol": "SPY", "quantity": 1, "price": 500}}`,
+	}, events...)
+	n, err = client.SyncHistory(store)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	if assert.Len(t, store.events, 5) {
+		assert.Equal(t, "trade", store.events[3].Type)
+		assert.Equal(t, "dividend", store.events[4].Type)
+	}
+	assert.Equal(t, HistoryCursor{
+		Date: time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC),
+		Seen: map[string]int{HistoryEventKey(store.events[4]): 1},
+	}, store.cursor)
+
+	t.Run("Empty history", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"history": "null"}`))
+		}))
+		defer server.Close()
+		params.Endpoint = server.URL
+		n, err := NewClient(params).SyncHistory(&memoryHistoryStore{})
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+	})
+}
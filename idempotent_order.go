@@ -0,0 +1,132 @@
+package tradier
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AmbiguousOrderError is returned by PlaceOrderIdempotent if the order may
+// have been placed, but it could not be found among the account's orders.
+// The order can be looked up later by its tag.
+type AmbiguousOrderError struct {
+	Tag string
+	// Error of the last attempt to place the order, or of looking it up.
+	Err error
+}
+
+func (e *AmbiguousOrderError) Error() string {
+	return fmt.Sprintf("order %v may have been placed: %v", e.Tag, e.Err)
+}
+
+func (e *AmbiguousOrderError) Unwrap() error {
+	return e.Err
+}
+
+// PlaceOrderIdempotent places the order at most once. The order is tagged
+// with a unique tag, unless it already has one, which must then be unique
+// among the account's orders of the day. If placing the order fails in a way
+// that leaves its outcome unknown, such as a timeout after the request was
+// sent or a 5xx response, the account's orders are checked for the tag after
+// the wait of the retry policy: the order is resubmitted only if it is not
+// found, up to ClientParams.RetryLimit times. An order found this way is
+// returned with Recovered set.
+//
+// If the order may have been placed but was not found, an
+// *AmbiguousOrderError with its tag is returned.
+func (tc *Client) PlaceOrderIdempotent(ctx context.Context, order Order) (*OrderResult, error) {
+	if order.Tag == "" {
+		tag, err := newOrderTag()
+		if err != nil {
+			return nil, err
+		}
+		order.Tag = tag
+	}
+	if err := tc.checkPlaceOrder(order); err != nil {
+		return nil, err
+	}
+	form, err := orderToParams(order, tc.priceDecimals)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; ; i++ {
+		result, err := tc.submitOrder(ctx, form)
+		if err == nil || (result != nil && result.Status != "") || !orderMayBePlaced(err) {
+			return result, err
+		}
+		tc.logger.Warn("Order may have been placed", "tag", order.Tag, "attempt", i+1, "error", err)
+		if ctx.Err() != nil {
+			return nil, &AmbiguousOrderError{Tag: order.Tag, Err: err}
+		}
+
+		// The wait before looking the order up gives Tradier time to list it.
+		attempt := RetryAttempt{Method: "POST", Category: CategoryTrading, Idempotent: true, Attempt: i + 1, Err: err}
+		var te TradierError
+		if errors.As(err, &te) {
+			attempt.StatusCode, attempt.Header = te.HttpStatusCode, te.Header
+		}
+		wait, retry := tc.retry.Retry(attempt)
+		retry = retry && i < tc.retryLimit
+		if retry {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return nil, &AmbiguousOrderError{Tag: order.Tag, Err: err}
+			}
+		}
+
+		placed, lookupErr := tc.findOrderByTag(ctx, order.Tag)
+		if lookupErr != nil {
+			return nil, &AmbiguousOrderError{Tag: order.Tag, Err: lookupErr}
+		} else if placed != nil {
+			result := &OrderResult{Id: placed.Id, Status: StatusOK, Recovered: true}
+			if placed.Status == Rejected {
+				result.Status = Rejected
+			}
+			return result, result.err()
+		} else if !retry {
+			return nil, &AmbiguousOrderError{Tag: order.Tag, Err: err}
+		}
+		tc.logger.Info("Resubmitting order that was not placed", "tag", order.Tag)
+	}
+}
+
+// Returns true if the error of placing an order does not rule out that it
+// was placed: the request was sent, and no response or a 5xx response was
+// received.
+func orderMayBePlaced(err error) bool {
+	var te TradierError
+	if errors.As(err, &te) {
+		return te.HttpStatusCode >= 500
+	}
+	return RetryAttempt{Err: err}.Sent()
+}
+
+// Returns the order of the day with the tag, or nil if there is none. Tags
+// are only listed if requested, and every page is read from Tradier rather
+// than the memo, which may predate the order.
+func (tc *Client) findOrderByTag(ctx context.Context, tag string) (*Order, error) {
+	orders, err := tc.getOrders(ctx, OrdersParams{IncludeTags: true}, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		if order.Tag == tag {
+			return order, nil
+		}
+	}
+	return nil, nil
+}
+
+// Returns a random tag for an order.
+func newOrderTag() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "idem-" + hex.EncodeToString(b), nil
+}
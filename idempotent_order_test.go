@@ -0,0 +1,209 @@
+package tradier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOrderServer places orders, failing each POST as told by fail. Like
+// Tradier, it lists orders page by page, with their tags only if requested.
+type fakeOrderServer struct {
+	// Called with the number of each POST. Returns true if the order is
+	// placed, and the status to fail with, or zero to respond.
+	fail func(post int) (placed bool, status int, delay time.Duration)
+
+	mu    sync.Mutex
+	posts int
+	tags  []string
+	// Tags of the orders placed, including any placed before the test.
+	placed []string
+}
+
+// Orders listed per page by fakeOrderServer.
+const fakeOrdersPageSize = 2
+
+func (s *fakeOrderServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		page, _ := strconv.Atoi(r.FormValue("page"))
+		if page == 0 {
+			page = 1
+		}
+		s.mu.Lock()
+		var orders []string
+		for i, tag := range s.placed {
+			if i/fakeOrdersPageSize != page-1 {
+				continue
+			}
+			order := `{"id": ` + strconv.Itoa(i+1) + `, "status": "open"`
+			if r.FormValue("includeTags") == "true" {
+				order += `, "tag": "` + tag + `"`
+			}
+			orders = append(orders, order+"}")
+		}
+		s.mu.Unlock()
+		if len(orders) == 0 {
+			w.Write([]byte(`{"orders": "null"}`))
+			return
+		}
+		w.Write([]byte(`{"orders": {"order": [` + strings.Join(orders, ",") + `]}}`))
+		return
+	}
+
+	s.mu.Lock()
+	s.posts++
+	tag := r.FormValue("tag")
+	s.tags = append(s.tags, tag)
+	placed, status, delay := s.fail(s.posts)
+	if placed {
+		s.placed = append(s.placed, tag)
+	}
+	id := len(s.placed)
+	s.mu.Unlock()
+
+	time.Sleep(delay)
+	if status != 0 {
+		w.WriteHeader(status)
+		w.Write([]byte("Bad Gateway"))
+		return
+	}
+	w.Write([]byte(`{"order": {"id": ` + strconv.Itoa(id) + `, "status": "ok"}}`))
+}
+
+func newIdempotentTestClient(url string, retryLimit int) *Client {
+	return NewClient(newIdempotentTestParams(url, retryLimit))
+}
+
+func newIdempotentTestParams(url string, retryLimit int) ClientParams {
+	params := DefaultParams("token")
+	params.Endpoint = url
+	params.Account = "VA000"
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.Backoff = backoff.NewConstantBackOff(time.Millisecond)
+	params.RetryLimit = retryLimit
+	params.Timeouts = RequestTimeouts{Categories: map[EndpointCategory]time.Duration{CategoryTrading: 50 * time.Millisecond}}
+	return params
+}
+
+func TestClient_PlaceOrderIdempotent(t *testing.T) {
+	order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day}
+
+	t.Run("Placed", func(t *testing.T) {
+		fake := &fakeOrderServer{fail: func(post int) (bool, int, time.Duration) { return true, 0, 0 }}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		result, err := newIdempotentTestClient(server.URL, 2).PlaceOrderIdempotent(context.Background(), order)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, result.Id)
+			assert.False(t, result.Recovered)
+		}
+		if assert.Len(t, fake.tags, 1) {
+			assert.True(t, validOrderTag(fake.tags[0]), fake.tags[0])
+		}
+	})
+
+	t.Run("TimeoutAfterPlacing", func(t *testing.T) {
+		fake := &fakeOrderServer{fail: func(post int) (bool, int, time.Duration) { return true, 0, 200 * time.Millisecond }}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		tagged := order
+		tagged.Tag = "exit-1"
+		result, err := newIdempotentTestClient(server.URL, 2).PlaceOrderIdempotent(context.Background(), tagged)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, result.Id)
+			assert.Equal(t, StatusOK, result.Status)
+			assert.True(t, result.Recovered)
+		}
+		assert.Equal(t, []string{"exit-1"}, fake.tags, "the order is not resubmitted")
+	})
+
+	t.Run("TimeoutAfterPlacingOnLaterPage", func(t *testing.T) {
+		fake := &fakeOrderServer{
+			fail:   func(post int) (bool, int, time.Duration) { return true, 0, 200 * time.Millisecond },
+			placed: []string{"entry-1", "entry-2", "entry-3"},
+		}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		// Orders listed before placing are memoized, without the order.
+		params := newIdempotentTestParams(server.URL, 2)
+		params.MemoizeWindow = time.Minute
+		client := NewClient(params)
+		orders, err := client.GetOrders(OrdersParams{IncludeTags: true})
+		assert.NoError(t, err)
+		assert.Len(t, orders, 3)
+
+		tagged := order
+		tagged.Tag = "exit-1"
+		result, err := client.PlaceOrderIdempotent(context.Background(), tagged)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 4, result.Id)
+			assert.True(t, result.Recovered)
+		}
+		assert.Equal(t, []string{"exit-1"}, fake.tags, "the order is not resubmitted")
+	})
+
+	t.Run("NotPlaced", func(t *testing.T) {
+		fake := &fakeOrderServer{fail: func(post int) (bool, int, time.Duration) {
+			if post == 1 {
+				return false, http.StatusBadGateway, 0
+			}
+			return true, 0, 0
+		}}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		result, err := newIdempotentTestClient(server.URL, 2).PlaceOrderIdempotent(context.Background(), order)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, result.Id)
+			assert.False(t, result.Recovered)
+		}
+		if assert.Len(t, fake.tags, 2, "the order is resubmitted") {
+			assert.Equal(t, fake.tags[0], fake.tags[1], "with the same tag")
+		}
+	})
+
+	t.Run("Ambiguous", func(t *testing.T) {
+		fake := &fakeOrderServer{fail: func(post int) (bool, int, time.Duration) { return false, http.StatusBadGateway, 0 }}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		tagged := order
+		tagged.Tag = "exit-2"
+		_, err := newIdempotentTestClient(server.URL, 1).PlaceOrderIdempotent(context.Background(), tagged)
+		var ambiguous *AmbiguousOrderError
+		if assert.True(t, errors.As(err, &ambiguous), err) {
+			assert.Equal(t, "exit-2", ambiguous.Tag)
+			var te TradierError
+			assert.True(t, errors.As(err, &te))
+			assert.Equal(t, http.StatusBadGateway, te.HttpStatusCode)
+		}
+		assert.Len(t, fake.tags, 2, "resubmitted up to the retry limit")
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": {"error": "Backoffice rejected override of the order."}}`))
+		}))
+		defer server.Close()
+
+		_, err := newIdempotentTestClient(server.URL, 2).PlaceOrderIdempotent(context.Background(), order)
+		var te TradierError
+		assert.True(t, errors.As(err, &te), err)
+		var ambiguous *AmbiguousOrderError
+		assert.False(t, errors.As(err, &ambiguous))
+	})
+}
@@ -0,0 +1,113 @@
+package tradier
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ServiceIdentityHeader is the header that carries ClientParams.ServiceIdentity,
+// so that proxies in front of Tradier can attribute requests to services.
+const ServiceIdentityHeader = "X-Service-Identity"
+
+// IdentityUsage is the usage of the rate limit of a category by a service.
+type IdentityUsage struct {
+	// Requests sent, including retries.
+	Requests int
+	// Requests sent in the current rate limit window, as last reported.
+	WindowRequests int
+	WindowExpiry   time.Time
+	// Share of the requests used in the current window that were sent by
+	// the service, once a response has reported the window's usage.
+	WindowShare float64
+	// Responses that reported a rate limit or quota violation.
+	RateLimited int
+}
+
+// UsageLedger attributes the requests of clients that share a token to the
+// services that sent them, by the ServiceIdentity of each client. A ledger
+// is shared by passing it to each client as ClientParams.Usage.
+type UsageLedger struct {
+	mu    sync.Mutex
+	usage map[string]map[EndpointCategory]*IdentityUsage
+	// Requests used in each category's window, as last reported by Tradier.
+	used map[EndpointCategory]int
+}
+
+func NewUsageLedger() *UsageLedger {
+	return &UsageLedger{
+		usage: make(map[string]map[EndpointCategory]*IdentityUsage),
+		used:  make(map[EndpointCategory]int),
+	}
+}
+
+// Record a request sent by a client with the identity, and its response,
+// if it received one.
+func (ul *UsageLedger) record(identity string, category EndpointCategory, resp *http.Response) {
+	ul.mu.Lock()
+	defer ul.mu.Unlock()
+	if ul.usage == nil {
+		ul.usage = make(map[string]map[EndpointCategory]*IdentityUsage)
+		ul.used = make(map[EndpointCategory]int)
+	}
+	categories := ul.usage[identity]
+	if categories == nil {
+		categories = make(map[EndpointCategory]*IdentityUsage)
+		ul.usage[identity] = categories
+	}
+	u := categories[category]
+	if u == nil {
+		u = &IdentityUsage{}
+		categories[category] = u
+	}
+	u.Requests++
+	if resp == nil {
+		u.WindowRequests++
+		return
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		u.RateLimited++
+	}
+	expiry, err := ParseTimeMs(resp.Header.Get(rateLimitExpiry))
+	if err != nil {
+		u.WindowRequests++
+		return
+	}
+	// Each identity's count restarts with the first response in a new window.
+	for _, other := range ul.usage {
+		if o := other[category]; o != nil && o.WindowExpiry.Before(expiry) {
+			o.WindowRequests, o.WindowExpiry = 0, expiry
+		}
+	}
+	u.WindowRequests++
+	if used, err := strconv.Atoi(resp.Header.Get(rateLimitUsed)); err == nil {
+		ul.used[category] = used
+	}
+}
+
+// Usage returns the usage of each category by each service identity.
+// Clients without an identity are attributed to the empty identity.
+func (ul *UsageLedger) Usage() map[string]map[EndpointCategory]IdentityUsage {
+	ul.mu.Lock()
+	defer ul.mu.Unlock()
+	result := make(map[string]map[EndpointCategory]IdentityUsage, len(ul.usage))
+	for identity, categories := range ul.usage {
+		result[identity] = make(map[EndpointCategory]IdentityUsage, len(categories))
+		for category, u := range categories {
+			usage := *u
+			if used := ul.used[category]; used > 0 {
+				usage.WindowShare = float64(usage.WindowRequests) / float64(used)
+			}
+			result[identity][category] = usage
+		}
+	}
+	return result
+}
+
+// ServiceIdentity returns the identity that the client attaches to its
+// requests, or empty if it has none.
+func (tc *Client) ServiceIdentity() string {
+	return tc.identity
+}
@@ -0,0 +1,64 @@
+package tradier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageLedger(t *testing.T) {
+	var mu sync.Mutex
+	used := 0
+	identities := make(map[string]int)
+	expiry := time.Now().Add(time.Minute).UnixNano() / int64(time.Millisecond)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		used++
+		identities[r.Header.Get(ServiceIdentityHeader)]++
+		w.Header().Set(rateLimitAllowed, "120")
+		w.Header().Set(rateLimitUsed, strconv.Itoa(used))
+		w.Header().Set(rateLimitAvailable, strconv.Itoa(120-used))
+		w.Header().Set(rateLimitExpiry, strconv.FormatInt(expiry, 10))
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/markets/events/session":
+			w.Write([]byte(`{"stream": {"url": "wss://example.com", "sessionid": "abc"}}`))
+		default:
+			w.Write([]byte(`{"quotes": {"quote": {"symbol": "SPY", "last": 400}}}`))
+		}
+	}))
+	defer server.Close()
+
+	ledger := NewUsageLedger()
+	newClient := func(identity string) *Client {
+		params := DefaultParams("token")
+		params.Endpoint = server.URL
+		params.ServiceIdentity = identity
+		params.Usage = ledger
+		return NewClient(params)
+	}
+	scanner, trader := newClient("scanner"), newClient("trader")
+	assert.Equal(t, "scanner", scanner.ServiceIdentity())
+
+	for i := 0; i < 3; i++ {
+		_, err := scanner.GetQuotes([]string{"SPY"})
+		assert.NoError(t, err)
+	}
+	_, err := trader.GetQuotes([]string{"SPY"})
+	assert.NoError(t, err)
+	_, err = trader.createStreamSession(context.Background(), "/v1/markets/events/session")
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"scanner": 3, "trader": 2}, identities)
+	usage := ledger.Usage()
+	assert.Equal(t, 3, usage["scanner"][CategoryMarketData].Requests)
+	assert.InDelta(t, 0.75, usage["scanner"][CategoryMarketData].WindowShare, 1e-9)
+	assert.Equal(t, 1, usage["trader"][CategoryMarketData].Requests)
+	assert.Equal(t, 1, usage["trader"][CategoryStreaming].Requests)
+}
@@ -0,0 +1,198 @@
+package tradier
+
+import (
+	"math"
+	"time"
+)
+
+// Exercise styles of option contracts.
+const (
+	AmericanExercise = "american"
+	EuropeanExercise = "european"
+)
+
+// Settlement times of option contracts. AM-settled contracts stop trading the
+// business day before expiration, and settle on the opening prices of the
+// expiration day.
+const (
+	AMSettlement = "AM"
+	PMSettlement = "PM"
+)
+
+// ContractStyle describes how an option contract is exercised and settled.
+type ContractStyle struct {
+	// One of AmericanExercise or EuropeanExercise.
+	Exercise string
+	// Cash-settled contracts pay their intrinsic value in cash rather than
+	// delivering shares of the underlying.
+	CashSettled bool
+	// One of AMSettlement or PMSettlement.
+	Settlement string
+}
+
+// Equity and ETF options are American-style, delivering shares at the close.
+var equityOptionStyle = ContractStyle{Exercise: AmericanExercise, Settlement: PMSettlement}
+
+// Styles of the index options listed by their OCC root symbol. Weekly and
+// end of month series of an index are listed under a different root than
+// its standard monthly series, and settle at a different time.
+var indexOptionStyles = map[string]ContractStyle{
+	"SPX":   {Exercise: EuropeanExercise, CashSettled: true, Settlement: AMSettlement},
+	"SPXW":  {Exercise: EuropeanExercise, CashSettled: true, Settlement: PMSettlement},
+	"SPXPM": {Exercise: EuropeanExercise, CashSettled: true, Settlement: PMSettlement},
+	"XSP":   {Exercise: EuropeanExercise, CashSettled: true, Settlement: PMSettlement},
+	"NDX":   {Exercise: EuropeanExercise, CashSettled: true, Settlement: AMSettlement},
+	"NDXP":  {Exercise: EuropeanExercise, CashSettled: true, Settlement: PMSettlement},
+	"XND":   {Exercise: EuropeanExercise, CashSettled: true, Settlement: PMSettlement},
+	"RUT":   {Exercise: EuropeanExercise, CashSettled: true, Settlement: AMSettlement},
+	"RUTW":  {Exercise: EuropeanExercise, CashSettled: true, Settlement: PMSettlement},
+	"MRUT":  {Exercise: EuropeanExercise, CashSettled: true, Settlement: PMSettlement},
+	"DJX":   {Exercise: EuropeanExercise, CashSettled: true, Settlement: AMSettlement},
+	"VIX":   {Exercise: EuropeanExercise, CashSettled: true, Settlement: AMSettlement},
+	"VIXW":  {Exercise: EuropeanExercise, CashSettled: true, Settlement: AMSettlement},
+	"XEO":   {Exercise: EuropeanExercise, CashSettled: true, Settlement: PMSettlement},
+	"OEX":   {Exercise: AmericanExercise, CashSettled: true, Settlement: PMSettlement},
+}
+
+// Indexes of the roots above that are not the symbol of their index.
+var indexOptionUnderlyings = map[string]string{
+	"SPXW":  "SPX",
+	"SPXPM": "SPX",
+	"NDXP":  "NDX",
+	"RUTW":  "RUT",
+	"VIXW":  "VIX",
+}
+
+// Returns the symbol of the underlying of the option contracts with an OCC
+// root symbol, e.g. "SPX" for "SPXW".
+func optionRootUnderlying(root string) string {
+	if underlying, ok := indexOptionUnderlyings[root]; ok {
+		return underlying
+	}
+	return root
+}
+
+// OptionStyle returns the style of the option contracts with an OCC root
+// symbol, e.g. "SPXW". Roots other than the listed index options are assumed
+// to be equity or ETF options.
+func OptionStyle(root string) ContractStyle {
+	if style, ok := indexOptionStyles[root]; ok {
+		return style
+	}
+	return equityOptionStyle
+}
+
+// Style returns the style of the contract.
+func (os OptionSymbol) Style() ContractStyle {
+	return OptionStyle(os.Underlying)
+}
+
+// Style returns the style of the contract of an option quote, by the root
+// symbol of the option chain if it has one, or else by its OCC symbol.
+func (q *Quote) Style() ContractStyle {
+	if q.RootSymbol != "" {
+		return OptionStyle(q.RootSymbol)
+	}
+	if contract, err := ParseOptionSymbol(q.Symbol); err == nil {
+		return contract.Style()
+	}
+	return equityOptionStyle
+}
+
+// EarlyExercise returns true if contracts of the style may be exercised, and
+// so assigned, before expiration.
+func (cs ContractStyle) EarlyExercise() bool {
+	return cs.Exercise != EuropeanExercise
+}
+
+// LastTradingDay returns the last day on which contracts of the style that
+// expire on expiration trade: the business day before expiration for
+// AM-settled contracts, and the expiration date otherwise. Market holidays
+// are not accounted for.
+func (cs ContractStyle) LastTradingDay(expiration time.Time) time.Time {
+	if cs.Settlement != AMSettlement {
+		return expiration
+	}
+	date := expiration.AddDate(0, 0, -1)
+	for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// Intrinsic returns the value of the contract per unit of the underlying with
+// the underlying at price, which is its settlement value at expiration.
+func (os OptionSymbol) Intrinsic(price float64) float64 {
+	if os.OptionType == Put {
+		return math.Max(os.Strike-price, 0)
+	}
+	return math.Max(price-os.Strike, 0)
+}
+
+// AssignmentRisk returns true if a short position in the contract of an option
+// quote is at risk of assignment at now, with the underlying at price. Only
+// contracts in the money are at risk: European-style contracts from their last
+// trading day, since they are exercised at expiration, and American-style
+// contracts also before, once their time value (at the bid) is at most
+// maxTimeValue per unit.
+func AssignmentRisk(q *Quote, price, maxTimeValue float64, now time.Time) bool {
+	contract, err := ParseOptionSymbol(q.Symbol)
+	if err != nil {
+		return false
+	}
+	intrinsic := contract.Intrinsic(price)
+	if intrinsic <= 0 {
+		return false
+	}
+
+	style := q.Style()
+	lastDay := style.LastTradingDay(contract.Expiration)
+	today := now.In(marketTimezone)
+	if !time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC).Before(lastDay) {
+		return true
+	}
+	return style.EarlyExercise() && q.Bid-intrinsic <= maxTimeValue
+}
+
+// Settlement is the result of holding option legs through expiration.
+type Settlement struct {
+	// Cash received from the legs, negative if paid: the intrinsic value of
+	// cash-settled contracts and the strike of shares delivered by
+	// physically settled contracts.
+	Cash float64
+	// Shares of the underlying delivered, negative if sold.
+	Shares float64
+}
+
+// Value returns the value of the settlement with the underlying at price.
+func (s Settlement) Value(price float64) float64 {
+	return s.Cash + s.Shares*price
+}
+
+// SettleAt returns the settlement of the option legs of the strategy that
+// expire by expiration, with the underlying settling at price. In the money
+// contracts are exercised: cash-settled contracts pay their intrinsic value,
+// and physically settled contracts deliver shares at the strike. The stock leg
+// of a covered call and legs expiring later are not included.
+func (s Strategy) SettleAt(expiration time.Time, price float64) Settlement {
+	var settlement Settlement
+	for _, leg := range s.Legs {
+		contract := leg.Contract
+		if contract.Underlying == "" || contract.Expiration.After(expiration) || contract.Intrinsic(price) <= 0 {
+			continue
+		}
+		units := leg.Quantity * contractMultiplier(leg.Position.Symbol)
+		if contract.Style().CashSettled {
+			settlement.Cash += contract.Intrinsic(price) * units
+			continue
+		}
+		// Calls deliver stock to the long side, puts to the short side.
+		shares := units
+		if contract.OptionType == Put {
+			shares = -shares
+		}
+		settlement.Shares += shares
+		settlement.Cash -= shares * contract.Strike
+	}
+	return settlement
+}
@@ -0,0 +1,93 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionStyle(t *testing.T) {
+	assert.Equal(t, ContractStyle{Exercise: EuropeanExercise, CashSettled: true, Settlement: AMSettlement}, OptionStyle("SPX"))
+	assert.Equal(t, PMSettlement, OptionStyle("SPXW").Settlement)
+	assert.Equal(t, ContractStyle{Exercise: AmericanExercise, Settlement: PMSettlement}, OptionStyle("SPY"))
+	assert.True(t, OptionStyle("OEX").EarlyExercise())
+	assert.False(t, OptionStyle("RUT").EarlyExercise())
+
+	t.Run("Chain root symbol", func(t *testing.T) {
+		q := &Quote{Symbol: "SPXW210219P03800000", Underlying: "SPX", RootSymbol: "SPXW"}
+		assert.Equal(t, PMSettlement, q.Style().Settlement)
+		q = &Quote{Symbol: "SPX210219P03800000", Underlying: "SPX"}
+		assert.Equal(t, AMSettlement, q.Style().Settlement)
+	})
+}
+
+func TestContractStyle_LastTradingDay(t *testing.T) {
+	friday := time.Date(2021, time.February, 19, 0, 0, 0, 0, time.UTC)
+	monday := time.Date(2021, time.February, 22, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, friday, OptionStyle("SPY").LastTradingDay(friday))
+	assert.Equal(t, friday, OptionStyle("SPXW").LastTradingDay(friday))
+	assert.Equal(t, friday.AddDate(0, 0, -1), OptionStyle("SPX").LastTradingDay(friday))
+	assert.Equal(t, friday, OptionStyle("SPX").LastTradingDay(monday))
+}
+
+func TestAssignmentRisk(t *testing.T) {
+	before := time.Date(2021, time.February, 10, 15, 0, 0, 0, marketTimezone)
+	spy := &Quote{Symbol: "SPY210219P00400000", Bid: 20.02}
+	spx := &Quote{Symbol: "SPX210219P04000000", RootSymbol: "SPX", Bid: 200.05}
+
+	assert.True(t, AssignmentRisk(spy, 380, 0.05, before), "deep in the money without time value")
+	assert.False(t, AssignmentRisk(spy, 410, 0.05, before), "out of the money")
+	assert.False(t, AssignmentRisk(spy, 385, 0.05, before), "time value remains")
+	assert.False(t, AssignmentRisk(spx, 3800, 0.05, before), "European-style")
+
+	lastDay := time.Date(2021, time.February, 18, 15, 0, 0, 0, marketTimezone)
+	assert.True(t, AssignmentRisk(spx, 3800, 0.05, lastDay), "AM settlement on the next open")
+	assert.False(t, AssignmentRisk(spx, 4100, 0.05, lastDay))
+}
+
+func TestStrategy_SettleAt(t *testing.T) {
+	expiration := time.Date(2021, time.February, 19, 0, 0, 0, 0, time.UTC)
+	spread := func(root string, short, long float64) Strategy {
+		shortLeg, _ := ParseOptionSymbol(OptionSymbol{Underlying: root, Expiration: expiration, OptionType: Put, Strike: short}.String())
+		longLeg, _ := ParseOptionSymbol(OptionSymbol{Underlying: root, Expiration: expiration, OptionType: Put, Strike: long}.String())
+		return Strategy{Name: Vertical, Underlying: root, Quantity: 1, Legs: []StrategyLeg{
+			{Position: &Position{Symbol: shortLeg.String(), Quantity: -1}, Contract: shortLeg, Quantity: -1},
+			{Position: &Position{Symbol: longLeg.String(), Quantity: 1}, Contract: longLeg, Quantity: 1},
+		}}
+	}
+
+	t.Run("Physically settled", func(t *testing.T) {
+		settlement := spread("SPY", 400, 390).SettleAt(expiration, 395)
+		assert.Equal(t, Settlement{Cash: -40000, Shares: 100}, settlement)
+		assert.Equal(t, -500.0, settlement.Value(395))
+	})
+
+	t.Run("Cash settled", func(t *testing.T) {
+		settlement := spread("SPX", 4000, 3900).SettleAt(expiration, 3950)
+		assert.Equal(t, Settlement{Cash: -5000}, settlement)
+		assert.Equal(t, Settlement{Cash: -10000}, spread("SPX", 4000, 3900).SettleAt(expiration, 3800))
+	})
+
+	t.Run("Later expirations", func(t *testing.T) {
+		assert.Equal(t, Settlement{}, spread("SPX", 4000, 3900).SettleAt(expiration.AddDate(0, 0, -7), 3800))
+	})
+}
+
+func TestDetectAssignments_cashSettled(t *testing.T) {
+	day := time.Date(2021, time.February, 19, 0, 0, 0, 0, time.UTC)
+	history := []*Event{
+		{Type: "option", Date: DateTime{day}, Amount: -5000, Option: OptionEvent{OptionType: "ASSIGNMENT", Symbol: "SPXW210219P04000000", Quantity: -1}},
+	}
+	book := NewPositionBook([]*Position{{Symbol: "SPXW210219P04000000", Quantity: -1, CostBasis: -3000}})
+
+	events := DetectAssignments(history, book)
+	if !assert.Len(t, events, 1) {
+		return
+	}
+	assert.Equal(t, 0.0, events[0].Shares, "no shares are delivered")
+	assert.Equal(t, -5000.0, events[0].Cash)
+	assert.Equal(t, -2000.0, book.ApplyAssignment(events[0]))
+	assert.Nil(t, book.Position("SPXW210219P04000000"))
+	assert.Nil(t, book.Position("SPX"))
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -56,6 +58,7 @@ const (
 
 var OldestDailyDate = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
 
+// TradierError is returned for every unsuccessful response from the API.
 type TradierError struct {
 	Fault struct {
 		FaultString string
@@ -65,10 +68,63 @@ type TradierError struct {
 	}
 	HttpStatusCode int
 	Message        string
+	// Errors reported for a rejected request, e.g. an invalid order.
+	Errors struct {
+		Error stringList
+	}
+	// Response headers of interest, e.g. the rate limit headers.
+	Header http.Header `json:"-"`
 }
 
 func (te TradierError) Error() string {
-	return fmt.Sprintf("%d: %s - %s", te.HttpStatusCode, te.Fault.FaultString, te.Message)
+	msg := te.Message
+	if len(te.Errors.Error) > 0 {
+		msg = strings.Join(te.Errors.Error, "; ")
+	}
+	return fmt.Sprintf("%d: %s - %s", te.HttpStatusCode, te.Fault.FaultString, msg)
+}
+
+// Response headers copied to TradierError.
+var tradierErrorHeaders = []string{
+	rateLimitAllowed, rateLimitUsed, rateLimitAvailable, rateLimitExpiry,
+	"Retry-After", "Content-Type",
+}
+
+// parseTradierError builds the error for an unsuccessful response. It returns
+// false if the body is not JSON, in which case the body is used as the fault string.
+func parseTradierError(resp *http.Response, body []byte) (TradierError, bool) {
+	body = redactToken(body, requestToken(resp.Request))
+	te := TradierError{
+		HttpStatusCode: resp.StatusCode,
+		Header:         make(http.Header),
+	}
+	for _, h := range tradierErrorHeaders {
+		if v := resp.Header.Values(h); len(v) > 0 {
+			te.Header[h] = v
+		}
+	}
+
+	if err := json.Unmarshal(body, &te); err != nil {
+		te.Fault.FaultString = string(body)
+		return te, false
+	}
+	return te, true
+}
+
+func newTradierError(resp *http.Response, body []byte) TradierError {
+	te, _ := parseTradierError(resp, body)
+	return te
+}
+
+// If there is only a single value, then tradier sends back
+// a string, but if there are multiple values, then it sends
+// a list of strings...
+type stringList []string
+
+func (sl *stringList) UnmarshalJSON(data []byte) error {
+	values, err := decodeOneOrMany[string](data)
+	*sl = values
+	return err
 }
 
 type Security struct {
@@ -98,6 +154,15 @@ func (f *FloatOrNaN) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// MarshalJSON encodes NaN as the string "NaN", which UnmarshalJSON decodes,
+// since JSON numbers cannot be NaN.
+func (f FloatOrNaN) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(f)) {
+		return []byte(`"NaN"`), nil
+	}
+	return json.Marshal(float64(f))
+}
+
 func (f FloatOrNaN) Value() (driver.Value, error) {
 	if math.IsNaN(float64(f)) {
 		return nil, nil
@@ -141,6 +206,56 @@ type Quote struct {
 	ExpirationType   string   `json:"expiration_type"`
 	OptionType       string   `json:"option_type"`
 	RootSymbol       string   `json:"root_symbol"`
+	Greeks           *Greeks
+}
+
+// UnmarshalJSON decodes a quote, including numeric fields sent as strings.
+func (q *Quote) UnmarshalJSON(data []byte) error {
+	type quote Quote
+	return unmarshalNumbers(data, (*quote)(q))
+}
+
+// OptionQuote is the quote of a single option contract.
+type OptionQuote struct {
+	*Quote
+	Contract OptionSymbol
+}
+
+// UnmarshalJSON decodes the quote and contract, which would otherwise be
+// left to the embedded Quote's UnmarshalJSON.
+func (oq *OptionQuote) UnmarshalJSON(data []byte) error {
+	var contract struct{ Contract OptionSymbol }
+	if err := json.Unmarshal(data, &contract); err != nil {
+		return err
+	}
+	oq.Quote, oq.Contract = new(Quote), contract.Contract
+	return oq.Quote.UnmarshalJSON(data)
+}
+
+// Mid returns the midpoint of the bid and ask.
+func (oq *OptionQuote) Mid() float64 {
+	return (oq.Bid + oq.Ask) / 2
+}
+
+// Greeks are included with option quotes when requested.
+type Greeks struct {
+	Delta     float64
+	Gamma     float64
+	Theta     float64
+	Vega      float64
+	Rho       float64
+	Phi       float64
+	BidIV     float64  `json:"bid_iv"`
+	MidIV     float64  `json:"mid_iv"`
+	AskIV     float64  `json:"ask_iv"`
+	SmvVol    float64  `json:"smv_vol"`
+	UpdatedAt DateTime `json:"updated_at"`
+}
+
+// UnmarshalJSON decodes greeks, including numeric fields sent as strings.
+func (g *Greeks) UnmarshalJSON(data []byte) error {
+	type greeks Greeks
+	return unmarshalNumbers(data, (*greeks)(g))
 }
 
 type TimeSale struct {
@@ -176,8 +291,54 @@ type MarketCalendar struct {
 
 type MarketStatus struct {
 	Time        DateTime `json:"date"`
-	State       string
+	State       MarketState
 	Description string
-	NextChange  DateTime `json:"next_change"`
-	NextState   string   `json:"next_state"`
+	// Time of the change to NextState. Tradier only sends the time of day, in
+	// the exchange's time zone, which is taken to be the first such time
+	// after Timestamp. Over weekends and holidays the change is later than
+	// that.
+	NextChange DateTime    `json:"next_change"`
+	NextState  MarketState `json:"next_state"`
+	// Server time in seconds since the Unix epoch.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// UnmarshalJSON decodes a market status, resolving the time of day of the
+// next change to a time.
+func (ms *MarketStatus) UnmarshalJSON(data []byte) error {
+	type marketStatus MarketStatus
+	if err := json.Unmarshal(data, (*marketStatus)(ms)); err != nil {
+		return err
+	}
+	if ms.NextChange.IsZero() || ms.NextChange.Year() != 0 {
+		return nil
+	}
+
+	now := ms.Time.Time
+	if ms.Timestamp > 0 {
+		now = time.Unix(ms.Timestamp, 0)
+	} else if now.IsZero() {
+		return nil
+	}
+	y, m, d := now.In(marketTimezone).Date()
+	change := ms.NextChange.Time
+	next := time.Date(y, m, d, change.Hour(), change.Minute(), 0, 0, marketTimezone)
+	if ms.Timestamp > 0 && !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	ms.NextChange = DateTime{next}
+	return nil
+}
+
+// TimeUntilNextChange returns the time until the market changes to NextState,
+// or zero if the change is unknown or has passed.
+func (ms MarketStatus) TimeUntilNextChange() time.Duration {
+	return ms.timeUntilNextChange(time.Now())
+}
+
+func (ms MarketStatus) timeUntilNextChange(now time.Time) time.Duration {
+	if ms.NextChange.IsZero() || !ms.NextChange.After(now) {
+		return 0
+	}
+	return ms.NextChange.Sub(now)
 }
@@ -0,0 +1,95 @@
+package tradier
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// Intervals supported by GetTimeSales.
+	Intervals = []Interval{
+		IntervalTick, IntervalMinute, Interval5Min, Interval15Min,
+		IntervalDaily, IntervalWeekly, IntervalMonthly,
+	}
+
+	tickLookback   = 5 * 24 * time.Hour
+	minuteLookback = 20 * 24 * time.Hour
+)
+
+// IntervalRangeError is returned when the requested range is not available
+// at the requested interval.
+type IntervalRangeError struct {
+	Interval Interval
+	Start    time.Time
+	End      time.Time
+	// Earliest start time available at the interval.
+	Earliest time.Time
+	Reason   string
+}
+
+func (e *IntervalRangeError) Error() string {
+	return fmt.Sprintf("cannot request %v data from %v to %v: %s (available since %v)",
+		e.Interval, e.Start.Format(time.RFC3339), e.End.Format(time.RFC3339),
+		e.Reason, e.Earliest.Format(time.RFC3339))
+}
+
+// ParseInterval parses one of the supported interval names.
+func ParseInterval(s string) (Interval, error) {
+	for _, i := range Intervals {
+		if string(i) == s {
+			return i, nil
+		}
+	}
+	return "", fmt.Errorf("unknown interval %q, must be one of %v", s, intervalNames())
+}
+
+func intervalNames() string {
+	names := make([]string, len(Intervals))
+	for i, interval := range Intervals {
+		names[i] = string(interval)
+	}
+	return strings.Join(names, ", ")
+}
+
+// IsHistory returns true if the interval is served by the history endpoint
+// (daily, weekly or monthly bars) rather than time and sales.
+func (i Interval) IsHistory() bool {
+	return i == IntervalDaily || i == IntervalWeekly || i == IntervalMonthly
+}
+
+// Validate checks that data at the interval is available from start to end,
+// as of now: tick data for the past 5 days, minute data for the past 20 days,
+// and daily, weekly and monthly data since 1980. Zero start and end times use
+// the API defaults and are not checked. An empty interval is tick data.
+func (i Interval) Validate(start, end, now time.Time) error {
+	interval := i
+	if interval == "" {
+		interval = IntervalTick
+	}
+	if _, err := ParseInterval(string(interval)); err != nil {
+		return err
+	}
+
+	var earliest time.Time
+	var reason string
+	switch {
+	case interval == IntervalTick:
+		earliest, reason = now.Add(-tickLookback), "tick data is only available for the past 5 days"
+	case interval.IsHistory():
+		earliest, reason = OldestDailyDate, "history is only available since "+OldestDailyDate.Format("2006-01-02")
+	default:
+		earliest, reason = now.Add(-minuteLookback), "minute data is only available for the past 20 days"
+	}
+
+	rangeErr := &IntervalRangeError{Interval: interval, Start: start, End: end, Earliest: earliest}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		rangeErr.Reason = "end is before start"
+		return rangeErr
+	}
+	if !start.IsZero() && start.Before(earliest) {
+		rangeErr.Reason = reason
+		return rangeErr
+	}
+	return nil
+}
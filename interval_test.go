@@ -0,0 +1,35 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterval_Validate(t *testing.T) {
+	now := time.Date(2021, time.January, 15, 12, 0, 0, 0, time.UTC)
+	days := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	assert.NoError(t, IntervalTick.Validate(days(4), now, now))
+	assert.NoError(t, Interval("").Validate(time.Time{}, time.Time{}, now))
+	assert.NoError(t, Interval5Min.Validate(days(19), days(1), now))
+	assert.NoError(t, IntervalWeekly.Validate(time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC), now, now))
+
+	err := IntervalTick.Validate(days(6), now, now)
+	if assert.IsType(t, &IntervalRangeError{}, err) {
+		assert.Equal(t, days(5), err.(*IntervalRangeError).Earliest)
+		assert.Contains(t, err.Error(), "past 5 days")
+	}
+	err = IntervalMinute.Validate(days(21), now, now)
+	if assert.IsType(t, &IntervalRangeError{}, err) {
+		assert.Contains(t, err.Error(), "past 20 days")
+	}
+	assert.Error(t, IntervalDaily.Validate(time.Date(1979, time.January, 1, 0, 0, 0, 0, time.UTC), now, now))
+	assert.Error(t, IntervalDaily.Validate(days(1), days(2), now))
+	assert.Error(t, Interval("hourly").Validate(days(1), now, now))
+
+	interval, err := ParseInterval("15min")
+	assert.NoError(t, err)
+	assert.Equal(t, Interval15Min, interval)
+}
@@ -0,0 +1,146 @@
+package tradier
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JournalEntry records a request made by the client, for RecentRequests.
+type JournalEntry struct {
+	Time     time.Time `json:"time"`
+	Method   string    `json:"method"`
+	Endpoint string    `json:"endpoint"`
+	// Hash of the request's parameters, so that repeated requests can be
+	// recognized without recording parameters that may be sensitive.
+	ParamsHash string `json:"params_hash"`
+	// Status of the last response, or 0 if the request failed without one.
+	StatusCode int `json:"status_code"`
+	// Latency including retries.
+	Latency time.Duration `json:"latency_ns"`
+	Retries int           `json:"retries"`
+	Err     string        `json:"error,omitempty"`
+}
+
+// requestJournal keeps the most recent requests in a ring.
+type requestJournal struct {
+	mu      sync.Mutex
+	entries []JournalEntry
+	next    int
+	full    bool
+}
+
+func newRequestJournal(size int) *requestJournal {
+	if size <= 0 {
+		return nil
+	}
+	return &requestJournal{entries: make([]JournalEntry, size)}
+}
+
+func (rj *requestJournal) record(rr *requestRecord, rawURL string, body url.Values, start time.Time, err error) {
+	if rj == nil {
+		return
+	}
+	entry := JournalEntry{
+		Time:       start,
+		Method:     rr.Method,
+		Endpoint:   rr.Endpoint,
+		ParamsHash: paramsHash(rawURL, body),
+		StatusCode: rr.StatusCode,
+		Latency:    time.Since(start),
+		Retries:    rr.retries(),
+	}
+	if err != nil {
+		entry.Err = err.Error()
+	}
+
+	rj.mu.Lock()
+	defer rj.mu.Unlock()
+	rj.entries[rj.next] = entry
+	rj.next = (rj.next + 1) % len(rj.entries)
+	rj.full = rj.full || rj.next == 0
+}
+
+func (rj *requestJournal) recent() []JournalEntry {
+	if rj == nil {
+		return nil
+	}
+	rj.mu.Lock()
+	defer rj.mu.Unlock()
+	if !rj.full {
+		return append([]JournalEntry(nil), rj.entries[:rj.next]...)
+	}
+	return append(append([]JournalEntry(nil), rj.entries[rj.next:]...), rj.entries[:rj.next]...)
+}
+
+func paramsHash(rawURL string, body url.Values) string {
+	h := fnv.New64a()
+	if u, err := url.Parse(rawURL); err == nil {
+		io.WriteString(h, u.RawQuery)
+	}
+	io.WriteString(h, "&")
+	io.WriteString(h, body.Encode())
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// RecentRequests returns the last ClientParams.JournalSize requests made by
+// the client and its copies, oldest first, or nil if the journal is disabled.
+func (tc *Client) RecentRequests() []JournalEntry {
+	return tc.journal.recent()
+}
+
+// DumpRecentRequests writes the requests returned by RecentRequests, grouped
+// by the minute they were made in, with times in UTC, e.g. when recovering
+// from a panic:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			client.DumpRecentRequests(os.Stderr)
+//			panic(r)
+//		}
+//	}()
+func (tc *Client) DumpRecentRequests(w io.Writer) error {
+	entries := tc.RecentRequests()
+	for i := range entries {
+		entries[i].Time = exportTime(entries[i].Time)
+	}
+	var b strings.Builder
+	for i := 0; i < len(entries); {
+		minute := entries[i].Time.Truncate(time.Minute)
+		j, failed := i, 0
+		for ; j < len(entries) && entries[j].Time.Truncate(time.Minute).Equal(minute); j++ {
+			if entries[j].Err != "" {
+				failed++
+			}
+		}
+		fmt.Fprintf(&b, "%v: %d requests, %d failed\n", minute.Format("2006-01-02 15:04"), j-i, failed)
+		for _, e := range entries[i:j] {
+			fmt.Fprintf(&b, "  %v %v %v params=%v status=%d latency=%v retries=%d",
+				e.Time.Format("15:04:05.000"), e.Method, e.Endpoint, e.ParamsHash, e.StatusCode, e.Latency, e.Retries)
+			if e.Err != "" {
+				fmt.Fprintf(&b, " error=%q", e.Err)
+			}
+			b.WriteByte('\n')
+		}
+		i = j
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteRecentRequestsJSON writes the requests returned by RecentRequests as
+// a JSON array, with times in UTC.
+func (tc *Client) WriteRecentRequestsJSON(w io.Writer) error {
+	entries := tc.RecentRequests()
+	for i := range entries {
+		entries[i].Time = exportTime(entries[i].Time)
+	}
+	if entries == nil {
+		entries = []JournalEntry{}
+	}
+	return writeExportJSON(w, entries)
+}
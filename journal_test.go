@@ -0,0 +1,88 @@
+package tradier
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_RecentRequests(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/markets/quotes":
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"quotes": {"quote": {"symbol": "SPY"}}}`))
+		case "/v1/markets/clock":
+			w.Write([]byte(`{"clock": {"state": "open"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("secret-token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.Backoff = &backoff.ZeroBackOff{}
+
+	t.Run("Disabled", func(t *testing.T) {
+		client := NewClient(params)
+		client.GetMarketState()
+		assert.Nil(t, client.RecentRequests())
+	})
+
+	params.JournalSize = 3
+	client := NewClient(params)
+
+	t.Run("Entries", func(t *testing.T) {
+		_, err := client.GetQuotes([]string{"SPY"})
+		assert.NoError(t, err)
+		_, err = client.GetOrderStatus(42)
+		assert.Error(t, err)
+
+		entries := client.RecentRequests()
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "/v1/markets/quotes", entries[0].Endpoint)
+			assert.Equal(t, http.StatusOK, entries[0].StatusCode)
+			assert.Equal(t, 1, entries[0].Retries)
+			assert.Empty(t, entries[0].Err)
+			assert.Equal(t, "/v1/accounts/{account}/orders/{id}", entries[1].Endpoint)
+			assert.Equal(t, http.StatusNotFound, entries[1].StatusCode)
+			assert.NotEmpty(t, entries[1].Err)
+			assert.NotEqual(t, entries[0].ParamsHash, entries[1].ParamsHash)
+		}
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		client.GetMarketState()
+		client.WithAccount("VA001").GetMarketState()
+
+		entries := client.RecentRequests()
+		if assert.Len(t, entries, 3) {
+			assert.Equal(t, "/v1/accounts/{account}/orders/{id}", entries[0].Endpoint)
+			assert.Equal(t, "/v1/markets/clock", entries[2].Endpoint)
+			assert.Equal(t, entries[1].ParamsHash, entries[2].ParamsHash)
+		}
+	})
+
+	t.Run("Dump", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, client.DumpRecentRequests(&buf))
+		dump := buf.String()
+		assert.Regexp(t, `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}: \d requests, \d failed\n`, dump)
+		assert.Equal(t, 3, strings.Count(dump, " params="))
+		assert.Contains(t, dump, "GET /v1/markets/clock params=")
+		assert.Equal(t, 1, strings.Count(dump, "status=404"))
+		assert.NotContains(t, dump, "secret-token")
+	})
+}
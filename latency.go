@@ -0,0 +1,123 @@
+package tradier
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Number of recent samples per symbol used to compute latency percentiles.
+const latencyWindow = 1024
+
+// LatencyStats summarizes the receive latency of stream events for a symbol,
+// measured as the local receive time minus the event timestamp. Latency
+// includes any offset between the local and exchange clocks.
+type LatencyStats struct {
+	Count int
+	Last  time.Duration
+	Mean  time.Duration
+	Max   time.Duration
+	// Percentiles over the most recent events.
+	P50 time.Duration
+	P99 time.Duration
+}
+
+type latencySamples struct {
+	count   int
+	total   time.Duration
+	last    time.Duration
+	max     time.Duration
+	samples []time.Duration
+	next    int
+}
+
+func (ls *latencySamples) add(latency time.Duration) {
+	ls.count++
+	ls.total += latency
+	ls.last = latency
+	if latency > ls.max || ls.count == 1 {
+		ls.max = latency
+	}
+	if len(ls.samples) < latencyWindow {
+		ls.samples = append(ls.samples, latency)
+	} else {
+		ls.samples[ls.next] = latency
+		ls.next = (ls.next + 1) % latencyWindow
+	}
+}
+
+func (ls *latencySamples) stats() LatencyStats {
+	if ls.count == 0 {
+		return LatencyStats{}
+	}
+	sorted := make([]time.Duration, len(ls.samples))
+	copy(sorted, ls.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return LatencyStats{
+		Count: ls.count,
+		Last:  ls.last,
+		Mean:  ls.total / time.Duration(ls.count),
+		Max:   ls.max,
+		P50:   sorted[len(sorted)/2],
+		P99:   sorted[(len(sorted)*99)/100],
+	}
+}
+
+// LatencyTracker measures the delay between stream event timestamps and
+// the time they are received, per symbol.
+type LatencyTracker struct {
+	// Now returns the local receive time. Defaults to time.Now.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	symbols map[string]*latencySamples
+}
+
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{
+		Now:     time.Now,
+		symbols: make(map[string]*latencySamples),
+	}
+}
+
+// Observe records an event for symbol with the given timestamp and returns its latency.
+// Events without a timestamp are ignored.
+func (lt *LatencyTracker) Observe(symbol string, eventTime time.Time) time.Duration {
+	if eventTime.IsZero() || eventTime.Unix() == 0 {
+		return 0
+	}
+	latency := lt.Now().Sub(eventTime)
+
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	sl := lt.symbols[symbol]
+	if sl == nil {
+		sl = &latencySamples{}
+		lt.symbols[symbol] = sl
+	}
+	sl.add(latency)
+	return latency
+}
+
+// Stats returns the latency stats for symbol.
+func (lt *LatencyTracker) Stats(symbol string) LatencyStats {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	sl := lt.symbols[symbol]
+	if sl == nil {
+		return LatencyStats{}
+	}
+	return sl.stats()
+}
+
+// Symbols returns the symbols that have been observed.
+func (lt *LatencyTracker) Symbols() []string {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	symbols := make([]string, 0, len(lt.symbols))
+	for s := range lt.symbols {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
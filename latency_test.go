@@ -0,0 +1,46 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	now := time.Date(2021, time.January, 4, 14, 30, 0, 0, time.UTC)
+	lt := NewLatencyTracker()
+	lt.Now = func() time.Time { return now }
+
+	for _, ms := range []int{10, 20, 30, 40, 400} {
+		lt.Observe("SPY", now.Add(-time.Duration(ms)*time.Millisecond))
+	}
+	assert.Equal(t, time.Duration(0), lt.Observe("SPY", time.Time{}))
+
+	stats := lt.Stats("SPY")
+	assert.Equal(t, 5, stats.Count)
+	assert.Equal(t, 400*time.Millisecond, stats.Last)
+	assert.Equal(t, 100*time.Millisecond, stats.Mean)
+	assert.Equal(t, 400*time.Millisecond, stats.Max)
+	assert.Equal(t, 30*time.Millisecond, stats.P50)
+	assert.Equal(t, LatencyStats{}, lt.Stats("QQQ"))
+	assert.Equal(t, []string{"SPY"}, lt.Symbols())
+
+	t.Run("Demuxed events", func(t *testing.T) {
+		lt := NewLatencyTracker()
+		lt.Now = func() time.Time { return time.Unix(1557757189, 0) }
+		var received *TradeEvent
+		sd := &StreamDemuxer{
+			Trades:  func(trade *TradeEvent) { received = trade },
+			Latency: lt,
+		}
+
+		event := &StreamEvent{}
+		assert.NoError(t, UnmarshalStreamEvent([]byte(`{"type":"trade","symbol":"SPY","exch":"Q","price":"281.1","size":"100","cvol":"1","date":"1557757188750","last":"281.1"}`), event))
+		sd.Handle(event)
+		if assert.NotNil(t, received) {
+			assert.Equal(t, time.Unix(1557757188, 750000000), received.Date())
+		}
+		assert.Equal(t, 250*time.Millisecond, lt.Stats("SPY").Last)
+	})
+}
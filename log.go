@@ -1,14 +1,95 @@
 package tradier
 
 import (
-	"io/ioutil"
+	"fmt"
 	"log"
+	"strings"
 )
 
+// Logger receives the messages of the client, and of the streams and
+// monitors it creates, with fields as alternating keys and values, e.g.
+//
+//	logger.Warn("Retrying request", "path", "/v1/markets/quotes", "after", time.Second)
+//
+// *slog.Logger implements Logger, and NewZapLogger and NewStdLogger adapt
+// zap and standard library loggers.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// StdLogger is the interface of the standard library's *log.Logger.
 type StdLogger interface {
 	Print(v ...interface{})
 	Printf(format string, v ...interface{})
 	Println(v ...interface{})
 }
 
-var Logger StdLogger = log.New(ioutil.Discard, "[go-tradier] ", log.LstdFlags)
+// NewLogger returns a Logger that writes to the standard logger's output
+// with the package's prefix, for use as ClientParams.Logger.
+func NewLogger() Logger {
+	return NewStdLogger(log.New(log.Writer(), "[go-tradier] ", log.LstdFlags))
+}
+
+// NewStdLogger returns a Logger that prints each message on a line with its
+// level and fields, e.g. "WARN Retrying request path=/v1/markets/quotes after=1s".
+func NewStdLogger(l StdLogger) Logger {
+	return stdLogger{l}
+}
+
+type stdLogger struct {
+	l StdLogger
+}
+
+func (sl stdLogger) Debug(msg string, keyvals ...interface{}) { sl.print("DEBUG", msg, keyvals) }
+func (sl stdLogger) Info(msg string, keyvals ...interface{})  { sl.print("INFO", msg, keyvals) }
+func (sl stdLogger) Warn(msg string, keyvals ...interface{})  { sl.print("WARN", msg, keyvals) }
+func (sl stdLogger) Error(msg string, keyvals ...interface{}) { sl.print("ERROR", msg, keyvals) }
+
+func (sl stdLogger) print(level, msg string, keyvals []interface{}) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteByte(' ')
+	b.WriteString(msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 == len(keyvals) {
+			fmt.Fprintf(&b, " !BADKEY=%v", keyvals[i])
+			break
+		}
+		fmt.Fprintf(&b, " %v=%v", keyvals[i], keyvals[i+1])
+	}
+	sl.l.Println(b.String())
+}
+
+// SugaredLogger is the interface of zap's *SugaredLogger used by NewZapLogger.
+type SugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// NewZapLogger returns a Logger that logs to a zap logger, e.g.
+// NewZapLogger(logger.Sugar()).
+func NewZapLogger(l SugaredLogger) Logger {
+	return zapLogger{l}
+}
+
+type zapLogger struct {
+	l SugaredLogger
+}
+
+func (zl zapLogger) Debug(msg string, keyvals ...interface{}) { zl.l.Debugw(msg, keyvals...) }
+func (zl zapLogger) Info(msg string, keyvals ...interface{})  { zl.l.Infow(msg, keyvals...) }
+func (zl zapLogger) Warn(msg string, keyvals ...interface{})  { zl.l.Warnw(msg, keyvals...) }
+func (zl zapLogger) Error(msg string, keyvals ...interface{}) { zl.l.Errorw(msg, keyvals...) }
+
+// Clients without a logger discard their messages.
+type discardLogger struct{}
+
+func (discardLogger) Debug(msg string, keyvals ...interface{}) {}
+func (discardLogger) Info(msg string, keyvals ...interface{})  {}
+func (discardLogger) Warn(msg string, keyvals ...interface{})  {}
+func (discardLogger) Error(msg string, keyvals ...interface{}) {}
@@ -0,0 +1,34 @@
+//go:build go1.21
+
+package tradier
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_slogLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Backoff = backoff.NewConstantBackOff(time.Millisecond)
+	params.RetryLimit = 1
+	params.Logger = slog.New(slog.NewTextHandler(&out, nil))
+	_, err := NewClient(params).GetQuotes([]string{"SPY"})
+	assert.Error(t, err)
+
+	assert.Contains(t, out.String(), `level=WARN msg="Retrying request" method=GET path=/v1/markets/quotes`)
+	assert.Contains(t, out.String(), "attempt=1 status=503")
+}
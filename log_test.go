@@ -0,0 +1,86 @@
+package tradier
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Logger(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"quotes": {"quote": {"symbol": "SPY"}}}`))
+	}))
+	defer healthy.Close()
+
+	newClient := func(endpoint string, out *bytes.Buffer) *Client {
+		params := DefaultParams("token")
+		params.Endpoint = endpoint
+		params.Backoff = backoff.NewConstantBackOff(time.Millisecond)
+		params.RetryLimit = 2
+		params.Logger = NewStdLogger(log.New(out, "", 0))
+		return NewClient(params)
+	}
+
+	var failingLog, healthyLog bytes.Buffer
+	failingClient := newClient(failing.URL, &failingLog)
+	healthyClient := newClient(healthy.URL, &healthyLog)
+
+	_, err := failingClient.GetQuotes([]string{"SPY"})
+	assert.Error(t, err)
+	_, err = healthyClient.GetQuotes([]string{"SPY"})
+	assert.NoError(t, err)
+
+	assert.Contains(t, failingLog.String(), "WARN Retrying request method=GET path=/v1/markets/quotes")
+	assert.Contains(t, failingLog.String(), "attempt=1 status=503")
+	assert.Empty(t, healthyLog.String())
+
+	// Clients without a logger discard messages.
+	params := DefaultParams("token")
+	params.Endpoint = failing.URL
+	params.Backoff = backoff.NewConstantBackOff(time.Millisecond)
+	params.RetryLimit = 1
+	_, err = NewClient(params).GetQuotes([]string{"SPY"})
+	assert.Error(t, err)
+}
+
+type fakeSugaredLogger struct {
+	lines []string
+}
+
+func (fl *fakeSugaredLogger) log(level, msg string, keysAndValues []interface{}) {
+	fl.lines = append(fl.lines, fmt.Sprint(level, " ", msg, " ", keysAndValues))
+}
+
+func (fl *fakeSugaredLogger) Debugw(msg string, kv ...interface{}) { fl.log("debug", msg, kv) }
+func (fl *fakeSugaredLogger) Infow(msg string, kv ...interface{})  { fl.log("info", msg, kv) }
+func (fl *fakeSugaredLogger) Warnw(msg string, kv ...interface{})  { fl.log("warn", msg, kv) }
+func (fl *fakeSugaredLogger) Errorw(msg string, kv ...interface{}) { fl.log("error", msg, kv) }
+
+func TestLoggerAdapters(t *testing.T) {
+	t.Run("Std", func(t *testing.T) {
+		var out bytes.Buffer
+		logger := NewStdLogger(log.New(&out, "", 0))
+		logger.Info("Waiting for rate limit", "category", CategoryMarketData, "wait", time.Second)
+		logger.Error("Odd fields", "key")
+		assert.Equal(t, "INFO Waiting for rate limit category=market_data wait=1s\nERROR Odd fields !BADKEY=key\n", out.String())
+	})
+
+	t.Run("Zap", func(t *testing.T) {
+		sugared := &fakeSugaredLogger{}
+		logger := NewZapLogger(sugared)
+		logger.Debug("a", "k", 1)
+		logger.Warn("b")
+		assert.Equal(t, []string{"debug a [k 1]", "warn b []"}, sugared.lines)
+	})
+}
@@ -0,0 +1,167 @@
+package tradier
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/pkg/errors"
+)
+
+// ErrStreamEnded is reported when the market stream is closed by Tradier.
+var ErrStreamEnded = errors.New("market stream ended")
+
+// ManagedMarketStream streams market events for a set of symbols, recreating
+// the streaming session and resubscribing whenever the stream is disconnected.
+//
+// The symbol set can be changed while streaming with SetSymbols, e.g. from
+// ConsumerGroup.SymbolsChanged. If ClientParams.StreamStallTimeout is set then
+// streams that stall are also reconnected, reporting ErrStreamStalled.
+type ManagedMarketStream struct {
+	// Filter restricts the types of events streamed. Use SetFilter to change it while streaming.
+	Filter []Filter
+	// Backoff between reconnection attempts. It is reset once a stream is
+	// opened. Defaults to exponential backoff that never stops.
+	Backoff backoff.BackOff
+	// Errors is called with each error that caused the stream to reconnect.
+	Errors func(err error)
+
+	client *Client
+
+	mu          sync.Mutex
+	symbols     []string
+	resubscribe bool
+	cancel      context.CancelFunc
+}
+
+// NewManagedMarketStream returns a managed stream of market events for the given symbols.
+func (tc *Client) NewManagedMarketStream(symbols []string) *ManagedMarketStream {
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 0
+	return &ManagedMarketStream{
+		Backoff: b,
+		client:  tc,
+		symbols: symbols,
+	}
+}
+
+// Symbols returns the symbols currently subscribed.
+func (ms *ManagedMarketStream) Symbols() []string {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.symbols
+}
+
+// SetSymbols changes the subscribed symbols, reopening the stream if it is running.
+func (ms *ManagedMarketStream) SetSymbols(symbols []string) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.symbols = symbols
+	if ms.cancel != nil {
+		ms.resubscribe = true
+		ms.cancel()
+	}
+}
+
+// SetFilter changes the types of events streamed, reopening the stream if it is running.
+func (ms *ManagedMarketStream) SetFilter(filter []Filter) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.Filter = filter
+	if ms.cancel != nil {
+		ms.resubscribe = true
+		ms.cancel()
+	}
+}
+
+// Run streams events to output until stop is closed, reconnecting as needed.
+// Output is closed when Run returns. An error is returned only if the
+// backoff gives up on reconnecting.
+func (ms *ManagedMarketStream) Run(output chan *StreamEvent, stop <-chan struct{}) error {
+	defer close(output)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		connCtx, connCancel := context.WithCancel(ctx)
+		ms.mu.Lock()
+		symbols, filter := ms.symbols, ms.Filter
+		ms.cancel = connCancel
+		ms.resubscribe = false
+		ms.mu.Unlock()
+
+		var err error
+		if len(symbols) == 0 {
+			// Nothing to stream until symbols are set.
+			<-connCtx.Done()
+		} else {
+			var stream io.ReadCloser
+			stream, err = ms.client.openMarketStream(
+				connCtx, symbols, filter, ms.client.createStreamSessionWait)
+			if err == nil {
+				ms.Backoff.Reset()
+				err = ms.consume(connCtx, stream, output)
+				stream.Close()
+			}
+		}
+		connCancel()
+
+		ms.mu.Lock()
+		ms.cancel = nil
+		resubscribe := ms.resubscribe
+		ms.mu.Unlock()
+		if ctx.Err() != nil {
+			return nil
+		} else if resubscribe {
+			continue
+		}
+
+		if ms.Errors != nil {
+			ms.Errors(err)
+		}
+		wait := ms.Backoff.NextBackOff()
+		if wait == backoff.Stop {
+			ms.client.logger.Error("Market stream gave up reconnecting", "error", err)
+			return err
+		}
+		ms.client.logger.Warn("Reconnecting market stream", "error", err, "after", wait, "symbols", len(symbols))
+		ms.client.metrics.ObserveStreamReconnect("market")
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// consume delivers events from the stream until it ends or ctx is done.
+func (ms *ManagedMarketStream) consume(ctx context.Context, stream io.Reader, output chan *StreamEvent) error {
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		event := &StreamEvent{}
+		if err := UnmarshalStreamEvent(scanner.Bytes(), event); err != nil {
+			ms.client.logger.Warn("Malformed stream event", "error", err)
+		}
+
+		select {
+		case output <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return ErrStreamEnded
+}
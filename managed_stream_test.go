@@ -0,0 +1,79 @@
+package tradier
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagedMarketStream(t *testing.T) {
+	var mu sync.Mutex
+	var sessions, streams int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/markets/events/session":
+			sessions++
+			fmt.Fprintf(w, `{"stream": {"url": "%s/v1/markets/events", "sessionid": "s%d"}}`, server.URL, sessions)
+		case "/v1/markets/events":
+			streams++
+			if streams == 2 {
+				// A failed attempt to reopen the stream.
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, `{"type":"quote","symbol":"%s"}`+"\n", r.FormValue("symbols"))
+			w.(http.Flusher).Flush()
+			if streams == 1 {
+				// The first stream is disconnected after one event.
+				return
+			}
+			mu.Unlock()
+			<-r.Context().Done()
+			mu.Lock()
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+
+	var errs []error
+	ms := client.NewManagedMarketStream([]string{"SPY"})
+	ms.Backoff = backoff.NewConstantBackOff(time.Millisecond)
+	ms.Errors = func(err error) { errs = append(errs, err) }
+
+	events := make(chan *StreamEvent)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- ms.Run(events, stop) }()
+
+	assert.Equal(t, "SPY", (<-events).Symbol)
+	// Reconnected after the disconnect and the failed attempt.
+	assert.Equal(t, "SPY", (<-events).Symbol)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, ErrStreamEnded, errs[0])
+		assert.IsType(t, TradierError{}, errs[1])
+	}
+
+	ms.SetSymbols([]string{"QQQ", "IWM"})
+	assert.Equal(t, "QQQ,IWM", (<-events).Symbol)
+	assert.Len(t, errs, 2, "resubscribing is not an error")
+
+	close(stop)
+	assert.NoError(t, <-done)
+	_, ok := <-events
+	assert.False(t, ok)
+	mu.Lock()
+	assert.Equal(t, 4, sessions)
+	mu.Unlock()
+}
@@ -0,0 +1,329 @@
+package tradier
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BarSource fetches price history and time sales. It is implemented by Client.
+type BarSource interface {
+	GetTimeSales(symbol string, interval Interval, start, end time.Time) ([]TimeSale, error)
+}
+
+// MarketDataSource is the market data used by the analytics: the latest
+// quotes, option chains and bars. It is implemented by Client, by
+// CachedMarketData and by RecordedMarketData, so that analytics work the
+// same with live, cached and recorded data.
+type MarketDataSource interface {
+	QuoteSource
+	ChainProvider
+	BarSource
+}
+
+type cachedQuote struct {
+	quote   *Quote
+	fetched time.Time
+}
+
+type cachedChain struct {
+	chain   []*Quote
+	fetched time.Time
+}
+
+type cachedExpirations struct {
+	expirations []time.Time
+	fetched     time.Time
+}
+
+type cachedBars struct {
+	bars    []TimeSale
+	fetched time.Time
+}
+
+// CachedMarketData caches the market data of a source. Quotes are cached by
+// symbol, so only uncached (or expired) symbols are requested.
+type CachedMarketData struct {
+	Source MarketDataSource
+	// How long data is cached. If zero it never expires.
+	TTL time.Duration
+
+	mu          sync.Mutex
+	quotes      map[string]cachedQuote
+	expirations map[string]cachedExpirations
+	chains      map[chainKey]cachedChain
+	bars        map[string]cachedBars
+}
+
+func NewCachedMarketData(source MarketDataSource, ttl time.Duration) *CachedMarketData {
+	return &CachedMarketData{
+		Source:      source,
+		TTL:         ttl,
+		quotes:      make(map[string]cachedQuote),
+		expirations: make(map[string]cachedExpirations),
+		chains:      make(map[chainKey]cachedChain),
+		bars:        make(map[string]cachedBars),
+	}
+}
+
+func (cm *CachedMarketData) fresh(fetched, now time.Time) bool {
+	return cm.TTL == 0 || now.Sub(fetched) < cm.TTL
+}
+
+// GetQuotes returns the quotes of the symbols, requesting those not cached.
+func (cm *CachedMarketData) GetQuotes(symbols []string) ([]*Quote, error) {
+	now := time.Now()
+	var missing []string
+	cm.mu.Lock()
+	for _, s := range symbols {
+		if e, ok := cm.quotes[s]; !ok || !cm.fresh(e.fetched, now) {
+			missing = append(missing, s)
+		}
+	}
+	cm.mu.Unlock()
+
+	if len(missing) > 0 {
+		quotes, err := cm.Source.GetQuotes(missing)
+		if err != nil {
+			return nil, err
+		}
+		cm.mu.Lock()
+		if cm.quotes == nil {
+			cm.quotes = make(map[string]cachedQuote)
+		}
+		for _, q := range quotes {
+			cm.quotes[q.Symbol] = cachedQuote{quote: q, fetched: now}
+		}
+		cm.mu.Unlock()
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	var result []*Quote
+	for _, s := range symbols {
+		if e, ok := cm.quotes[s]; ok {
+			result = append(result, e.quote)
+		}
+	}
+	return result, nil
+}
+
+func (cm *CachedMarketData) GetOptionExpirationDates(symbol string) ([]time.Time, error) {
+	now := time.Now()
+	cm.mu.Lock()
+	e, ok := cm.expirations[symbol]
+	cm.mu.Unlock()
+	if ok && cm.fresh(e.fetched, now) {
+		return e.expirations, nil
+	}
+
+	expirations, err := cm.Source.GetOptionExpirationDates(symbol)
+	if err != nil {
+		return nil, err
+	}
+	cm.mu.Lock()
+	if cm.expirations == nil {
+		cm.expirations = make(map[string]cachedExpirations)
+	}
+	cm.expirations[symbol] = cachedExpirations{expirations: expirations, fetched: now}
+	cm.mu.Unlock()
+	return expirations, nil
+}
+
+func (cm *CachedMarketData) GetOptionChainWithGreeks(symbol string, expiration time.Time) ([]*Quote, error) {
+	now := time.Now()
+	key := chainKey{symbol, expiration.Format("2006-01-02")}
+	cm.mu.Lock()
+	e, ok := cm.chains[key]
+	cm.mu.Unlock()
+	if ok && cm.fresh(e.fetched, now) {
+		return e.chain, nil
+	}
+
+	chain, err := cm.Source.GetOptionChainWithGreeks(symbol, expiration)
+	if err != nil {
+		return nil, err
+	}
+	cm.mu.Lock()
+	if cm.chains == nil {
+		cm.chains = make(map[chainKey]cachedChain)
+	}
+	cm.chains[key] = cachedChain{chain: chain, fetched: now}
+	cm.mu.Unlock()
+	return chain, nil
+}
+
+// GetTimeSales returns the bars of the symbol, which are cached by the
+// interval and range requested.
+func (cm *CachedMarketData) GetTimeSales(symbol string, interval Interval, start, end time.Time) ([]TimeSale, error) {
+	now := time.Now()
+	key := fmt.Sprintf("%s/%s/%d/%d", symbol, interval, start.Unix(), end.Unix())
+	cm.mu.Lock()
+	e, ok := cm.bars[key]
+	cm.mu.Unlock()
+	if ok && cm.fresh(e.fetched, now) {
+		return e.bars, nil
+	}
+
+	bars, err := cm.Source.GetTimeSales(symbol, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+	cm.mu.Lock()
+	if cm.bars == nil {
+		cm.bars = make(map[string]cachedBars)
+	}
+	cm.bars[key] = cachedBars{bars: bars, fetched: now}
+	cm.mu.Unlock()
+	return bars, nil
+}
+
+// RecordedMarketData is a MarketDataSource of recorded market data, such as
+// a test fixture encoded as JSON. If Source is set, data that has not been
+// recorded is fetched from it and recorded, so that a fixture can be
+// captured from a live client and replayed without it.
+type RecordedMarketData struct {
+	// Quotes by symbol.
+	Quotes map[string]*Quote `json:"quotes,omitempty"`
+	// Expirations by symbol.
+	Expirations map[string][]time.Time `json:"expirations,omitempty"`
+	// Chains by symbol and expiration date, as by RecordedChainKey.
+	Chains map[string][]*Quote `json:"chains,omitempty"`
+	// Bars by symbol and interval, as by RecordedBarsKey.
+	Bars map[string][]TimeSale `json:"bars,omitempty"`
+
+	Source MarketDataSource `json:"-"`
+
+	mu sync.Mutex
+}
+
+// ErrNotRecorded is returned by RecordedMarketData for data that has not
+// been recorded.
+type ErrNotRecorded struct {
+	Key string
+}
+
+func (e ErrNotRecorded) Error() string {
+	return fmt.Sprintf("market data not recorded: %v", e.Key)
+}
+
+// RecordedChainKey returns the key of the chain in RecordedMarketData.Chains.
+func RecordedChainKey(symbol string, expiration time.Time) string {
+	return symbol + "/" + expiration.Format("2006-01-02")
+}
+
+// RecordedBarsKey returns the key of the bars in RecordedMarketData.Bars.
+func RecordedBarsKey(symbol string, interval Interval) string {
+	return symbol + "/" + string(interval)
+}
+
+// GetQuotes returns the recorded quotes of the symbols. Without a Source,
+// symbols that have not been recorded are omitted, as Tradier omits
+// unknown symbols.
+func (rm *RecordedMarketData) GetQuotes(symbols []string) ([]*Quote, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	var missing []string
+	for _, s := range symbols {
+		if _, ok := rm.Quotes[s]; !ok {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 && rm.Source != nil {
+		quotes, err := rm.Source.GetQuotes(missing)
+		if err != nil {
+			return nil, err
+		}
+		if rm.Quotes == nil {
+			rm.Quotes = make(map[string]*Quote)
+		}
+		for _, q := range quotes {
+			rm.Quotes[q.Symbol] = q
+		}
+	}
+
+	var result []*Quote
+	for _, s := range symbols {
+		if q, ok := rm.Quotes[s]; ok {
+			result = append(result, q)
+		}
+	}
+	return result, nil
+}
+
+func (rm *RecordedMarketData) GetOptionExpirationDates(symbol string) ([]time.Time, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if expirations, ok := rm.Expirations[symbol]; ok {
+		return expirations, nil
+	} else if rm.Source == nil {
+		return nil, ErrNotRecorded{Key: symbol}
+	}
+
+	expirations, err := rm.Source.GetOptionExpirationDates(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if rm.Expirations == nil {
+		rm.Expirations = make(map[string][]time.Time)
+	}
+	rm.Expirations[symbol] = expirations
+	return expirations, nil
+}
+
+func (rm *RecordedMarketData) GetOptionChainWithGreeks(symbol string, expiration time.Time) ([]*Quote, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	key := RecordedChainKey(symbol, expiration)
+	if chain, ok := rm.Chains[key]; ok {
+		return chain, nil
+	} else if rm.Source == nil {
+		return nil, ErrNotRecorded{Key: key}
+	}
+
+	chain, err := rm.Source.GetOptionChainWithGreeks(symbol, expiration)
+	if err != nil {
+		return nil, err
+	}
+	if rm.Chains == nil {
+		rm.Chains = make(map[string][]*Quote)
+	}
+	rm.Chains[key] = chain
+	return chain, nil
+}
+
+// GetTimeSales returns the recorded bars of the symbol and interval in the
+// range [start, end]. A zero start or end is unbounded. If a Source is set,
+// bars that have not been recorded for the symbol and interval are fetched
+// for the range, and recorded.
+func (rm *RecordedMarketData) GetTimeSales(symbol string, interval Interval, start, end time.Time) ([]TimeSale, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	key := RecordedBarsKey(symbol, interval)
+	bars, ok := rm.Bars[key]
+	if !ok {
+		if rm.Source == nil {
+			return nil, ErrNotRecorded{Key: key}
+		}
+		var err error
+		if bars, err = rm.Source.GetTimeSales(symbol, interval, start, end); err != nil {
+			return nil, err
+		}
+		if rm.Bars == nil {
+			rm.Bars = make(map[string][]TimeSale)
+		}
+		rm.Bars[key] = bars
+	}
+
+	var result []TimeSale
+	for _, bar := range bars {
+		t := bar.Time.Time
+		if t.IsZero() {
+			t = bar.Date.Time
+		}
+		if (start.IsZero() || !t.Before(start)) && (end.IsZero() || !t.After(end)) {
+			result = append(result, bar)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,143 @@
+package tradier
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ MarketDataSource = (*Client)(nil)
+
+type fakeMarketData struct {
+	calls map[string]int
+}
+
+func (fm *fakeMarketData) call(name string) {
+	if fm.calls == nil {
+		fm.calls = make(map[string]int)
+	}
+	fm.calls[name]++
+}
+
+func (fm *fakeMarketData) GetQuotes(symbols []string) ([]*Quote, error) {
+	fm.call("quotes")
+	var quotes []*Quote
+	for _, s := range symbols {
+		if s != "UNKNOWN" {
+			quotes = append(quotes, &Quote{Symbol: s, Last: 100, AverageVolume: 1000})
+		}
+	}
+	return quotes, nil
+}
+
+func (fm *fakeMarketData) GetOptionExpirationDates(symbol string) ([]time.Time, error) {
+	fm.call("expirations")
+	return []time.Time{time.Date(2021, 2, 19, 0, 0, 0, 0, time.UTC)}, nil
+}
+
+func (fm *fakeMarketData) GetOptionChainWithGreeks(symbol string, expiration time.Time) ([]*Quote, error) {
+	fm.call("chain")
+	return []*Quote{{Symbol: "SPY210219C00360000", Strike: 360, Greeks: &Greeks{Delta: 0.5}}}, nil
+}
+
+func (fm *fakeMarketData) GetTimeSales(symbol string, interval Interval, start, end time.Time) ([]TimeSale, error) {
+	fm.call("bars")
+	var bars []TimeSale
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		bars = append(bars, TimeSale{Date: DateTime{d}, Close: 100})
+	}
+	return bars, nil
+}
+
+func TestCachedMarketData(t *testing.T) {
+	source := &fakeMarketData{}
+	cache := NewCachedMarketData(source, time.Hour)
+	expiration := time.Date(2021, 2, 19, 0, 0, 0, 0, time.UTC)
+	start, end := time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		quotes, err := cache.GetQuotes([]string{"SPY", "UNKNOWN"})
+		assert.NoError(t, err)
+		assert.Len(t, quotes, 1)
+		_, err = cache.GetOptionExpirationDates("SPY")
+		assert.NoError(t, err)
+		chain, err := cache.GetOptionChainWithGreeks("SPY", expiration)
+		assert.NoError(t, err)
+		assert.Len(t, chain, 1)
+		bars, err := cache.GetTimeSales("SPY", IntervalDaily, start, end)
+		assert.NoError(t, err)
+		assert.Len(t, bars, 5)
+	}
+	// Symbols that Tradier has no quote for are requested again.
+	assert.Equal(t, map[string]int{"quotes": 2, "expirations": 1, "chain": 1, "bars": 1}, source.calls)
+
+	_, err := cache.GetQuotes([]string{"SPY", "QQQ"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, source.calls["quotes"], "only QQQ is requested")
+
+	t.Run("Expired", func(t *testing.T) {
+		cache.TTL = time.Nanosecond
+		time.Sleep(time.Millisecond)
+		_, err := cache.GetOptionChainWithGreeks("SPY", expiration)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, source.calls["chain"])
+	})
+}
+
+func TestRecordedMarketData(t *testing.T) {
+	expiration := time.Date(2021, 2, 19, 0, 0, 0, 0, time.UTC)
+	start, end := time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 8, 0, 0, 0, 0, time.UTC)
+	analyze := func(source MarketDataSource) (float64, Greeks, []TimeSale) {
+		quotes, err := source.GetQuotes([]string{"SPY"})
+		assert.NoError(t, err)
+		chain, err := source.GetOptionChainWithGreeks("SPY", expiration)
+		assert.NoError(t, err)
+		bars, err := source.GetTimeSales("SPY", IntervalDaily, start, end)
+		assert.NoError(t, err)
+		if len(quotes) == 0 || len(chain) == 0 {
+			return 0, Greeks{}, nil
+		}
+		return quotes[0].Last, *chain[0].Greeks, bars
+	}
+
+	recorder := &RecordedMarketData{Source: &fakeMarketData{}}
+	last, greeks, bars := analyze(recorder)
+	data, err := json.Marshal(recorder)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var fixture RecordedMarketData
+	assert.NoError(t, json.Unmarshal(data, &fixture))
+	replayedLast, replayedGreeks, replayedBars := analyze(&fixture)
+	assert.Equal(t, last, replayedLast)
+	assert.Equal(t, greeks, replayedGreeks)
+	if assert.Len(t, replayedBars, len(bars)) {
+		assert.True(t, bars[0].Date.Equal(replayedBars[0].Date.Time))
+	}
+
+	bars, err = fixture.GetTimeSales("SPY", IntervalDaily, start.AddDate(0, 0, 1), end.AddDate(0, 0, -1))
+	assert.NoError(t, err)
+	assert.Len(t, bars, 3, "recorded bars are filtered by the range")
+	_, err = fixture.GetOptionChainWithGreeks("QQQ", expiration)
+	assert.Equal(t, ErrNotRecorded{Key: "QQQ/2021-02-19"}, err)
+}
+
+func TestStrategyQuotes(t *testing.T) {
+	strategies := RecognizeStrategies([]*Position{
+		{Symbol: "SPY210219C00360000", Quantity: 1},
+		{Symbol: "SPY210219C00370000", Quantity: -1},
+	})
+	source := &RecordedMarketData{Quotes: map[string]*Quote{
+		"SPY210219C00360000": {Symbol: "SPY210219C00360000", Bid: 5, Ask: 5.2},
+		"SPY210219C00370000": {Symbol: "SPY210219C00370000", Bid: 2, Ask: 2.2},
+	}}
+	quotes, err := StrategyQuotes(source, strategies)
+	assert.NoError(t, err)
+	assert.Len(t, quotes, 2)
+	if assert.Len(t, strategies, 1) {
+		assert.InDelta(t, 300.0, strategies[0].MarketValue(quotes), 1e-9)
+	}
+}
@@ -0,0 +1,222 @@
+package tradier
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// MarketEventsWebsocketEndpoint is the websocket endpoint for market events.
+const MarketEventsWebsocketEndpoint = "wss://ws.tradier.com/v1/markets/events"
+
+// MarketWebsocketStream streams market events over a websocket, whose
+// symbols can be changed without reconnecting.
+type MarketWebsocketStream struct {
+	conn         *websocket.Conn
+	sessionId    string
+	closeChan    chan struct{}
+	once         sync.Once
+	stallTimeout time.Duration
+	logger       Logger
+	span         Span
+	metrics      MetricsRecorder
+
+	// Guards writing to conn and the subscription.
+	writeMu sync.Mutex
+	symbols map[string]bool
+	filter  []Filter
+
+	mu  sync.Mutex
+	err error
+}
+
+type marketSubscription struct {
+	Symbols         []string `json:"symbols"`
+	SessionId       string   `json:"sessionid"`
+	Filter          []Filter `json:"filter,omitempty"`
+	Linebreak       bool     `json:"linebreak"`
+	AdvancedDetails bool     `json:"advancedDetails"`
+}
+
+// StreamMarketEventsWebsocket creates a streaming session and delivers market
+// events for the given symbols on output, until the stream is stopped or fails.
+// Filter restricts the type of events streamed; if nil then all events are streamed.
+// The output channel is closed when the stream ends.
+// https://documentation.tradier.com/brokerage-api/streaming/wss-market-websocket
+func (tc *Client) StreamMarketEventsWebsocket(
+	symbols []string, filter []Filter, output chan *StreamEvent) (*MarketWebsocketStream, error) {
+	if len(symbols) == 0 {
+		return nil, errors.New("list of symbols is required")
+	}
+
+	ctx, span := tc.startStreamSpan(context.Background(), "market", symbols)
+	session, err := tc.createStreamSession(ctx, "/v1/markets/events/session")
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	authorization, err := tc.authorization(ctx)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+	header := http.Header{"Authorization": authorization}
+	if tc.identityHeader != nil {
+		header[ServiceIdentityHeader] = tc.identityHeader
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(marketWebsocketURL(session.Url), header)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	mws := &MarketWebsocketStream{
+		conn:         conn,
+		sessionId:    session.SessionId,
+		closeChan:    make(chan struct{}),
+		stallTimeout: tc.streamStallTimeout,
+		logger:       tc.logger,
+		span:         span,
+		metrics:      tc.metrics,
+		symbols:      make(map[string]bool),
+		filter:       filter,
+	}
+	if err := mws.Subscribe(symbols...); err != nil {
+		conn.Close()
+		span.End(err)
+		return nil, err
+	}
+	go mws.consumeEvents(output)
+	return mws, nil
+}
+
+// The session URL is for the HTTP stream, so use the websocket endpoint
+// of the stream's host.
+func marketWebsocketURL(sessionURL string) string {
+	if sessionURL == "" || strings.HasPrefix(sessionURL, StreamEndpoint) {
+		return MarketEventsWebsocketEndpoint
+	}
+	return "ws" + strings.TrimPrefix(sessionURL, "http")
+}
+
+// Symbols returns the subscribed symbols.
+func (mws *MarketWebsocketStream) Symbols() []string {
+	mws.writeMu.Lock()
+	defer mws.writeMu.Unlock()
+	return mws.subscribedSymbols()
+}
+
+func (mws *MarketWebsocketStream) subscribedSymbols() []string {
+	symbols := make([]string, 0, len(mws.symbols))
+	for s := range mws.symbols {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Subscribe adds symbols to the stream.
+func (mws *MarketWebsocketStream) Subscribe(symbols ...string) error {
+	return mws.update(symbols, true)
+}
+
+// Unsubscribe removes symbols from the stream. Tradier requires at least one
+// symbol, so the last symbol cannot be removed.
+func (mws *MarketWebsocketStream) Unsubscribe(symbols ...string) error {
+	return mws.update(symbols, false)
+}
+
+// update sends the new subscription, which replaces the previous one.
+func (mws *MarketWebsocketStream) update(symbols []string, add bool) error {
+	mws.writeMu.Lock()
+	defer mws.writeMu.Unlock()
+
+	updated := make(map[string]bool, len(mws.symbols))
+	for s := range mws.symbols {
+		updated[s] = true
+	}
+	for _, s := range symbols {
+		if add {
+			updated[s] = true
+		} else {
+			delete(updated, s)
+		}
+	}
+	if len(updated) == 0 {
+		return errors.New("cannot unsubscribe from every symbol")
+	}
+
+	previous := mws.symbols
+	mws.symbols = updated
+	if err := mws.conn.WriteJSON(marketSubscription{
+		Symbols:         mws.subscribedSymbols(),
+		SessionId:       mws.sessionId,
+		Filter:          mws.filter,
+		Linebreak:       true,
+		AdvancedDetails: true,
+	}); err != nil {
+		mws.symbols = previous
+		return err
+	}
+	return nil
+}
+
+// Stop closes the stream.
+func (mws *MarketWebsocketStream) Stop() {
+	mws.once.Do(func() {
+		close(mws.closeChan)
+		mws.conn.Close()
+	})
+}
+
+// Err returns the error that ended the stream, if it was not stopped.
+func (mws *MarketWebsocketStream) Err() error {
+	mws.mu.Lock()
+	defer mws.mu.Unlock()
+	return mws.err
+}
+
+func (mws *MarketWebsocketStream) consumeEvents(output chan *StreamEvent) {
+	defer close(output)
+	defer func() { mws.span.End(mws.Err()) }()
+	for {
+		if mws.stallTimeout > 0 {
+			mws.conn.SetReadDeadline(time.Now().Add(mws.stallTimeout))
+		}
+		_, msg, err := mws.conn.ReadMessage()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				err = ErrStreamStalled{Timeout: mws.stallTimeout}
+			}
+			select {
+			case <-mws.closeChan:
+			default:
+				if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					mws.mu.Lock()
+					mws.err = err
+					mws.mu.Unlock()
+				}
+			}
+			return
+		}
+		mws.metrics.ObserveStreamBytes("market", len(msg))
+
+		event := &StreamEvent{}
+		if err := UnmarshalStreamEvent(msg, event); err != nil {
+			mws.logger.Warn("Malformed stream event", "error", err)
+		}
+		select {
+		case output <- event:
+		case <-mws.closeChan:
+			return
+		}
+	}
+}
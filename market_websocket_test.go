@@ -0,0 +1,71 @@
+package tradier
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_StreamMarketEventsWebsocket(t *testing.T) {
+	var server *httptest.Server
+	connections := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/markets/events/session":
+			fmt.Fprintf(w, `{"stream": {"url": "%s/v1/markets/events", "sessionid": "session-1"}}`, server.URL)
+		case "/v1/markets/events":
+			connections++
+			conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+
+			// Send a quote for each symbol of every subscription received.
+			for {
+				var sub marketSubscription
+				if err := conn.ReadJSON(&sub); err != nil {
+					return
+				}
+				assert.Equal(t, "session-1", sub.SessionId)
+				assert.Equal(t, []Filter{FilterQuote}, sub.Filter)
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"quote","symbol":"`+strings.Join(sub.Symbols, ",")+`"}`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+
+	events := make(chan *StreamEvent, 4)
+	stream, err := client.StreamMarketEventsWebsocket([]string{"SPY"}, []Filter{FilterQuote}, events)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "SPY", (<-events).Symbol)
+
+	assert.NoError(t, stream.Subscribe("QQQ", "IWM"))
+	assert.Equal(t, "IWM,QQQ,SPY", (<-events).Symbol)
+	assert.NoError(t, stream.Unsubscribe("SPY", "IWM"))
+	assert.Equal(t, "QQQ", (<-events).Symbol)
+	assert.Error(t, stream.Unsubscribe("QQQ"))
+	assert.Equal(t, []string{"QQQ"}, stream.Symbols())
+	assert.Equal(t, 1, connections, "symbols changed without reconnecting")
+
+	stream.Stop()
+	for range events {
+	}
+	assert.NoError(t, stream.Err())
+}
+
+func TestMarketWebsocketURL(t *testing.T) {
+	assert.Equal(t, MarketEventsWebsocketEndpoint, marketWebsocketURL(StreamEndpoint+"/v1/markets/events"))
+	assert.Equal(t, "ws://localhost:8080/v1/markets/events", marketWebsocketURL("http://localhost:8080/v1/markets/events"))
+}
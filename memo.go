@@ -0,0 +1,65 @@
+package tradier
+
+import (
+	"sync"
+	"time"
+)
+
+// getMemo collapses identical GET requests made within a short window
+// (or while one is already in flight) into a single API call.
+type getMemo struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*memoEntry
+}
+
+type memoEntry struct {
+	done    chan struct{}
+	body    []byte
+	err     error
+	expires time.Time
+}
+
+func newGetMemo(window time.Duration) *getMemo {
+	return &getMemo{
+		window:  window,
+		entries: make(map[string]*memoEntry),
+	}
+}
+
+// get returns the memoized response body for url, calling fetch if there
+// is no in-flight or unexpired response. Errors are shared with concurrent
+// callers but are not memoized.
+func (m *getMemo) get(url string, fetch func() ([]byte, error)) ([]byte, error) {
+	now := time.Now()
+	m.mu.Lock()
+	for key, e := range m.entries {
+		if !e.expires.IsZero() && now.After(e.expires) {
+			delete(m.entries, key)
+		}
+	}
+
+	if e, ok := m.entries[url]; ok {
+		m.mu.Unlock()
+		<-e.done
+		return e.body, e.err
+	}
+
+	e := &memoEntry{done: make(chan struct{})}
+	m.entries[url] = e
+	m.mu.Unlock()
+
+	e.body, e.err = fetch()
+
+	m.mu.Lock()
+	if e.err != nil {
+		delete(m.entries, url)
+	} else {
+		e.expires = time.Now().Add(m.window)
+	}
+	m.mu.Unlock()
+	close(e.done)
+
+	return e.body, e.err
+}
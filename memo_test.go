@@ -0,0 +1,65 @@
+package tradier
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getMemo(t *testing.T) {
+	t.Run("Concurrent requests share one fetch", func(t *testing.T) {
+		m := newGetMemo(time.Minute)
+		var calls int32
+		release := make(chan struct{})
+		fetch := func() ([]byte, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return []byte("ok"), nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				body, err := m.get("/v1/markets/clock", fetch)
+				assert.NoError(t, err)
+				assert.Equal(t, "ok", string(body))
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(t, int32(1), calls)
+	})
+
+	t.Run("Expired responses are refetched", func(t *testing.T) {
+		m := newGetMemo(time.Millisecond)
+		var calls int
+		fetch := func() ([]byte, error) {
+			calls++
+			return nil, nil
+		}
+		m.get("/v1/markets/clock", fetch)
+		time.Sleep(5 * time.Millisecond)
+		m.get("/v1/markets/clock", fetch)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Errors are not memoized", func(t *testing.T) {
+		m := newGetMemo(time.Minute)
+		var calls int
+		fetch := func() ([]byte, error) {
+			calls++
+			return nil, errors.New("connection reset")
+		}
+		_, err := m.get("/v1/markets/clock", fetch)
+		assert.Error(t, err)
+		m.get("/v1/markets/clock", fetch)
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -0,0 +1,209 @@
+package tradier
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsRecorder receives measurements of a client's API usage. Metrics
+// implements it, and it can be implemented with Prometheus vectors as shown
+// in the README. Endpoints are named as for spans, with the account number
+// and order ids replaced, e.g. "/v1/accounts/{account}/orders/{id}".
+type MetricsRecorder interface {
+	// ObserveRequest records a request with the status of its last response,
+	// or 0 if it failed without one, the number of times it was retried, and
+	// its latency including the retries.
+	ObserveRequest(method, endpoint string, status, retries int, latency time.Duration)
+	// ObserveRateLimitWait records a request that waited for the rate limit
+	// window of its category to renew.
+	ObserveRateLimitWait(category EndpointCategory, wait time.Duration)
+	// ObserveStreamReconnect records a managed stream reconnecting.
+	ObserveStreamReconnect(stream string)
+	// ObserveStreamBytes records data read from a stream.
+	ObserveStreamBytes(stream string, n int)
+}
+
+// Clients without a metrics recorder do not measure.
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(method, endpoint string, status, retries int, latency time.Duration) {
+}
+func (noopMetrics) ObserveRateLimitWait(category EndpointCategory, wait time.Duration) {}
+func (noopMetrics) ObserveStreamReconnect(stream string)                               {}
+func (noopMetrics) ObserveStreamBytes(stream string, n int)                            {}
+
+// Upper bounds of the request latency histogram buckets, in seconds, as
+// Prometheus' default buckets.
+var requestLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics accumulates the measurements of one or more clients, and serves
+// them in the Prometheus text format:
+//
+//	tradier_requests_total{method, endpoint, status}
+//	tradier_request_duration_seconds{method, endpoint} (histogram)
+//	tradier_retries_total{method, endpoint}
+//	tradier_rate_limit_waits_total{category}
+//	tradier_rate_limit_wait_seconds_total{category}
+//	tradier_stream_reconnects_total{stream}
+//	tradier_stream_bytes_total{stream}
+type Metrics struct {
+	mu             sync.Mutex
+	requests       map[[3]string]int
+	latencies      map[[2]string]*latencyHistogram
+	retries        map[[2]string]int
+	rateLimitWaits map[EndpointCategory]int
+	rateLimitWait  map[EndpointCategory]time.Duration
+	reconnects     map[string]int
+	streamBytes    map[string]int64
+}
+
+type latencyHistogram struct {
+	buckets []int
+	count   int
+	sum     time.Duration
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests:       make(map[[3]string]int),
+		latencies:      make(map[[2]string]*latencyHistogram),
+		retries:        make(map[[2]string]int),
+		rateLimitWaits: make(map[EndpointCategory]int),
+		rateLimitWait:  make(map[EndpointCategory]time.Duration),
+		reconnects:     make(map[string]int),
+		streamBytes:    make(map[string]int64),
+	}
+}
+
+func (m *Metrics) ObserveRequest(method, endpoint string, status, retries int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[[3]string{method, endpoint, strconv.Itoa(status)}]++
+	key := [2]string{method, endpoint}
+	h := m.latencies[key]
+	if h == nil {
+		h = &latencyHistogram{buckets: make([]int, len(requestLatencyBuckets))}
+		m.latencies[key] = h
+	}
+	for i, le := range requestLatencyBuckets {
+		if latency.Seconds() <= le {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += latency
+	if retries > 0 {
+		m.retries[key] += retries
+	}
+}
+
+func (m *Metrics) ObserveRateLimitWait(category EndpointCategory, wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rateLimitWaits[category]++
+	m.rateLimitWait[category] += wait
+}
+
+func (m *Metrics) ObserveStreamReconnect(stream string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reconnects[stream]++
+}
+
+func (m *Metrics) ObserveStreamBytes(stream string, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streamBytes[stream] += int64(n)
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format, for scraping.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WritePrometheus(w)
+}
+
+// WritePrometheus writes the metrics in the Prometheus text format.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	header := func(name, kind, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	header("tradier_requests_total", "counter", "Requests to the Tradier API, by the status of their last response.")
+	for _, key := range sortedKeys(m.requests) {
+		fmt.Fprintf(&b, "tradier_requests_total{method=%q,endpoint=%q,status=%q} %d\n", key[0], key[1], key[2], m.requests[key])
+	}
+
+	header("tradier_request_duration_seconds", "histogram", "Latency of requests to the Tradier API, including retries.")
+	for _, key := range sortedKeys(m.latencies) {
+		h := m.latencies[key]
+		labels := fmt.Sprintf("method=%q,endpoint=%q", key[0], key[1])
+		for i, le := range requestLatencyBuckets {
+			fmt.Fprintf(&b, "tradier_request_duration_seconds_bucket{%s,le=%q} %d\n", labels, strconv.FormatFloat(le, 'g', -1, 64), h.buckets[i])
+		}
+		fmt.Fprintf(&b, "tradier_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(&b, "tradier_request_duration_seconds_sum{%s} %g\n", labels, h.sum.Seconds())
+		fmt.Fprintf(&b, "tradier_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	header("tradier_retries_total", "counter", "Retries of requests to the Tradier API.")
+	for _, key := range sortedKeys(m.retries) {
+		fmt.Fprintf(&b, "tradier_retries_total{method=%q,endpoint=%q} %d\n", key[0], key[1], m.retries[key])
+	}
+
+	header("tradier_rate_limit_waits_total", "counter", "Requests delayed until their rate limit window renewed.")
+	for _, category := range sortedKeys(m.rateLimitWaits) {
+		fmt.Fprintf(&b, "tradier_rate_limit_waits_total{category=%q} %d\n", category, m.rateLimitWaits[category])
+	}
+	header("tradier_rate_limit_wait_seconds_total", "counter", "Time requests were delayed for their rate limit window.")
+	for _, category := range sortedKeys(m.rateLimitWait) {
+		fmt.Fprintf(&b, "tradier_rate_limit_wait_seconds_total{category=%q} %g\n", category, m.rateLimitWait[category].Seconds())
+	}
+
+	header("tradier_stream_reconnects_total", "counter", "Reconnections of managed streams.")
+	for _, stream := range sortedKeys(m.reconnects) {
+		fmt.Fprintf(&b, "tradier_stream_reconnects_total{stream=%q} %d\n", stream, m.reconnects[stream])
+	}
+	header("tradier_stream_bytes_total", "counter", "Data read from streams.")
+	for _, stream := range sortedKeys(m.streamBytes) {
+		fmt.Fprintf(&b, "tradier_stream_bytes_total{stream=%q} %d\n", stream, m.streamBytes[stream])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func sortedKeys[K interface {
+	~string | [2]string | [3]string
+}, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+	return keys
+}
+
+// countingReader reports the data read from a stream to a MetricsRecorder.
+type countingReader struct {
+	io.ReadCloser
+	metrics MetricsRecorder
+	stream  string
+}
+
+func (cr countingReader) Read(p []byte) (int, error) {
+	n, err := cr.ReadCloser.Read(p)
+	if n > 0 {
+		cr.metrics.ObserveStreamBytes(cr.stream, n)
+	}
+	return n, err
+}
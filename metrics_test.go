@@ -0,0 +1,92 @@
+package tradier
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Metrics(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/markets/quotes":
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"quotes": {"quote": {"symbol": "SPY"}}}`))
+		case "/v1/markets/events/session":
+			w.Write([]byte(`{"stream": {"url": "http://` + r.Host + `/v1/markets/events", "sessionid": "1"}}`))
+		case "/v1/markets/events":
+			w.Write([]byte(`{"type": "quote", "symbol": "SPY"}` + "\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	metrics := NewMetrics()
+	params := DefaultParams("secret-token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.Backoff = &backoff.ZeroBackOff{}
+	params.Metrics = metrics
+	client := NewClient(params)
+
+	_, err := client.GetQuotes([]string{"SPY"})
+	assert.NoError(t, err)
+	_, err = client.GetOrderStatus(42)
+	assert.Error(t, err)
+	stream, err := client.StreamMarketEvents([]string{"SPY"}, nil)
+	assert.NoError(t, err)
+	ioutil.ReadAll(stream)
+	stream.Close()
+
+	var buf bytes.Buffer
+	assert.NoError(t, metrics.WritePrometheus(&buf))
+	output := buf.String()
+	for _, line := range []string{
+		`tradier_requests_total{method="GET",endpoint="/v1/markets/quotes",status="200"} 1`,
+		`tradier_requests_total{method="GET",endpoint="/v1/accounts/{account}/orders/{id}",status="404"} 1`,
+		`tradier_retries_total{method="GET",endpoint="/v1/markets/quotes"} 1`,
+		`tradier_request_duration_seconds_count{method="GET",endpoint="/v1/markets/quotes"} 1`,
+		`tradier_request_duration_seconds_bucket{method="GET",endpoint="/v1/markets/quotes",le="+Inf"} 1`,
+		`tradier_stream_bytes_total{stream="market"} 35`,
+	} {
+		assert.Contains(t, output, line+"\n")
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.ObserveRequest("POST", "/v1/accounts/{account}/orders", 200, 0, 30*time.Millisecond)
+	metrics.ObserveRequest("POST", "/v1/accounts/{account}/orders", 200, 0, 3*time.Second)
+	metrics.ObserveRateLimitWait(CategoryTrading, 1500*time.Millisecond)
+	metrics.ObserveStreamReconnect("market")
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	output := recorder.Body.String()
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+	for _, line := range []string{
+		`# TYPE tradier_request_duration_seconds histogram`,
+		`tradier_request_duration_seconds_bucket{method="POST",endpoint="/v1/accounts/{account}/orders",le="0.025"} 0`,
+		`tradier_request_duration_seconds_bucket{method="POST",endpoint="/v1/accounts/{account}/orders",le="0.05"} 1`,
+		`tradier_request_duration_seconds_bucket{method="POST",endpoint="/v1/accounts/{account}/orders",le="5"} 2`,
+		`tradier_request_duration_seconds_sum{method="POST",endpoint="/v1/accounts/{account}/orders"} 3.03`,
+		`tradier_rate_limit_waits_total{category="trading"} 1`,
+		`tradier_rate_limit_wait_seconds_total{category="trading"} 1.5`,
+		`tradier_stream_reconnects_total{stream="market"} 1`,
+	} {
+		assert.Contains(t, output, line+"\n")
+	}
+}
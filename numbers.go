@@ -0,0 +1,170 @@
+package tradier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Tradier accepts prices to the cent, and prices under $1 to the hundredth
+// of a cent.
+const (
+	priceDecimals     = 2
+	subDollarDecimals = 4
+)
+
+// formatDecimal formats x with the given number of decimal places, or
+// returns false if x has more places than that, beyond the error of its
+// float64 representation.
+func formatDecimal(x float64, decimals int) (string, bool) {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return "", false
+	}
+	scaled := x * math.Pow10(decimals)
+	if math.Abs(scaled-math.Round(scaled)) > 1e-6 {
+		return "", false
+	}
+	return strconv.FormatFloat(x, 'f', decimals, 64), true
+}
+
+// formatPrice formats a price with the given number of decimal places, or
+// with as many as Tradier accepts for the price if decimals is zero. Prices
+// with more places are refused rather than rounded, so that an order is
+// never sent with a different price than it was given.
+func formatPrice(price float64, decimals int) (string, error) {
+	if decimals <= 0 {
+		decimals = priceDecimals
+		if math.Abs(price) < 1 {
+			decimals = subDollarDecimals
+		}
+	}
+	s, ok := formatDecimal(price, decimals)
+	if !ok {
+		return "", fmt.Errorf("%v has more than %d decimal places", price, decimals)
+	}
+	return s, nil
+}
+
+// formatQuantity formats a quantity of shares or contracts, which must be
+// a whole number.
+func formatQuantity(quantity float64) (string, error) {
+	s, ok := formatDecimal(quantity, 0)
+	if !ok {
+		return "", fmt.Errorf("%v is not a whole number", quantity)
+	}
+	return s, nil
+}
+
+// orderForm adds the fields of an order to the parameters of a request,
+// keeping the first price or quantity that cannot be formatted.
+type orderForm struct {
+	url.Values
+	decimals int
+	err      error
+}
+
+func newOrderForm(decimals int) *orderForm {
+	return &orderForm{Values: url.Values{}, decimals: decimals}
+}
+
+func (f *orderForm) price(key string, price float64) {
+	s, err := formatPrice(price, f.decimals)
+	f.add(key, s, err)
+}
+
+func (f *orderForm) quantity(key string, quantity float64) {
+	s, err := formatQuantity(quantity)
+	f.add(key, s, err)
+}
+
+func (f *orderForm) add(key, value string, err error) {
+	if err != nil && f.err == nil {
+		f.err = fmt.Errorf("%v: %w", key, err)
+	}
+	f.Add(key, value)
+}
+
+// unmarshalNumbers decodes data into v, a pointer to a struct, accepting
+// numeric fields sent as strings (e.g. "280.50"), as Tradier does for some
+// fields. Strings are parsed as JSON numbers regardless of locale, with
+// all of their precision, so that values decode exactly as if they had been
+// sent as numbers; empty strings and "null" decode as zero.
+func unmarshalNumbers(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Value != "string" {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return err
+	}
+	t := reflect.TypeOf(v).Elem()
+	for key, raw := range fields {
+		field, ok := fieldByJSONName(t, key)
+		if !ok || !isNumericKind(field.Type.Kind()) {
+			continue
+		}
+		var s string
+		if json.Unmarshal(raw, &s) != nil {
+			continue
+		}
+		s = strings.TrimSpace(s)
+		if s == "" || s == "null" {
+			fields[key] = json.RawMessage("null")
+		} else if isJSONNumber(s) {
+			fields[key] = json.RawMessage(s)
+		}
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// fieldByJSONName returns the field of struct type t that the JSON key
+// decodes into, matched case insensitively as by encoding/json.
+func fieldByJSONName(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isJSONNumber returns true if s is a number in JSON syntax, such as "-1.5"
+// or "2e3", but not "NaN", "+1", "1,000" or "0x10".
+func isJSONNumber(s string) bool {
+	if s[0] != '-' && (s[0] < '0' || s[0] > '9') {
+		return false
+	}
+	var n json.Number
+	return json.Unmarshal([]byte(s), &n) == nil
+}
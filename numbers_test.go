@@ -0,0 +1,171 @@
+package tradier
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatPrice(t *testing.T) {
+	for _, tc := range []struct {
+		price    float64
+		decimals int
+		expected string
+	}{
+		{400, 0, "400.00"},
+		{187.5, 0, "187.50"},
+		{0.1 + 0.2, 0, "0.3000"},
+		{0.0575, 0, "0.0575"},
+		{1.0575, 4, "1.0575"},
+		{52.1, 1, "52.1"},
+	} {
+		s, err := formatPrice(tc.price, tc.decimals)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, s)
+	}
+
+	for _, tc := range []struct {
+		price    float64
+		decimals int
+	}{
+		{187.505, 0},
+		{0.00001, 0},
+		{52.15, 1},
+		{math.NaN(), 0},
+	} {
+		_, err := formatPrice(tc.price, tc.decimals)
+		assert.Error(t, err, tc.price)
+	}
+}
+
+func Test_formatQuantity(t *testing.T) {
+	s, err := formatQuantity(100)
+	assert.NoError(t, err)
+	assert.Equal(t, "100", s)
+
+	_, err = formatQuantity(0.5)
+	assert.Error(t, err)
+}
+
+func Test_orderToParams_precision(t *testing.T) {
+	t.Run("Refuses rounding", func(t *testing.T) {
+		_, err := orderToParams(Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 400.125, Duration: Day}, 0)
+		assert.EqualError(t, err, "price: 400.125 has more than 2 decimal places")
+
+		_, err = orderToParams(Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 2.5, Type: MarketOrder, Duration: Day}, 0)
+		assert.EqualError(t, err, "quantity: 2.5 is not a whole number")
+
+		_, err = updateOrderParams(Order{Type: StopOrder, Duration: GTC, StopPrice: 99.999}, 0)
+		assert.EqualError(t, err, "stop: 99.999 has more than 2 decimal places")
+	})
+
+	t.Run("Sub-dollar prices", func(t *testing.T) {
+		form, err := orderToParams(Order{Class: Equity, Symbol: "XYZ", Side: Buy, Quantity: 1000, Type: LimitOrder, Price: 0.5525, Duration: Day}, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.5525", form.Get("price"))
+	})
+
+	t.Run("Configured decimals", func(t *testing.T) {
+		form, err := orderToParams(Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 400.125, Duration: Day}, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, "400.125", form.Get("price"))
+	})
+}
+
+func TestOrder_UnmarshalJSON(t *testing.T) {
+	t.Run("Numbers as strings", func(t *testing.T) {
+		var order Order
+		err := json.Unmarshal([]byte(`{"id": 228175, "symbol": "AAPL", "quantity": "50.00000000", "price": "187.5", "stop_price": "", "exec_quantity": " 0 ", "leg": [{"quantity": "1", "price": "0.0575"}]}`), &order)
+		assert.NoError(t, err)
+		assert.Equal(t, 228175, order.Id)
+		assert.Equal(t, 50.0, order.Quantity)
+		assert.Equal(t, 187.5, order.Price)
+		assert.Zero(t, order.StopPrice)
+		assert.Equal(t, 0.0575, order.Legs[0].Price)
+	})
+
+	t.Run("Legs", func(t *testing.T) {
+		var order Order
+		err := json.Unmarshal([]byte(`{"id": 1, "class": "otoco", "leg": [{"id": 2, "type": "limit"}, {"id": 3, "type": "limit"}, {"id": 4, "type": "stop"}]}`), &order)
+		assert.NoError(t, err)
+		assert.Len(t, order.Legs, 3)
+
+		order = Order{}
+		err = json.Unmarshal([]byte(`{"id": 1, "class": "multileg", "leg": {"id": 2, "option_symbol": "SPY240621C00530000"}}`), &order)
+		assert.NoError(t, err)
+		if assert.Len(t, order.Legs, 1) {
+			assert.Equal(t, "SPY240621C00530000", order.Legs[0].OptionSymbol)
+		}
+
+		// Orders encoded by this package keep their legs.
+		data, err := json.Marshal(Order{Class: Multileg, Legs: []Order{{Id: 2}, {Id: 3}}})
+		assert.NoError(t, err)
+		order = Order{}
+		assert.NoError(t, json.Unmarshal(data, &order))
+		assert.Len(t, order.Legs, 2)
+	})
+
+	t.Run("Invalid numbers", func(t *testing.T) {
+		for _, price := range []string{`"187,50"`, `"NaN"`, `"$187.50"`} {
+			var order Order
+			assert.Error(t, json.Unmarshal([]byte(`{"price": `+price+`}`), &order), price)
+		}
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 0.1 + 0.2, Duration: Day}
+		data, err := json.Marshal(order)
+		assert.NoError(t, err)
+		var decoded Order
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, order, decoded)
+
+		before, err := orderToParams(order, 0)
+		assert.NoError(t, err)
+		after, err := orderToParams(decoded, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
+
+func TestQuote_UnmarshalJSON(t *testing.T) {
+	t.Run("Numbers as strings", func(t *testing.T) {
+		var quote Quote
+		err := json.Unmarshal([]byte(`{"symbol": "SPY", "bid": "412.31", "ask": 412.33, "bidsize": "12", "greeks": {"delta": "-0.4512"}}`), &quote)
+		assert.NoError(t, err)
+		assert.Equal(t, 412.31, quote.Bid)
+		assert.Equal(t, 412.33, quote.Ask)
+		assert.Equal(t, 12, quote.BidSize)
+		assert.Equal(t, -0.4512, quote.Greeks.Delta)
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		quote := Quote{Symbol: "SPY", Last: 412.3125, Bid: 1e-7, Ask: 412.33, Volume: 1234567}
+		data, err := json.Marshal(quote)
+		assert.NoError(t, err)
+		var decoded Quote
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, quote, decoded)
+	})
+
+	t.Run("Option quote", func(t *testing.T) {
+		var oq OptionQuote
+		err := json.Unmarshal([]byte(`{"symbol": "SPY210219P00360000", "bid": "3.10", "Contract": {"Underlying": "SPY"}}`), &oq)
+		assert.NoError(t, err)
+		assert.Equal(t, 3.10, oq.Bid)
+		assert.Equal(t, "SPY", oq.Contract.Underlying)
+	})
+}
+
+func TestFloatOrNaN_MarshalJSON(t *testing.T) {
+	ts := TimeSale{Price: FloatOrNaN(math.NaN()), Close: 412.5}
+	data, err := json.Marshal(ts)
+	assert.NoError(t, err)
+
+	var decoded TimeSale
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, math.IsNaN(float64(decoded.Price)))
+	assert.Equal(t, FloatOrNaN(412.5), decoded.Close)
+}
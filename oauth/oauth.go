@@ -0,0 +1,222 @@
+// Package oauth implements Tradier's OAuth 2.0 authorization code flow:
+// building the authorize URL, exchanging the authorization code for an access
+// token, and refreshing the token before it expires.
+// https://documentation.tradier.com/brokerage-api/oauth/authorization-code
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	APIEndpoint = "https://api.tradier.com"
+
+	// Tokens are refreshed this long before they expire by default.
+	defaultRefreshBefore = 5 * time.Minute
+)
+
+// Scopes of access that may be requested.
+const (
+	ScopeRead   = "read"
+	ScopeWrite  = "write"
+	ScopeMarket = "market"
+	ScopeTrade  = "trade"
+	ScopeStream = "stream"
+)
+
+// ErrNoRefreshToken is returned when an expired token cannot be refreshed.
+var ErrNoRefreshToken = errors.New("token expired and has no refresh token")
+
+// Config is the configuration of an application registered with Tradier.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	// Scopes requested when authorizing.
+	Scopes []string
+	// Defaults to APIEndpoint.
+	Endpoint string
+	// Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Token is an access token granted to the application.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	Scope        string
+	Expiry       time.Time
+}
+
+// Expired returns true if the token expires within d of now.
+func (t *Token) Expired(now time.Time, d time.Duration) bool {
+	return !t.Expiry.IsZero() && !now.Add(d).Before(t.Expiry)
+}
+
+func (c *Config) endpoint() string {
+	if c.Endpoint == "" {
+		return APIEndpoint
+	}
+	return c.Endpoint
+}
+
+// AuthorizeURL returns the URL to which the user is sent to authorize the
+// application. Tradier redirects back with the code and the given state,
+// which should be checked to match.
+func (c *Config) AuthorizeURL(state string) string {
+	params := url.Values{}
+	params.Set("client_id", c.ClientID)
+	params.Set("scope", strings.Join(c.Scopes, ","))
+	params.Set("state", state)
+	return c.endpoint() + "/v1/oauth/authorize?" + params.Encode()
+}
+
+// Exchange exchanges the authorization code for an access token.
+func (c *Config) Exchange(ctx context.Context, code string) (*Token, error) {
+	return c.requestToken(ctx, "/v1/oauth/accesstoken", url.Values{
+		"grant_type": {"authorization_code"},
+		"code":       {code},
+	})
+}
+
+// Refresh exchanges the refresh token for a new access token.
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return c.requestToken(ctx, "/v1/oauth/refreshtoken", url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func (c *Config) requestToken(ctx context.Context, path string, form url.Values) (*Token, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint()+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.ClientID, c.ClientSecret)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	requested := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%v: %s", resp.Status, c.redact(body, form))
+	}
+
+	var result struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+		Scope        string
+		Status       string
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		// The body is not included, since it may hold a token.
+		return nil, errors.Wrap(err, "error decoding token")
+	} else if result.AccessToken == "" {
+		return nil, errors.Errorf("no access token granted: %s", c.redact(body, form))
+	}
+
+	token := &Token{
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		Scope:        result.Scope,
+	}
+	if result.ExpiresIn > 0 {
+		token.Expiry = requested.Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// TokenSource supplies a token that is refreshed before it expires. It can
+// be used as the TokenSource of a tradier.Client, so that long-running
+// services survive the expiry of their access token.
+type TokenSource struct {
+	// The token is refreshed when it expires within this. Defaults to 5m.
+	RefreshBefore time.Duration
+	// If set, Refreshed is called with each new token, e.g. to persist it.
+	Refreshed func(token *Token)
+
+	config *Config
+	mu     sync.Mutex
+	token  *Token
+}
+
+// TokenSource returns a source of token, refreshed with this config.
+func (c *Config) TokenSource(token *Token) *TokenSource {
+	return &TokenSource{
+		RefreshBefore: defaultRefreshBefore,
+		config:        c,
+		token:         token,
+	}
+}
+
+// Token returns a valid access token, refreshing it first if it is about to expire.
+func (ts *TokenSource) Token() (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if !ts.token.Expired(time.Now(), ts.RefreshBefore) {
+		return ts.token.AccessToken, nil
+	}
+	if ts.token.RefreshToken == "" {
+		return "", ErrNoRefreshToken
+	}
+
+	token, err := ts.config.Refresh(context.Background(), ts.token.RefreshToken)
+	if err != nil {
+		// Keep using the token until it has actually expired.
+		if !ts.token.Expired(time.Now(), 0) {
+			return ts.token.AccessToken, nil
+		}
+		return "", errors.Wrap(err, "error refreshing token")
+	}
+	// Refresh tokens may not be reissued with each refresh.
+	if token.RefreshToken == "" {
+		token.RefreshToken = ts.token.RefreshToken
+	}
+	ts.token = token
+	if ts.Refreshed != nil {
+		ts.Refreshed(token)
+	}
+	return token.AccessToken, nil
+}
+
+// Current returns the current token, without refreshing it.
+func (ts *TokenSource) Current() Token {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return *ts.token
+}
+
+// Returns the body of a response with the secrets of the request replaced,
+// for errors that echo the request.
+func (c *Config) redact(body []byte, form url.Values) string {
+	text := string(body)
+	secrets := []string{c.ClientSecret, form.Get("refresh_token"), form.Get("code")}
+	for _, secret := range secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, "[REDACTED]")
+		}
+	}
+	return text
+}
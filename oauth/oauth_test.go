@@ -0,0 +1,105 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gnagel/go-tradier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_AuthorizeURL(t *testing.T) {
+	config := &Config{ClientID: "app", Scopes: []string{ScopeRead, ScopeTrade}}
+	u, err := url.Parse(config.AuthorizeURL("xyz"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "api.tradier.com", u.Host)
+		assert.Equal(t, "/v1/oauth/authorize", u.Path)
+		assert.Equal(t, "app", u.Query().Get("client_id"))
+		assert.Equal(t, "read,trade", u.Query().Get("scope"))
+		assert.Equal(t, "xyz", u.Query().Get("state"))
+	}
+}
+
+func TestTokenSource(t *testing.T) {
+	var refreshes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, _ := r.BasicAuth(); r.URL.Path != "/v1/markets/clock" && (id != "app" || secret != "secret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/oauth/accesstoken":
+			assert.Equal(t, "authorization_code", r.FormValue("grant_type"))
+			assert.Equal(t, "code", r.FormValue("code"))
+			// Expires immediately, so that the first use refreshes it.
+			w.Write([]byte(`{"access_token": "first", "refresh_token": "refresh", "expires_in": 1, "scope": "read", "status": "approved"}`))
+		case "/v1/oauth/refreshtoken":
+			assert.Equal(t, "refresh_token", r.FormValue("grant_type"))
+			assert.Equal(t, "refresh", r.FormValue("refresh_token"))
+			refreshes++
+			w.Write([]byte(`{"access_token": "second", "expires_in": 86399, "scope": "read", "status": "approved"}`))
+		case "/v1/markets/clock":
+			assert.Equal(t, "Bearer second", r.Header.Get("Authorization"))
+			w.Write([]byte(`{"clock": {"state": "open"}}`))
+		}
+	}))
+	defer server.Close()
+
+	config := &Config{ClientID: "app", ClientSecret: "secret", Endpoint: server.URL}
+	token, err := config.Exchange(context.Background(), "code")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "first", token.AccessToken)
+	assert.WithinDuration(t, time.Now().Add(time.Second), token.Expiry, time.Second)
+
+	var refreshed []*Token
+	source := config.TokenSource(token)
+	source.Refreshed = func(token *Token) { refreshed = append(refreshed, token) }
+
+	params := tradier.DefaultParams("")
+	params.Endpoint = server.URL
+	params.TokenSource = source
+	client := tradier.NewClient(params)
+	for i := 0; i < 2; i++ {
+		status, err := client.GetMarketState()
+		assert.NoError(t, err)
+		assert.Equal(t, tradier.MarketOpen, status.State)
+	}
+	assert.Equal(t, 1, refreshes)
+	if assert.Len(t, refreshed, 1) {
+		// The refresh token is kept if a new one is not issued.
+		assert.Equal(t, "refresh", refreshed[0].RefreshToken)
+	}
+	assert.Equal(t, "second", source.Current().AccessToken)
+
+	t.Run("Expired without refresh token", func(t *testing.T) {
+		source := config.TokenSource(&Token{AccessToken: "old", Expiry: time.Now().Add(-time.Minute)})
+		_, err := source.Token()
+		assert.Equal(t, ErrNoRefreshToken, err)
+	})
+
+	t.Run("Errors are redacted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid request " + r.FormValue("refresh_token")))
+		}))
+		defer server.Close()
+		config := &Config{ClientID: "app", ClientSecret: "secret", Endpoint: server.URL}
+		_, err := config.Refresh(context.Background(), "refresh-secret")
+		if assert.Error(t, err) {
+			assert.NotContains(t, err.Error(), "refresh-secret")
+			assert.Contains(t, err.Error(), "[REDACTED]")
+		}
+	})
+
+	t.Run("Invalid client", func(t *testing.T) {
+		config := &Config{ClientID: "app", ClientSecret: "wrong", Endpoint: server.URL}
+		_, err := config.Exchange(context.Background(), "code")
+		assert.Error(t, err)
+	})
+}
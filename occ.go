@@ -0,0 +1,63 @@
+package tradier
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var occSymbolRegexp = regexp.MustCompile(`^([A-Z0-9.]{1,6})(\d{6})([CP])(\d{8})$`)
+
+// OptionSymbol is a parsed OCC option symbol, e.g. SPY190621C00250000.
+type OptionSymbol struct {
+	Underlying string
+	Expiration time.Time
+	OptionType string
+	Strike     float64
+}
+
+// ParseOptionSymbol parses an OCC option symbol.
+func ParseOptionSymbol(symbol string) (OptionSymbol, error) {
+	m := occSymbolRegexp.FindStringSubmatch(symbol)
+	if m == nil {
+		return OptionSymbol{}, fmt.Errorf("invalid OCC option symbol: %q", symbol)
+	}
+
+	expiration, err := time.Parse("060102", m[2])
+	if err != nil {
+		return OptionSymbol{}, fmt.Errorf("invalid expiration in option symbol %q: %v", symbol, err)
+	}
+	strike, err := strconv.ParseInt(m[4], 10, 64)
+	if err != nil {
+		return OptionSymbol{}, fmt.Errorf("invalid strike in option symbol %q: %v", symbol, err)
+	}
+
+	optionType := Call
+	if m[3] == "P" {
+		optionType = Put
+	}
+
+	return OptionSymbol{
+		Underlying: m[1],
+		Expiration: expiration,
+		OptionType: optionType,
+		Strike:     float64(strike) / 1000,
+	}, nil
+}
+
+// IsOptionSymbol returns true if the symbol is a valid OCC option symbol.
+func IsOptionSymbol(symbol string) bool {
+	_, err := ParseOptionSymbol(symbol)
+	return err == nil
+}
+
+// String formats the option symbol in OCC format.
+func (os OptionSymbol) String() string {
+	optionType := "C"
+	if os.OptionType == Put {
+		optionType = "P"
+	}
+	strike := int64(os.Strike*1000 + 0.5)
+	return fmt.Sprintf("%s%s%s%08d", os.Underlying, os.Expiration.Format("060102"), optionType, strike)
+}
@@ -0,0 +1,20 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOptionSymbol(t *testing.T) {
+	os, err := ParseOptionSymbol("SPY190621C00250500")
+	assert.NoError(t, err)
+	assert.Equal(t, "SPY", os.Underlying)
+	assert.Equal(t, Call, os.OptionType)
+	assert.Equal(t, 250.5, os.Strike)
+	assert.Equal(t, "2019-06-21", os.Expiration.Format("2006-01-02"))
+	assert.Equal(t, "SPY190621C00250500", os.String())
+
+	_, err = ParseOptionSymbol("SPY")
+	assert.Error(t, err)
+}
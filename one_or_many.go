@@ -0,0 +1,60 @@
+package tradier
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// decodeOneOrMany decodes a value that Tradier sends as a single object
+// when there is one element, and as a list when there are several.
+// An empty envelope (null, or the string "null") decodes to no elements.
+func decodeOneOrMany[T any](data []byte) ([]T, error) {
+	data = bytes.TrimSpace(data)
+	if isNull(data) {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		values := make([]T, 0)
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return []T{value}, nil
+}
+
+// isNull returns true if data is an empty JSON value: null, or the string
+// "null" that Tradier sends in place of empty objects and lists.
+func isNull(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) == 0 || bytes.Equal(data, []byte("null")) || bytes.Equal(data, []byte(`"null"`))
+}
+
+// oneOrMany is a list decoded with decodeOneOrMany.
+type oneOrMany[T any] []T
+
+func (om *oneOrMany[T]) UnmarshalJSON(data []byte) error {
+	values, err := decodeOneOrMany[T](data)
+	*om = values
+	return err
+}
+
+// nullable is an object that Tradier replaces with null or "null" when it
+// is empty, e.g. {"positions": "null"} for an account without positions.
+// An empty object decodes to the zero value.
+type nullable[T any] struct {
+	Value T
+}
+
+func (n *nullable[T]) UnmarshalJSON(data []byte) error {
+	if isNull(data) {
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
@@ -0,0 +1,114 @@
+package tradier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeOneOrMany(t *testing.T) {
+	t.Run("list", func(t *testing.T) {
+		values, err := decodeOneOrMany[string]([]byte(`["a", "b"]`))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, values)
+	})
+
+	t.Run("single", func(t *testing.T) {
+		values, err := decodeOneOrMany[*Quote]([]byte(`{"symbol": "SPY"}`))
+		assert.NoError(t, err)
+		if assert.Len(t, values, 1) {
+			assert.Equal(t, "SPY", values[0].Symbol)
+		}
+	})
+
+	t.Run("empty envelope", func(t *testing.T) {
+		for _, data := range []string{``, `null`, ` "null" `} {
+			values, err := decodeOneOrMany[*Quote]([]byte(data))
+			assert.NoError(t, err, data)
+			assert.Empty(t, values, data)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := decodeOneOrMany[int64]([]byte(`["a"]`))
+		assert.Error(t, err)
+		_, err = decodeOneOrMany[int64]([]byte(`{"a": 1}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestQuoteList_UnmarshalJSON(t *testing.T) {
+	var result struct {
+		Quotes struct {
+			Quote quoteList
+		}
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"quotes": {"quote": null}}`), &result))
+	assert.Empty(t, result.Quotes.Quote)
+
+	err := json.Unmarshal([]byte(`{"quotes": {"quote": {"last": "x"}}}`), &result)
+	assert.Error(t, err)
+}
+
+// Tradier replaces empty objects and lists with null or the string "null".
+func TestClient_nullResponses(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// e.g. {"positions": "null"} for the positions endpoint.
+		envelope := map[string]string{
+			"positions":   "positions",
+			"history":     "history",
+			"gainloss":    "gainloss",
+			"orders":      "orders",
+			"etb":         "securities",
+			"expirations": "expirations",
+			"strikes":     "strikes",
+			"chains":      "options",
+			"quotes":      "quotes",
+			"timesales":   "series",
+		}[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]]
+		w.Write([]byte(`{"` + envelope + `": ` + body + `}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000001"
+	client := NewClient(params)
+	day := time.Date(2021, time.February, 19, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	endpoints := map[string]func() (interface{}, error){
+		"positions": func() (interface{}, error) { return client.GetAccountPositions() },
+		"history":   func() (interface{}, error) { return client.GetAccountHistory(0) },
+		"gainloss":  func() (interface{}, error) { return client.GetAccountCostBasis() },
+		"orders":    func() (interface{}, error) { return client.GetOpenOrders() },
+		"etb":       func() (interface{}, error) { return client.GetEasyToBorrow() },
+		"expirations": func() (interface{}, error) {
+			return client.GetOptionExpirationDates("SPY")
+		},
+		"strikes": func() (interface{}, error) { return client.GetOptionStrikes("SPY", day) },
+		"chain":   func() (interface{}, error) { return client.GetOptionChain("SPY", day) },
+		"quotes":  func() (interface{}, error) { return client.GetQuotes([]string{"SPY"}) },
+		"timesales": func() (interface{}, error) {
+			return client.GetTimeSales("SPY", IntervalMinute, now.Add(-time.Hour), now)
+		},
+		"history bars": func() (interface{}, error) {
+			return client.GetTimeSales("SPY", IntervalDaily, day, day)
+		},
+	}
+	for _, body = range []string{`null`, `"null"`} {
+		for name, get := range endpoints {
+			t.Run(body+" "+name, func(t *testing.T) {
+				values, err := get()
+				assert.NoError(t, err)
+				assert.Empty(t, values)
+			})
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package tradier
+
+import (
+	"fmt"
+	"sync"
+)
+
+// OptionLevel is the option trading approval level of an account. Each
+// level permits the strategies of the levels below it.
+type OptionLevel int
+
+const (
+	// Options trading is not approved.
+	OptionLevelNone OptionLevel = 0
+	// Covered calls and cash-secured puts.
+	OptionLevelCovered OptionLevel = 1
+	// Buying calls and puts.
+	OptionLevelLong OptionLevel = 2
+	// Spreads.
+	OptionLevelSpreads OptionLevel = 3
+	// Uncovered puts.
+	OptionLevelNakedPuts OptionLevel = 4
+	// Uncovered calls.
+	OptionLevelNakedCalls OptionLevel = 5
+)
+
+func (ol OptionLevel) String() string {
+	switch {
+	case ol <= OptionLevelNone:
+		return "level 0 (no options)"
+	case ol == OptionLevelCovered:
+		return "level 1 (covered calls and cash-secured puts)"
+	case ol == OptionLevelLong:
+		return "level 2 (long calls and puts)"
+	case ol == OptionLevelSpreads:
+		return "level 3 (spreads)"
+	case ol == OptionLevelNakedPuts:
+		return "level 4 (uncovered puts)"
+	default:
+		return fmt.Sprintf("level %d (uncovered calls)", int(ol))
+	}
+}
+
+// ErrOptionLevel is returned for orders that require a higher option level
+// than the account is approved for.
+type ErrOptionLevel struct {
+	Account  string
+	Required OptionLevel
+	Approved OptionLevel
+	Reason   string
+}
+
+func (e ErrOptionLevel) Error() string {
+	return fmt.Sprintf("%v requires option %v, but account %v is approved for %v", e.Reason, e.Required, e.Account, e.Approved)
+}
+
+// RequiredOptionLevel returns the option level that the order requires, and
+// the strategy that requires it, given the account's positions. Orders that
+// only close positions, or that have no option legs, require no level.
+//
+// Calls sold to open are covered by the account's shares of the underlying,
+// or by calls bought to open in the same order, and puts sold to open by
+// puts bought in the same order. Otherwise puts are assumed to be cash
+// secured, which Tradier checks against the account's cash when they are
+// placed.
+func RequiredOptionLevel(order Order, positions []*Position) (OptionLevel, string) {
+	legs := []*Order{&order}
+	if len(order.Legs) > 0 {
+		legs = legs[:0]
+		for i := range order.Legs {
+			legs = append(legs, &order.Legs[i])
+		}
+	}
+	combined := order.Class == Multileg || order.Class == Combo
+	shares := make(map[string]float64)
+	for _, p := range positions {
+		if !IsOptionSymbol(p.Symbol) {
+			shares[p.Symbol] += p.Quantity
+		}
+	}
+
+	// Contracts bought to open in the order, by underlying and type.
+	bought := make(map[[2]string]float64)
+	for _, leg := range legs {
+		if contract, err := ParseOptionSymbol(orderSymbol(leg)); err == nil && leg.Side == BuyToOpen {
+			bought[[2]string{contract.Underlying, contract.OptionType}] += leg.Quantity
+		}
+	}
+
+	required, strategy := OptionLevelNone, ""
+	require := func(level OptionLevel, reason string) {
+		if level > required {
+			required, strategy = level, reason
+		}
+	}
+	for _, leg := range legs {
+		contract, err := ParseOptionSymbol(orderSymbol(leg))
+		if err != nil {
+			continue
+		}
+		key := [2]string{contract.Underlying, contract.OptionType}
+		switch {
+		case leg.Side == BuyToOpen:
+			require(OptionLevelLong, "buying "+contract.OptionType+"s")
+		case leg.Side != SellToOpen:
+			// Closing positions requires no approval.
+		case combined && bought[key] >= leg.Quantity:
+			bought[key] -= leg.Quantity
+			require(OptionLevelSpreads, "a spread")
+		case contract.OptionType == Call && shares[contract.Underlying] >= 100*leg.Quantity:
+			shares[contract.Underlying] -= 100 * leg.Quantity
+			require(OptionLevelCovered, "a covered call")
+		case contract.OptionType == Call:
+			require(OptionLevelNakedCalls, "an uncovered call")
+		default:
+			require(OptionLevelCovered, "a cash-secured put")
+		}
+	}
+	return required, strategy
+}
+
+// Option levels of accounts, read from the user profile once.
+type optionLevelCache struct {
+	mu     sync.Mutex
+	levels map[string]OptionLevel
+}
+
+// Return the option level of the selected account.
+func (tc *Client) optionLevel() (OptionLevel, error) {
+	tc.optionLevels.mu.Lock()
+	defer tc.optionLevels.mu.Unlock()
+	if level, ok := tc.optionLevels.levels[tc.account]; ok {
+		return level, nil
+	}
+
+	profile, err := tc.GetUserProfile()
+	if err != nil {
+		return OptionLevelNone, err
+	}
+	if tc.optionLevels.levels == nil {
+		tc.optionLevels.levels = make(map[string]OptionLevel)
+	}
+	for _, account := range profile.Account {
+		tc.optionLevels.levels[account.AccountNumber] = account.OptionLevel
+	}
+	level, ok := tc.optionLevels.levels[tc.account]
+	if !ok {
+		return OptionLevelNone, fmt.Errorf("account %v is not in the user profile", tc.account)
+	}
+	return level, nil
+}
+
+// Check that the account is approved for the option level that the order
+// requires. Positions are only requested for orders that sell calls to open.
+func (tc *Client) checkOptionLevel(order Order) error {
+	if !tc.checkOptionLevels {
+		return nil
+	}
+	required, strategy := RequiredOptionLevel(order, nil)
+	if required == OptionLevelNone {
+		return nil
+	}
+	approved, err := tc.optionLevel()
+	if err != nil {
+		return err
+	}
+	if required > approved && required == OptionLevelNakedCalls {
+		// The calls may be covered by shares held.
+		positions, err := tc.GetAccountPositions()
+		if err != nil {
+			return err
+		}
+		required, strategy = RequiredOptionLevel(order, positions)
+	}
+	if required > approved {
+		return ErrOptionLevel{Account: tc.account, Required: required, Approved: approved, Reason: strategy}
+	}
+	return nil
+}
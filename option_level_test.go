@@ -0,0 +1,83 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredOptionLevel(t *testing.T) {
+	call := func(side string, quantity float64) Order {
+		return Order{Class: Option, Symbol: "SPY", OptionSymbol: "SPY210219C00360000", Side: side, Quantity: quantity}
+	}
+	shares := []*Position{{Symbol: "SPY", Quantity: 150}}
+	spread := Order{Class: Multileg, Symbol: "SPY", Legs: []Order{
+		{OptionSymbol: "SPY210219C00360000", Side: BuyToOpen, Quantity: 1},
+		{OptionSymbol: "SPY210219C00370000", Side: SellToOpen, Quantity: 1},
+	}}
+
+	for _, test := range []struct {
+		name      string
+		order     Order
+		positions []*Position
+		level     OptionLevel
+	}{
+		{"Equity", Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1}, nil, OptionLevelNone},
+		{"Long call", call(BuyToOpen, 1), nil, OptionLevelLong},
+		{"Closing", call(SellToClose, 1), nil, OptionLevelNone},
+		{"Covered call", call(SellToOpen, 1), shares, OptionLevelCovered},
+		{"Partly covered call", call(SellToOpen, 2), shares, OptionLevelNakedCalls},
+		{"Uncovered call", call(SellToOpen, 1), nil, OptionLevelNakedCalls},
+		{"Short put", Order{Class: Option, OptionSymbol: "SPY210219P00360000", Side: SellToOpen, Quantity: 1}, nil, OptionLevelCovered},
+		{"Spread", spread, nil, OptionLevelSpreads},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			level, _ := RequiredOptionLevel(test.order, test.positions)
+			assert.Equal(t, test.level, level)
+		})
+	}
+}
+
+func TestClient_CheckOptionLevel(t *testing.T) {
+	var profiles, positions, placed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/user/profile":
+			profiles++
+			w.Write([]byte(`{"profile": {"id": "id-test", "account": [{"account_number": "VA000", "option_level": 2, "status": "active"}]}}`))
+		case "/v1/accounts/VA000/positions":
+			positions++
+			w.Write([]byte(`{"positions": {"position": [{"symbol": "SPY", "quantity": 100}]}}`))
+		case "/v1/accounts/VA000/orders":
+			placed++
+			w.Write([]byte(`{"order": {"id": 1, "status": "ok"}}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.Account = "VA000"
+	params.CheckOptionLevel = true
+	client := NewClient(params)
+
+	_, err := client.PlaceOrder(Order{Class: Option, Symbol: "QQQ", OptionSymbol: "QQQ210219C00300000", Side: SellToOpen, Quantity: 1, Type: MarketOrder, Duration: Day})
+	if assert.IsType(t, ErrOptionLevel{}, err) {
+		assert.Equal(t, OptionLevelNakedCalls, err.(ErrOptionLevel).Required)
+		assert.Equal(t, OptionLevelLong, err.(ErrOptionLevel).Approved)
+		assert.Contains(t, err.Error(), "uncovered call")
+	}
+	assert.Zero(t, placed)
+
+	_, err = client.PlaceOrder(Order{Class: Option, Symbol: "SPY", OptionSymbol: "SPY210219C00360000", Side: SellToOpen, Quantity: 1, Type: MarketOrder, Duration: Day})
+	assert.NoError(t, err, "covered by shares")
+	_, err = client.PlaceOrder(Order{Class: Option, Symbol: "SPY", OptionSymbol: "SPY210219P00360000", Side: BuyToOpen, Quantity: 1, Type: MarketOrder, Duration: Day})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, placed)
+	assert.Equal(t, 1, profiles, "the profile is requested once")
+	assert.Equal(t, 2, positions, "positions are only requested for calls sold to open")
+}
@@ -0,0 +1,194 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+)
+
+// OrderBuilder builds an equity or option order step by step, e.g.
+//
+//	order, err := tradier.NewEquityOrder("AAPL").Buy(100).Limit(187.50).Day().Build()
+//
+// Build reports the first inconsistency of the order, such as a side for the
+// wrong class, a missing price or a type set twice, as an *OrderFieldError.
+type OrderBuilder struct {
+	order Order
+	err   error
+}
+
+// NewEquityOrder starts an equity order for the symbol.
+func NewEquityOrder(symbol string) *OrderBuilder {
+	b := &OrderBuilder{order: Order{Class: Equity, Symbol: symbol}}
+	if symbol == "" {
+		b.fail("symbol", symbol, "symbol is required")
+	} else if _, err := ParseOptionSymbol(symbol); err == nil {
+		b.fail("symbol", symbol, "option symbol of an equity order; use NewOptionOrder")
+	}
+	return b
+}
+
+// NewOptionOrder starts an order for the option contract, by its OCC symbol.
+func NewOptionOrder(optionSymbol string) *OrderBuilder {
+	b := &OrderBuilder{order: Order{Class: Option, OptionSymbol: optionSymbol}}
+	contract, err := ParseOptionSymbol(optionSymbol)
+	if err != nil {
+		b.fail("option_symbol", optionSymbol, "invalid OCC option symbol")
+	} else {
+		b.order.Symbol = contract.Underlying
+	}
+	return b
+}
+
+// Records the first error of the order.
+func (b *OrderBuilder) fail(field string, value interface{}, reason string) *OrderBuilder {
+	if b.err == nil {
+		b.err = &OrderFieldError{Field: field, Value: value, Reason: reason}
+	}
+	return b
+}
+
+func (b *OrderBuilder) side(side string, quantity float64) *OrderBuilder {
+	if b.order.Side != "" {
+		return b.fail("side", side, fmt.Sprintf("side is already %v", b.order.Side))
+	}
+	option := side == BuyToOpen || side == BuyToClose || side == SellToOpen || side == SellToClose
+	if option && b.order.Class != Option {
+		b.fail("side", side, "option side of an equity order")
+	} else if !option && b.order.Class == Option {
+		b.fail("side", side, "equity side of an option order")
+	}
+	if quantity <= 0 {
+		b.fail("quantity", quantity, "quantity must be positive")
+	} else if quantity != math.Trunc(quantity) {
+		b.fail("quantity", quantity, "quantity must be a whole number")
+	}
+	b.order.Side, b.order.Quantity = side, quantity
+	return b
+}
+
+// Buy buys quantity shares.
+func (b *OrderBuilder) Buy(quantity float64) *OrderBuilder { return b.side(Buy, quantity) }
+
+// Sell sells quantity shares.
+func (b *OrderBuilder) Sell(quantity float64) *OrderBuilder { return b.side(Sell, quantity) }
+
+// SellShort sells quantity shares short.
+func (b *OrderBuilder) SellShort(quantity float64) *OrderBuilder { return b.side(SellShort, quantity) }
+
+// BuyToCover buys quantity shares to cover a short position.
+func (b *OrderBuilder) BuyToCover(quantity float64) *OrderBuilder {
+	return b.side(BuyToCover, quantity)
+}
+
+// BuyToOpen buys quantity contracts to open a position.
+func (b *OrderBuilder) BuyToOpen(quantity float64) *OrderBuilder { return b.side(BuyToOpen, quantity) }
+
+// BuyToClose buys quantity contracts to close a short position.
+func (b *OrderBuilder) BuyToClose(quantity float64) *OrderBuilder {
+	return b.side(BuyToClose, quantity)
+}
+
+// SellToOpen sells quantity contracts to open a short position.
+func (b *OrderBuilder) SellToOpen(quantity float64) *OrderBuilder {
+	return b.side(SellToOpen, quantity)
+}
+
+// SellToClose sells quantity contracts to close a position.
+func (b *OrderBuilder) SellToClose(quantity float64) *OrderBuilder {
+	return b.side(SellToClose, quantity)
+}
+
+func (b *OrderBuilder) orderType(orderType string, price, stop float64) *OrderBuilder {
+	if b.order.Type != "" {
+		return b.fail("type", orderType, fmt.Sprintf("type is already %v", b.order.Type))
+	}
+	if (orderType == LimitOrder || orderType == StopLimitOrder) && price <= 0 {
+		b.fail("price", price, "limit price must be positive")
+	}
+	if (orderType == StopOrder || orderType == StopLimitOrder) && stop <= 0 {
+		b.fail("stop", stop, "stop price must be positive")
+	}
+	b.order.Type, b.order.Price, b.order.StopPrice = orderType, price, stop
+	return b
+}
+
+// Market makes the order a market order.
+func (b *OrderBuilder) Market() *OrderBuilder { return b.orderType(MarketOrder, 0, 0) }
+
+// Limit makes the order a limit order at price.
+func (b *OrderBuilder) Limit(price float64) *OrderBuilder { return b.orderType(LimitOrder, price, 0) }
+
+// Stop makes the order a stop order, triggered at stop.
+func (b *OrderBuilder) Stop(stop float64) *OrderBuilder { return b.orderType(StopOrder, 0, stop) }
+
+// StopLimit makes the order a stop limit order at price, triggered at stop.
+func (b *OrderBuilder) StopLimit(stop, price float64) *OrderBuilder {
+	return b.orderType(StopLimitOrder, price, stop)
+}
+
+func (b *OrderBuilder) duration(duration string) *OrderBuilder {
+	if b.order.Duration != "" {
+		return b.fail("duration", duration, fmt.Sprintf("duration is already %v", b.order.Duration))
+	}
+	b.order.Duration = duration
+	return b
+}
+
+// Day makes the order good for the day. Orders are day orders unless
+// another duration is set.
+func (b *OrderBuilder) Day() *OrderBuilder { return b.duration(Day) }
+
+// GTC makes the order good until canceled.
+func (b *OrderBuilder) GTC() *OrderBuilder { return b.duration(GTC) }
+
+// PreMarket makes the order an equity limit order for the pre-market session.
+func (b *OrderBuilder) PreMarket() *OrderBuilder { return b.duration(PreMarket) }
+
+// PostMarket makes the order an equity limit order for the post-market session.
+func (b *OrderBuilder) PostMarket() *OrderBuilder { return b.duration(PostMarket) }
+
+// Tag tags the order, e.g. to find it among the account's orders. Tags may
+// only contain letters, digits and dashes.
+func (b *OrderBuilder) Tag(tag string) *OrderBuilder {
+	if !validOrderTag(tag) {
+		b.fail("tag", tag, "tag must be 1 to 255 letters, digits or dashes")
+	}
+	b.order.Tag = tag
+	return b
+}
+
+// Build returns the order, or the first error in building it.
+func (b *OrderBuilder) Build() (Order, error) {
+	if b.err != nil {
+		return Order{}, b.err
+	}
+	order := b.order
+	if order.Side == "" {
+		return Order{}, &OrderFieldError{Field: "side", Value: order.Side, Reason: "side is required"}
+	}
+	if order.Type == "" {
+		return Order{}, &OrderFieldError{Field: "type", Value: order.Type, Reason: "type is required"}
+	}
+	if order.Duration == "" {
+		order.Duration = Day
+	}
+	if order.Duration == PreMarket || order.Duration == PostMarket {
+		if order.Class != Equity || order.Type != LimitOrder {
+			return Order{}, &OrderFieldError{Field: "duration", Value: order.Duration,
+				Reason: "extended hours orders must be equity limit orders"}
+		}
+	}
+	return order, checkOrderFields(order, "")
+}
+
+func validOrderTag(tag string) bool {
+	if len(tag) == 0 || len(tag) > 255 {
+		return false
+	}
+	for _, r := range tag {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
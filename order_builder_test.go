@@ -0,0 +1,64 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderBuilder(t *testing.T) {
+	t.Run("Equity", func(t *testing.T) {
+		order, err := NewEquityOrder("AAPL").Buy(100).Limit(187.50).Day().Build()
+		assert.NoError(t, err)
+		assert.Equal(t, Order{Class: Equity, Symbol: "AAPL", Side: Buy, Quantity: 100,
+			Type: LimitOrder, Price: 187.50, Duration: Day}, order)
+
+		_, err = NewEquityOrder("AAPL").SellShort(10).StopLimit(180, 179.5).PostMarket().Build()
+		if assert.Error(t, err) {
+			assert.Equal(t, "duration", err.(*OrderFieldError).Field)
+		}
+		order, err = NewEquityOrder("AAPL").Sell(10).Market().Tag("exit-1").Build()
+		assert.NoError(t, err)
+		assert.Equal(t, Day, order.Duration, "orders are day orders by default")
+		assert.Equal(t, "exit-1", order.Tag)
+	})
+
+	t.Run("Option", func(t *testing.T) {
+		order, err := NewOptionOrder("SPY210219P00360000").SellToOpen(2).Limit(1.25).GTC().Build()
+		assert.NoError(t, err)
+		assert.Equal(t, Order{Class: Option, Symbol: "SPY", OptionSymbol: "SPY210219P00360000",
+			Side: SellToOpen, Quantity: 2, Type: LimitOrder, Price: 1.25, Duration: GTC}, order)
+		_, err = orderToParams(order, 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Inconsistent", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			builder *OrderBuilder
+			field   string
+		}{
+			"no symbol":             {NewEquityOrder("").Buy(1).Market(), "symbol"},
+			"option as equity":      {NewEquityOrder("SPY210219P00360000").Buy(1).Market(), "symbol"},
+			"bad option symbol":     {NewOptionOrder("SPY").BuyToOpen(1).Market(), "option_symbol"},
+			"option side":           {NewEquityOrder("SPY").BuyToOpen(1).Market(), "side"},
+			"equity side":           {NewOptionOrder("SPY210219P00360000").Buy(1).Market(), "side"},
+			"two sides":             {NewEquityOrder("SPY").Buy(1).Sell(1).Market(), "side"},
+			"no quantity":           {NewEquityOrder("SPY").Buy(0).Market(), "quantity"},
+			"fractional":            {NewEquityOrder("SPY").Buy(1.5).Market(), "quantity"},
+			"no price":              {NewEquityOrder("SPY").Buy(1).Limit(0), "price"},
+			"no stop":               {NewEquityOrder("SPY").Buy(1).StopLimit(0, 400), "stop"},
+			"two types":             {NewEquityOrder("SPY").Buy(1).Market().Limit(400), "type"},
+			"two durations":         {NewEquityOrder("SPY").Buy(1).Market().Day().GTC(), "duration"},
+			"no side":               {NewEquityOrder("SPY").Market(), "side"},
+			"no type":               {NewEquityOrder("SPY").Buy(1), "type"},
+			"extended hours stop":   {NewEquityOrder("SPY").Buy(1).Stop(400).PreMarket(), "duration"},
+			"extended hours option": {NewOptionOrder("SPY210219P00360000").BuyToOpen(1).Limit(1).PreMarket(), "duration"},
+			"bad tag":               {NewEquityOrder("SPY").Buy(1).Market().Tag("my tag"), "tag"},
+		} {
+			_, err := tc.builder.Build()
+			if assert.IsType(t, &OrderFieldError{}, err, name) {
+				assert.Equal(t, tc.field, err.(*OrderFieldError).Field, name)
+			}
+		}
+	})
+}
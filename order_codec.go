@@ -0,0 +1,144 @@
+package tradier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	orderClasses = map[string]bool{
+		Equity: true, Option: true, Multileg: true, Combo: true,
+		OneTriggersOther: true, OneCancelsOther: true, OneTriggersOneCancelsOther: true,
+	}
+	orderSides = map[string]bool{
+		Buy: true, BuyToCover: true, BuyToOpen: true, BuyToClose: true,
+		Sell: true, SellShort: true, SellToOpen: true, SellToClose: true,
+	}
+	orderTypes = map[string]bool{
+		MarketOrder: true, LimitOrder: true, StopOrder: true, StopLimitOrder: true,
+		Credit: true, Debit: true, Even: true,
+	}
+	orderDurations = map[string]bool{
+		Day: true, GTC: true, PreMarket: true, PostMarket: true,
+	}
+)
+
+// OrderFieldError reports an invalid field of an order, identified by its
+// path within the order (e.g. "legs[1].side").
+type OrderFieldError struct {
+	Field  string
+	Value  interface{}
+	Reason string
+}
+
+func (e *OrderFieldError) Error() string {
+	return fmt.Sprintf("%s: %s (got %q)", e.Field, e.Reason, fmt.Sprint(e.Value))
+}
+
+// ParseOrderJSON decodes a declaratively defined order from JSON.
+// Unknown fields are rejected and the enumerated fields (class, side, type,
+// duration) are checked so that typos are reported against the offending field.
+func ParseOrderJSON(data []byte) (Order, error) {
+	var order Order
+	// Order's UnmarshalJSON does not see the decoder's DisallowUnknownFields,
+	// so unknown fields are checked separately.
+	if err := checkUnknownOrderFields(data, ""); err != nil {
+		return order, err
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return order, err
+	}
+
+	return order, checkOrderFields(order, "")
+}
+
+// checkUnknownOrderFields rejects fields of the order and its legs that do
+// not decode into a field of Order.
+func checkUnknownOrderFields(data []byte, prefix string) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	t := reflect.TypeOf(Order{})
+	for key, raw := range fields {
+		field, ok := fieldByJSONName(t, key)
+		if !ok {
+			return &OrderFieldError{Field: prefix + key, Value: key, Reason: "unknown field"}
+		}
+		if field.Name != "Legs" || isNull(raw) {
+			continue
+		}
+		var legs []json.RawMessage
+		if err := json.Unmarshal(raw, &legs); err != nil {
+			return err
+		}
+		for i, leg := range legs {
+			if err := checkUnknownOrderFields(leg, fmt.Sprintf("%slegs[%d].", prefix, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ParseOrderYAML decodes a declaratively defined order from YAML,
+// with the same checks as ParseOrderJSON.
+func ParseOrderYAML(data []byte) (Order, error) {
+	var order Order
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&order); err != nil {
+		return order, err
+	}
+
+	return order, checkOrderFields(order, "")
+}
+
+// checkOrderFields validates the enumerated fields of the order and its legs.
+// Empty fields are only rejected where an order of the given class requires them.
+func checkOrderFields(order Order, prefix string) error {
+	if prefix == "" && !orderClasses[order.Class] {
+		return &OrderFieldError{Field: "class", Value: order.Class, Reason: "unknown order class"}
+	}
+	if order.Side != "" && !orderSides[order.Side] {
+		return &OrderFieldError{Field: prefix + "side", Value: order.Side, Reason: "unknown order side"}
+	}
+	if order.Type != "" && !orderTypes[order.Type] {
+		return &OrderFieldError{Field: prefix + "type", Value: order.Type, Reason: "unknown order type"}
+	}
+	if order.Duration != "" && !orderDurations[order.Duration] {
+		return &OrderFieldError{Field: prefix + "duration", Value: order.Duration, Reason: "unknown order duration"}
+	}
+	if order.Quantity < 0 {
+		return &OrderFieldError{Field: prefix + "quantity", Value: order.Quantity, Reason: "quantity cannot be negative"}
+	}
+
+	switch order.Class {
+	case Equity, Option:
+		if order.Symbol == "" {
+			return &OrderFieldError{Field: prefix + "symbol", Value: order.Symbol, Reason: "symbol is required"}
+		}
+		if order.Side == "" {
+			return &OrderFieldError{Field: prefix + "side", Value: order.Side, Reason: "side is required"}
+		}
+		if order.Type == "" {
+			return &OrderFieldError{Field: prefix + "type", Value: order.Type, Reason: "type is required"}
+		}
+	case Multileg, Combo, OneTriggersOther, OneCancelsOther, OneTriggersOneCancelsOther:
+		if len(order.Legs) == 0 {
+			return &OrderFieldError{Field: prefix + "legs", Value: len(order.Legs), Reason: "at least one leg is required"}
+		}
+	}
+
+	for i, leg := range order.Legs {
+		if err := checkOrderFields(leg, fmt.Sprintf("%slegs[%d].", prefix, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package tradier
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseOrderJSON(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		order := Order{
+			Class:    OneCancelsOther,
+			Duration: GTC,
+			Legs: []Order{
+				{Symbol: "SPY", Side: Sell, Quantity: 10, Type: LimitOrder, Price: 450},
+				{Symbol: "SPY", Side: Sell, Quantity: 10, Type: StopOrder, StopPrice: 400},
+			},
+		}
+
+		data, err := json.Marshal(order)
+		assert.NoError(t, err)
+		output, err := ParseOrderJSON(data)
+		assert.NoError(t, err)
+		assert.Equal(t, order, output)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		_, err := ParseOrderJSON([]byte(`{"class": "equity", "sid": "buy"}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown leg field", func(t *testing.T) {
+		_, err := ParseOrderJSON([]byte(`{"class": "oco", "legs": [{"symbol": "SPY", "side": "sell", "type": "limit", "prise": 450}]}`))
+		if assert.IsType(t, &OrderFieldError{}, err) {
+			assert.Equal(t, "legs[0].prise", err.(*OrderFieldError).Field)
+		}
+	})
+
+	t.Run("Invalid leg side", func(t *testing.T) {
+		_, err := ParseOrderJSON([]byte(`{"class": "multileg", "type": "market", "legs": [
+			{"option_symbol": "SPY190621C00250000", "side": "buy_to_open", "quantity": 1},
+			{"option_symbol": "SPY190621C00260000", "side": "sell_to_opne", "quantity": 1}]}`))
+		if assert.IsType(t, &OrderFieldError{}, err) {
+			assert.Equal(t, "legs[1].side", err.(*OrderFieldError).Field)
+		}
+	})
+}
+
+func TestParseOrderYAML(t *testing.T) {
+	t.Run("Round trip", func(t *testing.T) {
+		order := Order{
+			Class:    Equity,
+			Symbol:   "AAPL",
+			Side:     Buy,
+			Quantity: 100,
+			Type:     LimitOrder,
+			Price:    187.5,
+			Duration: Day,
+		}
+
+		data, err := yaml.Marshal(order)
+		assert.NoError(t, err)
+		output, err := ParseOrderYAML(data)
+		assert.NoError(t, err)
+		assert.Equal(t, order, output)
+	})
+
+	t.Run("Missing symbol", func(t *testing.T) {
+		_, err := ParseOrderYAML([]byte("class: equity\nside: buy\ntype: market\nquantity: 1\n"))
+		if assert.IsType(t, &OrderFieldError{}, err) {
+			assert.Equal(t, "symbol", err.(*OrderFieldError).Field)
+		}
+	})
+
+	t.Run("Unknown class", func(t *testing.T) {
+		_, err := ParseOrderYAML([]byte("class: equtiy\n"))
+		if assert.IsType(t, &OrderFieldError{}, err) {
+			assert.Equal(t, "class", err.(*OrderFieldError).Field)
+		}
+	})
+}
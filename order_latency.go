@@ -0,0 +1,86 @@
+package tradier
+
+import (
+	"sync"
+	"time"
+)
+
+// OrderLatencyAlert is passed to ClientParams.OrderLatencyAlert when order
+// submission has exceeded its latency budget for consecutive orders.
+type OrderLatencyAlert struct {
+	Budget time.Duration
+	// Latencies of the consecutive orders that exceeded the budget, oldest first.
+	Latencies []time.Duration
+	Stats     LatencyStats
+}
+
+// orderLatencyTracker measures the time from submitting an order
+// until its ID is acknowledged.
+type orderLatencyTracker struct {
+	budget      time.Duration
+	consecutive int
+	alert       func(OrderLatencyAlert)
+	logger      Logger
+
+	mu      sync.Mutex
+	samples latencySamples
+	over    []time.Duration
+}
+
+func newOrderLatencyTracker(params ClientParams) *orderLatencyTracker {
+	olt := &orderLatencyTracker{
+		budget:      params.OrderLatencyBudget,
+		consecutive: params.OrderLatencyAlertAfter,
+		alert:       params.OrderLatencyAlert,
+		logger:      params.Logger,
+	}
+	if olt.consecutive < 1 {
+		olt.consecutive = 1
+	}
+	return olt
+}
+
+// observe records an acknowledged order that was submitted at start. The alert
+// is invoked once per run of orders over budget, when the run reaches the
+// configured length, and is re-armed by an order within budget.
+func (olt *orderLatencyTracker) observe(start time.Time) {
+	latency := time.Since(start)
+
+	olt.mu.Lock()
+	olt.samples.add(latency)
+	var alert *OrderLatencyAlert
+	if olt.budget <= 0 || latency <= olt.budget {
+		olt.over = olt.over[:0]
+	} else {
+		olt.over = append(olt.over, latency)
+		if len(olt.over) == olt.consecutive {
+			alert = &OrderLatencyAlert{
+				Budget:    olt.budget,
+				Latencies: append([]time.Duration(nil), olt.over...),
+				Stats:     olt.samples.stats(),
+			}
+		}
+	}
+	olt.mu.Unlock()
+
+	if alert != nil {
+		if olt.alert != nil {
+			olt.alert(*alert)
+		} else {
+			olt.logger.Warn("Order submission exceeded the latency budget", "budget", alert.Budget,
+				"orders", len(alert.Latencies), "p50", alert.Stats.P50, "p99", alert.Stats.P99)
+		}
+	}
+}
+
+func (olt *orderLatencyTracker) stats() LatencyStats {
+	olt.mu.Lock()
+	defer olt.mu.Unlock()
+	return olt.samples.stats()
+}
+
+// OrderLatency returns stats of the time taken from submitting an order with
+// PlaceOrder or FastPlaceOrder until its ID is returned by Tradier.
+func (tc *Client) OrderLatency() LatencyStats {
+	return tc.orderLatency.stats()
+}
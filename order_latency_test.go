@@ -0,0 +1,51 @@
+package tradier
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_OrderLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delay, _ := time.ParseDuration(r.FormValue("tag"))
+		time.Sleep(delay)
+		w.Write([]byte(`{"order": {"id": 1, "status": "ok"}}`))
+	}))
+	defer server.Close()
+
+	var alerts []OrderLatencyAlert
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.Account = "VA000"
+	params.OrderLatencyBudget = 20 * time.Millisecond
+	params.OrderLatencyAlertAfter = 2
+	params.OrderLatencyAlert = func(alert OrderLatencyAlert) { alerts = append(alerts, alert) }
+	client := NewClient(params)
+
+	// The server delays each response by the duration in the order tag.
+	delays := []string{"50ms", "0s", "50ms", "50ms", "50ms", "0s", "50ms", "50ms"}
+	for i, delay := range delays {
+		order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day, Tag: delay}
+		_, err := client.PlaceOrder(order)
+		assert.NoError(t, err, fmt.Sprint("order ", i))
+	}
+
+	assert.Len(t, alerts, 2, "alerted once per run of slow orders")
+	if assert.NotEmpty(t, alerts) {
+		assert.Equal(t, 20*time.Millisecond, alerts[0].Budget)
+		assert.Len(t, alerts[0].Latencies, 2)
+		assert.Equal(t, 4, alerts[0].Stats.Count)
+	}
+
+	stats := client.Stats().OrderLatency
+	assert.Equal(t, len(delays), stats.Count)
+	assert.True(t, stats.Max >= 50*time.Millisecond)
+	assert.True(t, stats.P99 >= 50*time.Millisecond)
+}
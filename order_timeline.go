@@ -0,0 +1,220 @@
+package tradier
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Kinds of order timeline entries.
+const (
+	TimelineSubmitted   = "submitted"
+	TimelineAcked       = "acked"
+	TimelineModified    = "modified"
+	TimelinePartialFill = "partial_fill"
+	TimelineFilled      = "filled"
+	TimelineCanceled    = "canceled"
+	TimelineRejected    = "rejected"
+	TimelineExpired     = "expired"
+)
+
+// TimelineEntry is a step in the lifecycle of an order.
+type TimelineEntry struct {
+	Time time.Time `json:"time"`
+	// One of the Timeline kinds, e.g. TimelinePartialFill.
+	Kind   string `json:"kind"`
+	Status string `json:"status,omitempty"`
+	// Limit price of the order, or the price of a fill.
+	Price     float64 `json:"price,omitempty"`
+	StopPrice float64 `json:"stop_price,omitempty"`
+	// Quantity of the order when submitted, or of a fill.
+	Quantity  float64 `json:"quantity,omitempty"`
+	Remaining float64 `json:"remaining,omitempty"`
+}
+
+// OrderTimeline is the lifecycle of an order, for rendering in dashboards.
+type OrderTimeline struct {
+	OrderId int             `json:"order_id"`
+	Symbol  string          `json:"symbol,omitempty"`
+	Entries []TimelineEntry `json:"entries"`
+	// Status of the order once it will receive no further updates, or empty
+	// while it is working.
+	FinalStatus string `json:"final_status,omitempty"`
+}
+
+type timelineState struct {
+	timeline  OrderTimeline
+	status    string
+	price     float64
+	stopPrice float64
+	executed  float64
+}
+
+// TimelineRecorder assembles the timelines of orders from the orders placed
+// and changed by the client, the events of the account stream and polled
+// orders. Its methods may be called concurrently, e.g. with ObserveEvent
+// reading from a stream.
+type TimelineRecorder struct {
+	mu     sync.Mutex
+	orders map[int]*timelineState
+}
+
+func NewTimelineRecorder() *TimelineRecorder {
+	return &TimelineRecorder{orders: make(map[int]*timelineState)}
+}
+
+func (tr *TimelineRecorder) state(orderId int) *timelineState {
+	if tr.orders == nil {
+		tr.orders = make(map[int]*timelineState)
+	}
+	st, ok := tr.orders[orderId]
+	if !ok {
+		st = &timelineState{timeline: OrderTimeline{OrderId: orderId}}
+		tr.orders[orderId] = st
+	}
+	return st
+}
+
+// Submitted records an order placed at the given time, e.g. with the result
+// of PlaceOrder.
+func (tr *TimelineRecorder) Submitted(order Order, result *OrderResult, at time.Time) {
+	if result == nil || result.Id == 0 {
+		return
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	st := tr.state(result.Id)
+	if st.timeline.Symbol == "" {
+		st.timeline.Symbol = orderSymbol(&order)
+	}
+	if st.status == "" {
+		st.status = Submitted
+	}
+	st.price, st.stopPrice = order.Price, order.StopPrice
+	st.timeline.Entries = append(st.timeline.Entries, TimelineEntry{
+		Time:      at,
+		Kind:      TimelineSubmitted,
+		Status:    result.Status,
+		Price:     order.Price,
+		StopPrice: order.StopPrice,
+		Quantity:  order.Quantity,
+	})
+}
+
+// Modified records a change of the order at the given time, e.g. with
+// ChangeOrder. The event of the change from the stream is not recorded again.
+func (tr *TimelineRecorder) Modified(orderId int, order Order, at time.Time) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	st := tr.state(orderId)
+	st.price, st.stopPrice = order.Price, order.StopPrice
+	st.timeline.Entries = append(st.timeline.Entries, TimelineEntry{
+		Time:      at,
+		Kind:      TimelineModified,
+		Status:    st.status,
+		Price:     order.Price,
+		StopPrice: order.StopPrice,
+		Quantity:  order.Quantity,
+	})
+}
+
+// ObserveEvent records an event from StreamAccountEvents.
+func (tr *TimelineRecorder) ObserveEvent(event *AccountEvent) {
+	at := event.TransactionDate.Time
+	if at.IsZero() {
+		at = event.CreateDate.Time
+	}
+	tr.observe(event.Id, "", at, Order{
+		Status:            event.Status,
+		Price:             event.Price,
+		StopPrice:         event.StopPrice,
+		ExecutedQuantity:  event.ExecutedQuantity,
+		LastFillPrice:     event.LastFillPrice,
+		RemainingQuantity: event.RemainingQuantity,
+	})
+}
+
+// ObserveOrder records the state of a polled order, e.g. from GetOrders or
+// GetOrderStatus, filling in changes missed by the stream.
+func (tr *TimelineRecorder) ObserveOrder(order *Order) {
+	tr.observe(order.Id, orderSymbol(order), orderUpdateTime(order), *order)
+}
+
+// Record the changes of the order from its last observed state.
+func (tr *TimelineRecorder) observe(orderId int, symbol string, at time.Time, order Order) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	st := tr.state(orderId)
+	if st.timeline.Symbol == "" {
+		st.timeline.Symbol = symbol
+	}
+	add := func(kind string, price, quantity float64) {
+		st.timeline.Entries = append(st.timeline.Entries, TimelineEntry{
+			Time:      at,
+			Kind:      kind,
+			Status:    order.Status,
+			Price:     price,
+			StopPrice: order.StopPrice,
+			Quantity:  quantity,
+			Remaining: order.RemainingQuantity,
+		})
+	}
+
+	switch {
+	case order.ExecutedQuantity > st.executed:
+		kind := TimelinePartialFill
+		if order.Status == Filled {
+			kind = TimelineFilled
+		}
+		add(kind, order.LastFillPrice, order.ExecutedQuantity-st.executed)
+		st.executed = order.ExecutedQuantity
+	case order.Status == st.status:
+		if isOpenStatus(order.Status) && (order.Price != st.price || order.StopPrice != st.stopPrice) {
+			add(TimelineModified, order.Price, 0)
+		}
+	case order.Status == Filled:
+		add(TimelineFilled, order.LastFillPrice, 0)
+	case order.Status == Canceled || order.Status == Rejected || order.Status == Expired:
+		add(order.Status, order.Price, 0)
+	case order.Status == Open && (st.status == "" || st.status == Pending || st.status == Submitted):
+		add(TimelineAcked, order.Price, 0)
+	}
+	st.status, st.price, st.stopPrice = order.Status, order.Price, order.StopPrice
+	switch order.Status {
+	case Filled, Canceled, Rejected, Expired:
+		st.timeline.FinalStatus = order.Status
+	}
+}
+
+// Timelines returns the timelines of the recorded orders by id, with their
+// entries in order of time.
+func (tr *TimelineRecorder) Timelines() []OrderTimeline {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	timelines := make([]OrderTimeline, 0, len(tr.orders))
+	for _, st := range tr.orders {
+		timeline := st.timeline
+		timeline.Entries = append([]TimelineEntry(nil), timeline.Entries...)
+		sort.SliceStable(timeline.Entries, func(i, j int) bool {
+			return timeline.Entries[i].Time.Before(timeline.Entries[j].Time)
+		})
+		timelines = append(timelines, timeline)
+	}
+	sort.Slice(timelines, func(i, j int) bool { return timelines[i].OrderId < timelines[j].OrderId })
+	return timelines
+}
+
+// WriteJSON writes the timelines to w as a JSON array, with times in UTC.
+func (tr *TimelineRecorder) WriteJSON(w io.Writer) error {
+	timelines := tr.Timelines()
+	for i := range timelines {
+		for j := range timelines[i].Entries {
+			timelines[i].Entries[j].Time = exportTime(timelines[i].Entries[j].Time)
+		}
+	}
+	return writeExportJSON(w, timelines)
+}
@@ -0,0 +1,61 @@
+package tradier
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimelineRecorder(t *testing.T) {
+	start := time.Date(2024, 6, 3, 14, 0, 0, 0, time.UTC)
+	at := func(seconds int) DateTime { return DateTime{start.Add(time.Duration(seconds) * time.Second)} }
+	order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 530, Duration: Day}
+
+	tr := NewTimelineRecorder()
+	tr.Submitted(order, &OrderResult{Id: 1, Status: StatusOK}, start)
+	tr.ObserveEvent(&AccountEvent{Id: 1, Status: Open, Price: 530, RemainingQuantity: 10, TransactionDate: at(1)})
+	tr.ObserveEvent(&AccountEvent{Id: 1, Status: Open, Price: 530, RemainingQuantity: 10, TransactionDate: at(2)})
+	order.Price = 530.5
+	tr.Modified(1, order, at(3).Time)
+	tr.ObserveEvent(&AccountEvent{Id: 1, Status: Open, Price: 530.5, RemainingQuantity: 10, TransactionDate: at(4)})
+	tr.ObserveEvent(&AccountEvent{Id: 1, Status: PartiallyFilled, Price: 530.5, ExecutedQuantity: 4, LastFillPrice: 530.5, RemainingQuantity: 6, TransactionDate: at(5)})
+	tr.ObserveEvent(&AccountEvent{Id: 1, Status: Filled, Price: 530.5, ExecutedQuantity: 10, LastFillPrice: 530.4, TransactionDate: at(6)})
+
+	// An order seen only by polling.
+	tr.ObserveOrder(&Order{Id: 2, Symbol: "AAPL", Status: Open, Price: 190, CreateDate: at(10)})
+	tr.ObserveOrder(&Order{Id: 2, Symbol: "AAPL", Status: Open, Price: 191, CreateDate: at(10), TransactionDate: at(20)})
+	tr.ObserveOrder(&Order{Id: 2, Symbol: "AAPL", Status: Canceled, Price: 191, CreateDate: at(10), TransactionDate: at(30)})
+
+	timelines := tr.Timelines()
+	if !assert.Len(t, timelines, 2) {
+		return
+	}
+	kinds := func(timeline OrderTimeline) []string {
+		var kinds []string
+		for _, e := range timeline.Entries {
+			kinds = append(kinds, e.Kind)
+		}
+		return kinds
+	}
+	assert.Equal(t, "SPY", timelines[0].Symbol)
+	assert.Equal(t, []string{TimelineSubmitted, TimelineAcked, TimelineModified, TimelinePartialFill, TimelineFilled}, kinds(timelines[0]))
+	assert.Equal(t, Filled, timelines[0].FinalStatus)
+	fill := timelines[0].Entries[4]
+	assert.Equal(t, 6.0, fill.Quantity)
+	assert.Equal(t, 530.4, fill.Price)
+	assert.Equal(t, at(6).Time, fill.Time)
+
+	assert.Equal(t, []string{TimelineAcked, TimelineModified, TimelineCanceled}, kinds(timelines[1]))
+	assert.Equal(t, Canceled, timelines[1].FinalStatus)
+
+	var buf bytes.Buffer
+	assert.NoError(t, tr.WriteJSON(&buf))
+	var decoded []OrderTimeline
+	if assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded)) {
+		assert.Equal(t, timelines, decoded)
+	}
+	assert.Contains(t, buf.String(), `"kind": "partial_fill"`)
+}
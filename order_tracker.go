@@ -0,0 +1,223 @@
+package tradier
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// OrderTransition is a change of the status of a tracked order.
+type OrderTransition struct {
+	OrderId int
+	// Status before the transition, or empty if the order was not tracked.
+	From string
+	To   string
+	// Tracked state of the order after the transition.
+	Order *Order
+	At    time.Time
+}
+
+// OrderTracker maintains a local view of the orders placed by the client,
+// from their placement and the status updates of the account stream or
+// polling. Each order moves forward through pending, open and partially
+// filled to a final status; updates that would move it back, such as events
+// delivered late, are ignored. Its methods may be called concurrently.
+type OrderTracker struct {
+	// If set, called with each transition of a tracked order, after the
+	// update has been applied.
+	Transition func(transition OrderTransition)
+
+	mu     sync.Mutex
+	orders map[int]*Order
+}
+
+func NewOrderTracker(transition func(transition OrderTransition)) *OrderTracker {
+	return &OrderTracker{Transition: transition, orders: make(map[int]*Order)}
+}
+
+// Placed tracks an order placed with the result of PlaceOrder, as pending.
+func (ot *OrderTracker) Placed(order Order, result *OrderResult) {
+	if result == nil || result.Id == 0 {
+		return
+	}
+	order.Id = result.Id
+	order.Status = Pending
+	order.Legs = append([]Order(nil), order.Legs...)
+	ot.update(&order, time.Now())
+}
+
+// ObserveEvent applies an event from StreamAccountEvents.
+func (ot *OrderTracker) ObserveEvent(event *AccountEvent) {
+	at := event.TransactionDate.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+	ot.update(&Order{
+		Id:                event.Id,
+		Type:              event.Type,
+		Status:            event.Status,
+		Price:             event.Price,
+		StopPrice:         event.StopPrice,
+		AverageFillPrice:  event.AverageFillPrice,
+		ExecutedQuantity:  event.ExecutedQuantity,
+		LastFillPrice:     event.LastFillPrice,
+		LastFillQuantity:  event.LastFillQuantity,
+		RemainingQuantity: event.RemainingQuantity,
+		TransactionDate:   event.TransactionDate,
+		CreateDate:        event.CreateDate,
+	}, at)
+}
+
+// ObserveOrder applies the state of a polled order, e.g. from GetOpenOrders
+// or GetOrderStatus. Orders that are not tracked yet are tracked from then on.
+func (ot *OrderTracker) ObserveOrder(order *Order) {
+	at := orderUpdateTime(order)
+	if at.IsZero() {
+		at = time.Now()
+	}
+	polled := *order
+	polled.Legs = append([]Order(nil), order.Legs...)
+	ot.update(&polled, at)
+}
+
+// Poll applies the orders of the account listed by orders.
+func (ot *OrderTracker) Poll(orders OrderLister) error {
+	polled, err := orders.GetOpenOrders()
+	if err != nil {
+		return err
+	}
+	for _, o := range polled {
+		ot.ObserveOrder(o)
+	}
+	return nil
+}
+
+func (ot *OrderTracker) update(update *Order, at time.Time) {
+	ot.mu.Lock()
+	if ot.orders == nil {
+		ot.orders = make(map[int]*Order)
+	}
+	tracked, ok := ot.orders[update.Id]
+	var transition *OrderTransition
+	switch {
+	case !ok:
+		if update.Status == "" {
+			update.Status = Pending
+		}
+		ot.orders[update.Id] = update
+		transition = &OrderTransition{OrderId: update.Id, To: update.Status}
+		tracked = update
+	case update.Status == "" || orderStatusRank(update.Status) < orderStatusRank(tracked.Status) ||
+		isFinalStatus(tracked.Status) || update.ExecutedQuantity < tracked.ExecutedQuantity:
+		// A stale update, or one of an order that can no longer change.
+	default:
+		from := tracked.Status
+		mergeOrderUpdate(tracked, update)
+		if tracked.Status != from {
+			transition = &OrderTransition{OrderId: tracked.Id, From: from, To: tracked.Status}
+		}
+	}
+	if transition != nil {
+		order := *tracked
+		transition.Order, transition.At = &order, at
+	}
+	ot.mu.Unlock()
+
+	if transition != nil && ot.Transition != nil {
+		ot.Transition(*transition)
+	}
+}
+
+// Copies the fields of the update that change while an order is working.
+func mergeOrderUpdate(tracked, update *Order) {
+	tracked.Status = update.Status
+	if update.Price != 0 {
+		tracked.Price = update.Price
+	}
+	if update.StopPrice != 0 {
+		tracked.StopPrice = update.StopPrice
+	}
+	if update.AverageFillPrice != 0 {
+		tracked.AverageFillPrice = update.AverageFillPrice
+	}
+	if update.LastFillPrice != 0 {
+		tracked.LastFillPrice, tracked.LastFillQuantity = update.LastFillPrice, update.LastFillQuantity
+	}
+	tracked.ExecutedQuantity = update.ExecutedQuantity
+	tracked.RemainingQuantity = update.RemainingQuantity
+	if !update.TransactionDate.IsZero() {
+		tracked.TransactionDate = update.TransactionDate
+	}
+	if tracked.Symbol == "" {
+		tracked.Symbol, tracked.OptionSymbol = update.Symbol, update.OptionSymbol
+	}
+	if tracked.Side == "" {
+		tracked.Side, tracked.Quantity = update.Side, update.Quantity
+	}
+	if len(tracked.Legs) == 0 {
+		tracked.Class, tracked.Legs = update.Class, update.Legs
+	}
+}
+
+// Order returns the tracked state of the order, or nil if it is not tracked.
+func (ot *OrderTracker) Order(orderId int) *Order {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+	tracked, ok := ot.orders[orderId]
+	if !ok {
+		return nil
+	}
+	order := *tracked
+	return &order
+}
+
+// Working returns the tracked orders that are not in a final status, by id.
+func (ot *OrderTracker) Working() []*Order {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+	var working []*Order
+	for _, tracked := range ot.orders {
+		if !isFinalStatus(tracked.Status) {
+			order := *tracked
+			working = append(working, &order)
+		}
+	}
+	sort.Slice(working, func(i, j int) bool { return working[i].Id < working[j].Id })
+	return working
+}
+
+// Exposure returns the exposure of the working orders in each symbol, as
+// GroupWorkingOrders does.
+func (ot *OrderTracker) Exposure() map[string]*SymbolExposure {
+	return GroupWorkingOrders(ot.Working())
+}
+
+// Forget stops tracking the orders in a final status, e.g. at the end of
+// the day.
+func (ot *OrderTracker) Forget() {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+	for id, tracked := range ot.orders {
+		if isFinalStatus(tracked.Status) {
+			delete(ot.orders, id)
+		}
+	}
+}
+
+// Returns the position of the status in the lifecycle of an order.
+func orderStatusRank(status string) int {
+	switch status {
+	case Submitted, Pending:
+		return 0
+	case Open:
+		return 1
+	case PartiallyFilled:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func isFinalStatus(status string) bool {
+	return status == Filled || status == Canceled || status == Rejected || status == Expired
+}
@@ -0,0 +1,56 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderTracker(t *testing.T) {
+	start := time.Date(2024, 6, 3, 14, 0, 0, 0, time.UTC)
+	at := func(seconds int) DateTime { return DateTime{start.Add(time.Duration(seconds) * time.Second)} }
+
+	var transitions []string
+	tracker := NewOrderTracker(func(transition OrderTransition) {
+		transitions = append(transitions, transition.From+" -> "+transition.To)
+	})
+	tracker.Placed(Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 530, Duration: Day},
+		&OrderResult{Id: 1, Status: StatusOK})
+	tracker.ObserveEvent(&AccountEvent{Id: 1, Status: Open, Price: 530, RemainingQuantity: 10, TransactionDate: at(1)})
+	tracker.ObserveEvent(&AccountEvent{Id: 1, Status: PartiallyFilled, Price: 530, ExecutedQuantity: 4, LastFillPrice: 529.9, RemainingQuantity: 6, TransactionDate: at(3)})
+	// Delivered late, after the partial fill.
+	tracker.ObserveEvent(&AccountEvent{Id: 1, Status: Open, Price: 530, RemainingQuantity: 10, TransactionDate: at(2)})
+
+	order := tracker.Order(1)
+	if assert.NotNil(t, order) {
+		assert.Equal(t, PartiallyFilled, order.Status)
+		assert.Equal(t, 6.0, order.RemainingQuantity)
+		assert.Equal(t, "SPY", order.Symbol)
+	}
+	if exposure := tracker.Exposure()["SPY"]; assert.NotNil(t, exposure) {
+		assert.Equal(t, 6.0, exposure.PendingBuy)
+	}
+
+	err := tracker.Poll(&fakeOrderLister{orders: []*Order{
+		{Id: 1, Symbol: "SPY", Side: Buy, Quantity: 10, Status: Filled, ExecutedQuantity: 10, LastFillPrice: 530, TransactionDate: at(5)},
+		{Id: 2, Symbol: "AAPL", Side: Sell, Quantity: 5, Status: Open, RemainingQuantity: 5, Price: 190, TransactionDate: at(6)},
+	}})
+	assert.NoError(t, err)
+	tracker.ObserveEvent(&AccountEvent{Id: 1, Status: Canceled, TransactionDate: at(7)})
+	tracker.ObserveEvent(&AccountEvent{Id: 2, Status: Canceled, RemainingQuantity: 5, TransactionDate: at(8)})
+
+	assert.Equal(t, []string{
+		" -> pending",
+		"pending -> open",
+		"open -> partially_filled",
+		"partially_filled -> filled",
+		" -> open",
+		"open -> canceled",
+	}, transitions)
+	assert.Equal(t, Filled, tracker.Order(1).Status, "final statuses do not change")
+	assert.Empty(t, tracker.Working())
+
+	tracker.Forget()
+	assert.Nil(t, tracker.Order(1))
+}
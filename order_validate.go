@@ -0,0 +1,225 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// OrderValidationError reports every invalid field of an order found by
+// Order.Validate.
+type OrderValidationError struct {
+	Errors []*OrderFieldError
+}
+
+func (e *OrderValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "invalid order: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of the fields.
+func (e *OrderValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// As sets an *OrderFieldError target to the first error of the fields, so
+// that errors.As finds it with the releases of Go before multiple errors
+// could be unwrapped.
+func (e *OrderValidationError) As(target interface{}) bool {
+	if fieldErr, ok := target.(**OrderFieldError); ok && len(e.Errors) > 0 {
+		*fieldErr = e.Errors[0]
+		return true
+	}
+	return false
+}
+
+// Numbers of legs allowed by Tradier for each class of order.
+var orderLegCounts = map[string][2]int{
+	Multileg:                   {2, 4},
+	Combo:                      {2, 3},
+	OneTriggersOther:           {2, 2},
+	OneCancelsOther:            {2, 2},
+	OneTriggersOneCancelsOther: {3, 3},
+}
+
+// Validate checks the order as Tradier would before accepting it: the
+// class, side, type and duration of the order and its legs, that quantities
+// are positive whole numbers, that the prices its type requires are
+// positive, the number of legs of its class and the format of its option
+// symbols. It returns an *OrderValidationError with every problem found, or
+// nil. PlaceOrder, FastPlaceOrder and PreviewOrder validate orders before
+// sending them, so that orders Tradier would reject cost no requests.
+func (o *Order) Validate() error {
+	v := &orderValidator{}
+	v.validate(o)
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &OrderValidationError{Errors: v.errors}
+}
+
+type orderValidator struct {
+	errors []*OrderFieldError
+}
+
+func (v *orderValidator) fail(field string, value interface{}, reason string) {
+	v.errors = append(v.errors, &OrderFieldError{Field: field, Value: value, Reason: reason})
+}
+
+func (v *orderValidator) validate(o *Order) {
+	if !orderClasses[o.Class] {
+		v.fail("class", o.Class, "unknown order class")
+		return
+	}
+	if o.Tag != "" && !validOrderTag(o.Tag) {
+		v.fail("tag", o.Tag, "tag must be 1 to 255 letters, digits or dashes")
+	}
+	switch {
+	case o.Duration == "":
+		v.fail("duration", o.Duration, "duration is required")
+	case !orderDurations[o.Duration]:
+		v.fail("duration", o.Duration, "unknown order duration")
+	case o.Duration == PreMarket || o.Duration == PostMarket:
+		if o.Class != Equity || o.Type != LimitOrder {
+			v.fail("duration", o.Duration, "extended hours orders must be equity limit orders")
+		}
+	}
+
+	switch o.Class {
+	case Equity, Option:
+		v.validateSingle(o, "", o.Class == Option)
+	case Multileg, Combo:
+		if o.Symbol == "" {
+			v.fail("symbol", o.Symbol, "symbol is required")
+		}
+		switch o.Type {
+		case MarketOrder, Even:
+		case Credit, Debit:
+			v.validatePrice("price", o.Price, "net price")
+		case "":
+			v.fail("type", o.Type, "type is required")
+		default:
+			v.fail("type", o.Type, fmt.Sprintf("type of %v orders must be market, debit, credit or even", o.Class))
+		}
+	default:
+		if o.Duration != "" && o.Duration != Day && o.Duration != GTC {
+			v.fail("duration", o.Duration, fmt.Sprintf("%v orders must be day or gtc", o.Class))
+		}
+	}
+
+	counts, ok := orderLegCounts[o.Class]
+	if !ok {
+		return
+	}
+	if len(o.Legs) < counts[0] || len(o.Legs) > counts[1] {
+		reason := fmt.Sprintf("%v orders must have %d to %d legs", o.Class, counts[0], counts[1])
+		if counts[0] == counts[1] {
+			reason = fmt.Sprintf("%v orders must have %d legs", o.Class, counts[0])
+		}
+		v.fail("legs", len(o.Legs), reason)
+	}
+	for i := range o.Legs {
+		leg := &o.Legs[i]
+		prefix := fmt.Sprintf("legs[%d].", i)
+		if o.Class == Multileg || o.Class == Combo {
+			v.validateSpreadLeg(o, leg, prefix)
+		} else {
+			v.validateSingle(leg, prefix, leg.OptionSymbol != "")
+		}
+	}
+}
+
+// Validate an equity or option order, or a leg of an advanced order.
+func (v *orderValidator) validateSingle(o *Order, prefix string, option bool) {
+	if o.Symbol == "" {
+		v.fail(prefix+"symbol", o.Symbol, "symbol is required")
+	}
+	if option {
+		optionSymbol := o.OptionSymbol
+		if optionSymbol == "" {
+			// PlaceOrder accepts the contract of option orders in Symbol.
+			optionSymbol = o.Symbol
+		}
+		v.validateOptionSymbol(prefix+"option_symbol", optionSymbol)
+	}
+	v.validateSide(prefix+"side", o.Side, option)
+	v.validateQuantity(prefix+"quantity", o.Quantity)
+
+	switch o.Type {
+	case MarketOrder:
+	case LimitOrder:
+		v.validatePrice(prefix+"price", o.Price, "limit price")
+	case StopOrder:
+		v.validatePrice(prefix+"stop", o.StopPrice, "stop price")
+	case StopLimitOrder:
+		v.validatePrice(prefix+"price", o.Price, "limit price")
+		v.validatePrice(prefix+"stop", o.StopPrice, "stop price")
+	case "":
+		v.fail(prefix+"type", o.Type, "type is required")
+	default:
+		v.fail(prefix+"type", o.Type, "type must be market, limit, stop or stop_limit")
+	}
+	if o.StopPrice != 0 && o.Type != StopOrder && o.Type != StopLimitOrder {
+		v.fail(prefix+"stop", o.StopPrice, "stop price of an order that is not a stop order")
+	}
+}
+
+// Validate a leg of a multileg or combo order. Combo orders may have an
+// equity leg, without an option symbol.
+func (v *orderValidator) validateSpreadLeg(o, leg *Order, prefix string) {
+	option := o.Class == Multileg || leg.OptionSymbol != ""
+	if option {
+		v.validateOptionSymbol(prefix+"option_symbol", leg.OptionSymbol)
+		// The roots of index options may differ from their index, e.g. SPXW.
+		contract, err := ParseOptionSymbol(leg.OptionSymbol)
+		if err == nil && o.Symbol != "" && optionRootUnderlying(contract.Underlying) != optionRootUnderlying(o.Symbol) {
+			v.fail(prefix+"option_symbol", leg.OptionSymbol, "option of a different underlying than "+o.Symbol)
+		}
+	}
+	v.validateSide(prefix+"side", leg.Side, option)
+	v.validateQuantity(prefix+"quantity", leg.Quantity)
+}
+
+func (v *orderValidator) validateOptionSymbol(field, symbol string) {
+	if symbol == "" {
+		v.fail(field, symbol, "option symbol is required")
+	} else if _, err := ParseOptionSymbol(symbol); err != nil {
+		v.fail(field, symbol, "invalid OCC option symbol")
+	}
+}
+
+func (v *orderValidator) validateSide(field, side string, option bool) {
+	switch {
+	case side == "":
+		v.fail(field, side, "side is required")
+	case !orderSides[side]:
+		v.fail(field, side, "unknown order side")
+	case option != (side == BuyToOpen || side == BuyToClose || side == SellToOpen || side == SellToClose):
+		if option {
+			v.fail(field, side, "equity side of an option order")
+		} else {
+			v.fail(field, side, "option side of an equity order")
+		}
+	}
+}
+
+func (v *orderValidator) validateQuantity(field string, quantity float64) {
+	if quantity <= 0 {
+		v.fail(field, quantity, "quantity must be positive")
+	} else if quantity != math.Trunc(quantity) {
+		v.fail(field, quantity, "quantity must be a whole number")
+	}
+}
+
+func (v *orderValidator) validatePrice(field string, price float64, name string) {
+	if price <= 0 {
+		v.fail(field, price, name+" must be positive")
+	}
+}
@@ -0,0 +1,154 @@
+package tradier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder_Validate(t *testing.T) {
+	valid := []Order{
+		{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: MarketOrder, Duration: Day},
+		{Class: Equity, Symbol: "SPY", Side: Sell, Quantity: 10, Type: LimitOrder, Price: 450, Duration: PreMarket},
+		{Class: Equity, Symbol: "SPY", Side: SellShort, Quantity: 10, Type: StopLimitOrder, Price: 440, StopPrice: 441, Duration: GTC},
+		{Class: Option, Symbol: "SPY", OptionSymbol: "SPY230120C00400000", Side: BuyToOpen, Quantity: 1, Type: LimitOrder, Price: 2.5, Duration: Day},
+		{Class: Option, Symbol: "SPY230120C00400000", Side: SellToClose, Quantity: 1, Type: MarketOrder, Duration: Day},
+		{Class: Multileg, Symbol: "SPY", Type: Debit, Price: 1.25, Duration: Day, Legs: []Order{
+			{OptionSymbol: "SPY230120C00400000", Side: BuyToOpen, Quantity: 1},
+			{OptionSymbol: "SPY230120C00410000", Side: SellToOpen, Quantity: 1},
+		}},
+		{Class: Multileg, Symbol: "SPX", Type: Credit, Price: 2.1, Duration: Day, Legs: []Order{
+			{OptionSymbol: "SPXW240621P05300000", Side: SellToOpen, Quantity: 1},
+			{OptionSymbol: "SPXW240621P05250000", Side: BuyToOpen, Quantity: 1},
+			{OptionSymbol: "SPX240621P05200000", Side: BuyToOpen, Quantity: 1},
+		}},
+		{Class: Combo, Symbol: "SPY", Type: Even, Duration: Day, Legs: []Order{
+			{Side: Buy, Quantity: 100},
+			{OptionSymbol: "SPY230120C00410000", Side: SellToOpen, Quantity: 1},
+		}},
+		{Class: OneTriggersOther, Duration: GTC, Legs: []Order{
+			{Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder, Price: 400},
+			{Symbol: "SPY", Side: Sell, Quantity: 10, Type: StopOrder, StopPrice: 390},
+		}},
+	}
+	for _, order := range valid {
+		assert.NoError(t, order.Validate(), "%+v", order)
+	}
+
+	tests := []struct {
+		name   string
+		order  Order
+		fields []string
+	}{
+		{"unknown class", Order{Class: "bond"}, []string{"class"}},
+		{"missing fields",
+			Order{Class: Equity},
+			[]string{"duration", "symbol", "side", "quantity", "type"}},
+		{"bad enums",
+			Order{Class: Equity, Symbol: "SPY", Side: "hold", Quantity: 1, Type: "trailing", Duration: "week"},
+			[]string{"duration", "side", "type"}},
+		{"quantities",
+			Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1.5, Type: MarketOrder, Duration: Day},
+			[]string{"quantity"}},
+		{"prices",
+			Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: -1, Type: StopLimitOrder, Duration: Day},
+			[]string{"quantity", "price", "stop"}},
+		{"stop price of a limit order",
+			Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: LimitOrder, Price: 1, StopPrice: 2, Duration: Day},
+			[]string{"stop"}},
+		{"extended hours market order",
+			Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: PostMarket},
+			[]string{"duration"}},
+		{"option sides and symbols",
+			Order{Class: Option, Symbol: "SPY", OptionSymbol: "SPY230120X00400000", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day},
+			[]string{"option_symbol", "side"}},
+		{"option side of an equity order",
+			Order{Class: Equity, Symbol: "SPY", Side: BuyToOpen, Quantity: 1, Type: MarketOrder, Duration: Day, Tag: "my tag"},
+			[]string{"tag", "side"}},
+		{"multileg",
+			Order{Class: Multileg, Symbol: "SPY", Type: Credit, Duration: Day, Legs: []Order{
+				{OptionSymbol: "QQQ230120C00400000", Side: BuyToOpen, Quantity: 1},
+			}},
+			[]string{"price", "legs", "legs[0].option_symbol"}},
+		{"multileg type",
+			Order{Class: Multileg, Symbol: "SPY", Type: StopOrder, Duration: Day, Legs: []Order{
+				{OptionSymbol: "SPY230120C00400000", Side: BuyToOpen, Quantity: 1},
+				{OptionSymbol: "SPY230120C00410000", Side: Sell, Quantity: 0},
+			}},
+			[]string{"type", "legs[1].side", "legs[1].quantity"}},
+		{"otoco",
+			Order{Class: OneTriggersOneCancelsOther, Duration: PreMarket, Legs: []Order{
+				{Symbol: "SPY", Side: Buy, Quantity: 10, Type: LimitOrder},
+				{Symbol: "SPY", Side: Sell, Quantity: 10, Type: LimitOrder, Price: 410},
+			}},
+			[]string{"duration", "duration", "legs", "legs[0].price"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.order.Validate()
+			var validationErr *OrderValidationError
+			if assert.True(t, errors.As(err, &validationErr), "%v", err) {
+				var fields []string
+				for _, fieldErr := range validationErr.Errors {
+					fields = append(fields, fieldErr.Field)
+				}
+				assert.Equal(t, tt.fields, fields, err.Error())
+			}
+		})
+	}
+
+	err := (&Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 0, Type: LimitOrder, Duration: Day}).Validate()
+	assert.EqualError(t, err, `invalid order: quantity: quantity must be positive (got "0"); price: limit price must be positive (got "0")`)
+	var fieldErr *OrderFieldError
+	if assert.True(t, errors.As(err, &fieldErr)) {
+		assert.Equal(t, "quantity", fieldErr.Field)
+	}
+}
+
+func TestClient_PlaceOrder_Invalid(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"order": {"id": 1, "status": "ok"}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	client := NewClient(params)
+	order := Order{Class: Equity, Symbol: "SPY", Side: BuyToOpen, Quantity: 1, Type: LimitOrder, Duration: Day}
+
+	var validationErr *OrderValidationError
+	_, err := client.PlaceOrder(order)
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Len(t, validationErr.Errors, 2)
+	}
+	_, err = client.PreviewOrder(order)
+	assert.True(t, errors.As(err, &validationErr))
+	_, err = client.FastPlaceOrder(context.Background(), order)
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, 0, requests)
+
+	order.Side, order.Price = Buy, 450
+	_, err = client.PlaceOrder(order)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestOrderToParams_NetPrice(t *testing.T) {
+	order := Order{Class: Multileg, Symbol: "SPY", Type: Credit, Price: 1.25, Duration: Day, Legs: []Order{
+		{OptionSymbol: "SPY230120C00400000", Side: SellToOpen, Quantity: 1},
+		{OptionSymbol: "SPY230120C00410000", Side: BuyToOpen, Quantity: 1},
+	}}
+	form, err := orderToParams(order, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1.25", form.Get("price"))
+	}
+}
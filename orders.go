@@ -0,0 +1,136 @@
+package tradier
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrdersParams configure GetOrders. The zero value requests all the orders
+// of the account.
+type OrdersParams struct {
+	// Page of orders to request, starting from 1. If zero, every page is
+	// requested.
+	Page int
+	// Number of orders per page, or zero for Tradier's default.
+	Limit int
+	// If set, the tags of the orders are included.
+	IncludeTags bool
+
+	// The following filters are applied to the orders returned by Tradier.
+
+	// If set, only orders with one of these statuses are returned, such as
+	// Open, Filled or Canceled.
+	Statuses []string
+	// If set, only orders of the symbol are returned. It matches either the
+	// underlying or the option symbol of an option order, or any of the legs
+	// of a multileg order.
+	Symbol string
+	// If set, only orders created at or after Start, and before End, are
+	// returned.
+	Start, End time.Time
+}
+
+// Returns whether the order matches the filters of the params.
+func (op OrdersParams) match(order *Order) bool {
+	if len(op.Statuses) > 0 && !containsString(op.Statuses, order.Status) {
+		return false
+	}
+	if op.Symbol != "" && !orderHasSymbol(order, op.Symbol) {
+		return false
+	}
+	if !op.Start.IsZero() && order.CreateDate.Before(op.Start) {
+		return false
+	}
+	if !op.End.IsZero() && !order.CreateDate.Before(op.End) {
+		return false
+	}
+	return true
+}
+
+func orderHasSymbol(order *Order, symbol string) bool {
+	if strings.EqualFold(order.Symbol, symbol) || strings.EqualFold(order.OptionSymbol, symbol) {
+		return true
+	}
+	for i := range order.Legs {
+		if orderHasSymbol(&order.Legs[i], symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetOrders returns the orders of the selected account that match params.
+// Unlike GetOpenOrders, it can request the orders page by page, so that
+// accounts with long order histories are not truncated. When every page is
+// requested, pages are requested until one is empty, or only has orders
+// already returned.
+func (tc *Client) GetOrders(params OrdersParams) ([]*Order, error) {
+	return tc.getOrders(context.Background(), params, false)
+}
+
+// getOrders is GetOrders with a context. If current, the pages are requested
+// from Tradier even if the memo has them.
+func (tc *Client) getOrders(ctx context.Context, params OrdersParams, current bool) ([]*Order, error) {
+	if tc.account == "" {
+		return nil, ErrNoAccountSelected
+	}
+
+	if params.Page > 0 {
+		orders, err := tc.getOrdersPage(ctx, params.Page, params, current)
+		return filterOrders(orders, params), err
+	}
+
+	var orders []*Order
+	seen := make(map[int]bool)
+	for page := 1; ; page++ {
+		pageOrders, err := tc.getOrdersPage(ctx, page, params, current)
+		if err != nil {
+			return nil, err
+		}
+		fresh := 0
+		for _, order := range pageOrders {
+			if !seen[order.Id] {
+				seen[order.Id] = true
+				orders = append(orders, order)
+				fresh++
+			}
+		}
+		if fresh == 0 {
+			break
+		}
+	}
+	return filterOrders(orders, params), nil
+}
+
+func (tc *Client) getOrdersPage(ctx context.Context, page int, params OrdersParams, current bool) ([]*Order, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.IncludeTags {
+		query.Set("includeTags", "true")
+	}
+
+	var result openOrdersResponse
+	var err error
+	if current {
+		err = tc.getJSONUnmemoized(ctx, tc.ordersURL+"?"+query.Encode(), &result)
+	} else {
+		err = tc.getJSONContext(ctx, tc.ordersURL+"?"+query.Encode(), &result)
+	}
+	return []*Order(result.Orders.Value.Order), err
+}
+
+func filterOrders(orders []*Order, params OrdersParams) []*Order {
+	var matched []*Order
+	for _, order := range orders {
+		if params.match(order) {
+			matched = append(matched, order)
+		}
+	}
+	return matched
+}
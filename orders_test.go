@@ -0,0 +1,83 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetOrders(t *testing.T) {
+	pages := map[string]string{
+		"1": `{"orders": {"order": [
+			{"id": 1, "symbol": "SPY", "status": "filled", "create_date": "2024-06-03T14:00:00.000Z", "tag": "entry"},
+			{"id": 2, "symbol": "AAPL", "status": "canceled", "create_date": "2024-06-04T14:00:00.000Z"}]}}`,
+		"2": `{"orders": {"order": {"id": 3, "class": "multileg", "symbol": "SPY", "status": "open", "create_date": "2024-06-05T14:00:00.000Z",
+			"leg": {"id": 4, "option_symbol": "SPY240621C00530000"}}}}`,
+		"3": `{"orders": "null"}`,
+	}
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte(pages[r.URL.Query().Get("page")]))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	client := NewClient(params)
+
+	ids := func(orders []*Order) []int {
+		var ids []int
+		for _, o := range orders {
+			ids = append(ids, o.Id)
+		}
+		return ids
+	}
+
+	t.Run("All pages", func(t *testing.T) {
+		queries = nil
+		orders, err := client.GetOrders(OrdersParams{IncludeTags: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, ids(orders))
+		assert.Equal(t, "entry", orders[0].Tag)
+		assert.Equal(t, []string{"includeTags=true&page=1", "includeTags=true&page=2", "includeTags=true&page=3"}, queries)
+	})
+
+	t.Run("Page", func(t *testing.T) {
+		queries = nil
+		orders, err := client.GetOrders(OrdersParams{Page: 2, Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3}, ids(orders))
+		assert.Equal(t, []string{"limit=2&page=2"}, queries)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		orders, err := client.GetOrders(OrdersParams{Statuses: []string{Filled, Canceled}})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, ids(orders))
+
+		orders, err = client.GetOrders(OrdersParams{Symbol: "SPY240621C00530000"})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3}, ids(orders), "legs")
+
+		orders, err = client.GetOrders(OrdersParams{
+			Start: time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2}, ids(orders))
+	})
+
+	t.Run("Repeated pages", func(t *testing.T) {
+		// Pages are not requested forever if Tradier ignores the page.
+		pages["3"] = pages["2"]
+		defer func() { pages["3"] = `{"orders": "null"}` }()
+		orders, err := client.GetOrders(OrdersParams{})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, ids(orders))
+	})
+}
@@ -0,0 +1,136 @@
+package tradier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OrderPlacer places orders. It is implemented by Client and by RiskGate.
+type OrderPlacer interface {
+	PlaceOrder(order Order) (*OrderResult, error)
+}
+
+const (
+	// Plan step states.
+	PlanPending  = "pending"
+	PlanPlaced   = "placed"
+	PlanDeclined = "declined"
+	PlanFailed   = "failed"
+)
+
+// PlanStep is the execution of an order of a plan.
+type PlanStep struct {
+	Order  Order
+	State  string
+	Result *OrderResult
+	Err    error
+}
+
+// PlanReport reports the execution of a plan, with its steps in the order
+// they were executed.
+type PlanReport struct {
+	Steps []PlanStep
+	// Set if execution stopped at a failed step. The steps after it are
+	// still pending, and the plan can be continued with Resume.
+	Paused bool
+}
+
+// Count returns the number of steps in the state.
+func (pr *PlanReport) Count(state string) int {
+	n := 0
+	for _, step := range pr.Steps {
+		if step.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+func (pr *PlanReport) String() string {
+	var sb strings.Builder
+	for i, step := range pr.Steps {
+		fmt.Fprintf(&sb, "%d. %v: %v", i+1, describePlanOrder(step.Order), step.State)
+		if step.Result != nil {
+			fmt.Fprintf(&sb, " (order %d)", step.Result.Id)
+		}
+		if step.Err != nil {
+			fmt.Fprintf(&sb, ": %v", step.Err)
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "%d placed, %d declined, %d failed, %d pending\n",
+		pr.Count(PlanPlaced), pr.Count(PlanDeclined), pr.Count(PlanFailed), pr.Count(PlanPending))
+	return sb.String()
+}
+
+// PlanExecutor executes multi-order plans, such as a rebalance, one order at
+// a time. Orders that free cash (sells, and credit multileg orders) are placed
+// before those that use it, and execution pauses at the first order that
+// fails rather than placing the rest of the plan without it.
+type PlanExecutor struct {
+	Placer OrderPlacer
+	// If set, called before each order is placed. Returning false declines
+	// the order, and execution continues with the next one.
+	Confirm func(order Order) bool
+	// If set, called with each step once it has been executed.
+	Executed func(step PlanStep)
+}
+
+func NewPlanExecutor(placer OrderPlacer, confirm func(order Order) bool) *PlanExecutor {
+	return &PlanExecutor{Placer: placer, Confirm: confirm}
+}
+
+// Execute executes the orders of the plan. If an order fails, the report is
+// returned paused with the error.
+func (pe *PlanExecutor) Execute(orders []Order) (*PlanReport, error) {
+	report := &PlanReport{Steps: make([]PlanStep, len(orders))}
+	for i, order := range orders {
+		report.Steps[i] = PlanStep{Order: order, State: PlanPending}
+	}
+	sort.SliceStable(report.Steps, func(i, j int) bool {
+		return freesCash(report.Steps[i].Order) && !freesCash(report.Steps[j].Order)
+	})
+	return report, pe.run(report)
+}
+
+// Resume continues a paused plan, retrying the step that failed.
+func (pe *PlanExecutor) Resume(report *PlanReport) (*PlanReport, error) {
+	return report, pe.run(report)
+}
+
+func (pe *PlanExecutor) run(report *PlanReport) error {
+	report.Paused = false
+	for i := range report.Steps {
+		step := &report.Steps[i]
+		if step.State != PlanPending && step.State != PlanFailed {
+			continue
+		}
+
+		if pe.Confirm != nil && !pe.Confirm(step.Order) {
+			step.State, step.Err = PlanDeclined, nil
+		} else {
+			step.Result, step.Err = pe.Placer.PlaceOrder(step.Order)
+			step.State = PlanPlaced
+			if step.Err != nil {
+				step.State = PlanFailed
+			}
+		}
+		if pe.Executed != nil {
+			pe.Executed(*step)
+		}
+		if step.State == PlanFailed {
+			report.Paused = true
+			return fmt.Errorf("order %d of %d: %w", i+1, len(report.Steps), step.Err)
+		}
+	}
+	return nil
+}
+
+// Returns whether the order raises cash rather than using it.
+func freesCash(order Order) bool {
+	if len(order.Legs) > 0 {
+		return order.Type == Credit
+	}
+	return !isBuySide(order.Side)
+}
@@ -0,0 +1,74 @@
+package tradier
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ OrderPlacer = (*Client)(nil)
+	_ OrderPlacer = (*RiskGate)(nil)
+)
+
+type fakeOrderPlacer struct {
+	placed []string
+	fail   map[string]bool
+}
+
+func (fp *fakeOrderPlacer) PlaceOrder(order Order) (*OrderResult, error) {
+	if fp.fail[order.Symbol] {
+		return nil, errors.New("rejected")
+	}
+	fp.placed = append(fp.placed, order.Symbol)
+	return &OrderResult{Id: len(fp.placed), Status: StatusOK}, nil
+}
+
+func TestPlanExecutor(t *testing.T) {
+	orders := []Order{
+		{Class: Equity, Symbol: "AAPL", Side: Buy, Quantity: 10, Type: MarketOrder, Duration: Day},
+		{Class: Equity, Symbol: "SPY", Side: Sell, Quantity: 5, Type: MarketOrder, Duration: Day},
+		{Class: Equity, Symbol: "MSFT", Side: Buy, Quantity: 3, Type: MarketOrder, Duration: Day},
+		{Class: Multileg, Symbol: "QQQ", Type: Credit, Price: 1.2, Duration: Day, Legs: []Order{
+			{OptionSymbol: "QQQ210219P00300000", Side: SellToOpen, Quantity: 1},
+			{OptionSymbol: "QQQ210219P00290000", Side: BuyToOpen, Quantity: 1},
+		}},
+	}
+
+	t.Run("Sells first", func(t *testing.T) {
+		placer := &fakeOrderPlacer{}
+		var executed []string
+		executor := NewPlanExecutor(placer, func(order Order) bool { return order.Symbol != "MSFT" })
+		executor.Executed = func(step PlanStep) { executed = append(executed, step.Order.Symbol+" "+step.State) }
+
+		report, err := executor.Execute(orders)
+		assert.NoError(t, err)
+		assert.False(t, report.Paused)
+		assert.Equal(t, []string{"SPY", "QQQ", "AAPL"}, placer.placed)
+		assert.Equal(t, []string{"SPY placed", "QQQ placed", "AAPL placed", "MSFT declined"}, executed)
+		assert.Equal(t, 3, report.Count(PlanPlaced))
+		assert.Contains(t, report.String(), "4. buy 3 MSFT: declined\n")
+	})
+
+	t.Run("Pauses on errors", func(t *testing.T) {
+		placer := &fakeOrderPlacer{fail: map[string]bool{"QQQ": true}}
+		executor := NewPlanExecutor(placer, nil)
+
+		report, err := executor.Execute(orders)
+		if assert.Error(t, err) {
+			assert.Equal(t, "order 2 of 4: rejected", err.Error())
+		}
+		assert.True(t, report.Paused)
+		assert.Equal(t, []string{"SPY"}, placer.placed, "nothing is placed after the failure")
+		assert.Equal(t, 2, report.Count(PlanPending))
+		assert.Contains(t, report.String(), "1 placed, 0 declined, 1 failed, 2 pending")
+
+		placer.fail = nil
+		report, err = executor.Resume(report)
+		assert.NoError(t, err)
+		assert.False(t, report.Paused)
+		assert.Equal(t, []string{"SPY", "QQQ", "AAPL", "MSFT"}, placer.placed, "placed orders are not repeated")
+		assert.Equal(t, 4, report.Count(PlanPlaced))
+	})
+}
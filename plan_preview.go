@@ -0,0 +1,128 @@
+package tradier
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Number of orders of a plan previewed at once by PreviewPlan. The client's
+// rate limiting still applies to each preview.
+const planPreviewConcurrency = 4
+
+// PlanPreview is the preview of a plan of orders, such as those rendered from
+// templates, to be confirmed before any of them are placed.
+type PlanPreview struct {
+	Orders []Order
+	// Preview of each order, or nil if its preview failed.
+	Previews []*OrderPreview
+	// Error previewing each order, or nil if it succeeded.
+	Errors []error
+
+	// Totals of the successful previews.
+	Commission   float64
+	Fees         float64
+	Cost         float64
+	OrderCost    float64
+	MarginChange float64
+}
+
+// Err returns the first error previewing an order of the plan, if any.
+func (pp *PlanPreview) Err() error {
+	for i, err := range pp.Errors {
+		if err != nil {
+			return fmt.Errorf("order %d of %d: %w", i+1, len(pp.Orders), err)
+		}
+	}
+	return nil
+}
+
+// Warnings returns the warnings of the previews, by index of the order.
+func (pp *PlanPreview) Warnings() map[int][]PreviewWarning {
+	warnings := make(map[int][]PreviewWarning)
+	for i, preview := range pp.Previews {
+		if preview == nil {
+			continue
+		}
+		if w := preview.Warnings(); len(w) > 0 {
+			warnings[i] = w
+		}
+	}
+	return warnings
+}
+
+// String summarizes the plan on one screen: a line per order, and the totals.
+func (pp *PlanPreview) String() string {
+	var sb strings.Builder
+	for i, order := range pp.Orders {
+		fmt.Fprintf(&sb, "%d. %v", i+1, describePlanOrder(order))
+		if preview := pp.Previews[i]; preview != nil {
+			fmt.Fprintf(&sb, ": cost %.2f, commission %.2f, margin change %.2f", preview.Cost, preview.Commission, preview.MarginChange)
+			for _, w := range preview.Warnings() {
+				fmt.Fprintf(&sb, " [%v]", w)
+			}
+		} else {
+			fmt.Fprintf(&sb, ": %v", pp.Errors[i])
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "Total: cost %.2f, commission %.2f, fees %.2f, margin change %.2f\n",
+		pp.Cost, pp.Commission, pp.Fees, pp.MarginChange)
+	return sb.String()
+}
+
+func describePlanOrder(order Order) string {
+	if len(order.Legs) == 0 {
+		return fmt.Sprintf("%v %v %v", order.Side, order.Quantity, orderSymbol(&order))
+	}
+	legs := make([]string, len(order.Legs))
+	for i, leg := range order.Legs {
+		legs[i] = describePlanOrder(leg)
+	}
+	return fmt.Sprintf("%v %v (%v)", order.Class, order.Symbol, strings.Join(legs, ", "))
+}
+
+// PreviewPlan previews the orders concurrently and totals their commissions,
+// cost and margin impact, so that a multi-order plan can be confirmed before
+// any of it is executed. The plan is returned even if some previews failed,
+// with the first failure as the error.
+func (tc *Client) PreviewPlan(orders []Order) (*PlanPreview, error) {
+	plan := &PlanPreview{
+		Orders:   orders,
+		Previews: make([]*OrderPreview, len(orders)),
+		Errors:   make([]error, len(orders)),
+	}
+
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+	for w := 0; w < planPreviewConcurrency && w < len(orders); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				preview, err := tc.PreviewOrder(orders[i])
+				if err != nil {
+					preview = nil
+				}
+				plan.Previews[i], plan.Errors[i] = preview, err
+			}
+		}()
+	}
+	for i := range orders {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, preview := range plan.Previews {
+		if preview == nil {
+			continue
+		}
+		plan.Commission += preview.Commission
+		plan.Fees += preview.Fees
+		plan.Cost += preview.Cost
+		plan.OrderCost += preview.OrderCost
+		plan.MarginChange += preview.MarginChange
+	}
+	return plan, plan.Err()
+}
@@ -0,0 +1,70 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_PreviewPlan(t *testing.T) {
+	var mu sync.Mutex
+	var placed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("preview") != "true" {
+			mu.Lock()
+			placed++
+			mu.Unlock()
+		}
+		switch r.FormValue("symbol") {
+		case "SPY":
+			w.Write([]byte(`{"order": {"status": "ok", "commission": 1, "cost": 4101, "order_cost": 4100, "margin_change": 2050, "day_trade": true}}`))
+		case "AAPL":
+			w.Write([]byte(`{"order": {"status": "ok", "commission": 1, "fees": 0.5, "cost": -1999, "order_cost": -2000, "margin_change": -1000}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": {"error": "Invalid symbol"}}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.RetryLimit = 0
+	client := NewClient(params)
+
+	orders := []Order{
+		{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 10, Type: MarketOrder, Duration: Day},
+		{Class: Equity, Symbol: "AAPL", Side: Sell, Quantity: 20, Type: MarketOrder, Duration: Day},
+	}
+	plan, err := client.PreviewPlan(orders)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, plan.Commission)
+	assert.Equal(t, 0.5, plan.Fees)
+	assert.Equal(t, 2102.0, plan.Cost)
+	assert.Equal(t, 2100.0, plan.OrderCost)
+	assert.Equal(t, 1050.0, plan.MarginChange)
+	assert.Equal(t, map[int][]PreviewWarning{0: {{Type: WarningDayTrade}}}, plan.Warnings())
+	assert.Equal(t, "1. buy 10 SPY: cost 4101.00, commission 1.00, margin change 2050.00 [day_trade]\n"+
+		"2. sell 20 AAPL: cost -1999.00, commission 1.00, margin change -1000.00\n"+
+		"Total: cost 2102.00, commission 2.00, fees 0.50, margin change 1050.00\n", plan.String())
+	assert.Zero(t, placed, "nothing is placed")
+
+	t.Run("Failed preview", func(t *testing.T) {
+		many := append(orders, Order{Class: Equity, Symbol: "NOPE", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day})
+		for i := 0; i < 2*planPreviewConcurrency; i++ {
+			many = append(many, orders[0])
+		}
+		plan, err := client.PreviewPlan(many)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "order 3 of 11")
+		}
+		assert.Nil(t, plan.Previews[2])
+		assert.NotNil(t, plan.Previews[10])
+		assert.Equal(t, 10.0, plan.Commission, "failed previews are not totalled")
+		assert.Contains(t, plan.String(), "3. buy 1 NOPE: ")
+	})
+}
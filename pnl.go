@@ -0,0 +1,230 @@
+package tradier
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"time"
+)
+
+// PnLAttribution is the P&L of one symbol attributed to one order tag.
+// Untagged fills and positions carried over from the previous session
+// are attributed to the empty tag.
+type PnLAttribution struct {
+	Symbol string
+	Tag    string
+	// Mark-to-market change of the position held at the previous close.
+	Position float64
+	// P&L of the day's fills marked to the closing price.
+	Trading float64
+	Fees    float64
+	Net     float64
+}
+
+// PnLReport attributes one day's P&L by symbol and tag.
+type PnLReport struct {
+	Date  time.Time
+	Rows  []PnLAttribution
+	Total PnLAttribution
+}
+
+// PnLInputs are the account state used to build a PnLReport.
+type PnLInputs struct {
+	Date time.Time
+	// Orders placed during the day. Only executed quantity is counted.
+	Orders []*Order
+	// Positions held at the end of the day.
+	Positions []*Position
+	// Closing and previous closing quotes by symbol.
+	Quotes map[string]*Quote
+	// Fees returns the fees charged for an order. If nil then fees are not deducted.
+	Fees func(order *Order) float64
+}
+
+type pnlKey struct {
+	symbol, tag string
+}
+
+// AttributePnL builds the P&L report for the given inputs. The position held
+// at the previous close is derived from the end of day positions and the
+// day's fills.
+func AttributePnL(in PnLInputs) *PnLReport {
+	rows := make(map[pnlKey]*PnLAttribution)
+	row := func(symbol, tag string) *PnLAttribution {
+		k := pnlKey{symbol, tag}
+		if rows[k] == nil {
+			rows[k] = &PnLAttribution{Symbol: symbol, Tag: tag}
+		}
+		return rows[k]
+	}
+
+	closes := func(symbol string) (float64, float64) {
+		q := in.Quotes[symbol]
+		if q == nil {
+			return 0, 0
+		}
+		close := q.Close
+		if close == 0 {
+			close = q.Last
+		}
+		return close, q.PreviousClose
+	}
+
+	// Net quantity bought during the day, by symbol.
+	bought := make(map[string]float64)
+	for _, order := range in.Orders {
+		fills := order.Legs
+		if len(fills) == 0 {
+			fills = []Order{*order}
+		}
+
+		var filled bool
+		for _, fill := range fills {
+			if fill.ExecutedQuantity == 0 {
+				continue
+			}
+			filled = true
+
+			symbol := orderSymbol(&fill)
+			close, _ := closes(symbol)
+			quantity := fill.ExecutedQuantity
+			if !isBuySide(fill.Side) {
+				quantity = -quantity
+			}
+			bought[symbol] += quantity
+			row(symbol, order.Tag).Trading += quantity * (close - fill.AverageFillPrice) * contractMultiplier(symbol)
+		}
+
+		if filled && in.Fees != nil {
+			row(orderSymbol(order), order.Tag).Fees += in.Fees(order)
+		}
+	}
+
+	held := make(map[string]float64)
+	for _, p := range in.Positions {
+		held[p.Symbol] += p.Quantity
+	}
+	for symbol := range bought {
+		if _, ok := held[symbol]; !ok {
+			held[symbol] = 0
+		}
+	}
+	for symbol, quantity := range held {
+		previous := quantity - bought[symbol]
+		if previous == 0 {
+			continue
+		}
+		close, prevClose := closes(symbol)
+		row(symbol, "").Position += previous * (close - prevClose) * contractMultiplier(symbol)
+	}
+
+	report := &PnLReport{Date: in.Date}
+	for _, r := range rows {
+		r.Net = r.Position + r.Trading - r.Fees
+		report.Rows = append(report.Rows, *r)
+		report.Total.Position += r.Position
+		report.Total.Trading += r.Trading
+		report.Total.Fees += r.Fees
+		report.Total.Net += r.Net
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].Symbol != report.Rows[j].Symbol {
+			return report.Rows[i].Symbol < report.Rows[j].Symbol
+		}
+		return report.Rows[i].Tag < report.Rows[j].Tag
+	})
+	return report
+}
+
+// WriteCSV writes the report rows followed by a total row.
+func (r *PnLReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	date := r.Date.Format("2006-01-02")
+	if err := cw.Write([]string{"date", "symbol", "tag", "position", "trading", "fees", "net"}); err != nil {
+		return err
+	}
+
+	total := r.Total
+	total.Symbol = "TOTAL"
+	for _, row := range append(r.Rows, total) {
+		record := []string{
+			date, row.Symbol, row.Tag,
+			formatExportAmount(row.Position),
+			formatExportAmount(row.Trading),
+			formatExportAmount(row.Fees),
+			formatExportAmount(row.Net),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// GetDailyPnL builds the P&L report for the current session from today's
+// orders, the current positions and their closing quotes.
+func (tc *Client) GetDailyPnL(fees func(order *Order) float64) (*PnLReport, error) {
+	return dailyPnL(tc, fees, time.Now())
+}
+
+func dailyPnL(account EODAccount, fees func(order *Order) float64, now time.Time) (*PnLReport, error) {
+	orders, err := account.GetOpenOrders()
+	if err != nil {
+		return nil, err
+	}
+	positions, err := account.GetAccountPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make(map[string]bool)
+	for _, p := range positions {
+		symbols[p.Symbol] = true
+	}
+	for _, o := range orders {
+		symbols[orderSymbol(o)] = true
+		for i := range o.Legs {
+			symbols[orderSymbol(&o.Legs[i])] = true
+		}
+	}
+	delete(symbols, "")
+
+	quotes := make(map[string]*Quote)
+	if len(symbols) > 0 {
+		list := make([]string, 0, len(symbols))
+		for s := range symbols {
+			list = append(list, s)
+		}
+		result, err := account.GetQuotes(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, q := range result {
+			quotes[q.Symbol] = q
+		}
+	}
+
+	return AttributePnL(PnLInputs{
+		Date:      now,
+		Orders:    orders,
+		Positions: positions,
+		Quotes:    quotes,
+		Fees:      fees,
+	}), nil
+}
+
+func orderSymbol(order *Order) string {
+	if order.OptionSymbol != "" {
+		return order.OptionSymbol
+	}
+	return order.Symbol
+}
+
+// contractMultiplier returns the number of shares per unit of the symbol.
+func contractMultiplier(symbol string) float64 {
+	if IsOptionSymbol(symbol) {
+		return 100
+	}
+	return 1
+}
@@ -0,0 +1,55 @@
+package tradier
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributePnL(t *testing.T) {
+	in := PnLInputs{
+		Date: time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC),
+		Orders: []*Order{
+			{Symbol: "SPY", Side: Buy, ExecutedQuantity: 10, AverageFillPrice: 370, Tag: "momentum"},
+			{Symbol: "SPY", Side: Sell, ExecutedQuantity: 5, AverageFillPrice: 372, Tag: "meanrev"},
+			{Symbol: "AAPL", Side: Buy, Quantity: 10, Status: Canceled, Tag: "momentum"},
+			{Class: Multileg, Symbol: "SPY", Tag: "spreads", Legs: []Order{
+				{OptionSymbol: "SPY210219P00360000", Side: SellToOpen, ExecutedQuantity: 1, AverageFillPrice: 3.10},
+				{OptionSymbol: "SPY210219P00355000", Side: BuyToOpen, ExecutedQuantity: 1, AverageFillPrice: 2.10},
+			}},
+		},
+		Positions: []*Position{
+			{Symbol: "SPY", Quantity: 105},
+			{Symbol: "SPY210219P00360000", Quantity: -1},
+			{Symbol: "SPY210219P00355000", Quantity: 1},
+		},
+		Quotes: map[string]*Quote{
+			"SPY":                {Symbol: "SPY", Close: 371, PreviousClose: 369},
+			"SPY210219P00360000": {Close: 3.00},
+			"SPY210219P00355000": {Close: 2.05},
+		},
+		Fees: func(order *Order) float64 { return 1 },
+	}
+
+	report := AttributePnL(in)
+	if assert.Len(t, report.Rows, 6) {
+		// 100 shares carried from the previous close gained $2 each.
+		assert.Equal(t, PnLAttribution{Symbol: "SPY", Position: 200, Net: 200}, report.Rows[0])
+		assert.Equal(t, PnLAttribution{Symbol: "SPY", Tag: "meanrev", Trading: 5, Fees: 1, Net: 4}, report.Rows[1])
+		assert.Equal(t, PnLAttribution{Symbol: "SPY", Tag: "momentum", Trading: 10, Fees: 1, Net: 9}, report.Rows[2])
+		assert.Equal(t, PnLAttribution{Symbol: "SPY", Tag: "spreads", Fees: 1, Net: -1}, report.Rows[3])
+		assert.InDelta(t, -5, report.Rows[4].Trading, 1e-9)
+		assert.InDelta(t, 10, report.Rows[5].Trading, 1e-9)
+	}
+	assert.InDelta(t, 217, report.Total.Net, 1e-9)
+
+	var buf bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 8)
+	assert.Equal(t, "date,symbol,tag,position,trading,fees,net", lines[0])
+	assert.Equal(t, "2021-01-04,TOTAL,,200.00,20.00,3.00,217.00", lines[7])
+}
@@ -0,0 +1,141 @@
+package tradier
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TaxLot is an opening purchase or sale of a symbol. Quantity is negative for
+// short lots, whose cost basis is the (negative) proceeds of the sale.
+type TaxLot struct {
+	Symbol       string
+	Quantity     float64
+	CostBasis    float64
+	DateAcquired time.Time
+}
+
+// PositionBook is a local record of the account's positions as tax lots,
+// which are closed first in, first out.
+type PositionBook struct {
+	mu   sync.Mutex
+	lots map[string][]TaxLot
+}
+
+// NewPositionBook returns a book with a lot for each of the given positions.
+func NewPositionBook(positions []*Position) *PositionBook {
+	pb := &PositionBook{lots: make(map[string][]TaxLot)}
+	for _, p := range positions {
+		pb.lots[p.Symbol] = append(pb.lots[p.Symbol], TaxLot{
+			Symbol:       p.Symbol,
+			Quantity:     p.Quantity,
+			CostBasis:    p.CostBasis,
+			DateAcquired: p.DateAcquired.Time,
+		})
+	}
+	return pb
+}
+
+// Lots returns the open lots of symbol, oldest first.
+func (pb *PositionBook) Lots(symbol string) []TaxLot {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	return append([]TaxLot(nil), pb.lots[symbol]...)
+}
+
+// Position returns the total position in symbol, or nil if there is none.
+func (pb *PositionBook) Position(symbol string) *Position {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	return pb.position(symbol)
+}
+
+func (pb *PositionBook) position(symbol string) *Position {
+	lots := pb.lots[symbol]
+	if len(lots) == 0 {
+		return nil
+	}
+	p := &Position{Symbol: symbol, DateAcquired: DateTime{lots[0].DateAcquired}}
+	for _, lot := range lots {
+		p.Quantity += lot.Quantity
+		p.CostBasis += lot.CostBasis
+	}
+	return p
+}
+
+// Positions returns the total position in each symbol, ordered by symbol.
+func (pb *PositionBook) Positions() []*Position {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	symbols := make([]string, 0, len(pb.lots))
+	for s := range pb.lots {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+
+	positions := make([]*Position, len(symbols))
+	for i, s := range symbols {
+		positions[i] = pb.position(s)
+	}
+	return positions
+}
+
+// Trade records a trade of quantity units (negative for sales) for a total
+// cost (negative for proceeds). Lots of the opposite sign are closed first,
+// and the realized gain on them is returned; any remainder opens a new lot.
+func (pb *PositionBook) Trade(symbol string, quantity, cost float64, date time.Time) float64 {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	return pb.trade(symbol, quantity, cost, date)
+}
+
+func (pb *PositionBook) trade(symbol string, quantity, cost float64, date time.Time) float64 {
+	if quantity == 0 {
+		return 0
+	}
+	closed, basis := pb.close(symbol, -quantity)
+	closingCost := cost * math.Abs(closed) / math.Abs(quantity)
+	realized := -(basis + closingCost)
+
+	if remaining := quantity + closed; remaining != 0 {
+		pb.lots[symbol] = append(pb.lots[symbol], TaxLot{
+			Symbol:       symbol,
+			Quantity:     remaining,
+			CostBasis:    cost - closingCost,
+			DateAcquired: date,
+		})
+	}
+	return realized
+}
+
+// close removes up to quantity units (signed like the lots to close) from the
+// lots of symbol that have the same sign, first in first out. It returns the
+// quantity removed and its cost basis.
+func (pb *PositionBook) close(symbol string, quantity float64) (float64, float64) {
+	var removed, basis float64
+	lots := pb.lots[symbol]
+	for len(lots) > 0 && math.Abs(removed) < math.Abs(quantity) {
+		lot := &lots[0]
+		if (lot.Quantity > 0) != (quantity > 0) {
+			break
+		}
+
+		take := math.Copysign(math.Min(math.Abs(lot.Quantity), math.Abs(quantity-removed)), quantity)
+		share := lot.CostBasis * take / lot.Quantity
+		removed += take
+		basis += share
+		lot.Quantity -= take
+		lot.CostBasis -= share
+		if lot.Quantity == 0 {
+			lots = lots[1:]
+		}
+	}
+
+	if len(lots) == 0 {
+		delete(pb.lots, symbol)
+	} else {
+		pb.lots[symbol] = lots
+	}
+	return removed, basis
+}
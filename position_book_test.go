@@ -0,0 +1,34 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionBook_Trade(t *testing.T) {
+	day := time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC)
+	book := NewPositionBook([]*Position{
+		{Symbol: "SPY", Quantity: 100, CostBasis: 36000, DateAcquired: DateTime{day}},
+	})
+
+	assert.Equal(t, 0.0, book.Trade("SPY", 50, 19000, day.AddDate(0, 0, 1)))
+	// Sales close the oldest lot first.
+	assert.InDelta(t, 1400.0, book.Trade("SPY", -120, -45000, day.AddDate(0, 0, 2)), 1e-9)
+	lots := book.Lots("SPY")
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, 30.0, lots[0].Quantity)
+		assert.InDelta(t, 11400.0, lots[0].CostBasis, 1e-9)
+	}
+
+	// Selling more than is held opens a short lot.
+	assert.InDelta(t, 2100.0, book.Trade("SPY", -50, -22500, day.AddDate(0, 0, 3)), 1e-9)
+	if p := book.Position("SPY"); assert.NotNil(t, p) {
+		assert.Equal(t, -20.0, p.Quantity)
+		assert.InDelta(t, -9000.0, p.CostBasis, 1e-9)
+	}
+	assert.InDelta(t, 1000.0, book.Trade("SPY", 20, 8000, day.AddDate(0, 0, 4)), 1e-9)
+	assert.Nil(t, book.Position("SPY"))
+	assert.Empty(t, book.Positions())
+}
@@ -0,0 +1,194 @@
+package tradier
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Types of order preview warnings.
+const (
+	WarningDayTrade      = "day_trade"
+	WarningMarginCall    = "margin_call"
+	WarningExtendedHours = "extended_hours"
+	WarningOther         = "other"
+)
+
+// PreviewWarning is a warning raised by the preview of an order.
+type PreviewWarning struct {
+	// One of WarningDayTrade, WarningMarginCall, WarningExtendedHours or WarningOther.
+	Type    string
+	Message string
+}
+
+func (pw PreviewWarning) String() string {
+	if pw.Message == "" {
+		return pw.Type
+	}
+	return pw.Type + ": " + pw.Message
+}
+
+// Warning messages are sent as a string, a list of strings or an object of
+// them keyed by type. Unrecognized warnings must not fail the preview, so
+// they are ignored.
+type previewMessages []string
+
+func (pm *previewMessages) UnmarshalJSON(data []byte) error {
+	if messages, err := decodeOneOrMany[string](data); err == nil {
+		*pm = messages
+		return nil
+	}
+
+	var byKey map[string]stringList
+	if err := json.Unmarshal(data, &byKey); err == nil {
+		keys := make([]string, 0, len(byKey))
+		for key := range byKey {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var messages []string
+		for _, key := range keys {
+			messages = append(messages, byKey[key]...)
+		}
+		*pm = messages
+	}
+	return nil
+}
+
+// Warnings returns the typed warnings of the preview, from its flags and the
+// warning messages it was returned with.
+func (op *OrderPreview) Warnings() []PreviewWarning {
+	var warnings []PreviewWarning
+	seen := make(map[string]bool)
+	add := func(warningType, message string) {
+		if message == "" && seen[warningType] {
+			return
+		}
+		seen[warningType] = true
+		warnings = append(warnings, PreviewWarning{Type: warningType, Message: message})
+	}
+
+	for _, message := range op.Messages {
+		add(classifyPreviewWarning(message), message)
+	}
+	if op.DayTrade {
+		add(WarningDayTrade, "")
+	}
+	if op.MarginCall {
+		add(WarningMarginCall, "")
+	}
+	if op.ExtendedHours {
+		add(WarningExtendedHours, "")
+	}
+	return warnings
+}
+
+// HasWarning returns true if the preview raised a warning of the given type.
+func (op *OrderPreview) HasWarning(warningType string) bool {
+	for _, w := range op.Warnings() {
+		if w.Type == warningType {
+			return true
+		}
+	}
+	return false
+}
+
+func classifyPreviewWarning(message string) string {
+	m := strings.ToLower(strings.NewReplacer("_", " ", "-", " ").Replace(message))
+	switch {
+	case strings.Contains(m, "day trad"), strings.Contains(m, "pattern day"), strings.Contains(m, "pdt"):
+		return WarningDayTrade
+	case strings.Contains(m, "margin call"), strings.Contains(m, "maintenance call"), strings.Contains(m, "fed call"):
+		return WarningMarginCall
+	case strings.Contains(m, "extended hours"), strings.Contains(m, "pre market"), strings.Contains(m, "post market"):
+		return WarningExtendedHours
+	default:
+		return WarningOther
+	}
+}
+
+// ErrRiskRejected is returned when a rule of a RiskGate rejects an order.
+type ErrRiskRejected struct {
+	Rule   string
+	Reason string
+}
+
+func (e ErrRiskRejected) Error() string {
+	return fmt.Sprintf("order rejected by %v: %v", e.Rule, e.Reason)
+}
+
+// RiskRule checks an order before it is placed, given its preview.
+// It returns a non-nil error (usually ErrRiskRejected) to reject the order.
+type RiskRule func(order Order, preview *OrderPreview) error
+
+// RiskGate previews orders and checks them against its rules before placing
+// them, making the preview an automated pre-trade compliance step.
+type RiskGate struct {
+	Client *Client
+	Rules  []RiskRule
+}
+
+func (tc *Client) NewRiskGate(rules ...RiskRule) *RiskGate {
+	return &RiskGate{Client: tc, Rules: rules}
+}
+
+// Check previews the order and applies each rule to it, returning the
+// preview and the first rule's rejection, if any.
+func (rg *RiskGate) Check(order Order) (*OrderPreview, error) {
+	preview, err := rg.Client.PreviewOrder(order)
+	if err != nil {
+		return preview, err
+	}
+	for _, rule := range rg.Rules {
+		if err := rule(order, preview); err != nil {
+			return preview, err
+		}
+	}
+	return preview, nil
+}
+
+// PlaceOrder places the order if it passes every rule.
+func (rg *RiskGate) PlaceOrder(order Order) (*OrderResult, error) {
+	if _, err := rg.Check(order); err != nil {
+		return nil, err
+	}
+	return rg.Client.PlaceOrder(order)
+}
+
+// RejectDayTrades rejects orders that would be day trades once maxDayTrades
+// day trades have been made in the period, e.g. 3 to avoid being flagged a
+// pattern day trader.
+func RejectDayTrades(maxDayTrades int) RiskRule {
+	return func(order Order, preview *OrderPreview) error {
+		if preview.HasWarning(WarningDayTrade) && preview.DayTrades >= maxDayTrades {
+			return ErrRiskRejected{
+				Rule:   "RejectDayTrades",
+				Reason: fmt.Sprintf("day trade with %d of %d day trades made", preview.DayTrades, maxDayTrades),
+			}
+		}
+		return nil
+	}
+}
+
+// RejectMarginCalls rejects orders whose preview warns of a margin call.
+func RejectMarginCalls() RiskRule {
+	return rejectWarning("RejectMarginCalls", WarningMarginCall)
+}
+
+// RejectExtendedHours rejects orders whose preview warns that they would
+// execute in extended hours.
+func RejectExtendedHours() RiskRule {
+	return rejectWarning("RejectExtendedHours", WarningExtendedHours)
+}
+
+func rejectWarning(rule, warningType string) RiskRule {
+	return func(order Order, preview *OrderPreview) error {
+		for _, w := range preview.Warnings() {
+			if w.Type == warningType {
+				return ErrRiskRejected{Rule: rule, Reason: w.String()}
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package tradier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderPreview_Warnings(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		preview  string
+		expected []PreviewWarning
+	}{
+		{"No warnings", `{"status": "ok", "cost": 100}`, nil},
+		{"Flags", `{"status": "ok", "day_trade": true, "day_trades": 2, "margin_call": true}`,
+			[]PreviewWarning{{Type: WarningDayTrade}, {Type: WarningMarginCall}}},
+		{"Messages", `{"status": "ok", "extended_hours": true, "warnings": ["This order may result in a Pattern Day Trader designation", "Order will execute in extended hours"]}`,
+			[]PreviewWarning{
+				{Type: WarningDayTrade, Message: "This order may result in a Pattern Day Trader designation"},
+				{Type: WarningExtendedHours, Message: "Order will execute in extended hours"},
+			}},
+		{"Keyed messages", `{"status": "ok", "warnings": {"warning": "Margin call expected"}}`,
+			[]PreviewWarning{{Type: WarningMarginCall, Message: "Margin call expected"}}},
+		{"Unrecognized", `{"status": "ok", "warnings": {"warning": {"code": 1}}}`, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var preview OrderPreview
+			if assert.NoError(t, json.Unmarshal([]byte(test.preview), &preview)) {
+				assert.Equal(t, test.expected, preview.Warnings())
+			}
+		})
+	}
+}
+
+func TestClient_PreviewOrder(t *testing.T) {
+	var response string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	client := NewClient(params)
+	order := Order{Class: Option, Symbol: "SPY", OptionSymbol: "SPY210219P00360000", Side: SellToOpen, Quantity: 1, Type: LimitOrder, Price: 4.4, Duration: Day}
+
+	response = `{"order": {"status": "ok", "commission": "0.35000000", "cost": -439.65, "fees": 0.04, "symbol": "SPY",
+		"option_symbol": "SPY210219P00360000", "quantity": 1, "side": "sell_to_open", "type": "limit", "duration": "day",
+		"price": 4.4, "result": true, "order_cost": -440, "margin_change": 7200, "option_requirement": 7200,
+		"request_date": "2021-01-04T15:40:33.617", "extended_hours": false, "class": "option", "strategy": "option",
+		"day_trades": 0}}`
+	preview, err := client.PreviewOrder(order)
+	if assert.NoError(t, err) {
+		assert.True(t, preview.Result)
+		assert.Equal(t, 0.35, preview.Commission, "numbers sent as strings")
+		assert.Equal(t, 0.04, preview.Fees)
+		assert.Equal(t, -440.0, preview.OrderCost)
+		assert.Equal(t, -439.65, preview.Cost)
+		assert.Equal(t, 7200.0, preview.MarginChange)
+		assert.Equal(t, 7200.0, preview.OptionRequirement)
+		assert.Equal(t, "SPY210219P00360000", preview.OptionSymbol)
+		assert.Equal(t, SellToOpen, preview.Side)
+		assert.Equal(t, 4.4, preview.Price)
+		assert.Equal(t, "option", preview.Strategy)
+		assert.Equal(t, 2021, preview.RequestDate.Year())
+	}
+
+	response = `{"order": {"status": "failed", "result": false, "errors": {"error": ["Insufficient buying power", "Invalid price"]}}}`
+	preview, err = client.PreviewOrder(order)
+	assert.EqualError(t, err, "received order status: failed: Insufficient buying power; Invalid price")
+	if assert.NotNil(t, preview) {
+		assert.False(t, preview.Result)
+		assert.Equal(t, []string{"Insufficient buying power", "Invalid price"}, []string(preview.ErrorMessages))
+	}
+}
+
+func TestRiskGate(t *testing.T) {
+	var preview string
+	var placed int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("preview") == "true" {
+			w.Write([]byte(`{"order": ` + preview + `}`))
+			return
+		}
+		placed++
+		w.Write([]byte(`{"order": {"id": 1, "status": "ok"}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Environment = EnvironmentUnspecified
+	params.EnableTrading = true
+	params.Account = "VA000"
+	gate := NewClient(params).NewRiskGate(RejectDayTrades(3), RejectMarginCalls())
+	order := Order{Class: Equity, Symbol: "SPY", Side: Sell, Quantity: 1, Type: MarketOrder, Duration: Day}
+
+	preview = `{"status": "ok", "day_trade": true, "day_trades": 2}`
+	_, err := gate.PlaceOrder(order)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, placed)
+
+	preview = `{"status": "ok", "day_trade": true, "day_trades": 3}`
+	_, err = gate.PlaceOrder(order)
+	if assert.IsType(t, ErrRiskRejected{}, err) {
+		assert.Equal(t, "RejectDayTrades", err.(ErrRiskRejected).Rule)
+	}
+
+	preview = `{"status": "ok", "warnings": "Order would result in a margin call"}`
+	_, err = gate.PlaceOrder(order)
+	if assert.IsType(t, ErrRiskRejected{}, err) {
+		assert.Equal(t, "RejectMarginCalls", err.(ErrRiskRejected).Rule)
+	}
+	assert.Equal(t, 1, placed)
+}
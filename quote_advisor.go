@@ -0,0 +1,164 @@
+package tradier
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// QuoteAllocation divides symbols between a market stream and polling.
+type QuoteAllocation struct {
+	// Symbols to subscribe to on the stream.
+	Stream []string
+	// Plan for polling the other symbols. Requests are spaced no closer
+	// than needed to poll them within Freshness, leaving the rest of the
+	// rate limit for other requests.
+	Poll PollPlan
+	// False if the rate limit does not allow polled quotes to be as fresh
+	// as the advisor's Freshness.
+	Fresh bool
+}
+
+// QuoteAdvisor allocates symbols between streaming and polling for the
+// freshest quotes within the account's entitlements and the market data
+// rate limit, and reallocates them when the symbols or rate limit change.
+//
+// Symbols are allocated in the order they are added: the first
+// MaxStreamSymbols are streamed if the account is entitled to stream, and
+// the rest are polled.
+type QuoteAdvisor struct {
+	// True if the account is entitled to stream market data.
+	Streaming bool
+	// Maximum symbols subscribed to on the stream. Zero means no limit.
+	MaxStreamSymbols int
+	// Desired maximum age of polled quotes. If zero they are polled as
+	// fast as the rate limit allows.
+	Freshness time.Duration
+	Planner   PollPlanner
+	// If set, used by Refresh to read the current market data rate limit,
+	// e.g. Client.Stats.
+	Stats func() Stats
+	// How often Run refreshes the allocation.
+	Interval time.Duration
+	// Called with the new allocation each time it changes.
+	Changes func(allocation QuoteAllocation)
+
+	mu         sync.Mutex
+	symbols    []string
+	window     RateLimitWindow
+	allocation QuoteAllocation
+}
+
+func NewQuoteAdvisor(streaming bool, freshness time.Duration, changes func(allocation QuoteAllocation)) *QuoteAdvisor {
+	return &QuoteAdvisor{
+		Streaming: streaming,
+		Freshness: freshness,
+		Planner:   PollPlanner{Headroom: 0.1},
+		Interval:  time.Minute,
+		Changes:   changes,
+	}
+}
+
+// Allocation returns the current allocation.
+func (qa *QuoteAdvisor) Allocation() QuoteAllocation {
+	qa.mu.Lock()
+	defer qa.mu.Unlock()
+	return qa.allocation
+}
+
+// Add adds symbols after those already allocated.
+func (qa *QuoteAdvisor) Add(symbols ...string) {
+	qa.update(func() {
+		for _, s := range symbols {
+			if !containsString(qa.symbols, s) {
+				qa.symbols = append(qa.symbols, s)
+			}
+		}
+	})
+}
+
+// Remove removes symbols from the allocation.
+func (qa *QuoteAdvisor) Remove(symbols ...string) {
+	qa.update(func() {
+		kept := qa.symbols[:0]
+		for _, s := range qa.symbols {
+			if !containsString(symbols, s) {
+				kept = append(kept, s)
+			}
+		}
+		qa.symbols = kept
+	})
+}
+
+// SetRateLimit reallocates for the market data rate limit window.
+func (qa *QuoteAdvisor) SetRateLimit(window RateLimitWindow) {
+	qa.update(func() { qa.window = window })
+}
+
+// Refresh reallocates for the current rate limit read from Stats, if set.
+func (qa *QuoteAdvisor) Refresh() {
+	var window RateLimitWindow
+	if qa.Stats != nil {
+		window = qa.Stats().RateLimits[CategoryMarketData]
+	}
+	qa.SetRateLimit(window)
+}
+
+// Run refreshes the allocation every Interval until stop is closed.
+func (qa *QuoteAdvisor) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(qa.Interval)
+	defer ticker.Stop()
+
+	for {
+		qa.Refresh()
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (qa *QuoteAdvisor) update(change func()) {
+	qa.mu.Lock()
+	change()
+	allocation := qa.allocate(time.Now())
+	changed := !reflect.DeepEqual(allocation, qa.allocation)
+	qa.allocation = allocation
+	qa.mu.Unlock()
+
+	if changed && qa.Changes != nil {
+		qa.Changes(allocation)
+	}
+}
+
+func (qa *QuoteAdvisor) allocate(now time.Time) QuoteAllocation {
+	var allocation QuoteAllocation
+	polled := qa.symbols
+	if qa.Streaming {
+		n := len(qa.symbols)
+		if qa.MaxStreamSymbols > 0 && n > qa.MaxStreamSymbols {
+			n = qa.MaxStreamSymbols
+		}
+		allocation.Stream = append([]string(nil), qa.symbols[:n]...)
+		polled = qa.symbols[n:]
+	}
+
+	allocation.Poll = qa.Planner.Plan(polled, qa.window, now)
+	allocation.Fresh = qa.Freshness <= 0 || allocation.Poll.Interval <= qa.Freshness
+	if n := len(allocation.Poll.Batches); n > 0 && allocation.Poll.Interval < qa.Freshness {
+		// Poll no faster than needed.
+		allocation.Poll.Spacing = qa.Freshness / time.Duration(n)
+		allocation.Poll.Interval = allocation.Poll.Spacing * time.Duration(n)
+	}
+	return allocation
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
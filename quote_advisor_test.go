@@ -0,0 +1,58 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteAdvisor(t *testing.T) {
+	var changes []QuoteAllocation
+	qa := NewQuoteAdvisor(true, 10*time.Second, func(a QuoteAllocation) { changes = append(changes, a) })
+	qa.MaxStreamSymbols = 2
+	qa.Planner = PollPlanner{BatchSize: 1, RequestsPerMinute: 60}
+
+	qa.Add("SPY", "QQQ")
+	allocation := qa.Allocation()
+	assert.Equal(t, []string{"SPY", "QQQ"}, allocation.Stream)
+	assert.Empty(t, allocation.Poll.Batches)
+	assert.True(t, allocation.Fresh)
+
+	t.Run("Symbols beyond the stream limit are polled", func(t *testing.T) {
+		qa.Add("IWM", "DIA")
+		allocation := qa.Allocation()
+		assert.Equal(t, []string{"SPY", "QQQ"}, allocation.Stream)
+		assert.Equal(t, [][]string{{"DIA"}, {"IWM"}}, allocation.Poll.Batches)
+		assert.True(t, allocation.Fresh)
+		assert.Equal(t, 5*time.Second, allocation.Poll.Spacing, "polled no faster than needed")
+	})
+
+	t.Run("Rate limit changes", func(t *testing.T) {
+		qa.SetRateLimit(RateLimitWindow{Allowed: 6})
+		allocation := qa.Allocation()
+		assert.False(t, allocation.Fresh)
+		assert.Equal(t, 10*time.Second, allocation.Poll.Spacing)
+	})
+
+	t.Run("Removed symbols", func(t *testing.T) {
+		qa.SetRateLimit(RateLimitWindow{})
+		qa.Remove("SPY")
+		allocation := qa.Allocation()
+		assert.Equal(t, []string{"QQQ", "IWM"}, allocation.Stream)
+		assert.Equal(t, [][]string{{"DIA"}}, allocation.Poll.Batches)
+	})
+
+	n := len(changes)
+	qa.Refresh()
+	assert.Len(t, changes, n, "unchanged allocations are not reported")
+}
+
+func TestQuoteAdvisor_withoutStreaming(t *testing.T) {
+	qa := NewQuoteAdvisor(false, 0, nil)
+	qa.Add("SPY", "QQQ")
+	allocation := qa.Allocation()
+	assert.Empty(t, allocation.Stream)
+	assert.Equal(t, [][]string{{"QQQ", "SPY"}}, allocation.Poll.Batches)
+	assert.True(t, allocation.Fresh)
+}
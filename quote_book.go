@@ -0,0 +1,122 @@
+package tradier
+
+import (
+	"sync"
+	"time"
+)
+
+// SessionStats are the statistics of a symbol for the current session,
+// initialized from summary events and updated by trades.
+type SessionStats struct {
+	Open             float64
+	High             float64
+	Low              float64
+	PreviousClose    float64
+	Last             float64
+	CumulativeVolume int64
+	UpdatedAt        time.Time
+}
+
+// QuoteBook maintains the latest quote and session statistics of each
+// streamed symbol. Its methods may be used as StreamDemuxer callbacks.
+type QuoteBook struct {
+	mu       sync.RWMutex
+	quotes   map[string]QuoteEvent
+	sessions map[string]*SessionStats
+}
+
+func NewQuoteBook() *QuoteBook {
+	return &QuoteBook{
+		quotes:   make(map[string]QuoteEvent),
+		sessions: make(map[string]*SessionStats),
+	}
+}
+
+// Demuxer returns a StreamDemuxer that updates the book.
+func (qb *QuoteBook) Demuxer(errors func(err error)) *StreamDemuxer {
+	return &StreamDemuxer{
+		Quotes:    qb.OnQuote,
+		Trades:    qb.OnTrade,
+		Summaries: qb.OnSummary,
+		Errors:    errors,
+	}
+}
+
+func (qb *QuoteBook) OnQuote(quote *QuoteEvent) {
+	qb.mu.Lock()
+	qb.quotes[quote.Symbol] = *quote
+	qb.mu.Unlock()
+}
+
+func (qb *QuoteBook) OnTrade(trade *TradeEvent) {
+	qb.mu.Lock()
+	defer qb.mu.Unlock()
+	ss := qb.session(trade.Symbol)
+	if ss.Open == 0 {
+		ss.Open = trade.Price
+	}
+	if trade.Price > ss.High {
+		ss.High = trade.Price
+	}
+	if ss.Low == 0 || trade.Price < ss.Low {
+		ss.Low = trade.Price
+	}
+	ss.Last = trade.Price
+	if trade.CumulativeVolume > ss.CumulativeVolume {
+		ss.CumulativeVolume = trade.CumulativeVolume
+	}
+	ss.UpdatedAt = trade.Date()
+}
+
+func (qb *QuoteBook) OnSummary(summary *SummaryEvent) {
+	qb.mu.Lock()
+	defer qb.mu.Unlock()
+	ss := qb.session(summary.Symbol)
+	ss.Open = summary.Open
+	ss.PreviousClose = summary.PreviousClose
+	// The summary may lag trades that have already been applied.
+	if summary.High > ss.High {
+		ss.High = summary.High
+	}
+	if ss.Low == 0 || (summary.Low > 0 && summary.Low < ss.Low) {
+		ss.Low = summary.Low
+	}
+	if summary.Close > 0 {
+		ss.Last = summary.Close
+	}
+}
+
+func (qb *QuoteBook) session(symbol string) *SessionStats {
+	ss := qb.sessions[symbol]
+	if ss == nil {
+		ss = &SessionStats{}
+		qb.sessions[symbol] = ss
+	}
+	return ss
+}
+
+// Quote returns the latest quote of the symbol.
+func (qb *QuoteBook) Quote(symbol string) (QuoteEvent, bool) {
+	qb.mu.RLock()
+	defer qb.mu.RUnlock()
+	q, ok := qb.quotes[symbol]
+	return q, ok
+}
+
+// SessionStats returns the session statistics of the symbol.
+func (qb *QuoteBook) SessionStats(symbol string) (SessionStats, bool) {
+	qb.mu.RLock()
+	defer qb.mu.RUnlock()
+	ss, ok := qb.sessions[symbol]
+	if !ok {
+		return SessionStats{}, false
+	}
+	return *ss, true
+}
+
+// Reset clears the session statistics, e.g. at the start of a new session.
+func (qb *QuoteBook) Reset() {
+	qb.mu.Lock()
+	qb.sessions = make(map[string]*SessionStats)
+	qb.mu.Unlock()
+}
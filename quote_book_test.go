@@ -0,0 +1,40 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteBook(t *testing.T) {
+	qb := NewQuoteBook()
+	sd := qb.Demuxer(func(err error) { t.Error(err) })
+
+	for _, msg := range []string{
+		`{"type":"summary","symbol":"SPY","open":"280.50","high":"282.00","low":"279.80","prevClose":"280.00"}`,
+		`{"type":"trade","symbol":"SPY","exch":"Q","price":"282.25","size":"100","cvol":"1200000","date":"1557757188750","last":"282.25"}`,
+		`{"type":"trade","symbol":"SPY","exch":"Q","price":"279.50","size":"100","cvol":"1200100","date":"1557757189750","last":"279.50"}`,
+		`{"type":"quote","symbol":"SPY","bid":279.45,"bidsz":5,"bidexch":"Q","biddate":"1557757189000","ask":279.55,"asksz":3,"askexch":"P","askdate":"1557757189500"}`,
+	} {
+		event := &StreamEvent{}
+		assert.NoError(t, UnmarshalStreamEvent([]byte(msg), event))
+		sd.Handle(event)
+	}
+
+	stats, ok := qb.SessionStats("SPY")
+	assert.True(t, ok)
+	assert.Equal(t, 280.50, stats.Open)
+	assert.Equal(t, 282.25, stats.High)
+	assert.Equal(t, 279.50, stats.Low)
+	assert.Equal(t, 280.00, stats.PreviousClose)
+	assert.Equal(t, 279.50, stats.Last)
+	assert.Equal(t, int64(1200100), stats.CumulativeVolume)
+
+	quote, ok := qb.Quote("SPY")
+	assert.True(t, ok)
+	assert.Equal(t, 279.55, quote.Ask)
+
+	qb.Reset()
+	_, ok = qb.SessionStats("SPY")
+	assert.False(t, ok)
+}
@@ -0,0 +1,150 @@
+package tradier
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	defaultPollBatchSize           = 100
+	defaultMarketDataRatePerMinute = 120
+)
+
+// QuoteSource fetches quotes. It is implemented by Client, and is part of
+// MarketDataSource.
+type QuoteSource interface {
+	GetQuotes(symbols []string) ([]*Quote, error)
+}
+
+// PollPlan is a layout of symbols into quote requests and the cadence
+// at which to make them.
+type PollPlan struct {
+	Batches [][]string
+	// Delay between consecutive requests.
+	Spacing time.Duration
+	// Time to poll every batch once, i.e. the staleness of each quote.
+	Interval time.Duration
+}
+
+// PollPlanner computes the fastest polling cadence that stays within the
+// market data rate limit.
+type PollPlanner struct {
+	// Maximum symbols per request. Defaults to 100.
+	BatchSize int
+	// Requests allowed per minute, if no rate limit has been reported. Defaults to 120.
+	RequestsPerMinute int
+	// Fraction of the rate limit left unused for other requests, e.g. 0.1.
+	Headroom float64
+}
+
+// Plan lays out the symbols in evenly sized batches and spaces requests so
+// that a full minute of polling uses the allowed rate less the headroom. If
+// the current window has fewer requests available than would be made before
+// it renews, requests are spread over the rest of the window instead.
+func (pp PollPlanner) Plan(symbols []string, window RateLimitWindow, now time.Time) PollPlan {
+	if len(symbols) == 0 {
+		return PollPlan{}
+	}
+
+	k := pp.BatchSize
+	if k <= 0 {
+		k = defaultPollBatchSize
+	}
+	n := (len(symbols) + k - 1) / k
+	size := (len(symbols) + n - 1) / n
+
+	sorted := make([]string, len(symbols))
+	copy(sorted, symbols)
+	sort.Strings(sorted)
+	plan := PollPlan{}
+	for i := 0; i < len(sorted); i += size {
+		end := int(math.Min(float64(i+size), float64(len(sorted))))
+		plan.Batches = append(plan.Batches, sorted[i:end])
+	}
+
+	rpm := pp.RequestsPerMinute
+	if window.Allowed > 0 {
+		rpm = window.Allowed
+	}
+	if rpm <= 0 {
+		rpm = defaultMarketDataRatePerMinute
+	}
+	usable := math.Max(1, float64(rpm)*(1-pp.Headroom))
+	plan.Spacing = time.Duration(float64(time.Minute) / usable)
+
+	if remaining := window.Expiry.Sub(now); remaining > 0 && window.Allowed > 0 {
+		budget := float64(window.Available) * (1 - pp.Headroom)
+		if budget < 1 {
+			plan.Spacing = remaining
+		} else if spacing := time.Duration(float64(remaining) / budget); spacing > plan.Spacing {
+			plan.Spacing = spacing
+		}
+	}
+
+	plan.Interval = plan.Spacing * time.Duration(len(plan.Batches))
+	return plan
+}
+
+// QuotePoller repeatedly polls quotes for a set of symbols as fast as the
+// rate limit allows.
+type QuotePoller struct {
+	Quotes  QuoteSource
+	Symbols []string
+	Planner PollPlanner
+	// If set, used to read the current market data rate limit before each
+	// cycle, e.g. Client.Stats.
+	Stats func() Stats
+	// Called with the quotes of each batch, and with errors from polling.
+	Handler func(quotes []*Quote)
+	Errors  func(err error)
+}
+
+func NewQuotePoller(quotes QuoteSource, symbols []string, handler func(quotes []*Quote)) *QuotePoller {
+	qp := &QuotePoller{
+		Quotes:  quotes,
+		Symbols: symbols,
+		Planner: PollPlanner{Headroom: 0.1},
+		Handler: handler,
+	}
+	if client, ok := quotes.(*Client); ok {
+		qp.Stats = client.Stats
+	}
+	return qp
+}
+
+// Plan returns the plan for the next polling cycle.
+func (qp *QuotePoller) Plan() PollPlan {
+	var window RateLimitWindow
+	if qp.Stats != nil {
+		window = qp.Stats().RateLimits[CategoryMarketData]
+	}
+	return qp.Planner.Plan(qp.Symbols, window, time.Now())
+}
+
+// Run polls until stop is closed, replanning at the start of each cycle.
+func (qp *QuotePoller) Run(stop <-chan struct{}) {
+	for {
+		plan := qp.Plan()
+		if len(plan.Batches) == 0 {
+			return
+		}
+
+		for _, batch := range plan.Batches {
+			quotes, err := qp.Quotes.GetQuotes(batch)
+			if err != nil {
+				if qp.Errors != nil {
+					qp.Errors(err)
+				}
+			} else if qp.Handler != nil {
+				qp.Handler(quotes)
+			}
+
+			select {
+			case <-time.After(plan.Spacing):
+			case <-stop:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package tradier
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQuoteSource struct {
+	mu       sync.Mutex
+	requests [][]string
+}
+
+func (fq *fakeQuoteSource) GetQuotes(symbols []string) ([]*Quote, error) {
+	fq.mu.Lock()
+	defer fq.mu.Unlock()
+	fq.requests = append(fq.requests, symbols)
+	quotes := make([]*Quote, len(symbols))
+	for i, s := range symbols {
+		quotes[i] = &Quote{Symbol: s}
+	}
+	return quotes, nil
+}
+
+func TestPollPlanner_Plan(t *testing.T) {
+	symbols := make([]string, 250)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("S%03d", i)
+	}
+	now := time.Date(2021, time.January, 4, 14, 30, 0, 0, time.UTC)
+
+	t.Run("Balanced batches at the default rate", func(t *testing.T) {
+		plan := PollPlanner{}.Plan(symbols, RateLimitWindow{}, now)
+		if assert.Len(t, plan.Batches, 3) {
+			assert.Len(t, plan.Batches[0], 84)
+			assert.Len(t, plan.Batches[2], 82)
+		}
+		assert.Equal(t, 500*time.Millisecond, plan.Spacing)
+		assert.Equal(t, 1500*time.Millisecond, plan.Interval)
+	})
+
+	t.Run("Reported limit with headroom", func(t *testing.T) {
+		window := RateLimitWindow{Allowed: 60, Available: 60, Expiry: now.Add(time.Minute)}
+		plan := PollPlanner{BatchSize: 250, Headroom: 0.5}.Plan(symbols, window, now)
+		assert.Len(t, plan.Batches, 1)
+		assert.Equal(t, 2*time.Second, plan.Spacing)
+	})
+
+	t.Run("Depleted window", func(t *testing.T) {
+		window := RateLimitWindow{Allowed: 120, Available: 5, Expiry: now.Add(30 * time.Second)}
+		plan := PollPlanner{}.Plan(symbols, window, now)
+		assert.Equal(t, 6*time.Second, plan.Spacing)
+
+		window.Available = 0
+		plan = PollPlanner{}.Plan(symbols, window, now)
+		assert.Equal(t, 30*time.Second, plan.Spacing)
+	})
+}
+
+func TestQuotePoller_Run(t *testing.T) {
+	source := &fakeQuoteSource{}
+	received := make(chan []*Quote, 10)
+	qp := NewQuotePoller(source, []string{"SPY", "QQQ", "IWM"}, func(quotes []*Quote) {
+		select {
+		case received <- quotes:
+		default:
+		}
+	})
+	qp.Planner = PollPlanner{BatchSize: 2, RequestsPerMinute: 60000}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		qp.Run(stop)
+		close(done)
+	}()
+	assert.Len(t, <-received, 2)
+	assert.Len(t, <-received, 1)
+	assert.Len(t, <-received, 2)
+	close(stop)
+	<-done
+
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	assert.Equal(t, []string{"IWM", "QQQ"}, source.requests[0])
+}
@@ -1,11 +1,215 @@
 package tradier
 
 import (
+	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+// EndpointCategory groups the endpoints that share a Tradier rate limit.
+type EndpointCategory string
+
+const (
+	CategoryStandard   EndpointCategory = "standard"
+	CategoryMarketData EndpointCategory = "market_data"
+	CategoryTrading    EndpointCategory = "trading"
+	CategoryStreaming  EndpointCategory = "streaming"
 )
 
+// Determine the rate limit category of a request from its method and path.
+func endpointCategory(method, path string) EndpointCategory {
+	switch {
+	case strings.Contains(path, "/events/session"):
+		return CategoryStreaming
+	case strings.Contains(path, "/markets/"):
+		return CategoryMarketData
+	case strings.Contains(path, "/orders") && method != http.MethodGet:
+		return CategoryTrading
+	default:
+		return CategoryStandard
+	}
+}
+
+// Requests allowed per minute in each category, as documented by Tradier.
+var (
+	knownRateLimits = map[EndpointCategory]int{
+		CategoryStandard:   120,
+		CategoryMarketData: 120,
+		CategoryTrading:    60,
+	}
+	knownSandboxRateLimits = map[EndpointCategory]int{
+		CategoryStandard:   60,
+		CategoryMarketData: 60,
+		CategoryTrading:    60,
+	}
+)
+
+// KnownRateLimit returns the documented number of requests allowed per minute
+// in the category, in the client's environment, or 0 if it is not known.
+// The limits reported by Tradier with each response are in Stats.
+func (tc *Client) KnownRateLimit(category EndpointCategory) int {
+	if tc.sandbox {
+		return knownSandboxRateLimits[category]
+	}
+	return knownRateLimits[category]
+}
+
+// RateLimit returns the rate limit state of the category, as reported by the
+// most recent response in it. It returns false if no response has yet.
+// Stats returns the state of all categories.
+func (tc *Client) RateLimit(category EndpointCategory) (RateLimitWindow, bool) {
+	tc.rateLimits.mu.Lock()
+	defer tc.rateLimits.mu.Unlock()
+	w, ok := tc.rateLimits.windows[category]
+	return w, ok
+}
+
+// Length of the rate limit windows, which renew every minute.
+const rateLimitInterval = time.Minute
+
+// RateLimitWindow is the most recently reported rate limit state of a category.
+type RateLimitWindow struct {
+	Allowed   int
+	Used      int
+	Available int
+	Expiry    time.Time
+	UpdatedAt time.Time
+}
+
+type rateLimitTracker struct {
+	mu      sync.Mutex
+	windows map[EndpointCategory]RateLimitWindow
+	// The reported windows, less the requests sent since.
+	budgets map[EndpointCategory]RateLimitWindow
+}
+
+func newRateLimitTracker() *rateLimitTracker {
+	return &rateLimitTracker{
+		windows: make(map[EndpointCategory]RateLimitWindow),
+		budgets: make(map[EndpointCategory]RateLimitWindow),
+	}
+}
+
+// Record the rate limit headers of a response, if present.
+func (rt *rateLimitTracker) update(category EndpointCategory, header http.Header) {
+	available := header.Get(rateLimitAvailable)
+	if available == "" {
+		return
+	}
+
+	w := RateLimitWindow{UpdatedAt: time.Now()}
+	w.Available, _ = strconv.Atoi(available)
+	w.Allowed, _ = strconv.Atoi(header.Get(rateLimitAllowed))
+	w.Used, _ = strconv.Atoi(header.Get(rateLimitUsed))
+	if expiry, err := ParseTimeMs(header.Get(rateLimitExpiry)); err == nil {
+		w.Expiry = expiry
+	}
+
+	rt.mu.Lock()
+	rt.windows[category] = w
+	rt.budgets[category] = w
+	rt.mu.Unlock()
+}
+
+// Take a request from the budget of the category, and return how long to wait
+// before sending it: until the window renews if no more than reserve requests
+// remain available in it. Requests waiting for the window are counted against
+// its renewed budget, so that they do not all exhaust it at once.
+func (rt *rateLimitTracker) take(category EndpointCategory, reserve int, now time.Time) time.Duration {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	b, ok := rt.budgets[category]
+	if !ok || b.Expiry.IsZero() || b.Allowed <= 0 || reserve < 0 {
+		return 0
+	}
+	// Until the next response reports it, assume each window renews the
+	// full allowance.
+	for !now.Before(b.Expiry) {
+		b.renew()
+	}
+	if b.Available <= reserve {
+		b.renew()
+	}
+	b.Used++
+	b.Available--
+	rt.budgets[category] = b
+
+	if start := b.Expiry.Add(-rateLimitInterval); start.After(now) {
+		return start.Sub(now)
+	}
+	return 0
+}
+
+func (w *RateLimitWindow) renew() {
+	w.Expiry = w.Expiry.Add(rateLimitInterval)
+	w.Used = 0
+	w.Available = w.Allowed
+}
+
+func (rt *rateLimitTracker) snapshot() map[EndpointCategory]RateLimitWindow {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	result := make(map[EndpointCategory]RateLimitWindow, len(rt.windows))
+	for category, w := range rt.windows {
+		result[category] = w
+	}
+	return result
+}
+
+// Retry backoffs are kept for each category, so that retries of requests in
+// one category do not delay those in another. Exponential backoffs are copied
+// for each category, and other kinds of backoff are shared.
+type categoryBackoffs struct {
+	mu       sync.Mutex
+	shared   backoff.BackOff
+	backoffs map[EndpointCategory]backoff.BackOff
+}
+
+func newCategoryBackoffs(shared backoff.BackOff) *categoryBackoffs {
+	return &categoryBackoffs{
+		shared:   shared,
+		backoffs: make(map[EndpointCategory]backoff.BackOff),
+	}
+}
+
+func (cb *categoryBackoffs) get(category EndpointCategory) backoff.BackOff {
+	if b, ok := cb.backoffs[category]; ok {
+		return b
+	}
+	b := cb.shared
+	if eb, ok := b.(*backoff.ExponentialBackOff); ok {
+		copied := *eb
+		copied.Reset()
+		b = &copied
+	}
+	cb.backoffs[category] = b
+	return b
+}
+
+// Returns the time to wait before retrying a request of the category.
+func (cb *categoryBackoffs) next(category EndpointCategory) time.Duration {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if b := cb.get(category); b != nil {
+		return b.NextBackOff()
+	}
+	return backoff.Stop
+}
+
+// Reset the backoff of the category after a successful request.
+func (cb *categoryBackoffs) reset(category EndpointCategory) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if b := cb.get(category); b != nil {
+		b.Reset()
+	}
+}
+
 // Extract quota violation expiration from body message.
 func parseQuotaViolationExpiration(body string) time.Time {
 	if !strings.HasPrefix(body, "Quota Violation") {
@@ -2,7 +2,11 @@ package tradier
 
 import (
 	"fmt"
+	"github.com/cenkalti/backoff"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -25,3 +29,140 @@ func Test_parseQuotaViolationExpiration(t *testing.T) {
 		assert.Equal(t, output.Unix(), expiration.Unix())
 	})
 }
+
+func Test_endpointCategory(t *testing.T) {
+	assert.Equal(t, CategoryMarketData, endpointCategory("GET", "/v1/markets/quotes"))
+	assert.Equal(t, CategoryMarketData, endpointCategory("GET", "/beta/markets/fundamentals/company"))
+	assert.Equal(t, CategoryStreaming, endpointCategory("POST", "/v1/markets/events/session"))
+	assert.Equal(t, CategoryTrading, endpointCategory("POST", "/v1/accounts/VA000/orders"))
+	assert.Equal(t, CategoryStandard, endpointCategory("GET", "/v1/accounts/VA000/orders"))
+	assert.Equal(t, CategoryStandard, endpointCategory("GET", "/v1/accounts/VA000/balances"))
+}
+
+func Test_rateLimitTracker(t *testing.T) {
+	t.Run("Missing headers", func(t *testing.T) {
+		rt := newRateLimitTracker()
+		rt.update(CategoryMarketData, http.Header{})
+		assert.Empty(t, rt.snapshot())
+	})
+
+	t.Run("Records headers", func(t *testing.T) {
+		rt := newRateLimitTracker()
+		header := http.Header{}
+		header.Set(rateLimitAllowed, "120")
+		header.Set(rateLimitUsed, "20")
+		header.Set(rateLimitAvailable, "100")
+		header.Set(rateLimitExpiry, "1609459200000")
+		rt.update(CategoryMarketData, header)
+
+		w := rt.snapshot()[CategoryMarketData]
+		assert.Equal(t, 120, w.Allowed)
+		assert.Equal(t, 20, w.Used)
+		assert.Equal(t, 100, w.Available)
+		assert.Equal(t, int64(1609459200), w.Expiry.Unix())
+	})
+}
+
+func Test_rateLimitTracker_take(t *testing.T) {
+	now := time.Date(2021, time.January, 1, 0, 0, 30, 0, time.UTC)
+	window := func(available int) *rateLimitTracker {
+		rt := newRateLimitTracker()
+		rt.budgets[CategoryMarketData] = RateLimitWindow{Allowed: 2, Used: 2 - available, Available: available, Expiry: now.Add(30 * time.Second)}
+		return rt
+	}
+
+	t.Run("Unknown window", func(t *testing.T) {
+		assert.Zero(t, newRateLimitTracker().take(CategoryMarketData, 0, now))
+	})
+
+	t.Run("Budget available", func(t *testing.T) {
+		rt := window(2)
+		assert.Zero(t, rt.take(CategoryMarketData, 0, now))
+		assert.Zero(t, rt.take(CategoryMarketData, 0, now))
+		assert.Equal(t, 30*time.Second, rt.take(CategoryMarketData, 0, now), "budget exhausted")
+	})
+
+	t.Run("Queued requests", func(t *testing.T) {
+		rt := window(0)
+		assert.Equal(t, 30*time.Second, rt.take(CategoryMarketData, 0, now))
+		assert.Equal(t, 30*time.Second, rt.take(CategoryMarketData, 0, now))
+		assert.Equal(t, 90*time.Second, rt.take(CategoryMarketData, 0, now), "renewed budget taken by the queue")
+	})
+
+	t.Run("Reserve", func(t *testing.T) {
+		assert.Equal(t, 30*time.Second, window(1).take(CategoryMarketData, 1, now))
+		assert.Zero(t, window(0).take(CategoryMarketData, -1, now), "never delayed")
+	})
+
+	t.Run("Expired window", func(t *testing.T) {
+		assert.Zero(t, window(0).take(CategoryMarketData, 0, now.Add(time.Minute)))
+	})
+}
+
+func TestClient_rateLimitDelay(t *testing.T) {
+	var requests []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		w.Header().Set(rateLimitAllowed, "120")
+		w.Header().Set(rateLimitUsed, "120")
+		w.Header().Set(rateLimitAvailable, "0")
+		w.Header().Set(rateLimitExpiry, strconv.FormatInt(time.Now().Add(200*time.Millisecond).UnixNano()/1e6, 10))
+		w.Write([]byte(`{"clock": {"state": "open"}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+	_, err := client.GetMarketState()
+	assert.NoError(t, err)
+	_, err = client.GetMarketState()
+	assert.NoError(t, err)
+	if assert.Len(t, requests, 2) {
+		assert.True(t, requests[1].Sub(requests[0]) >= 100*time.Millisecond, "second request waits for the window")
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(rateLimitAllowed, "120")
+		w.Header().Set(rateLimitUsed, "7")
+		w.Header().Set(rateLimitAvailable, "113")
+		w.Header().Set(rateLimitExpiry, "1609459200000")
+		w.Write([]byte(`{"clock": {"state": "open"}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+	_, ok := client.RateLimit(CategoryMarketData)
+	assert.False(t, ok)
+
+	_, err := client.GetMarketState()
+	assert.NoError(t, err)
+	w, ok := client.RateLimit(CategoryMarketData)
+	if assert.True(t, ok) {
+		assert.Equal(t, 113, w.Available)
+		assert.Equal(t, 7, w.Used)
+		assert.Equal(t, int64(1609459200), w.Expiry.Unix())
+	}
+	_, ok = client.RateLimit(CategoryTrading)
+	assert.False(t, ok)
+}
+
+func Test_categoryBackoffs(t *testing.T) {
+	eb := backoff.NewExponentialBackOff()
+	eb.InitialInterval = time.Second
+	eb.RandomizationFactor = 0
+	eb.Multiplier = 2
+	cb := newCategoryBackoffs(eb)
+
+	assert.Equal(t, time.Second, cb.next(CategoryMarketData))
+	assert.Equal(t, 2*time.Second, cb.next(CategoryMarketData))
+	assert.Equal(t, time.Second, cb.next(CategoryTrading), "backoff is kept per category")
+	cb.reset(CategoryMarketData)
+	assert.Equal(t, time.Second, cb.next(CategoryMarketData))
+
+	assert.Equal(t, backoff.Stop, newCategoryBackoffs(nil).next(CategoryStandard))
+}
@@ -0,0 +1,106 @@
+package tradier
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultMaxResponseSize    = 64 << 20
+	defaultStreamingThreshold = 1 << 20
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds ClientParams.MaxResponseSize.
+type ErrResponseTooLarge struct {
+	URL   string
+	Limit int64
+}
+
+func (e ErrResponseTooLarge) Error() string {
+	return fmt.Sprintf("response from %s exceeds the %d byte limit", e.URL, e.Limit)
+}
+
+// Endpoints whose responses may be large enough that they
+// should be decoded as they are read rather than buffered.
+var largeResponsePaths = []string{
+	"/v1/markets/options/chains",
+	"/v1/markets/history",
+	"/v1/markets/timesales",
+	"/history",
+	"/gainloss",
+}
+
+func isLargeResponsePath(path string) bool {
+	for _, p := range largeResponsePaths {
+		if strings.HasSuffix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedBody fails reads with ErrResponseTooLarge once more than limit bytes have been read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	err       ErrResponseTooLarge
+}
+
+func (lb *limitedBody) Read(p []byte) (int, error) {
+	if lb.remaining < 0 {
+		return 0, lb.err
+	}
+	if int64(len(p)) > lb.remaining+1 {
+		p = p[:lb.remaining+1]
+	}
+	n, err := lb.ReadCloser.Read(p)
+	lb.remaining -= int64(n)
+	if lb.remaining < 0 {
+		return n + int(lb.remaining), lb.err
+	}
+	return n, err
+}
+
+// limitResponse applies the maximum response size to resp. Responses that
+// declare a larger Content-Length are refused without reading the body.
+// Streams are not limited.
+func (tc *Client) limitResponse(resp *http.Response) error {
+	if tc.maxResponseSize <= 0 || isStreamPath(resp.Request.URL.Path) {
+		return nil
+	}
+
+	tooLarge := ErrResponseTooLarge{URL: resp.Request.URL.String(), Limit: tc.maxResponseSize}
+	if resp.ContentLength > tc.maxResponseSize {
+		resp.Body.Close()
+		return tooLarge
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: tc.maxResponseSize, err: tooLarge}
+	return nil
+}
+
+// errResponseStreamed is returned by readBody when the response was passed
+// to the overflow function rather than buffered.
+var errResponseStreamed = errors.New("response streamed rather than buffered")
+
+// readBody returns the response body, unless the response is from a large endpoint
+// and exceeds the streaming threshold, in which case the whole body is passed
+// to overflow instead and errResponseStreamed is returned.
+func (tc *Client) readBody(resp *http.Response, overflow func(r io.Reader)) ([]byte, error) {
+	if overflow == nil || !isLargeResponsePath(resp.Request.URL.Path) {
+		return io.ReadAll(resp.Body)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, resp.Body, tc.streamingThreshold+1); err == io.EOF {
+		return buf.Bytes(), nil
+	} else if err != nil {
+		return nil, err
+	}
+	overflow(io.MultiReader(&buf, resp.Body))
+	return nil, errResponseStreamed
+}
@@ -0,0 +1,75 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_MaxResponseSize(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/v1/markets/quotes":
+			if r.URL.Query().Get("symbols") == "HUGE" {
+				w.Write([]byte(`{"quotes": {"quote": {"symbol": "HUGE", "description": "` + strings.Repeat("x", 4096) + `"}}}`))
+				return
+			}
+			// Flushing first omits the Content-Length.
+			w.(http.Flusher).Flush()
+			w.Write([]byte(`{"quotes": {"quote": {"symbol": "` + r.URL.Query().Get("symbols") + `", "description": "` + strings.Repeat("x", 4096) + `"}}}`))
+		case "/v1/markets/options/chains":
+			w.Write([]byte(`{"options": {"option": [{"symbol": "SPY210219P00360000", "strike": 360}, {"symbol": "SPY210219P00365000", "strike": 365}]}}`))
+		case "/v1/markets/clock":
+			w.Write([]byte(`{"clock": {"state": "open"}}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.RetryLimit = 2
+	params.MaxResponseSize = 1024
+	params.StreamingThreshold = 16
+	params.MemoizeWindow = time.Minute
+	client := NewClient(params)
+
+	t.Run("Too large", func(t *testing.T) {
+		for _, symbol := range []string{"HUGE", "CHUNKED"} {
+			atomic.StoreInt32(&requests, 0)
+			_, err := client.GetQuotes([]string{symbol})
+			if assert.IsType(t, ErrResponseTooLarge{}, err, symbol) {
+				assert.Equal(t, int64(1024), err.(ErrResponseTooLarge).Limit)
+			}
+			assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "not retried")
+		}
+	})
+
+	t.Run("Large endpoints are streamed rather than memoized", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+		for i := 0; i < 2; i++ {
+			chain, err := client.GetOptionChain("SPY", time.Date(2021, time.February, 19, 0, 0, 0, 0, time.UTC))
+			assert.NoError(t, err)
+			if assert.Len(t, chain, 2) {
+				assert.Equal(t, 365.0, chain[1].Strike)
+			}
+		}
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("Other endpoints are memoized", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+		for i := 0; i < 2; i++ {
+			state, err := client.GetMarketState()
+			assert.NoError(t, err)
+			assert.Equal(t, MarketOpen, state.State)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+}
@@ -0,0 +1,136 @@
+package tradier
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+// RetryAttempt describes a failed attempt of a request, for a RetryPolicy.
+type RetryAttempt struct {
+	Method   string
+	Category EndpointCategory
+	// False for requests that change state if repeated, i.e. orders that are
+	// placed rather than previewed.
+	Idempotent bool
+	// Number of attempts made, starting at 1.
+	Attempt int
+	// Status code and header of the response, or zero if the attempt failed
+	// without one, in which case Err is the error from the transport.
+	StatusCode int
+	Header     http.Header
+	Err        error
+}
+
+// Sent returns false if the attempt failed before the request was sent, so
+// it cannot have been accepted.
+func (ra RetryAttempt) Sent() bool {
+	if ra.StatusCode != 0 {
+		return true
+	}
+	var opErr *net.OpError
+	return !(errors.As(ra.Err, &opErr) && opErr.Op == "dial")
+}
+
+// RetryPolicy decides whether a failed request is retried, and how long to
+// wait before the next attempt. Requests are attempted at most
+// ClientParams.RetryLimit more times.
+type RetryPolicy interface {
+	// Retry returns the time to wait before retrying the request, or false
+	// if it should not be retried.
+	Retry(attempt RetryAttempt) (time.Duration, bool)
+	// Reset is called after each successful request of the category.
+	Reset(category EndpointCategory)
+}
+
+// DefaultRetryPolicy retries requests that fail with a transport error, 429
+// or 5xx, waiting for the backoff of the request's category. Waits are at
+// least as long as the Retry-After header or quota violation of the response.
+//
+// Requests that are not idempotent are only retried if they cannot have been
+// accepted: if they failed before being sent, or were refused with 429 or a
+// quota violation. An order whose request timed out may have been placed, so
+// it is not placed again.
+type DefaultRetryPolicy struct {
+	// Each wait is lengthened by a random fraction of up to Jitter, so that
+	// clients sharing a token do not retry in lockstep.
+	Jitter float64
+
+	backoffs *categoryBackoffs
+	mu       sync.Mutex
+	rand     *rand.Rand
+}
+
+// NewRetryPolicy returns a DefaultRetryPolicy with a copy of b for each
+// endpoint category. If b is nil then requests are not retried.
+func NewRetryPolicy(b backoff.BackOff) *DefaultRetryPolicy {
+	return &DefaultRetryPolicy{
+		Jitter:   0.2,
+		backoffs: newCategoryBackoffs(b),
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (rp *DefaultRetryPolicy) Retry(attempt RetryAttempt) (time.Duration, bool) {
+	rateLimited := attempt.StatusCode == http.StatusTooManyRequests
+	var quotaExpiry time.Time
+	var te TradierError
+	if errors.As(attempt.Err, &te) {
+		quotaExpiry = parseQuotaViolationExpiration(te.Fault.FaultString)
+		rateLimited = rateLimited || !quotaExpiry.IsZero()
+	}
+
+	switch {
+	case !attempt.Idempotent && attempt.Sent() && !rateLimited:
+		return 0, false
+	case attempt.StatusCode != 0 && !rateLimited && attempt.StatusCode < 500:
+		return 0, false
+	}
+
+	wait := rp.backoffs.next(attempt.Category)
+	if wait == backoff.Stop {
+		return 0, false
+	}
+	if retryAfter, ok := parseRetryAfter(attempt.Header, time.Now()); ok && retryAfter > wait {
+		wait = retryAfter
+	}
+	if untilExpiry := time.Until(quotaExpiry) + time.Second; !quotaExpiry.IsZero() && untilExpiry > wait {
+		wait = untilExpiry
+	}
+	return rp.jitter(wait), true
+}
+
+func (rp *DefaultRetryPolicy) Reset(category EndpointCategory) {
+	rp.backoffs.reset(category)
+}
+
+func (rp *DefaultRetryPolicy) jitter(wait time.Duration) time.Duration {
+	if rp.Jitter <= 0 {
+		return wait
+	}
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	return wait + time.Duration(rp.rand.Float64()*rp.Jitter*float64(wait))
+}
+
+// Returns the wait requested by the Retry-After header, in seconds or as
+// an HTTP date.
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.Sub(now), true
+	}
+	return 0, false
+}
@@ -0,0 +1,113 @@
+package tradier
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRetryPolicy_Retry(t *testing.T) {
+	rp := NewRetryPolicy(backoff.NewConstantBackOff(time.Millisecond))
+	rp.Jitter = 0
+	timeout := errors.New("net/http: timeout awaiting response headers")
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	quota := TradierError{HttpStatusCode: http.StatusBadRequest}
+	quota.Fault.FaultString = "Quota Violation 0"
+
+	for _, tc := range []struct {
+		name    string
+		attempt RetryAttempt
+		retry   bool
+	}{
+		{"Timeout", RetryAttempt{Idempotent: true, Err: timeout}, true},
+		{"Unavailable", RetryAttempt{Idempotent: true, StatusCode: http.StatusServiceUnavailable}, true},
+		{"Client error", RetryAttempt{Idempotent: true, StatusCode: http.StatusNotFound}, false},
+		{"Order timeout", RetryAttempt{Method: http.MethodPost, Err: timeout}, false},
+		{"Order dial error", RetryAttempt{Method: http.MethodPost, Err: dialErr}, true},
+		{"Order server error", RetryAttempt{Method: http.MethodPost, StatusCode: http.StatusBadGateway}, false},
+		{"Order rate limited", RetryAttempt{Method: http.MethodPost, StatusCode: http.StatusTooManyRequests}, true},
+		{"Order quota violation", RetryAttempt{Method: http.MethodPost, StatusCode: http.StatusBadRequest, Err: quota}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, retry := rp.Retry(tc.attempt)
+			assert.Equal(t, tc.retry, retry)
+		})
+	}
+
+	t.Run("Retry-After", func(t *testing.T) {
+		header := http.Header{"Retry-After": []string{"2"}}
+		wait, retry := rp.Retry(RetryAttempt{Idempotent: true, StatusCode: http.StatusTooManyRequests, Header: header})
+		assert.True(t, retry)
+		assert.Equal(t, 2*time.Second, wait)
+
+		header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		wait, _ = rp.Retry(RetryAttempt{Idempotent: true, StatusCode: http.StatusServiceUnavailable, Header: header})
+		assert.InDelta(t, float64(time.Hour), float64(wait), float64(2*time.Second))
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		rp := NewRetryPolicy(backoff.NewConstantBackOff(time.Second))
+		for i := 0; i < 10; i++ {
+			wait, _ := rp.Retry(RetryAttempt{Idempotent: true, Err: timeout})
+			assert.True(t, wait >= time.Second && wait <= 1200*time.Millisecond, wait)
+		}
+	})
+
+	t.Run("Stopped backoff", func(t *testing.T) {
+		_, retry := NewRetryPolicy(&backoff.StopBackOff{}).Retry(RetryAttempt{Idempotent: true, Err: timeout})
+		assert.False(t, retry)
+	})
+}
+
+func TestClient_PlaceOrder_retries(t *testing.T) {
+	var requests int32
+	var status int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(int(atomic.LoadInt32(&status)))
+			return
+		}
+		w.Write([]byte(`{"order": {"id": 7, "status": "ok"}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.Account = "VA000001"
+	params.EnableTrading = true
+	params.Environment = EnvironmentUnspecified
+	params.Backoff = backoff.NewConstantBackOff(time.Millisecond)
+	client := NewClient(params)
+	order := Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1, Type: MarketOrder, Duration: Day}
+
+	for _, tc := range []struct {
+		status   int
+		requests int32
+	}{
+		{http.StatusTooManyRequests, 2},
+		{http.StatusGatewayTimeout, 1},
+	} {
+		t.Run(strconv.Itoa(tc.status), func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+			atomic.StoreInt32(&status, int32(tc.status))
+			result, err := client.PlaceOrder(order)
+			if tc.requests == 2 {
+				if assert.NoError(t, err) {
+					assert.Equal(t, 7, result.Id)
+				}
+			} else {
+				assert.Error(t, err, "the order may have been placed")
+			}
+			assert.Equal(t, tc.requests, atomic.LoadInt32(&requests))
+		})
+	}
+}
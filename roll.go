@@ -0,0 +1,183 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRollDeclined is returned by RollPosition if the confirmation hook rejects the roll.
+var ErrRollDeclined = errors.New("roll declined")
+
+// RollConfirm is called with the roll order and its preview before submission.
+// Returning false aborts the roll.
+type RollConfirm func(order Order, preview *OrderPreview) bool
+
+// RollParams configure RollPosition.
+type RollParams struct {
+	// Selects the new expiration among those after the current expiration.
+	Expiry ExpirySelector
+	// Selects the new contract. The contract being closed is Selected[0].
+	Strike StrikeSelector
+	// If set, the roll is placed as a sequenced OTO (close, then open)
+	// instead of a single multileg order.
+	Sequenced bool
+	Confirm   RollConfirm
+}
+
+// RollResult describes a submitted roll.
+type RollResult struct {
+	Order   Order
+	Preview *OrderPreview
+	OrderId int
+	// Result of placing the roll, or nil if it was not placed.
+	Placed *OrderResult
+}
+
+// SameStrike selects the contract with the strike nearest that of a preceding leg.
+func SameStrike(leg int) StrikeSelector {
+	return func(sc StrikeContext) (*Quote, error) {
+		if leg < 0 || leg >= len(sc.Selected) {
+			return nil, fmt.Errorf("leg %d has not been selected", leg)
+		}
+
+		var best *Quote
+		for _, q := range sc.Chain {
+			if best == nil || math.Abs(q.Strike-sc.Selected[leg].Strike) < math.Abs(best.Strike-sc.Selected[leg].Strike) {
+				best = q
+			}
+		}
+		if best == nil {
+			return nil, fmt.Errorf("no strikes available")
+		}
+		return best, nil
+	}
+}
+
+// BuildRollOrder builds the order closing the position (quoted by current)
+// and opening the same quantity of the next contract, priced at the mid.
+func BuildRollOrder(pos *Position, current, next *Quote, sequenced bool) (Order, error) {
+	os, err := ParseOptionSymbol(pos.Symbol)
+	if err != nil {
+		return Order{}, err
+	}
+	if pos.Quantity == 0 {
+		return Order{}, fmt.Errorf("position %v has zero quantity", pos.Symbol)
+	}
+
+	quantity := math.Abs(pos.Quantity)
+	closeSide, openSide := SellToClose, BuyToOpen
+	if pos.Quantity < 0 {
+		closeSide, openSide = BuyToClose, SellToOpen
+	}
+	closeMid := (current.Bid + current.Ask) / 2
+	openMid := (next.Bid + next.Ask) / 2
+
+	if sequenced {
+		return Order{
+			Class:    OneTriggersOther,
+			Duration: Day,
+			Legs: []Order{
+				{Symbol: os.Underlying, OptionSymbol: pos.Symbol, Side: closeSide,
+					Quantity: quantity, Type: LimitOrder, Price: roundPrice(closeMid)},
+				{Symbol: os.Underlying, OptionSymbol: next.Symbol, Side: openSide,
+					Quantity: quantity, Type: LimitOrder, Price: roundPrice(openMid)},
+			},
+		}, nil
+	}
+
+	// Positive is a net credit.
+	net := openMid - closeMid
+	if pos.Quantity > 0 {
+		net = -net
+	}
+	order := Order{
+		Class:    Multileg,
+		Symbol:   os.Underlying,
+		Duration: Day,
+		Price:    roundPrice(math.Abs(net)),
+		Legs: []Order{
+			{OptionSymbol: pos.Symbol, Side: closeSide, Quantity: quantity},
+			{OptionSymbol: next.Symbol, Side: openSide, Quantity: quantity},
+		},
+	}
+	switch {
+	case order.Price == 0:
+		order.Type = Even
+	case net > 0:
+		order.Type = Credit
+	default:
+		order.Type = Debit
+	}
+	return order, nil
+}
+
+// RollPosition closes an option position and opens the same quantity of a
+// further-dated contract, previewing the roll and calling the confirmation
+// hook (if any) before it is submitted.
+func (tc *Client) RollPosition(pos *Position, params RollParams) (*RollResult, error) {
+	if params.Expiry == nil || params.Strike == nil {
+		return nil, errors.New("roll requires an expiry and strike selector")
+	}
+
+	os, err := ParseOptionSymbol(pos.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, err := tc.GetQuotes([]string{pos.Symbol})
+	if err != nil {
+		return nil, err
+	} else if len(quotes) == 0 {
+		return nil, fmt.Errorf("no quote for %v", pos.Symbol)
+	}
+	current := quotes[0]
+
+	expirations, err := tc.GetOptionExpirationDates(os.Underlying)
+	if err != nil {
+		return nil, err
+	}
+	later := make([]time.Time, 0, len(expirations))
+	for _, exp := range expirations {
+		if exp.After(os.Expiration) {
+			later = append(later, exp)
+		}
+	}
+	expiration, err := params.Expiry(time.Now(), later)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := tc.GetOptionChainWithGreeks(os.Underlying, expiration)
+	if err != nil {
+		return nil, err
+	}
+	next, err := params.Strike(StrikeContext{
+		Chain:    filterChain(chain, os.OptionType),
+		Selected: []*Quote{current},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := BuildRollOrder(pos, current, next, params.Sequenced)
+	if err != nil {
+		return nil, err
+	}
+	preview, err := tc.PreviewOrder(order)
+	if err != nil {
+		return nil, errors.Wrap(err, "error previewing roll")
+	}
+	result := &RollResult{Order: order, Preview: preview}
+	if params.Confirm != nil && !params.Confirm(order, preview) {
+		return result, ErrRollDeclined
+	}
+
+	result.Placed, err = tc.PlaceOrder(order)
+	if result.Placed != nil {
+		result.OrderId = result.Placed.Id
+	}
+	return result, err
+}
@@ -0,0 +1,129 @@
+package tradier
+
+import "sort"
+
+// ScanPredicate selects symbols by their classification.
+type ScanPredicate func(c Classification) bool
+
+// InSector matches companies in any of the given sectors.
+func InSector(sectors ...Sector) ScanPredicate {
+	return func(c Classification) bool {
+		for _, s := range sectors {
+			if c.Sector == s {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// InIndustry matches companies in any of the given Morningstar industries.
+func InIndustry(industries ...int64) ScanPredicate {
+	return func(c Classification) bool {
+		for _, i := range industries {
+			if c.Industry == i {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MarketCapAbove matches companies with a market capitalization above the given value.
+func MarketCapAbove(marketCap int64) ScanPredicate {
+	return func(c Classification) bool {
+		return c.MarketCap > marketCap
+	}
+}
+
+// InIndex matches the constituents of an index. Tradier does not publish
+// index membership, so the constituents must be provided by the caller.
+func InIndex(constituents []string) ScanPredicate {
+	members := make(map[string]bool, len(constituents))
+	for _, s := range constituents {
+		members[s] = true
+	}
+	return func(c Classification) bool {
+		return members[c.Symbol]
+	}
+}
+
+// QuotePredicate selects symbols by their quotes.
+type QuotePredicate func(q *Quote) bool
+
+// PriceBetween matches symbols last traded at a price in [low, high].
+func PriceBetween(low, high float64) QuotePredicate {
+	return func(q *Quote) bool {
+		return q.Last >= low && q.Last <= high
+	}
+}
+
+// AverageVolumeAbove matches symbols with an average daily volume above the given value.
+func AverageVolumeAbove(volume int) QuotePredicate {
+	return func(q *Quote) bool {
+		return q.AverageVolume > volume
+	}
+}
+
+// Scanner filters a universe of symbols by predicates on their classification.
+type Scanner struct {
+	Classifications *ClassificationCache
+	// All predicates must match for a symbol to be selected.
+	Predicates []ScanPredicate
+	// If set, the quotes of the symbols matching Predicates are requested
+	// from Quotes, and all of QuotePredicates must match them too.
+	Quotes          QuoteSource
+	QuotePredicates []QuotePredicate
+}
+
+// Scan returns the symbols matching all predicates, sorted.
+func (s *Scanner) Scan(symbols []string) ([]string, error) {
+	classifications, err := s.Classifications.Lookup(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for symbol, c := range classifications {
+		matched := true
+		for _, p := range s.Predicates {
+			if !p(c) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, symbol)
+		}
+	}
+	sort.Strings(result)
+	if len(s.QuotePredicates) == 0 || len(result) == 0 {
+		return result, nil
+	}
+	return s.scanQuotes(result)
+}
+
+func (s *Scanner) scanQuotes(symbols []string) ([]string, error) {
+	quotes, err := s.Quotes.GetQuotes(symbols)
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[string]bool, len(quotes))
+	for _, q := range quotes {
+		matched[q.Symbol] = true
+		for _, p := range s.QuotePredicates {
+			if !p(q) {
+				matched[q.Symbol] = false
+				break
+			}
+		}
+	}
+
+	var result []string
+	for _, symbol := range symbols {
+		if matched[symbol] {
+			result = append(result, symbol)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package tradier
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCompanyInfo struct {
+	calls    int
+	response string
+}
+
+func (fc *fakeCompanyInfo) GetCompanyInfo(symbols []string) (GetCompanyInfoResponse, error) {
+	fc.calls++
+	var resp GetCompanyInfoResponse
+	err := json.Unmarshal([]byte(fc.response), &resp)
+	return resp, err
+}
+
+func TestScanner_Scan(t *testing.T) {
+	source := &fakeCompanyInfo{response: `[
+		{"request": "AAPL", "results": [{"tables": {
+			"asset_classification": {"morningstar_sector_code": 311},
+			"share_class_profile": {"market_cap": 2000000000000}}}]},
+		{"request": "XYZ", "results": [{"tables": {
+			"asset_classification": {"morningstar_sector_code": 311},
+			"share_class_profile": {"market_cap": 500000000}}}]},
+		{"request": "XOM", "results": [{"tables": {
+			"asset_classification": {"morningstar_sector_code": 309},
+			"share_class_profile": {"market_cap": 200000000000}}}]}
+	]`}
+
+	scanner := &Scanner{
+		Classifications: NewClassificationCache(source, time.Hour),
+		Predicates:      []ScanPredicate{InSector(SectorTechnology), MarketCapAbove(10e9)},
+	}
+	symbols, err := scanner.Scan([]string{"AAPL", "XYZ", "XOM"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AAPL"}, symbols)
+
+	_, err = scanner.Scan([]string{"AAPL", "XOM"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, source.calls, "classifications are cached")
+	assert.Equal(t, "Technology", SectorTechnology.String())
+}
+
+func TestScanner_QuotePredicates(t *testing.T) {
+	source := &fakeCompanyInfo{response: `[
+		{"request": "AAPL", "results": [{"tables": {"asset_classification": {"morningstar_sector_code": 311}}}]},
+		{"request": "MSFT", "results": [{"tables": {"asset_classification": {"morningstar_sector_code": 311}}}]}
+	]`}
+	quotes := &RecordedMarketData{Quotes: map[string]*Quote{
+		"AAPL": {Symbol: "AAPL", Last: 150, AverageVolume: 90000000},
+		"MSFT": {Symbol: "MSFT", Last: 310, AverageVolume: 30000000},
+	}}
+
+	scanner := &Scanner{
+		Classifications: NewClassificationCache(source, time.Hour),
+		Predicates:      []ScanPredicate{InSector(SectorTechnology)},
+		Quotes:          quotes,
+		QuotePredicates: []QuotePredicate{PriceBetween(100, 200), AverageVolumeAbove(1000000)},
+	}
+	symbols, err := scanner.Scan([]string{"AAPL", "MSFT"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AAPL"}, symbols)
+}
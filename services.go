@@ -0,0 +1,381 @@
+package tradier
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// AccountsService provides the requests for the user's profile, the selected
+// account's balances, positions and history, and reports built from them.
+//
+// The Client's methods are grouped by subsystem into services like this one,
+// which can be documented and evolve independently, and which callers can
+// replace with fakes behind interfaces of just the methods they use.
+// The Client's own methods remain, and are equivalent.
+type AccountsService struct {
+	tc *Client
+}
+
+// Accounts returns the service for the user's accounts.
+func (tc *Client) Accounts() *AccountsService {
+	return &AccountsService{tc: tc}
+}
+
+// OrdersService provides the requests for placing, changing and cancelling
+// orders in the selected account.
+type OrdersService struct {
+	tc *Client
+}
+
+// Orders returns the service for orders.
+func (tc *Client) Orders() *OrdersService {
+	return &OrdersService{tc: tc}
+}
+
+// MarketsService provides the requests for quotes, option chains, price
+// history, the market calendar and security lookups.
+type MarketsService struct {
+	tc *Client
+}
+
+// Markets returns the service for market data.
+func (tc *Client) Markets() *MarketsService {
+	return &MarketsService{tc: tc}
+}
+
+// FundamentalsService provides the requests for company fundamentals,
+// corporate actions and financial reports. These are beta endpoints, which
+// require ClientParams.EnableBeta.
+type FundamentalsService struct {
+	tc *Client
+}
+
+// Fundamentals returns the service for fundamentals.
+func (tc *Client) Fundamentals() *FundamentalsService {
+	return &FundamentalsService{tc: tc}
+}
+
+// StreamingService provides the requests for streams of market and account events.
+type StreamingService struct {
+	tc *Client
+}
+
+// Streaming returns the service for streams.
+func (tc *Client) Streaming() *StreamingService {
+	return &StreamingService{tc: tc}
+}
+
+// Get the profile of the user, including their accounts.
+func (as *AccountsService) GetUserProfile() (*UserProfile, error) {
+	return as.tc.GetUserProfile()
+}
+
+func (as *AccountsService) SelectAccount(account string) {
+	as.tc.SelectAccount(account)
+}
+
+// SelectMatchingAccount selects the first of the user's active accounts
+// accepted by the given function, or the first active account if it is nil.
+// This allows the account to be chosen by its properties rather than hard-coded,
+// e.g. the margin account with option level 3.
+func (as *AccountsService) SelectMatchingAccount(accept func(account UserAccount) bool) (UserAccount, error) {
+	return as.tc.SelectMatchingAccount(accept)
+}
+
+func (as *AccountsService) GetAccountBalances() (*AccountBalances, error) {
+	return as.tc.GetAccountBalances()
+}
+
+func (as *AccountsService) GetAccountPositions() ([]*Position, error) {
+	return as.tc.GetAccountPositions()
+}
+
+func (as *AccountsService) GetAccountHistory(limit int) ([]*Event, error) {
+	return as.tc.GetAccountHistory(limit)
+}
+
+// SyncHistory saves the events of the selected account that are not yet in
+// store, and returns the number saved.
+func (as *AccountsService) SyncHistory(store HistoryStore) (int, error) {
+	return as.tc.SyncHistory(store)
+}
+
+func (as *AccountsService) GetAccountCostBasis() ([]*ClosedPosition, error) {
+	return as.tc.GetAccountCostBasis()
+}
+
+// GetMonthlyStatements reconstructs the monthly statements of the selected
+// account from its history and gain/loss, and the given equity snapshots.
+func (as *AccountsService) GetMonthlyStatements(snapshots []EquitySnapshot) ([]MonthlyStatement, error) {
+	return as.tc.GetMonthlyStatements(snapshots)
+}
+
+// GetDailyPnL builds the P&L report for the current session from today's
+// orders, the current positions and their closing quotes.
+func (as *AccountsService) GetDailyPnL(fees func(order *Order) float64) (*PnLReport, error) {
+	return as.tc.GetDailyPnL(fees)
+}
+
+// GetUnsettledPurchases returns the positions in the selected cash account that
+// were bought with unsettled funds, which cannot be sold until they settle.
+// Margin accounts have none.
+func (as *AccountsService) GetUnsettledPurchases() ([]UnsettledPurchase, error) {
+	return as.tc.GetUnsettledPurchases()
+}
+
+// GetStrategies returns the selected account's option positions grouped into strategies.
+func (as *AccountsService) GetStrategies() ([]Strategy, error) {
+	return as.tc.GetStrategies()
+}
+
+// NewAssignmentMonitor returns a monitor of the selected account, with
+// a position book of its current positions.
+func (as *AccountsService) NewAssignmentMonitor(notify func(event AssignmentEvent, realized float64)) (*AssignmentMonitor, error) {
+	return as.tc.NewAssignmentMonitor(notify)
+}
+
+func (os *OrdersService) PlaceOrder(order Order) (*OrderResult, error) {
+	return os.tc.PlaceOrder(order)
+}
+
+// PlaceOrderIdempotent places the order at most once, looking it up by its
+// tag before resubmitting it after a failure that leaves its outcome unknown.
+func (os *OrdersService) PlaceOrderIdempotent(ctx context.Context, order Order) (*OrderResult, error) {
+	return os.tc.PlaceOrderIdempotent(ctx, order)
+}
+
+func (os *OrdersService) PreviewOrder(order Order) (*OrderPreview, error) {
+	return os.tc.PreviewOrder(order)
+}
+
+// PreviewPlan previews the orders concurrently and totals their commissions,
+// cost and margin impact, to confirm a multi-order plan before executing it.
+func (os *OrdersService) PreviewPlan(orders []Order) (*PlanPreview, error) {
+	return os.tc.PreviewPlan(orders)
+}
+
+// FastPlaceOrder places an order with as little overhead as possible, for
+// callers with tight latency budgets. Unlike PlaceOrder it accepts a
+// context, and it skips debug dumps, tracing and metrics. The request still
+// counts against the trading rate limit, the usage ledger and the journal,
+// but never waits for the rate limit. The same guards as PlaceOrder are
+// applied, including the settled cash, good faith and option level guards.
+// The request is never retried.
+func (os *OrdersService) FastPlaceOrder(ctx context.Context, order Order) (*OrderResult, error) {
+	return os.tc.FastPlaceOrder(ctx, order)
+}
+
+func (os *OrdersService) ChangeOrder(orderId int, order Order) error {
+	return os.tc.ChangeOrder(orderId, order)
+}
+
+// ModifyOrder changes only the fields of the order that are set in change.
+func (os *OrdersService) ModifyOrder(orderId int, change ChangeOrderRequest) error {
+	return os.tc.ModifyOrder(orderId, change)
+}
+
+func (os *OrdersService) CancelOrder(orderId int) error {
+	return os.tc.CancelOrder(orderId)
+}
+
+// CancelAllOrders cancels the open orders of the account concurrently, and
+// returns the result of each cancel.
+func (os *OrdersService) CancelAllOrders(ctx context.Context) ([]CancelResult, error) {
+	return os.tc.CancelAllOrders(ctx)
+}
+
+// CancelOrdersBySymbol cancels the open orders of the account for the symbol
+// concurrently, and returns the result of each cancel.
+func (os *OrdersService) CancelOrdersBySymbol(ctx context.Context, symbol string) ([]CancelResult, error) {
+	return os.tc.CancelOrdersBySymbol(ctx, symbol)
+}
+
+func (os *OrdersService) GetOpenOrders() ([]*Order, error) {
+	return os.tc.GetOpenOrders()
+}
+
+// GetOrders returns the orders of the account that match params, requesting
+// them page by page.
+func (os *OrdersService) GetOrders(params OrdersParams) ([]*Order, error) {
+	return os.tc.GetOrders(params)
+}
+
+// WorkingOrdersBySymbol returns the exposure of the working orders of the
+// account in each symbol.
+func (os *OrdersService) WorkingOrdersBySymbol() (map[string]*SymbolExposure, error) {
+	return os.tc.WorkingOrdersBySymbol()
+}
+
+func (os *OrdersService) GetOrderStatus(orderId int) (*Order, error) {
+	return os.tc.GetOrderStatus(orderId)
+}
+
+// CloseSpread closes the given option positions with a single multileg
+// order priced at the net mid. It returns the result of placing the order.
+func (os *OrdersService) CloseSpread(positions []*Position) (*OrderResult, error) {
+	return os.tc.CloseSpread(positions)
+}
+
+// RollPosition closes an option position and opens the same quantity of a
+// further-dated contract, previewing the roll and calling the confirmation
+// hook (if any) before it is submitted.
+func (os *OrdersService) RollPosition(pos *Position, params RollParams) (*RollResult, error) {
+	return os.tc.RollPosition(pos, params)
+}
+
+// AttachExitOCO places a protective OCO exit pair for quantity units of the
+// existing position in symbol: a limit order at takeProfit and a stop order at
+// stopLoss. The quantity is validated against the account's positions.
+// It returns the result of placing the order.
+func (os *OrdersService) AttachExitOCO(symbol string, quantity, takeProfit, stopLoss float64) (*OrderResult, error) {
+	return os.tc.AttachExitOCO(symbol, quantity, takeProfit, stopLoss)
+}
+
+// OrderLatency returns stats of the time taken from submitting an order with
+// PlaceOrder or FastPlaceOrder until its ID is returned by Tradier.
+func (os *OrdersService) OrderLatency() LatencyStats {
+	return os.tc.OrderLatency()
+}
+
+func (ms *MarketsService) GetQuotes(symbols []string) ([]*Quote, error) {
+	return ms.tc.GetQuotes(symbols)
+}
+
+// GetOptionQuote returns the quote of a single option contract, identified by
+// its OCC symbol, optionally with greeks. This is much cheaper than fetching
+// the whole chain to price one contract.
+func (ms *MarketsService) GetOptionQuote(occSymbol string, greeks bool) (*OptionQuote, error) {
+	return ms.tc.GetOptionQuote(occSymbol, greeks)
+}
+
+// Get an option chain.
+func (ms *MarketsService) GetOptionChain(symbol string, expiration time.Time) ([]*Quote, error) {
+	return ms.tc.GetOptionChain(symbol, expiration)
+}
+
+// Get an option chain with greeks and implied volatilities.
+func (ms *MarketsService) GetOptionChainWithGreeks(symbol string, expiration time.Time) ([]*Quote, error) {
+	return ms.tc.GetOptionChainWithGreeks(symbol, expiration)
+}
+
+// Get an option's expiration dates.
+func (ms *MarketsService) GetOptionExpirationDates(symbol string) ([]time.Time, error) {
+	return ms.tc.GetOptionExpirationDates(symbol)
+}
+
+// Get an option's expiration dates.
+func (ms *MarketsService) GetOptionStrikes(symbol string, expiration time.Time) ([]float64, error) {
+	return ms.tc.GetOptionStrikes(symbol, expiration)
+}
+
+// Return daily, minute, or tick price bars for the given symbol.
+// Tick data is available for the past 5 days, minute data for the past 20 days,
+// and daily data since 1980-01-01. Requests outside of these ranges are
+// rejected with an IntervalRangeError before being sent.
+// NOTE: The results are split, but not dividend-adjusted.
+// https://developer.tradier.com/documentation/markets/get-history
+// https://developer.tradier.com/documentation/markets/get-timesales
+func (ms *MarketsService) GetTimeSales(symbol string, interval Interval, start, end time.Time) ([]TimeSale, error) {
+	return ms.tc.GetTimeSales(symbol, interval, start, end)
+}
+
+// GetTimeSalesWithOptions returns the bars of the symbol as GetTimeSales
+// does, splitting ranges that are too big according to opts, and the number
+// of requests that were sent.
+func (ms *MarketsService) GetTimeSalesWithOptions(symbol string, interval Interval, start, end time.Time, opts TimeSalesOptions) ([]TimeSale, int, error) {
+	return ms.tc.GetTimeSalesWithOptions(symbol, interval, start, end, opts)
+}
+
+// Get the market calendar for a given month.
+func (ms *MarketsService) GetMarketCalendar(year int, month time.Month) ([]MarketCalendar, error) {
+	return ms.tc.GetMarketCalendar(year, month)
+}
+
+// Get the current state of the market (open/closed/etc.)
+func (ms *MarketsService) GetMarketState() (MarketStatus, error) {
+	return ms.tc.GetMarketState()
+}
+
+// Get a list of symbols matching the given parameters.
+func (ms *MarketsService) LookupSecurities(types []SecurityType, exchanges []string, query string) ([]Security, error) {
+	return ms.tc.LookupSecurities(types, exchanges, query)
+}
+
+// Get the securities on the Easy-to-Borrow list.
+func (ms *MarketsService) GetEasyToBorrow() ([]Security, error) {
+	return ms.tc.GetEasyToBorrow()
+}
+
+// Get company fundamentals.
+func (fs *FundamentalsService) GetCompanyInfo(symbols []string) (GetCompanyInfoResponse, error) {
+	return fs.tc.GetCompanyInfo(symbols)
+}
+
+// Get corporate actions.
+func (fs *FundamentalsService) GetCorporateActions(symbols []string) (GetCorporateActionsResponse, error) {
+	return fs.tc.GetCorporateActions(symbols)
+}
+
+// Get corporate calendars.
+func (fs *FundamentalsService) GetCorporateCalendars(symbols []string) (GetCorporateCalendarsResponse, error) {
+	return fs.tc.GetCorporateCalendars(symbols)
+}
+
+// Get dividends.
+func (fs *FundamentalsService) GetDividends(symbols []string) (GetDividendsResponse, error) {
+	return fs.tc.GetDividends(symbols)
+}
+
+// Get financial reports.
+func (fs *FundamentalsService) GetFinancials(symbols []string) (GetFinancialsResponse, error) {
+	return fs.tc.GetFinancials(symbols)
+}
+
+// Get price statistics.
+func (fs *FundamentalsService) GetPriceStatistics(symbols []string) (GetPriceStatisticsResponse, error) {
+	return fs.tc.GetPriceStatistics(symbols)
+}
+
+// Get corporate ratios.
+func (fs *FundamentalsService) GetRatios(symbols []string) (GetRatiosResponse, error) {
+	return fs.tc.GetRatios(symbols)
+}
+
+// Subscribe to a stream of market events for the given symbols.
+// Filter restricts the type of events streamed and can include:
+// summary, trade, quote, timesale. If nil then all events are streamed.
+// https://developer.tradier.com/documentation/streaming/get-markets-events
+//
+// If the streaming session limit has been reached, ErrStreamRateLimited
+// is returned with the time at which a session may be created.
+func (ss *StreamingService) StreamMarketEvents(symbols []string, filter []Filter) (io.ReadCloser, error) {
+	return ss.tc.StreamMarketEvents(symbols, filter)
+}
+
+// StreamMarketEventsWebsocket creates a streaming session and delivers market
+// events for the given symbols on output, until the stream is stopped or fails.
+// Filter restricts the type of events streamed; if nil then all events are streamed.
+// The output channel is closed when the stream ends.
+// https://documentation.tradier.com/brokerage-api/streaming/wss-market-websocket
+func (ss *StreamingService) StreamMarketEventsWebsocket(symbols []string, filter []Filter, output chan *StreamEvent) (*MarketWebsocketStream, error) {
+	return ss.tc.StreamMarketEventsWebsocket(symbols, filter, output)
+}
+
+// StreamAccountEvents creates an account streaming session and delivers the
+// order events of the user's accounts on output, until the stream is stopped or
+// fails. The output channel is closed when the stream ends.
+// https://documentation.tradier.com/brokerage-api/streaming/wss-account-websocket
+func (ss *StreamingService) StreamAccountEvents(output chan *AccountEvent) (*AccountEventStream, error) {
+	return ss.tc.StreamAccountEvents(output)
+}
+
+// NewManagedMarketStream returns a managed stream of market events for the given symbols.
+func (ss *StreamingService) NewManagedMarketStream(symbols []string) *ManagedMarketStream {
+	return ss.tc.NewManagedMarketStream(symbols)
+}
+
+// NewStreamHub returns a hub with no subscribers.
+func (ss *StreamingService) NewStreamHub() *StreamHub {
+	return ss.tc.NewStreamHub()
+}
@@ -0,0 +1,49 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Services(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/v1/markets/quotes":
+			w.Write([]byte(`{"quotes": {"quote": {"symbol": "SPY", "last": 400}}}`))
+		case "/v1/accounts/VA000/positions":
+			w.Write([]byte(`{"positions": {"position": [{"symbol": "SPY", "quantity": 10}]}}`))
+		case "/v1/accounts/VA000/orders/1":
+			w.Write([]byte(`{"order": {"id": 1, "status": "filled"}}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+	client.Accounts().SelectAccount("VA000")
+
+	quotes, err := client.Markets().GetQuotes([]string{"SPY"})
+	if assert.NoError(t, err) && assert.Len(t, quotes, 1) {
+		assert.Equal(t, 400.0, quotes[0].Last)
+	}
+
+	positions, err := client.Accounts().GetAccountPositions()
+	if assert.NoError(t, err) && assert.Len(t, positions, 1) {
+		assert.Equal(t, 10.0, positions[0].Quantity)
+	}
+
+	order, err := client.Orders().GetOrderStatus(1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "filled", order.Status)
+	}
+
+	_, err = client.Orders().PlaceOrder(Order{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 1})
+	assert.Equal(t, ErrTradingDisabled, err)
+	assert.Equal(t, []string{"/v1/markets/quotes", "/v1/accounts/VA000/positions", "/v1/accounts/VA000/orders/1"}, paths)
+}
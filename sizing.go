@@ -0,0 +1,109 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+)
+
+// Number of trading sessions used to annualize volatility.
+const tradingDaysPerYear = 252
+
+// Sizer converts per-trade risk settings into order quantities.
+type Sizer struct {
+	// Fraction of equity risked per trade, e.g. 0.01 for 1%.
+	RiskFraction float64
+	// Maximum fraction of equity committed to a single position.
+	// If zero then positions are not capped.
+	MaxPositionFraction float64
+	// Quantities are rounded down to a multiple of Lot. Defaults to 1.
+	Lot float64
+}
+
+// FixedFractional returns the quantity that risks RiskFraction of equity,
+// given the capital lost per unit if the trade fails (e.g. entry - stop).
+func (s Sizer) FixedFractional(equity, unitRisk, price float64) float64 {
+	if unitRisk <= 0 {
+		return 0
+	}
+	return s.round(equity*s.RiskFraction/unitRisk, equity, price)
+}
+
+// ByATR sizes a position whose stop is placed atrMultiple ATRs from the entry.
+func (s Sizer) ByATR(equity, price, atr, atrMultiple float64) float64 {
+	return s.FixedFractional(equity, atr*atrMultiple, price)
+}
+
+// ByVolatility sizes a position so that a one standard deviation move over
+// the holding period, implied by the annualized volatility, loses RiskFraction of equity.
+func (s Sizer) ByVolatility(equity, price, annualVol float64, holdingDays int) float64 {
+	move := price * annualVol * math.Sqrt(float64(holdingDays)/tradingDaysPerYear)
+	return s.FixedFractional(equity, move, price)
+}
+
+// Kelly sizes a position by the Kelly criterion scaled by the given fraction
+// (e.g. 0.5 for "half Kelly"). The risk fraction is ignored; MaxPositionFraction still applies.
+func (s Sizer) Kelly(equity, unitRisk, price, winProbability, payoffRatio, scale float64) float64 {
+	f := scale * KellyFraction(winProbability, payoffRatio)
+	if f <= 0 || unitRisk <= 0 {
+		return 0
+	}
+	return s.round(equity*f/unitRisk, equity, price)
+}
+
+func (s Sizer) round(quantity, equity, price float64) float64 {
+	if s.MaxPositionFraction > 0 && price > 0 {
+		quantity = math.Min(quantity, equity*s.MaxPositionFraction/price)
+	}
+
+	lot := s.Lot
+	if lot <= 0 {
+		lot = 1
+	}
+	return math.Max(0, math.Floor(quantity/lot)*lot)
+}
+
+// KellyFraction returns the fraction of capital that maximizes long run growth
+// for a bet won with the given probability that pays payoffRatio times the amount risked.
+func KellyFraction(winProbability, payoffRatio float64) float64 {
+	if payoffRatio <= 0 {
+		return 0
+	}
+	return winProbability - (1-winProbability)/payoffRatio
+}
+
+// ATR returns the average true range of the last window bars.
+func ATR(bars []TimeSale, window int) (float64, error) {
+	if window <= 0 || len(bars) < window+1 {
+		return 0, fmt.Errorf("need %d bars to compute %d period ATR, have %d", window+1, window, len(bars))
+	}
+
+	var total float64
+	for i := len(bars) - window; i < len(bars); i++ {
+		high, low := float64(bars[i].High), float64(bars[i].Low)
+		prevClose := float64(bars[i-1].Close)
+		tr := math.Max(high-low, math.Max(math.Abs(high-prevClose), math.Abs(low-prevClose)))
+		total += tr
+	}
+	return total / float64(window), nil
+}
+
+// FixedFractional is a template SizingRule risking the given fraction of total equity.
+func FixedFractional(riskFraction float64) SizingRule {
+	return func(env TemplateEnv, unitRisk float64) (float64, error) {
+		if env.Balances == nil {
+			return 0, fmt.Errorf("account balances are required to size by equity")
+		}
+		s := Sizer{RiskFraction: riskFraction}
+		return s.FixedFractional(env.Balances.TotalEquity, unitRisk, 0), nil
+	}
+}
+
+// KellySizing is a template SizingRule sizing by the scaled Kelly criterion.
+func KellySizing(winProbability, payoffRatio, scale float64) SizingRule {
+	return func(env TemplateEnv, unitRisk float64) (float64, error) {
+		if env.Balances == nil {
+			return 0, fmt.Errorf("account balances are required to size by equity")
+		}
+		return Sizer{}.Kelly(env.Balances.TotalEquity, unitRisk, 0, winProbability, payoffRatio, scale), nil
+	}
+}
@@ -0,0 +1,48 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizer(t *testing.T) {
+	t.Run("Fixed fractional", func(t *testing.T) {
+		s := Sizer{RiskFraction: 0.01}
+		assert.Equal(t, 50.0, s.FixedFractional(100000, 20, 0))
+	})
+
+	t.Run("Capped by max position", func(t *testing.T) {
+		s := Sizer{RiskFraction: 0.01, MaxPositionFraction: 0.1}
+		assert.Equal(t, 40.0, s.FixedFractional(100000, 1, 250))
+	})
+
+	t.Run("Round lots", func(t *testing.T) {
+		s := Sizer{RiskFraction: 0.01, Lot: 100}
+		assert.Equal(t, 300.0, s.ByATR(100000, 50, 1.5, 2))
+	})
+
+	t.Run("Negative edge", func(t *testing.T) {
+		assert.Equal(t, 0.0, Sizer{}.Kelly(100000, 100, 0, 0.4, 1, 1))
+	})
+}
+
+func TestKellyFraction(t *testing.T) {
+	assert.InDelta(t, 0.2, KellyFraction(0.6, 1), 1e-9)
+	assert.InDelta(t, 0.25, KellyFraction(0.5, 2), 1e-9)
+}
+
+func TestATR(t *testing.T) {
+	bars := []TimeSale{
+		{High: 11, Low: 9, Close: 10},
+		{High: 12, Low: 10, Close: 11},
+		{High: 11.5, Low: 8, Close: 9},
+	}
+
+	atr, err := ATR(bars, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.75, atr)
+
+	_, err = ATR(bars, 3)
+	assert.Error(t, err)
+}
@@ -0,0 +1,282 @@
+package tradier
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrSpillFull is passed to SpillingSink.Errors for each event dropped because
+// the spill file has reached MaxSpillBytes.
+var ErrSpillFull = errors.New("spill file full")
+
+// Sink receives stream events, e.g. to publish them to Kafka or write them to a file.
+type Sink interface {
+	Write(event *StreamEvent) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(event *StreamEvent) error
+
+func (f SinkFunc) Write(event *StreamEvent) error {
+	return f(event)
+}
+
+// SpillStats is a snapshot of the queues of a SpillingSink.
+type SpillStats struct {
+	// Events queued in memory.
+	Queued int
+	// Events in the spill file waiting to be replayed, and its size.
+	Spilled      int
+	SpilledBytes int64
+	// Events delivered from the spill file.
+	Replayed int64
+	// Events dropped because the spill file was full.
+	Dropped int64
+}
+
+// SpillingSink delivers stream events to a Sink without blocking the stream
+// reader when the sink stalls or fails. Events are queued in memory, and once
+// Buffer events are queued they are spilled to a bounded file at Path, to be
+// replayed in order when the sink recovers. Failed writes are retried.
+//
+// Events left in the spill file when Run stops are replayed by the next Run,
+// including by a new process using the same Path. An event being written
+// when the process exits may be delivered twice.
+type SpillingSink struct {
+	Sink Sink
+	Path string
+	// Number of events queued in memory before spilling.
+	Buffer int
+	// If not zero, events are dropped once the spill file reaches this size.
+	MaxSpillBytes int64
+	// Time between retries of a failed write.
+	RetryInterval time.Duration
+	// If set, Errors is called with each failed write, and each error
+	// spilling an event.
+	Errors func(err error)
+
+	mu       sync.Mutex
+	queue    []*StreamEvent
+	file     *os.File
+	readOff  int64
+	readSize int64 // Size of the record at readOff, once it has been read.
+	writeOff int64
+	spilled  int
+	replayed int64
+	dropped  int64
+	wake     chan struct{}
+}
+
+func NewSpillingSink(sink Sink, path string) *SpillingSink {
+	return &SpillingSink{
+		Sink:          sink,
+		Path:          path,
+		Buffer:        1024,
+		MaxSpillBytes: 64 << 20,
+		RetryInterval: time.Second,
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// Stats returns a snapshot of the sink's queues.
+func (ss *SpillingSink) Stats() SpillStats {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return SpillStats{
+		Queued:       len(ss.queue),
+		Spilled:      ss.spilled,
+		SpilledBytes: ss.writeOff - ss.readOff,
+		Replayed:     ss.replayed,
+		Dropped:      ss.dropped,
+	}
+}
+
+// Handle queues the event for the sink.
+func (ss *SpillingSink) Handle(event *StreamEvent) {
+	ss.mu.Lock()
+	var err error
+	// Once events are spilled, later events follow them to keep their order.
+	if ss.spilled == 0 && len(ss.queue) < ss.Buffer {
+		ss.queue = append(ss.queue, event)
+	} else {
+		err = ss.spill(event)
+	}
+	ss.mu.Unlock()
+
+	select {
+	case ss.wake <- struct{}{}:
+	default:
+	}
+	if err != nil && ss.Errors != nil {
+		ss.Errors(err)
+	}
+}
+
+// HandleChan queues the events until the channel is closed.
+func (ss *SpillingSink) HandleChan(events <-chan *StreamEvent) {
+	for event := range events {
+		ss.Handle(event)
+	}
+}
+
+// Run delivers the queued events to the sink until stop is closed.
+func (ss *SpillingSink) Run(stop <-chan struct{}) {
+	ss.mu.Lock()
+	err := ss.open()
+	ss.mu.Unlock()
+	if err != nil && ss.Errors != nil {
+		ss.Errors(err)
+	}
+
+	for {
+		event, fromFile, err := ss.next()
+		if err == nil && event == nil {
+			select {
+			case <-ss.wake:
+				continue
+			case <-stop:
+				return
+			}
+		}
+		if err == nil {
+			err = ss.Sink.Write(event)
+		}
+		if err == nil {
+			ss.ack(fromFile)
+			continue
+		}
+
+		if ss.Errors != nil {
+			ss.Errors(err)
+		}
+		select {
+		case <-time.After(ss.RetryInterval):
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Close closes the spill file, removing it if it is empty.
+func (ss *SpillingSink) Close() error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.file == nil {
+		return nil
+	}
+	err := ss.file.Close()
+	if ss.spilled == 0 {
+		os.Remove(ss.Path)
+	}
+	ss.file = nil
+	return err
+}
+
+// Returns the oldest undelivered event, which is in memory if any are, since
+// events are only spilled while the memory queue is full.
+func (ss *SpillingSink) next() (*StreamEvent, bool, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if len(ss.queue) > 0 {
+		return ss.queue[0], false, nil
+	}
+	if ss.spilled == 0 {
+		return nil, false, nil
+	}
+
+	data, err := ss.readRecord(ss.readOff)
+	if err != nil {
+		return nil, true, errors.Wrap(err, "error reading spill file")
+	}
+	ss.readSize = spillHeaderSize + int64(len(data))
+	event := &StreamEvent{}
+	UnmarshalStreamEvent(data, event)
+	return event, true, nil
+}
+
+// Remove the event returned by next once it has been delivered.
+func (ss *SpillingSink) ack(fromFile bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if !fromFile {
+		ss.queue[0] = nil
+		ss.queue = ss.queue[1:]
+		return
+	}
+
+	ss.readOff += ss.readSize
+	ss.readSize = 0
+	ss.spilled--
+	ss.replayed++
+	if ss.spilled == 0 {
+		// Reclaim the file once it has been replayed.
+		ss.file.Truncate(0)
+		ss.readOff, ss.writeOff = 0, 0
+	}
+}
+
+// Records are the raw message of the event, prefixed by its length.
+const spillHeaderSize = 4
+
+func (ss *SpillingSink) spill(event *StreamEvent) error {
+	if err := ss.open(); err != nil {
+		return err
+	}
+	size := spillHeaderSize + int64(len(event.Message))
+	if ss.MaxSpillBytes > 0 && ss.writeOff+size > ss.MaxSpillBytes {
+		ss.dropped++
+		return ErrSpillFull
+	}
+
+	record := make([]byte, size)
+	binary.BigEndian.PutUint32(record, uint32(len(event.Message)))
+	copy(record[spillHeaderSize:], event.Message)
+	if _, err := ss.file.WriteAt(record, ss.writeOff); err != nil {
+		return errors.Wrap(err, "error writing spill file")
+	}
+	ss.writeOff += size
+	ss.spilled++
+	return nil
+}
+
+func (ss *SpillingSink) readRecord(off int64) ([]byte, error) {
+	var header [spillHeaderSize]byte
+	if _, err := ss.file.ReadAt(header[:], off); err != nil {
+		return nil, err
+	}
+	data := make([]byte, binary.BigEndian.Uint32(header[:]))
+	if _, err := ss.file.ReadAt(data, off+spillHeaderSize); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Open the spill file, counting the events left in it by a previous run.
+// A partly written record at the end is discarded.
+func (ss *SpillingSink) open() error {
+	if ss.file != nil {
+		return nil
+	}
+	file, err := os.OpenFile(ss.Path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(err, "error opening spill file")
+	}
+	ss.file = file
+
+	for {
+		data, err := ss.readRecord(ss.writeOff)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, "error reading spill file")
+		}
+		ss.writeOff += spillHeaderSize + int64(len(data))
+		ss.spilled++
+	}
+	return ss.file.Truncate(ss.writeOff)
+}
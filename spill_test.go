@@ -0,0 +1,132 @@
+package tradier
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func spillTestEvent(i int) *StreamEvent {
+	event := &StreamEvent{}
+	UnmarshalStreamEvent([]byte(fmt.Sprintf(`{"type":"trade","symbol":"SPY","size":"%d"}`, i)), event)
+	return event
+}
+
+func TestSpillingSink(t *testing.T) {
+	t.Run("Spills while the sink stalls", func(t *testing.T) {
+		release := make(chan struct{})
+		delivered := make(chan string, 10)
+		sink := SinkFunc(func(event *StreamEvent) error {
+			<-release
+			delivered <- string(event.Message)
+			return nil
+		})
+		ss := NewSpillingSink(sink, filepath.Join(t.TempDir(), "spill"))
+		ss.Buffer = 2
+
+		stop := make(chan struct{})
+		defer close(stop)
+		go ss.Run(stop)
+
+		for i := 0; i < 5; i++ {
+			ss.Handle(spillTestEvent(i))
+		}
+		stats := ss.Stats()
+		assert.Equal(t, 2, stats.Queued)
+		assert.Equal(t, 3, stats.Spilled)
+		assert.True(t, stats.SpilledBytes > 0)
+
+		close(release)
+		for i := 0; i < 5; i++ {
+			select {
+			case message := <-delivered:
+				assert.Equal(t, string(spillTestEvent(i).Message), message, "delivered in order")
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for events")
+			}
+		}
+		assert.Eventually(t, func() bool { return ss.Stats().Replayed == 3 }, time.Second, time.Millisecond)
+		assert.Equal(t, SpillStats{Replayed: 3}, ss.Stats())
+	})
+
+	t.Run("Retries failed writes", func(t *testing.T) {
+		failures := 2
+		delivered := make(chan *StreamEvent, 1)
+		var errs []error
+		sink := SinkFunc(func(event *StreamEvent) error {
+			if failures > 0 {
+				failures--
+				return errors.New("broker unavailable")
+			}
+			delivered <- event
+			return nil
+		})
+		ss := NewSpillingSink(sink, filepath.Join(t.TempDir(), "spill"))
+		ss.RetryInterval = time.Millisecond
+		ss.Errors = func(err error) { errs = append(errs, err) }
+
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			ss.Run(stop)
+			close(done)
+		}()
+		ss.Handle(spillTestEvent(1))
+		select {
+		case event := <-delivered:
+			assert.Equal(t, "SPY", event.Symbol)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+		close(stop)
+		<-done
+		assert.Len(t, errs, 2)
+	})
+
+	t.Run("Drops when the spill file is full", func(t *testing.T) {
+		ss := NewSpillingSink(SinkFunc(func(*StreamEvent) error { return nil }), filepath.Join(t.TempDir(), "spill"))
+		ss.Buffer = 0
+		ss.MaxSpillBytes = int64(2 * (spillHeaderSize + len(spillTestEvent(0).Message)))
+		var errs []error
+		ss.Errors = func(err error) { errs = append(errs, err) }
+
+		for i := 0; i < 3; i++ {
+			ss.Handle(spillTestEvent(i))
+		}
+		assert.Equal(t, 2, ss.Stats().Spilled)
+		assert.Equal(t, int64(1), ss.Stats().Dropped)
+		assert.Equal(t, []error{ErrSpillFull}, errs)
+	})
+
+	t.Run("Replays the spill file of a previous run", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "spill")
+		first := NewSpillingSink(nil, path)
+		first.Buffer = 0
+		first.Handle(spillTestEvent(1))
+		first.Handle(spillTestEvent(2))
+		assert.NoError(t, first.Close())
+
+		delivered := make(chan *StreamEvent, 2)
+		second := NewSpillingSink(SinkFunc(func(event *StreamEvent) error {
+			delivered <- event
+			return nil
+		}), path)
+		stop := make(chan struct{})
+		defer close(stop)
+		go second.Run(stop)
+
+		for i := 1; i <= 2; i++ {
+			select {
+			case event := <-delivered:
+				assert.Equal(t, "trade", event.Type)
+				assert.Equal(t, string(spillTestEvent(i).Message), string(event.Message))
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for events")
+			}
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+)
+
+// BuildClosingSpreadOrder builds the multileg order that closes the given
+// option positions on a single underlying, priced at the net mid of the quotes
+// (keyed by option symbol). The price is per unit of the spread, where a unit is
+// the greatest common divisor of the leg quantities.
+func BuildClosingSpreadOrder(positions []*Position, quotes map[string]*Quote) (Order, error) {
+	if len(positions) < 2 {
+		return Order{}, fmt.Errorf("a spread requires at least 2 legs, got %d", len(positions))
+	}
+
+	var underlying string
+	var unit int64
+	for _, p := range positions {
+		os, err := ParseOptionSymbol(p.Symbol)
+		if err != nil {
+			return Order{}, err
+		}
+		if underlying != "" && os.Underlying != underlying {
+			return Order{}, fmt.Errorf("spread legs have different underlyings: %v and %v", underlying, os.Underlying)
+		}
+		underlying = os.Underlying
+		if p.Quantity == 0 {
+			return Order{}, fmt.Errorf("position %v has zero quantity", p.Symbol)
+		}
+		unit = gcd(unit, int64(math.Abs(p.Quantity)))
+	}
+
+	order := Order{
+		Class:    Multileg,
+		Symbol:   underlying,
+		Duration: Day,
+	}
+	var net float64 // Positive is a net credit received for closing.
+	for _, p := range positions {
+		q, ok := quotes[p.Symbol]
+		if !ok {
+			return Order{}, fmt.Errorf("no quote for %v", p.Symbol)
+		}
+
+		mid := (q.Bid + q.Ask) / 2
+		ratio := math.Abs(p.Quantity) / float64(unit)
+		leg := Order{OptionSymbol: p.Symbol, Quantity: math.Abs(p.Quantity)}
+		if p.Quantity > 0 {
+			leg.Side = SellToClose
+			net += mid * ratio
+		} else {
+			leg.Side = BuyToClose
+			net -= mid * ratio
+		}
+		order.Legs = append(order.Legs, leg)
+	}
+
+	order.Price = roundPrice(math.Abs(net))
+	switch {
+	case order.Price == 0:
+		order.Type = Even
+	case net > 0:
+		order.Type = Credit
+	default:
+		order.Type = Debit
+	}
+	return order, nil
+}
+
+// CloseSpread closes the given option positions with a single multileg
+// order priced at the net mid. It returns the result of placing the order.
+func (tc *Client) CloseSpread(positions []*Position) (*OrderResult, error) {
+	symbols := make([]string, len(positions))
+	for i, p := range positions {
+		symbols[i] = p.Symbol
+	}
+
+	quotes, err := tc.GetQuotes(symbols)
+	if err != nil {
+		return nil, err
+	}
+	bySymbol := make(map[string]*Quote, len(quotes))
+	for _, q := range quotes {
+		bySymbol[q.Symbol] = q
+	}
+
+	order, err := BuildClosingSpreadOrder(positions, bySymbol)
+	if err != nil {
+		return nil, err
+	}
+	return tc.PlaceOrder(order)
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
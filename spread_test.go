@@ -0,0 +1,75 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildClosingSpreadOrder(t *testing.T) {
+	quotes := map[string]*Quote{
+		"SPY210219P00365000": {Bid: 4.40, Ask: 4.60},
+		"SPY210219P00360000": {Bid: 3.00, Ask: 3.20},
+		"QQQ210219P00300000": {Bid: 1.00, Ask: 1.10},
+	}
+
+	t.Run("Put credit spread", func(t *testing.T) {
+		positions := []*Position{
+			{Symbol: "SPY210219P00365000", Quantity: -2},
+			{Symbol: "SPY210219P00360000", Quantity: 2},
+		}
+
+		order, err := BuildClosingSpreadOrder(positions, quotes)
+		assert.NoError(t, err)
+		assert.Equal(t, Multileg, order.Class)
+		assert.Equal(t, "SPY", order.Symbol)
+		assert.Equal(t, Debit, order.Type)
+		assert.Equal(t, 1.40, order.Price)
+		assert.Equal(t, BuyToClose, order.Legs[0].Side)
+		assert.Equal(t, 2.0, order.Legs[0].Quantity)
+		assert.Equal(t, SellToClose, order.Legs[1].Side)
+	})
+
+	t.Run("Mixed underlyings", func(t *testing.T) {
+		positions := []*Position{
+			{Symbol: "SPY210219P00365000", Quantity: -1},
+			{Symbol: "QQQ210219P00300000", Quantity: 1},
+		}
+		_, err := BuildClosingSpreadOrder(positions, quotes)
+		assert.Error(t, err)
+	})
+
+	t.Run("Not an option", func(t *testing.T) {
+		positions := []*Position{
+			{Symbol: "SPY", Quantity: 100},
+			{Symbol: "SPY210219P00360000", Quantity: 1},
+		}
+		_, err := BuildClosingSpreadOrder(positions, quotes)
+		assert.Error(t, err)
+	})
+}
+
+func TestBuildRollOrder(t *testing.T) {
+	pos := &Position{Symbol: "SPY210115P00360000", Quantity: -3}
+	current := &Quote{Symbol: "SPY210115P00360000", Bid: 0.90, Ask: 1.10}
+	next := &Quote{Symbol: "SPY210219P00360000", Bid: 3.00, Ask: 3.20}
+
+	t.Run("Multileg", func(t *testing.T) {
+		order, err := BuildRollOrder(pos, current, next, false)
+		assert.NoError(t, err)
+		assert.Equal(t, Multileg, order.Class)
+		assert.Equal(t, Credit, order.Type)
+		assert.Equal(t, 2.10, order.Price)
+		assert.Equal(t, BuyToClose, order.Legs[0].Side)
+		assert.Equal(t, SellToOpen, order.Legs[1].Side)
+		assert.Equal(t, 3.0, order.Legs[1].Quantity)
+	})
+
+	t.Run("Sequenced", func(t *testing.T) {
+		order, err := BuildRollOrder(pos, current, next, true)
+		assert.NoError(t, err)
+		assert.Equal(t, OneTriggersOther, order.Class)
+		assert.Equal(t, 1.0, order.Legs[0].Price)
+		assert.Equal(t, 3.1, order.Legs[1].Price)
+	})
+}
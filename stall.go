@@ -0,0 +1,52 @@
+package tradier
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStreamStalled is the error of a stream that was closed because
+// no data was received for Timeout.
+type ErrStreamStalled struct {
+	Timeout time.Duration
+}
+
+func (e ErrStreamStalled) Error() string {
+	return fmt.Sprintf("stream stalled: no data received for %v", e.Timeout)
+}
+
+// stallReader closes the underlying stream if no bytes are read from it
+// within the timeout, and then fails reads with ErrStreamStalled.
+type stallReader struct {
+	io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	stalled int32
+}
+
+func newStallReader(rc io.ReadCloser, timeout time.Duration) *stallReader {
+	sr := &stallReader{ReadCloser: rc, timeout: timeout}
+	sr.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&sr.stalled, 1)
+		rc.Close()
+	})
+	return sr
+}
+
+func (sr *stallReader) Read(p []byte) (int, error) {
+	n, err := sr.ReadCloser.Read(p)
+	if atomic.LoadInt32(&sr.stalled) != 0 {
+		return n, ErrStreamStalled{Timeout: sr.timeout}
+	}
+	if n > 0 {
+		sr.timer.Reset(sr.timeout)
+	}
+	return n, err
+}
+
+func (sr *stallReader) Close() error {
+	sr.timer.Stop()
+	return sr.ReadCloser.Close()
+}
@@ -0,0 +1,90 @@
+package tradier
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_StreamStallTimeout(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/markets/events/session":
+			fmt.Fprintf(w, `{"stream": {"url": "%s/v1/markets/events", "sessionid": "s"}}`, server.URL)
+		case "/v1/accounts/events/session":
+			wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/accounts/events"
+			fmt.Fprintf(w, `{"stream": {"url": "%s", "sessionid": "s"}}`, wsURL)
+		case "/v1/markets/events":
+			// Send one event and then stall without closing the connection.
+			fmt.Fprintf(w, `{"type":"quote","symbol":"%s"}`+"\n", r.FormValue("symbols"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case "/v1/accounts/events":
+			conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+			conn.ReadMessage()
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"id": 1, "event": "order", "status": "open"}`))
+			time.Sleep(time.Second)
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	params.StreamStallTimeout = 50 * time.Millisecond
+	client := NewClient(params)
+
+	t.Run("Market stream", func(t *testing.T) {
+		body, err := client.StreamMarketEvents([]string{"SPY"}, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		events := make(chan *StreamEvent, 1)
+		stream := NewMarketEventStream(body, events)
+		assert.Equal(t, "SPY", (<-events).Symbol)
+		_, ok := <-events
+		assert.False(t, ok)
+		assert.Equal(t, ErrStreamStalled{Timeout: 50 * time.Millisecond}, stream.Err())
+	})
+
+	t.Run("Managed stream reconnects", func(t *testing.T) {
+		errs := make(chan error, 2)
+		ms := client.NewManagedMarketStream([]string{"SPY"})
+		ms.Backoff = backoff.NewConstantBackOff(time.Millisecond)
+		ms.Errors = func(err error) { errs <- err }
+
+		events := make(chan *StreamEvent)
+		stop := make(chan struct{})
+		go ms.Run(events, stop)
+		assert.Equal(t, "SPY", (<-events).Symbol)
+		assert.IsType(t, ErrStreamStalled{}, <-errs)
+		assert.Equal(t, "SPY", (<-events).Symbol)
+		close(stop)
+		for range events {
+		}
+	})
+
+	t.Run("Account stream", func(t *testing.T) {
+		events := make(chan *AccountEvent)
+		stream, err := client.StreamAccountEvents(events)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer stream.Stop()
+		assert.Equal(t, 1, (<-events).Id)
+		_, ok := <-events
+		assert.False(t, ok)
+		assert.IsType(t, ErrStreamStalled{}, stream.Err())
+	})
+}
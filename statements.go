@@ -0,0 +1,216 @@
+package tradier
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// EquitySnapshot is the total equity of an account at a point in time.
+type EquitySnapshot struct {
+	Time        time.Time `json:"time"`
+	TotalEquity float64   `json:"total_equity"`
+}
+
+// SaveEquitySnapshot persists the account's total equity to store
+// under "equity/<account>", for later statement reconstruction.
+func SaveEquitySnapshot(store Store, account string, balances *AccountBalances, t time.Time) error {
+	data, err := json.Marshal(EquitySnapshot{Time: t, TotalEquity: balances.TotalEquity})
+	if err != nil {
+		return err
+	}
+	return store.Append("equity/"+account, Record{Time: t, Data: data})
+}
+
+// LoadEquitySnapshots returns the snapshots saved by SaveEquitySnapshot.
+func LoadEquitySnapshots(store Store, account string) ([]EquitySnapshot, error) {
+	records, err := store.Range("equity/"+account, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]EquitySnapshot, len(records))
+	for i, rec := range records {
+		if err := json.Unmarshal(rec.Data, &snapshots[i]); err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, nil
+}
+
+// MonthlyStatement summarizes one calendar month of account activity.
+// If there is no snapshot of equity before the month, the starting equity
+// is the first snapshot within it.
+type MonthlyStatement struct {
+	Month          time.Time `json:"month"`
+	StartingEquity float64   `json:"starting_equity"`
+	EndingEquity   float64   `json:"ending_equity"`
+	Deposits       float64   `json:"deposits"`
+	Withdrawals    float64   `json:"withdrawals"`
+	// Change in equity not due to deposits or withdrawals.
+	PnL float64 `json:"pnl"`
+	// Gain or loss of positions closed during the month.
+	RealizedPnL    float64 `json:"realized_pnl"`
+	Fees           float64 `json:"fees"`
+	DividendIncome float64 `json:"dividend_income"`
+}
+
+// StatementInputs are the account records used to reconstruct statements.
+type StatementInputs struct {
+	History         []*Event
+	Snapshots       []EquitySnapshot
+	ClosedPositions []*ClosedPosition
+	// Location used to determine month boundaries. Defaults to UTC.
+	Location *time.Location
+}
+
+// BuildMonthlyStatements reconstructs a statement for each month that has
+// any history, snapshot or closed position, in chronological order.
+func BuildMonthlyStatements(in StatementInputs) []MonthlyStatement {
+	loc := in.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	monthOf := func(t time.Time) time.Time {
+		t = t.In(loc)
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	}
+
+	months := make(map[time.Time]*MonthlyStatement)
+	month := func(t time.Time) *MonthlyStatement {
+		m := monthOf(t)
+		if months[m] == nil {
+			months[m] = &MonthlyStatement{Month: m}
+		}
+		return months[m]
+	}
+
+	for _, e := range in.History {
+		ms := month(e.Date.Time)
+		switch e.Type {
+		case "ach", "wire", "check", "journal", "transfer":
+			if e.Amount >= 0 {
+				ms.Deposits += e.Amount
+			} else {
+				ms.Withdrawals -= e.Amount
+			}
+		case "dividend":
+			ms.DividendIncome += e.Amount
+		case "fee":
+			ms.Fees -= e.Amount
+		case "trade", "option":
+			ms.Fees += e.Trade.Commission
+		}
+	}
+
+	for _, cp := range in.ClosedPositions {
+		month(cp.CloseDate.Time).RealizedPnL += cp.GainLoss
+	}
+
+	snapshots := make([]EquitySnapshot, len(in.Snapshots))
+	copy(snapshots, in.Snapshots)
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	for _, s := range snapshots {
+		month(s.Time)
+	}
+
+	statements := make([]MonthlyStatement, 0, len(months))
+	for _, ms := range months {
+		statements = append(statements, *ms)
+	}
+	sort.Slice(statements, func(i, j int) bool { return statements[i].Month.Before(statements[j].Month) })
+
+	for i := range statements {
+		ms := &statements[i]
+		end := ms.Month.AddDate(0, 1, 0)
+		start, startOk := lastSnapshotBefore(snapshots, ms.Month)
+		if !startOk {
+			start, startOk = firstSnapshotFrom(snapshots, ms.Month, end)
+		}
+		ending, endOk := lastSnapshotBefore(snapshots, end)
+
+		ms.StartingEquity = start.TotalEquity
+		ms.EndingEquity = ending.TotalEquity
+		if startOk && endOk {
+			ms.PnL = ms.EndingEquity - ms.StartingEquity - ms.Deposits + ms.Withdrawals
+		}
+	}
+	return statements
+}
+
+func lastSnapshotBefore(snapshots []EquitySnapshot, t time.Time) (EquitySnapshot, bool) {
+	i := sort.Search(len(snapshots), func(i int) bool { return !snapshots[i].Time.Before(t) })
+	if i == 0 {
+		return EquitySnapshot{}, false
+	}
+	return snapshots[i-1], true
+}
+
+func firstSnapshotFrom(snapshots []EquitySnapshot, start, end time.Time) (EquitySnapshot, bool) {
+	i := sort.Search(len(snapshots), func(i int) bool { return !snapshots[i].Time.Before(start) })
+	if i == len(snapshots) || !snapshots[i].Time.Before(end) {
+		return EquitySnapshot{}, false
+	}
+	return snapshots[i], true
+}
+
+// WriteStatementsCSV writes the statements as CSV with a header row, in
+// order of month.
+func WriteStatementsCSV(w io.Writer, statements []MonthlyStatement) error {
+	statements = sortedStatements(statements)
+	cw := csv.NewWriter(w)
+	header := []string{
+		"month", "starting_equity", "ending_equity", "deposits", "withdrawals",
+		"pnl", "realized_pnl", "fees", "dividend_income",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, ms := range statements {
+		record := []string{ms.Month.Format("2006-01")}
+		for _, v := range []float64{
+			ms.StartingEquity, ms.EndingEquity, ms.Deposits, ms.Withdrawals,
+			ms.PnL, ms.RealizedPnL, ms.Fees, ms.DividendIncome,
+		} {
+			record = append(record, formatExportAmount(v))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteStatementsJSON writes the statements as a JSON array, in order of month.
+func WriteStatementsJSON(w io.Writer, statements []MonthlyStatement) error {
+	return writeExportJSON(w, sortedStatements(statements))
+}
+
+func sortedStatements(statements []MonthlyStatement) []MonthlyStatement {
+	sorted := append([]MonthlyStatement(nil), statements...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Month.Before(sorted[j].Month) })
+	return sorted
+}
+
+// GetMonthlyStatements reconstructs the monthly statements of the selected
+// account from its history and gain/loss, and the given equity snapshots.
+func (tc *Client) GetMonthlyStatements(snapshots []EquitySnapshot) ([]MonthlyStatement, error) {
+	history, err := tc.GetAccountHistory(0)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := tc.GetAccountCostBasis()
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildMonthlyStatements(StatementInputs{
+		History:         history,
+		Snapshots:       snapshots,
+		ClosedPositions: closed,
+	}), nil
+}
@@ -0,0 +1,72 @@
+package tradier
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMonthlyStatements(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2021, month, d, 16, 0, 0, 0, time.UTC)
+	}
+	store := NewMemoryStore()
+	for _, s := range []EquitySnapshot{
+		{Time: day(time.January, 4), TotalEquity: 10000},
+		{Time: day(time.January, 29), TotalEquity: 15500},
+		{Time: day(time.February, 26), TotalEquity: 15000},
+	} {
+		assert.NoError(t, SaveEquitySnapshot(store, "VA000", &AccountBalances{TotalEquity: s.TotalEquity}, s.Time))
+	}
+	snapshots, err := LoadEquitySnapshots(store, "VA000")
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 3)
+
+	statements := BuildMonthlyStatements(StatementInputs{
+		History: []*Event{
+			{Type: "ach", Amount: 5000, Date: DateTime{day(time.January, 10)}},
+			{Type: "trade", Amount: -3701, Date: DateTime{day(time.January, 11)}, Trade: Trade{Commission: 1}},
+			{Type: "dividend", Amount: 12.5, Date: DateTime{day(time.January, 20)}},
+			{Type: "wire", Amount: -1000, Date: DateTime{day(time.February, 3)}},
+			{Type: "fee", Amount: -25, Date: DateTime{day(time.February, 5)}},
+		},
+		Snapshots: snapshots,
+		ClosedPositions: []*ClosedPosition{
+			{Symbol: "SPY", GainLoss: 120, CloseDate: DateTime{day(time.February, 10)}},
+		},
+	})
+
+	if assert.Len(t, statements, 2) {
+		jan, feb := statements[0], statements[1]
+		assert.Equal(t, time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), jan.Month)
+		assert.Equal(t, 10000.0, jan.StartingEquity)
+		assert.Equal(t, 15500.0, jan.EndingEquity)
+		assert.Equal(t, 5000.0, jan.Deposits)
+		assert.Equal(t, 500.0, jan.PnL)
+		assert.Equal(t, 1.0, jan.Fees)
+		assert.Equal(t, 12.5, jan.DividendIncome)
+
+		assert.Equal(t, 15500.0, feb.StartingEquity)
+		assert.Equal(t, 1000.0, feb.Withdrawals)
+		assert.Equal(t, 500.0, feb.PnL)
+		assert.Equal(t, 120.0, feb.RealizedPnL)
+		assert.Equal(t, 25.0, feb.Fees)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteStatementsCSV(&buf, statements))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, "2021-02,15500.00,15000.00,0.00,1000.00,500.00,120.00,25.00,0.00", lines[2])
+	}
+
+	buf.Reset()
+	assert.NoError(t, WriteStatementsJSON(&buf, statements))
+	var decoded []MonthlyStatement
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, statements[1].PnL, decoded[1].PnL)
+}
@@ -0,0 +1,24 @@
+package tradier
+
+// Stats is a point-in-time snapshot of the client's API usage.
+type Stats struct {
+	// Rate limit state by category, as reported by the most recent
+	// response for each category.
+	RateLimits map[EndpointCategory]RateLimitWindow
+	// Latest measurement of the local clock's skew, if any.
+	ClockSkew *ClockSkew
+	// Time taken for submitted orders to be acknowledged.
+	OrderLatency LatencyStats
+}
+
+// Stats returns a snapshot of the client's API usage.
+func (tc *Client) Stats() Stats {
+	stats := Stats{
+		RateLimits:   tc.rateLimits.snapshot(),
+		OrderLatency: tc.OrderLatency(),
+	}
+	if skew, ok := tc.ClockSkew(); ok {
+		stats.ClockSkew = &skew
+	}
+	return stats
+}
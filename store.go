@@ -0,0 +1,81 @@
+package tradier
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Record is a timestamped value persisted in a Store.
+type Record struct {
+	Time time.Time
+	Data []byte
+}
+
+// Store persists timestamped records under string keys.
+type Store interface {
+	// Append adds a record to the records stored under key.
+	Append(key string, rec Record) error
+	// Range returns the records stored under key with start <= Time < end,
+	// ordered by time. A zero end is unbounded.
+	Range(key string, start, end time.Time) ([]Record, error)
+	// Keys returns the stored keys having the given prefix.
+	Keys(prefix string) ([]string, error)
+}
+
+// MemoryStore is a Store that keeps all records in memory.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[string][]Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string][]Record),
+	}
+}
+
+func (ms *MemoryStore) Append(key string, rec Record) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	records := ms.records[key]
+	i := sort.Search(len(records), func(i int) bool { return records[i].Time.After(rec.Time) })
+	records = append(records, Record{})
+	copy(records[i+1:], records[i:])
+	records[i] = rec
+	ms.records[key] = records
+	return nil
+}
+
+func (ms *MemoryStore) Range(key string, start, end time.Time) ([]Record, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var result []Record
+	for _, rec := range ms.records[key] {
+		if rec.Time.Before(start) {
+			continue
+		}
+		if !end.IsZero() && !rec.Time.Before(end) {
+			break
+		}
+		result = append(result, rec)
+	}
+	return result, nil
+}
+
+func (ms *MemoryStore) Keys(prefix string) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var keys []string
+	for key := range ms.records {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
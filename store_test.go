@@ -0,0 +1,38 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	base := time.Date(2021, time.January, 4, 9, 30, 0, 0, time.UTC)
+	ms := NewMemoryStore()
+	assert.NoError(t, ms.Append("quotes/SPY", Record{Time: base.Add(2 * time.Minute), Data: []byte("c")}))
+	assert.NoError(t, ms.Append("quotes/SPY", Record{Time: base, Data: []byte("a")}))
+	assert.NoError(t, ms.Append("quotes/SPY", Record{Time: base.Add(time.Minute), Data: []byte("b")}))
+	assert.NoError(t, ms.Append("fundamentals/SPY", Record{Time: base}))
+
+	t.Run("Range is ordered and half open", func(t *testing.T) {
+		records, err := ms.Range("quotes/SPY", base, base.Add(2*time.Minute))
+		assert.NoError(t, err)
+		if assert.Len(t, records, 2) {
+			assert.Equal(t, "a", string(records[0].Data))
+			assert.Equal(t, "b", string(records[1].Data))
+		}
+	})
+
+	t.Run("Unbounded range", func(t *testing.T) {
+		records, err := ms.Range("quotes/SPY", base.Add(time.Minute), time.Time{})
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+	})
+
+	t.Run("Keys by prefix", func(t *testing.T) {
+		keys, err := ms.Keys("quotes/")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"quotes/SPY"}, keys)
+	})
+}
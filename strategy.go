@@ -0,0 +1,317 @@
+package tradier
+
+import (
+	"math"
+	"sort"
+)
+
+// Option strategies recognized by RecognizeStrategies.
+const (
+	Vertical     = "vertical"
+	IronCondor   = "iron_condor"
+	CoveredCall  = "covered_call"
+	Calendar     = "calendar"
+	SingleOption = "single"
+)
+
+// StrategyLeg is the part of a position allocated to a strategy.
+// Quantity is signed like Position.Quantity, and is smaller than the
+// position's if the position is split between several strategies.
+type StrategyLeg struct {
+	Position *Position
+	// Contract is the zero value for the stock leg of a covered call.
+	Contract OptionSymbol
+	Quantity float64
+}
+
+// Strategy is a group of positions on one underlying that form a named strategy.
+type Strategy struct {
+	Name       string
+	Underlying string
+	// Number of units of the strategy, e.g. the number of spreads.
+	Quantity float64
+	Legs     []StrategyLeg
+}
+
+// Positions returns the legs as positions with their allocated quantities and
+// cost basis, e.g. for closing the strategy with CloseSpread.
+func (s Strategy) Positions() []*Position {
+	positions := make([]*Position, len(s.Legs))
+	for i, leg := range s.Legs {
+		p := *leg.Position
+		p.CostBasis = leg.CostBasis()
+		p.Quantity = leg.Quantity
+		positions[i] = &p
+	}
+	return positions
+}
+
+// CostBasis returns the leg's share of its position's cost basis.
+func (leg StrategyLeg) CostBasis() float64 {
+	if leg.Position.Quantity == 0 {
+		return 0
+	}
+	return leg.Position.CostBasis * leg.Quantity / leg.Position.Quantity
+}
+
+// CostBasis returns the total cost basis of the strategy's legs.
+func (s Strategy) CostBasis() float64 {
+	var total float64
+	for _, leg := range s.Legs {
+		total += leg.CostBasis()
+	}
+	return total
+}
+
+// MarketValue returns the value of the legs at the mid of the given quotes,
+// keyed by symbol. Legs without a quote are valued at zero.
+func (s Strategy) MarketValue(quotes map[string]*Quote) float64 {
+	var total float64
+	for _, leg := range s.Legs {
+		if q, ok := quotes[leg.Position.Symbol]; ok {
+			total += (q.Bid + q.Ask) / 2 * leg.Quantity * contractMultiplier(leg.Position.Symbol)
+		}
+	}
+	return total
+}
+
+// StrategyQuotes returns the quotes of the legs of the strategies, keyed by
+// symbol, for MarketValue and Greeks.
+func StrategyQuotes(source QuoteSource, strategies []Strategy) (map[string]*Quote, error) {
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, s := range strategies {
+		for _, leg := range s.Legs {
+			if symbol := leg.Position.Symbol; !seen[symbol] {
+				seen[symbol] = true
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+	quotes := make(map[string]*Quote, len(symbols))
+	if len(symbols) == 0 {
+		return quotes, nil
+	}
+
+	result, err := source.GetQuotes(symbols)
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range result {
+		quotes[q.Symbol] = q
+	}
+	return quotes, nil
+}
+
+// Greeks returns the position greeks of the strategy in share-equivalent
+// terms, from quotes with greeks keyed by symbol. The stock leg of a
+// covered call has a delta of one per share.
+func (s Strategy) Greeks(quotes map[string]*Quote) Greeks {
+	var g Greeks
+	for _, leg := range s.Legs {
+		if leg.Contract.Underlying == "" {
+			g.Delta += leg.Quantity
+			continue
+		}
+		q, ok := quotes[leg.Position.Symbol]
+		if !ok || q.Greeks == nil {
+			continue
+		}
+		scale := leg.Quantity * contractMultiplier(leg.Position.Symbol)
+		g.Delta += q.Greeks.Delta * scale
+		g.Gamma += q.Greeks.Gamma * scale
+		g.Theta += q.Greeks.Theta * scale
+		g.Vega += q.Greeks.Vega * scale
+		g.Rho += q.Greeks.Rho * scale
+	}
+	return g
+}
+
+type strategyCandidate struct {
+	position  *Position
+	contract  OptionSymbol
+	isOption  bool
+	remaining float64
+}
+
+// RecognizeStrategies groups option positions into strategies. Legs are
+// matched greedily, preferring iron condors, then verticals, calendars and
+// covered calls, and pairing the nearest strikes or expirations. Options that
+// do not form part of a strategy are returned as single option strategies.
+// Stock positions are only included as the stock leg of covered calls.
+func RecognizeStrategies(positions []*Position) []Strategy {
+	byUnderlying := make(map[string][]*strategyCandidate)
+	var underlyings []string
+	for _, p := range positions {
+		if p.Quantity == 0 {
+			continue
+		}
+		c := &strategyCandidate{position: p, remaining: p.Quantity}
+		underlying := p.Symbol
+		if contract, err := ParseOptionSymbol(p.Symbol); err == nil {
+			c.contract = contract
+			c.isOption = true
+			underlying = contract.Underlying
+		}
+		if _, ok := byUnderlying[underlying]; !ok {
+			underlyings = append(underlyings, underlying)
+		}
+		byUnderlying[underlying] = append(byUnderlying[underlying], c)
+	}
+	sort.Strings(underlyings)
+
+	var strategies []Strategy
+	for _, underlying := range underlyings {
+		candidates := byUnderlying[underlying]
+		sort.SliceStable(candidates, func(i, j int) bool {
+			a, b := candidates[i].contract, candidates[j].contract
+			if !a.Expiration.Equal(b.Expiration) {
+				return a.Expiration.Before(b.Expiration)
+			}
+			return a.Strike < b.Strike
+		})
+		strategies = append(strategies, recognizeUnderlying(underlying, candidates)...)
+	}
+	return strategies
+}
+
+func recognizeUnderlying(underlying string, candidates []*strategyCandidate) []Strategy {
+	var strategies []Strategy
+	add := func(name string, quantity float64, legs ...*strategyCandidate) {
+		// Stock legs are allocated one contract's worth of shares per unit.
+		shares := 1.0
+		for _, c := range legs {
+			if c.isOption {
+				shares = contractMultiplier(c.position.Symbol)
+			}
+		}
+
+		s := Strategy{Name: name, Underlying: underlying, Quantity: quantity}
+		for _, c := range legs {
+			allocated := math.Copysign(quantity, c.remaining)
+			if !c.isOption {
+				allocated *= shares
+			}
+			c.remaining -= allocated
+			s.Legs = append(s.Legs, StrategyLeg{Position: c.position, Contract: c.contract, Quantity: allocated})
+		}
+		strategies = append(strategies, s)
+	}
+
+	// The nearest unallocated option that matches, by distance.
+	nearest := func(match func(o *strategyCandidate) bool, distance func(o *strategyCandidate) float64) *strategyCandidate {
+		var best *strategyCandidate
+		for _, o := range candidates {
+			if o.remaining != 0 && o.isOption && match(o) && (best == nil || distance(o) < distance(best)) {
+				best = o
+			}
+		}
+		return best
+	}
+	shortOptions := func(optionType string) []*strategyCandidate {
+		var shorts []*strategyCandidate
+		for _, c := range candidates {
+			if c.isOption && c.remaining < 0 && (optionType == "" || c.contract.OptionType == optionType) {
+				shorts = append(shorts, c)
+			}
+		}
+		return shorts
+	}
+
+	// Iron condors: a short put and short call above it, each protected by a
+	// long option further out of the money, all with the same expiration.
+	for _, sp := range shortOptions(Put) {
+		for sp.remaining < 0 {
+			exp := sp.contract.Expiration
+			lp := nearest(func(o *strategyCandidate) bool {
+				return o.remaining > 0 && o.contract.OptionType == Put && o.contract.Expiration.Equal(exp) && o.contract.Strike < sp.contract.Strike
+			}, func(o *strategyCandidate) float64 { return sp.contract.Strike - o.contract.Strike })
+			sc := nearest(func(o *strategyCandidate) bool {
+				return o.remaining < 0 && o.contract.OptionType == Call && o.contract.Expiration.Equal(exp) && o.contract.Strike >= sp.contract.Strike
+			}, func(o *strategyCandidate) float64 { return o.contract.Strike - sp.contract.Strike })
+			if lp == nil || sc == nil {
+				break
+			}
+			lc := nearest(func(o *strategyCandidate) bool {
+				return o.remaining > 0 && o.contract.OptionType == Call && o.contract.Expiration.Equal(exp) && o.contract.Strike > sc.contract.Strike
+			}, func(o *strategyCandidate) float64 { return o.contract.Strike - sc.contract.Strike })
+			if lc == nil {
+				break
+			}
+			add(IronCondor, minAbs(lp.remaining, sp.remaining, sc.remaining, lc.remaining), lp, sp, sc, lc)
+		}
+	}
+
+	// Verticals: a short and long option of the same type and expiration.
+	for _, s := range shortOptions("") {
+		for s.remaining < 0 {
+			l := nearest(func(o *strategyCandidate) bool {
+				return o.remaining > 0 && o.contract.OptionType == s.contract.OptionType &&
+					o.contract.Expiration.Equal(s.contract.Expiration) && o.contract.Strike != s.contract.Strike
+			}, func(o *strategyCandidate) float64 { return math.Abs(o.contract.Strike - s.contract.Strike) })
+			if l == nil {
+				break
+			}
+			legs := []*strategyCandidate{s, l}
+			if l.contract.Strike < s.contract.Strike {
+				legs = []*strategyCandidate{l, s}
+			}
+			add(Vertical, minAbs(s.remaining, l.remaining), legs...)
+		}
+	}
+
+	// Calendars: a short and long option of the same type and strike.
+	for _, s := range shortOptions("") {
+		for s.remaining < 0 {
+			l := nearest(func(o *strategyCandidate) bool {
+				return o.remaining > 0 && o.contract.OptionType == s.contract.OptionType &&
+					o.contract.Strike == s.contract.Strike && !o.contract.Expiration.Equal(s.contract.Expiration)
+			}, func(o *strategyCandidate) float64 {
+				return math.Abs(o.contract.Expiration.Sub(s.contract.Expiration).Hours())
+			})
+			if l == nil {
+				break
+			}
+			legs := []*strategyCandidate{s, l}
+			if l.contract.Expiration.Before(s.contract.Expiration) {
+				legs = []*strategyCandidate{l, s}
+			}
+			add(Calendar, minAbs(s.remaining, l.remaining), legs...)
+		}
+	}
+
+	// Covered calls: short calls covered by long stock.
+	for _, sc := range shortOptions(Call) {
+		multiplier := contractMultiplier(sc.position.Symbol)
+		for _, stock := range candidates {
+			if stock.isOption || stock.remaining < multiplier || sc.remaining == 0 {
+				continue
+			}
+			add(CoveredCall, math.Min(math.Abs(sc.remaining), math.Floor(stock.remaining/multiplier)), stock, sc)
+		}
+	}
+
+	for _, c := range candidates {
+		if c.isOption && c.remaining != 0 {
+			add(SingleOption, math.Abs(c.remaining), c)
+		}
+	}
+	return strategies
+}
+
+func minAbs(values ...float64) float64 {
+	result := math.Inf(1)
+	for _, v := range values {
+		result = math.Min(result, math.Abs(v))
+	}
+	return result
+}
+
+// GetStrategies returns the selected account's option positions grouped into strategies.
+func (tc *Client) GetStrategies() ([]Strategy, error) {
+	positions, err := tc.GetAccountPositions()
+	if err != nil {
+		return nil, err
+	}
+	return RecognizeStrategies(positions), nil
+}
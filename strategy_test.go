@@ -0,0 +1,91 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecognizeStrategies(t *testing.T) {
+	t.Run("Iron condor and vertical", func(t *testing.T) {
+		positions := []*Position{
+			{Symbol: "SPY210219C00400000", Quantity: -2, CostBasis: -300},
+			{Symbol: "SPY210219P00350000", Quantity: 2},
+			{Symbol: "SPY210219P00360000", Quantity: -3, CostBasis: -600},
+			{Symbol: "SPY210219C00410000", Quantity: 2},
+			{Symbol: "SPY210219P00345000", Quantity: 1},
+		}
+		strategies := RecognizeStrategies(positions)
+		if assert.Len(t, strategies, 2) {
+			condor := strategies[0]
+			assert.Equal(t, IronCondor, condor.Name)
+			assert.Equal(t, "SPY", condor.Underlying)
+			assert.Equal(t, 2.0, condor.Quantity)
+			if assert.Len(t, condor.Legs, 4) {
+				assert.Equal(t, "SPY210219P00350000", condor.Legs[0].Position.Symbol)
+				assert.Equal(t, -2.0, condor.Legs[1].Quantity)
+				assert.Equal(t, -400.0, condor.Legs[1].CostBasis())
+			}
+
+			// The rest of the short put is spread against the lower long put.
+			vertical := strategies[1]
+			assert.Equal(t, Vertical, vertical.Name)
+			assert.Equal(t, 1.0, vertical.Quantity)
+			if assert.Len(t, vertical.Legs, 2) {
+				assert.Equal(t, "SPY210219P00345000", vertical.Legs[0].Position.Symbol)
+				assert.Equal(t, "SPY210219P00360000", vertical.Legs[1].Position.Symbol)
+			}
+		}
+	})
+
+	t.Run("Calendar, covered call and single", func(t *testing.T) {
+		positions := []*Position{
+			{Symbol: "AAPL", Quantity: 250},
+			{Symbol: "AAPL210319C00150000", Quantity: -3},
+			{Symbol: "SPY210219P00360000", Quantity: -1},
+			{Symbol: "SPY210319P00360000", Quantity: 1},
+			{Symbol: "SPY210319C00420000", Quantity: 1},
+		}
+		strategies := RecognizeStrategies(positions)
+		if assert.Len(t, strategies, 4) {
+			assert.Equal(t, CoveredCall, strategies[0].Name)
+			assert.Equal(t, 2.0, strategies[0].Quantity)
+			assert.Equal(t, 200.0, strategies[0].Legs[0].Quantity)
+			assert.Equal(t, -2.0, strategies[0].Legs[1].Quantity)
+
+			assert.Equal(t, SingleOption, strategies[1].Name)
+			assert.Equal(t, "AAPL210319C00150000", strategies[1].Legs[0].Position.Symbol)
+			assert.Equal(t, -1.0, strategies[1].Legs[0].Quantity)
+
+			assert.Equal(t, Calendar, strategies[2].Name)
+			assert.Equal(t, "SPY210219P00360000", strategies[2].Legs[0].Position.Symbol)
+			assert.Equal(t, SingleOption, strategies[3].Name)
+		}
+	})
+}
+
+func TestStrategy_Greeks(t *testing.T) {
+	strategies := RecognizeStrategies([]*Position{
+		{Symbol: "SPY210219P00360000", Quantity: -2, CostBasis: -440},
+		{Symbol: "SPY210219P00355000", Quantity: 2, CostBasis: 300},
+	})
+	if !assert.Len(t, strategies, 1) {
+		return
+	}
+	quotes := map[string]*Quote{
+		"SPY210219P00360000": {Bid: 2.0, Ask: 2.2, Greeks: &Greeks{Delta: -0.30, Theta: -0.05}},
+		"SPY210219P00355000": {Bid: 1.4, Ask: 1.6, Greeks: &Greeks{Delta: -0.20, Theta: -0.04}},
+	}
+
+	s := strategies[0]
+	greeks := s.Greeks(quotes)
+	assert.InDelta(t, 20.0, greeks.Delta, 1e-9)
+	assert.InDelta(t, 2.0, greeks.Theta, 1e-9)
+	assert.InDelta(t, -140.0, s.CostBasis(), 1e-9)
+	assert.InDelta(t, -120.0, s.MarketValue(quotes), 1e-9)
+
+	order, err := BuildClosingSpreadOrder(s.Positions(), quotes)
+	assert.NoError(t, err)
+	assert.Equal(t, Debit, order.Type)
+	assert.Equal(t, 0.6, order.Price)
+}
@@ -4,6 +4,11 @@ import (
 	"bufio"
 	"encoding/json"
 	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 // StreamEvent is used to unmarshal stream events before they are demuxed.
@@ -37,6 +42,24 @@ type QuoteEvent struct {
 	AskDateMs   int64  `json:"askdate,string"`
 }
 
+// BidDate returns the time of the bid.
+func (qe *QuoteEvent) BidDate() time.Time {
+	return timeFromMs(qe.BidDateMs)
+}
+
+// AskDate returns the time of the ask.
+func (qe *QuoteEvent) AskDate() time.Time {
+	return timeFromMs(qe.AskDateMs)
+}
+
+// Date returns the time of the most recent side of the quote.
+func (qe *QuoteEvent) Date() time.Time {
+	if qe.AskDateMs > qe.BidDateMs {
+		return qe.AskDate()
+	}
+	return qe.BidDate()
+}
+
 type TimeSaleEvent struct {
 	Symbol     string
 	Exchange   string  `json:"exch"`
@@ -52,6 +75,11 @@ type TimeSaleEvent struct {
 	Session    string
 }
 
+// Date returns the time of the sale.
+func (tse *TimeSaleEvent) Date() time.Time {
+	return timeFromMs(tse.DateMs)
+}
+
 type TradeEvent struct {
 	Symbol           string
 	Exchange         string  `json:"exch"`
@@ -62,12 +90,19 @@ type TradeEvent struct {
 	DateMs           int64   `json:"date,string"`
 }
 
+// Date returns the time of the trade.
+func (te *TradeEvent) Date() time.Time {
+	return timeFromMs(te.DateMs)
+}
+
 type SummaryEvent struct {
 	Symbol        string
 	Open          float64 `json:",string"`
 	High          float64 `json:",string"`
 	Low           float64 `json:",string"`
 	PreviousClose float64 `json:"prevClose,string"`
+	// Only sent after the session has closed.
+	Close float64 `json:",string,omitempty"`
 }
 
 // MarketEventStream scans the newline-delimited market stream
@@ -76,6 +111,10 @@ type MarketEventStream struct {
 	// A message on this channel indicates to the http consumer to shutdown the stream.
 	// All channels will be closed by the goroutine that owns this stream.
 	closeChan chan struct{}
+	dropped   int64
+
+	mu  sync.Mutex
+	err error
 }
 
 func NewMarketEventStream(input io.ReadCloser, output chan *StreamEvent) *MarketEventStream {
@@ -90,6 +129,18 @@ func (mes *MarketEventStream) Stop() {
 	close(mes.closeChan)
 }
 
+// Dropped returns the number of events dropped because the output channel was full.
+func (mes *MarketEventStream) Dropped() int64 {
+	return atomic.LoadInt64(&mes.dropped)
+}
+
+// Err returns the error that ended the stream, such as ErrStreamStalled, if any.
+func (mes *MarketEventStream) Err() error {
+	mes.mu.Lock()
+	defer mes.mu.Unlock()
+	return mes.err
+}
+
 func (mes *MarketEventStream) consumeEvents(
 	input io.ReadCloser,
 	output chan *StreamEvent) {
@@ -98,22 +149,23 @@ func (mes *MarketEventStream) consumeEvents(
 	defer close(output)
 
 	for scanner.Scan() {
+		// Events that cannot be decoded are delivered with their Error set.
 		event := &StreamEvent{}
-		if err := UnmarshalStreamEvent(scanner.Bytes(), event); err != nil {
-			Logger.Println(err)
-		}
+		UnmarshalStreamEvent(scanner.Bytes(), event)
 
 		select {
 		case output <- event:
 		case <-mes.closeChan:
 			return
 		default:
-			Logger.Println("stream output channel is full, dropping stream event")
+			atomic.AddInt64(&mes.dropped, 1)
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		Logger.Println(err)
+		mes.mu.Lock()
+		mes.err = err
+		mes.mu.Unlock()
 	}
 }
 
@@ -140,3 +192,86 @@ func DecodeSummary(e *StreamEvent) (*SummaryEvent, error) {
 	err := json.Unmarshal(e.Message, s)
 	return s, err
 }
+
+// MarketEvent is a decoded market stream event: one of *QuoteEvent,
+// *TradeEvent, *SummaryEvent or *TimeSaleEvent.
+type MarketEvent interface {
+	// EventType returns the stream event type, e.g. "quote".
+	EventType() string
+	EventSymbol() string
+}
+
+func (qe *QuoteEvent) EventType() string       { return "quote" }
+func (qe *QuoteEvent) EventSymbol() string     { return qe.Symbol }
+func (te *TradeEvent) EventType() string       { return "trade" }
+func (te *TradeEvent) EventSymbol() string     { return te.Symbol }
+func (se *SummaryEvent) EventType() string     { return "summary" }
+func (se *SummaryEvent) EventSymbol() string   { return se.Symbol }
+func (tse *TimeSaleEvent) EventType() string   { return "timesale" }
+func (tse *TimeSaleEvent) EventSymbol() string { return tse.Symbol }
+
+// DecodeMarketEvent decodes the type-specific message of a stream event.
+// Events of other types are returned as nil with no error.
+func DecodeMarketEvent(e *StreamEvent) (MarketEvent, error) {
+	var event MarketEvent
+	var err error
+	switch e.Type {
+	case "quote":
+		event, err = DecodeQuote(e)
+	case "trade":
+		event, err = DecodeTrade(e)
+	case "summary":
+		event, err = DecodeSummary(e)
+	case "timesale":
+		event, err = DecodeTimeSale(e)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding %s: %v", e.Type, string(e.Message))
+	}
+	return event, nil
+}
+
+// Number of errors buffered by DecodeMarketEvents before further errors are dropped.
+const decodeErrorBuffer = 16
+
+// DecodeMarketEvents reads the newline-delimited market stream from r, such as
+// returned by StreamMarketEvents, and delivers each event as its typed struct.
+// Events that cannot be decoded are reported on the error channel, which is
+// buffered so that it need not be drained; errors are dropped if it is full.
+// Both channels are closed when r is exhausted.
+func DecodeMarketEvents(r io.Reader) (<-chan MarketEvent, <-chan error) {
+	events := make(chan MarketEvent)
+	errs := make(chan error, decodeErrorBuffer)
+	reportError := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			se := &StreamEvent{}
+			if err := UnmarshalStreamEvent(scanner.Bytes(), se); err != nil {
+				reportError(errors.Wrapf(err, "error decoding stream event: %v", scanner.Text()))
+				continue
+			}
+			event, err := DecodeMarketEvent(se)
+			if err != nil {
+				reportError(err)
+			} else if event != nil {
+				events <- event
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			reportError(err)
+		}
+	}()
+	return events, errs
+}
@@ -0,0 +1,55 @@
+package tradier
+
+// StreamHub shares a single upstream market stream between multiple
+// subscribers, each with its own symbols and filter, so that running several
+// strategies requires only one streaming session.
+//
+// The upstream stream is resubscribed to the union of the subscriptions
+// whenever they change, and reconnected if it is disconnected.
+type StreamHub struct {
+	// Stream is the upstream connection, which may be configured before Run.
+	Stream *ManagedMarketStream
+
+	group *ConsumerGroup
+}
+
+// NewStreamHub returns a hub with no subscribers.
+func (tc *Client) NewStreamHub() *StreamHub {
+	sh := &StreamHub{
+		Stream: tc.NewManagedMarketStream(nil),
+		group:  NewConsumerGroup(),
+	}
+	sh.group.SymbolsChanged = sh.Stream.SetSymbols
+	sh.group.FiltersChanged = sh.Stream.SetFilter
+	return sh
+}
+
+// Subscribe adds a subscriber to events of the given types for the given symbols,
+// with a queue of the given size. If filter is empty then all types are received.
+// The subscription can be changed, or closed, with the returned consumer.
+func (sh *StreamHub) Subscribe(name string, symbols []string, filter []Filter, buffer int, policy SlowConsumerPolicy) *Consumer {
+	c := sh.group.Join(name, nil, buffer, policy)
+	c.SetFilter(filter...)
+	c.Subscribe(symbols...)
+	return c
+}
+
+// Symbols returns the symbols subscribed upstream.
+func (sh *StreamHub) Symbols() []string {
+	return sh.group.Symbols()
+}
+
+// Run streams events to the subscribers until stop is closed, and then closes them.
+// An error is returned only if the upstream stream gives up on reconnecting.
+func (sh *StreamHub) Run(stop <-chan struct{}) error {
+	events := make(chan *StreamEvent)
+	done := make(chan struct{})
+	go func() {
+		sh.group.Run(events)
+		close(done)
+	}()
+
+	err := sh.Stream.Run(events, stop)
+	<-done
+	return err
+}
@@ -0,0 +1,71 @@
+package tradier
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamHub(t *testing.T) {
+	upstream := make(chan string, 4)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/markets/events/session":
+			fmt.Fprintf(w, `{"stream": {"url": "%s/v1/markets/events", "sessionid": "s"}}`, server.URL)
+		case "/v1/markets/events":
+			symbols, filter := r.FormValue("symbols"), r.FormValue("filter")
+			upstream <- symbols + " " + filter
+			for _, symbol := range strings.Split(symbols, ",") {
+				for _, eventType := range []string{"quote", "trade"} {
+					if filter == "" || strings.Contains(filter, eventType) {
+						fmt.Fprintf(w, `{"type":"%s","symbol":"%s"}`+"\n", eventType, symbol)
+					}
+				}
+			}
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+
+	hub := client.NewStreamHub()
+	a := hub.Subscribe("a", []string{"SPY"}, []Filter{FilterQuote}, 8, DropNewest)
+	b := hub.Subscribe("b", []string{"SPY", "QQQ"}, nil, 8, DropNewest)
+	assert.Equal(t, []string{"QQQ", "SPY"}, hub.Symbols())
+
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- hub.Run(stop) }()
+
+	assert.Equal(t, "QQQ,SPY ", <-upstream, "one upstream stream with all event types")
+	assert.Equal(t, "quote SPY", eventKey(<-a.Events))
+	var received []string
+	for i := 0; i < 4; i++ {
+		received = append(received, eventKey(<-b.Events))
+	}
+	assert.ElementsMatch(t, []string{"quote QQQ", "trade QQQ", "quote SPY", "trade SPY"}, received)
+
+	b.Close()
+	assert.Equal(t, "SPY quote", <-upstream)
+	assert.Equal(t, "quote SPY", eventKey(<-a.Events))
+
+	close(stop)
+	assert.NoError(t, <-done)
+	for e := range a.Events {
+		assert.Equal(t, "quote SPY", eventKey(e), "resubscribed events")
+	}
+	assert.Empty(t, a.Dropped())
+}
+
+func eventKey(e *StreamEvent) string {
+	return e.Type + " " + e.Symbol
+}
@@ -0,0 +1,87 @@
+package tradier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrStreamRateLimited is returned when a streaming session cannot be created
+// because the session creation limit has been reached. A session may be
+// created again after RetryAt.
+type ErrStreamRateLimited struct {
+	RetryAt time.Time
+}
+
+func (e ErrStreamRateLimited) Error() string {
+	return fmt.Sprintf("streaming session creation is rate limited until %v", e.RetryAt.Format(time.RFC3339))
+}
+
+type streamSession struct {
+	SessionId string
+	Url       string
+}
+
+// createStreamSession creates a streaming session at the given path. It is not
+// retried on rate limiting; instead ErrStreamRateLimited reports when it may be.
+func (tc *Client) createStreamSession(ctx context.Context, path string) (streamSession, error) {
+	var result struct {
+		Stream streamSession
+	}
+
+	resp, err := tc.doContext(ctx, "POST", tc.endpoint+path, nil, 0)
+	if err != nil {
+		if te, ok := err.(TradierError); ok {
+			if retryAt := streamRetryAt(te); !retryAt.IsZero() {
+				return result.Stream, ErrStreamRateLimited{RetryAt: retryAt}
+			}
+		}
+		return result.Stream, err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	err = dec.Decode(&result)
+	return result.Stream, err
+}
+
+// createStreamSessionWait creates a streaming session, waiting for the rate
+// limit to expire as many times as necessary until ctx is done.
+func (tc *Client) createStreamSessionWait(ctx context.Context, path string) (streamSession, error) {
+	for {
+		session, err := tc.createStreamSession(ctx, path)
+		limited, ok := err.(ErrStreamRateLimited)
+		if !ok {
+			return session, err
+		}
+
+		tc.logger.Info("Stream session creation rate limited", "retry_at", limited.RetryAt)
+		select {
+		case <-time.After(time.Until(limited.RetryAt)):
+		case <-ctx.Done():
+			return session, ctx.Err()
+		}
+	}
+}
+
+// Determine when a rate limited session creation may be retried,
+// or return the zero time if the error is not due to rate limiting.
+func streamRetryAt(te TradierError) time.Time {
+	if expiry := parseQuotaViolationExpiration(te.Fault.FaultString); !expiry.IsZero() {
+		return expiry
+	}
+	if te.HttpStatusCode != http.StatusTooManyRequests {
+		return time.Time{}
+	}
+
+	if ms, err := strconv.ParseInt(te.Header.Get(rateLimitExpiry), 10, 64); err == nil {
+		return timeFromMs(ms)
+	}
+	if secs, err := strconv.Atoi(te.Header.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	return time.Now().Add(time.Minute)
+}
@@ -0,0 +1,50 @@
+package tradier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_createStreamSession(t *testing.T) {
+	var sessions int32
+	var retryAt time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&sessions, 1) {
+		case 1:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "Quota Violation %d", retryAt.UnixNano()/1e6)
+		case 2:
+			w.Header().Set(rateLimitExpiry, strconv.FormatInt(retryAt.UnixNano()/1e6, 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"fault": {"faultstring": "Rate limit exceeded"}}`))
+		default:
+			w.Write([]byte(`{"stream": {"url": "https://stream.tradier.com/v1/markets/events", "sessionid": "c8638963-a6d4-4fb9-9bc6-e25fbd8c60c3"}}`))
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+
+	retryAt = time.Now().Add(time.Hour).Truncate(time.Second)
+	_, err := client.StreamMarketEvents([]string{"SPY"}, nil)
+	if assert.IsType(t, ErrStreamRateLimited{}, err) {
+		assert.Equal(t, retryAt, err.(ErrStreamRateLimited).RetryAt)
+	}
+
+	// Waits for the rate limit to expire and then retries.
+	retryAt = time.Now().Add(50 * time.Millisecond)
+	session, err := client.createStreamSessionWait(context.Background(), "/v1/markets/events/session")
+	assert.NoError(t, err)
+	assert.Equal(t, "c8638963-a6d4-4fb9-9bc6-e25fbd8c60c3", session.SessionId)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&sessions))
+}
@@ -0,0 +1,48 @@
+package tradier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeMarketEvents(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"type":"quote","symbol":"SPY","bid":281.84,"bidsz":60,"bidexch":"M","biddate":"1557757189000","ask":281.85,"asksz":6,"askexch":"Z","askdate":"1557757190000"}`,
+		`{"type":"trade","symbol":"SPY","exch":"J","price":"281.1319","size":"100","cvol":"2504","date":"1557757183222","last":"281.1319"}`,
+		`{"type":"heartbeat"}`,
+		`{"type":"summary","symbol":"SPY","open":"282.42","high":"283.49","low":"281.07","prevClose":"288.1"}`,
+		`{"type":"timesale","symbol":"SPY","exch":"Q","bid":"281.84","ask":"281.85","last":"281.8401","size":"bad","date":"1557757190000"}`,
+		`not json`,
+		`{"type":"timesale","symbol":"SPY","exch":"Q","bid":"281.84","ask":"281.85","last":"281.8401","size":"100","date":"1557757190000","seq":5}`,
+	}, "\n")
+
+	events, errs := DecodeMarketEvents(strings.NewReader(stream))
+	var decoded []MarketEvent
+	for event := range events {
+		decoded = append(decoded, event)
+	}
+	var decodeErrors []error
+	for err := range errs {
+		decodeErrors = append(decodeErrors, err)
+	}
+
+	if assert.Len(t, decoded, 4) {
+		if q, ok := decoded[0].(*QuoteEvent); assert.True(t, ok) {
+			assert.Equal(t, 281.84, q.Bid)
+			assert.Equal(t, int64(1557757190000), q.AskDateMs)
+		}
+		if trade, ok := decoded[1].(*TradeEvent); assert.True(t, ok) {
+			assert.Equal(t, int64(2504), trade.CumulativeVolume)
+		}
+		assert.Equal(t, "summary", decoded[2].EventType())
+		if ts, ok := decoded[3].(*TimeSaleEvent); assert.True(t, ok) {
+			assert.Equal(t, int64(5), ts.Seq)
+		}
+		for _, event := range decoded {
+			assert.Equal(t, "SPY", event.EventSymbol())
+		}
+	}
+	assert.Len(t, decodeErrors, 2)
+}
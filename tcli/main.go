@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 
 	"github.com/gnagel/go-tradier"
 )
@@ -104,8 +105,22 @@ func history(client *tradier.Client) {
 	}
 }
 
+func dailyPnL(client *tradier.Client, feePerOrder float64) {
+	report, err := client.GetDailyPnL(func(order *tradier.Order) float64 {
+		return feePerOrder
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := report.WriteCSV(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
 func main() {
-	subcommand := flag.String("command", "positions", "Command to run (positions, gainloss, openorders)")
+	subcommand := flag.String("command", "positions", "Command to run (positions, gainloss, openorders, history, pnl)")
+	feePerOrder := flag.Float64("fee", 0, "Fee charged per filled order, for the pnl command")
 	apiKey := flag.String("tradier.apikey", "", "Tradier API key")
 	account := flag.String("tradier.account", "", "Tradier account ID")
 	flag.Parse()
@@ -123,6 +138,8 @@ func main() {
 		openOrders(client)
 	case "history":
 		history(client)
+	case "pnl":
+		dailyPnL(client, *feePerOrder)
 	default:
 		log.Fatal("unknown command: ", *subcommand)
 	}
@@ -0,0 +1,331 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ChainProvider is the subset of the Client used to resolve option templates.
+// It is part of MarketDataSource.
+type ChainProvider interface {
+	GetOptionExpirationDates(symbol string) ([]time.Time, error)
+	GetOptionChainWithGreeks(symbol string, expiration time.Time) ([]*Quote, error)
+}
+
+// ExpirySelector picks one of the available expiration dates.
+type ExpirySelector func(now time.Time, expirations []time.Time) (time.Time, error)
+
+// ExpiryNearestDTE selects the expiration closest to the given days to expiration.
+func ExpiryNearestDTE(days int) ExpirySelector {
+	return func(now time.Time, expirations []time.Time) (time.Time, error) {
+		if len(expirations) == 0 {
+			return time.Time{}, fmt.Errorf("no expirations available")
+		}
+
+		target := now.AddDate(0, 0, days)
+		best := expirations[0]
+		for _, exp := range expirations[1:] {
+			if absDuration(exp.Sub(target)) < absDuration(best.Sub(target)) {
+				best = exp
+			}
+		}
+		return best, nil
+	}
+}
+
+// StrikeContext is passed to a StrikeSelector when rendering a template leg.
+// Chain contains only contracts of the leg's option type, sorted by strike.
+// Selected contains the contracts chosen for the preceding legs.
+type StrikeContext struct {
+	Chain    []*Quote
+	Selected []*Quote
+}
+
+// StrikeSelector picks a contract for a template leg.
+type StrikeSelector func(sc StrikeContext) (*Quote, error)
+
+// StrikeByDelta selects the contract whose delta is closest to the given
+// delta (negative for puts). The chain must have been fetched with greeks.
+func StrikeByDelta(delta float64) StrikeSelector {
+	return func(sc StrikeContext) (*Quote, error) {
+		var best *Quote
+		for _, q := range sc.Chain {
+			if q.Greeks == nil {
+				continue
+			}
+			if best == nil || math.Abs(q.Greeks.Delta-delta) < math.Abs(best.Greeks.Delta-delta) {
+				best = q
+			}
+		}
+		if best == nil {
+			return nil, fmt.Errorf("no contracts with greeks to select %v delta", delta)
+		}
+		return best, nil
+	}
+}
+
+// StrikeWidthFrom selects the contract whose strike is closest to the strike
+// selected for a preceding leg plus the given width (negative for lower strikes).
+func StrikeWidthFrom(leg int, width float64) StrikeSelector {
+	return func(sc StrikeContext) (*Quote, error) {
+		if leg < 0 || leg >= len(sc.Selected) {
+			return nil, fmt.Errorf("leg %d has not been selected", leg)
+		}
+
+		target := sc.Selected[leg].Strike + width
+		var best *Quote
+		for _, q := range sc.Chain {
+			if q.Strike == sc.Selected[leg].Strike {
+				continue
+			}
+			if best == nil || math.Abs(q.Strike-target) < math.Abs(best.Strike-target) {
+				best = q
+			}
+		}
+		if best == nil {
+			return nil, fmt.Errorf("no strike near %v", target)
+		}
+		return best, nil
+	}
+}
+
+// SizingRule returns the number of units to trade given the capital at risk per unit.
+type SizingRule func(env TemplateEnv, unitRisk float64) (float64, error)
+
+// FixedQuantity always trades the given number of units.
+func FixedQuantity(quantity float64) SizingRule {
+	return func(env TemplateEnv, unitRisk float64) (float64, error) {
+		return quantity, nil
+	}
+}
+
+// FractionOfBuyingPower risks the given fraction of the option buying power.
+func FractionOfBuyingPower(fraction float64) SizingRule {
+	return func(env TemplateEnv, unitRisk float64) (float64, error) {
+		if env.Balances == nil {
+			return 0, fmt.Errorf("account balances are required to size by buying power")
+		}
+		if unitRisk <= 0 {
+			return 0, fmt.Errorf("cannot size position with risk per unit %v", unitRisk)
+		}
+
+		buyingPower := env.Balances.Margin.OptionBuyingPower
+		if env.Balances.AccountType == "cash" {
+			buyingPower = env.Balances.Cash.CashAvailable
+		}
+		return math.Floor(fraction * buyingPower / unitRisk), nil
+	}
+}
+
+type Pricing int
+
+const (
+	// PriceAtMid submits a limit (or net credit/debit) order at the mid price.
+	PriceAtMid Pricing = iota
+	// PriceAtMarket submits a market order.
+	PriceAtMarket
+)
+
+// LegTemplate describes how to select a single option leg.
+type LegTemplate struct {
+	Side       string
+	OptionType string
+	Strike     StrikeSelector
+	// Ratio of this leg's quantity to the order quantity. Defaults to 1.
+	Ratio float64
+}
+
+// OrderTemplate is a parameterized option order that is resolved against
+// the current chain and account balances when rendered.
+type OrderTemplate struct {
+	Name     string
+	Duration string
+	Expiry   ExpirySelector
+	Legs     []LegTemplate
+	Pricing  Pricing
+	Sizing   SizingRule
+}
+
+// TemplateEnv provides the market and account state used to render a template.
+type TemplateEnv struct {
+	Symbol   string
+	Now      time.Time
+	Balances *AccountBalances
+	Chains   ChainProvider
+}
+
+// PutCreditSpread sells the put nearest the given delta (e.g. 0.30) and buys
+// the put the given width below it, at the expiration nearest dte days out.
+func PutCreditSpread(delta, width float64, dte int, sizing SizingRule) *OrderTemplate {
+	return &OrderTemplate{
+		Name:     fmt.Sprintf("%.0f-delta put credit spread, %d DTE", 100*delta, dte),
+		Duration: Day,
+		Expiry:   ExpiryNearestDTE(dte),
+		Legs: []LegTemplate{
+			{Side: SellToOpen, OptionType: Put, Strike: StrikeByDelta(-math.Abs(delta))},
+			{Side: BuyToOpen, OptionType: Put, Strike: StrikeWidthFrom(0, -math.Abs(width))},
+		},
+		Sizing: sizing,
+	}
+}
+
+// CallCreditSpread sells the call nearest the given delta and buys
+// the call the given width above it, at the expiration nearest dte days out.
+func CallCreditSpread(delta, width float64, dte int, sizing SizingRule) *OrderTemplate {
+	return &OrderTemplate{
+		Name:     fmt.Sprintf("%.0f-delta call credit spread, %d DTE", 100*delta, dte),
+		Duration: Day,
+		Expiry:   ExpiryNearestDTE(dte),
+		Legs: []LegTemplate{
+			{Side: SellToOpen, OptionType: Call, Strike: StrikeByDelta(math.Abs(delta))},
+			{Side: BuyToOpen, OptionType: Call, Strike: StrikeWidthFrom(0, math.Abs(width))},
+		},
+		Sizing: sizing,
+	}
+}
+
+// Render resolves the template into a concrete order.
+func (ot *OrderTemplate) Render(env TemplateEnv) (Order, error) {
+	if len(ot.Legs) == 0 {
+		return Order{}, fmt.Errorf("template %q has no legs", ot.Name)
+	}
+	if ot.Expiry == nil || ot.Sizing == nil {
+		return Order{}, fmt.Errorf("template %q requires an expiry selector and sizing rule", ot.Name)
+	}
+	if env.Now.IsZero() {
+		env.Now = time.Now()
+	}
+
+	expirations, err := env.Chains.GetOptionExpirationDates(env.Symbol)
+	if err != nil {
+		return Order{}, err
+	}
+	expiration, err := ot.Expiry(env.Now, expirations)
+	if err != nil {
+		return Order{}, err
+	}
+	chain, err := env.Chains.GetOptionChainWithGreeks(env.Symbol, expiration)
+	if err != nil {
+		return Order{}, err
+	}
+
+	selected := make([]*Quote, 0, len(ot.Legs))
+	for i, leg := range ot.Legs {
+		q, err := leg.Strike(StrikeContext{
+			Chain:    filterChain(chain, leg.OptionType),
+			Selected: selected,
+		})
+		if err != nil {
+			return Order{}, fmt.Errorf("template %q leg %d: %v", ot.Name, i, err)
+		}
+		selected = append(selected, q)
+	}
+
+	// Net price per unit: positive is a debit, negative a credit.
+	var net float64
+	contractSize := 100.0
+	minStrike, maxStrike := math.Inf(1), math.Inf(-1)
+	for i, leg := range ot.Legs {
+		q := selected[i]
+		mid := (q.Bid + q.Ask) / 2
+		if isBuySide(leg.Side) {
+			net += mid * leg.ratio()
+		} else {
+			net -= mid * leg.ratio()
+		}
+		if q.ContractSize > 0 {
+			contractSize = float64(q.ContractSize)
+		}
+		minStrike = math.Min(minStrike, q.Strike)
+		maxStrike = math.Max(maxStrike, q.Strike)
+	}
+
+	unitRisk := net * contractSize
+	if net <= 0 {
+		if len(ot.Legs) > 1 {
+			unitRisk = (maxStrike - minStrike + net) * contractSize
+		} else {
+			unitRisk = selected[0].Strike * contractSize
+		}
+	}
+	quantity, err := ot.Sizing(env, unitRisk)
+	if err != nil {
+		return Order{}, err
+	}
+	if quantity < 1 {
+		return Order{}, fmt.Errorf("template %q sized to %v units", ot.Name, quantity)
+	}
+
+	order := Order{
+		Symbol:   env.Symbol,
+		Duration: ot.Duration,
+		Type:     MarketOrder,
+	}
+	if len(ot.Legs) == 1 {
+		order.Class = Option
+		order.OptionSymbol = selected[0].Symbol
+		order.Side = ot.Legs[0].Side
+		order.Quantity = quantity * ot.Legs[0].ratio()
+		if ot.Pricing == PriceAtMid {
+			order.Type = LimitOrder
+			order.Price = roundPrice(math.Abs(net))
+		}
+		return order, nil
+	}
+
+	order.Class = Multileg
+	for i, leg := range ot.Legs {
+		order.Legs = append(order.Legs, Order{
+			OptionSymbol: selected[i].Symbol,
+			Side:         leg.Side,
+			Quantity:     quantity * leg.ratio(),
+		})
+	}
+	if ot.Pricing == PriceAtMid {
+		order.Price = roundPrice(math.Abs(net))
+		switch {
+		case order.Price == 0:
+			order.Type = Even
+		case net > 0:
+			order.Type = Debit
+		default:
+			order.Type = Credit
+		}
+	}
+	return order, nil
+}
+
+func (lt LegTemplate) ratio() float64 {
+	if lt.Ratio == 0 {
+		return 1
+	}
+	return lt.Ratio
+}
+
+func filterChain(chain []*Quote, optionType string) []*Quote {
+	result := make([]*Quote, 0, len(chain))
+	for _, q := range chain {
+		if q.OptionType == optionType {
+			result = append(result, q)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Strike < result[j].Strike })
+	return result
+}
+
+func isBuySide(side string) bool {
+	return side == Buy || side == BuyToOpen || side == BuyToClose || side == BuyToCover
+}
+
+func roundPrice(price float64) float64 {
+	return math.Round(100*price) / 100
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
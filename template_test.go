@@ -0,0 +1,69 @@
+package tradier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeChains struct {
+	expirations []time.Time
+	chain       []*Quote
+}
+
+func (fc *fakeChains) GetOptionExpirationDates(symbol string) ([]time.Time, error) {
+	return fc.expirations, nil
+}
+
+func (fc *fakeChains) GetOptionChainWithGreeks(symbol string, expiration time.Time) ([]*Quote, error) {
+	return fc.chain, nil
+}
+
+func TestOrderTemplate_Render(t *testing.T) {
+	now := time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC)
+	chains := &fakeChains{
+		expirations: []time.Time{
+			now.AddDate(0, 0, 11),
+			now.AddDate(0, 0, 46),
+			now.AddDate(0, 0, 74),
+		},
+		chain: []*Quote{
+			{Symbol: "SPY210219P00360000", OptionType: Put, Strike: 360, Bid: 3.00, Ask: 3.20, Greeks: &Greeks{Delta: -0.25}},
+			{Symbol: "SPY210219P00355000", OptionType: Put, Strike: 355, Bid: 2.00, Ask: 2.20, Greeks: &Greeks{Delta: -0.20}},
+			{Symbol: "SPY210219P00365000", OptionType: Put, Strike: 365, Bid: 4.40, Ask: 4.60, Greeks: &Greeks{Delta: -0.31}},
+			{Symbol: "SPY210219C00365000", OptionType: Call, Strike: 365, Bid: 9.00, Ask: 9.20, Greeks: &Greeks{Delta: 0.69}},
+		},
+	}
+
+	t.Run("Put credit spread", func(t *testing.T) {
+		tmpl := PutCreditSpread(0.30, 5, 45, FixedQuantity(2))
+		order, err := tmpl.Render(TemplateEnv{Symbol: "SPY", Now: now, Chains: chains})
+		assert.NoError(t, err)
+		assert.Equal(t, Multileg, order.Class)
+		assert.Equal(t, Credit, order.Type)
+		assert.Equal(t, 1.4, order.Price)
+		if assert.Len(t, order.Legs, 2) {
+			assert.Equal(t, "SPY210219P00365000", order.Legs[0].OptionSymbol)
+			assert.Equal(t, SellToOpen, order.Legs[0].Side)
+			assert.Equal(t, "SPY210219P00360000", order.Legs[1].OptionSymbol)
+			assert.Equal(t, 2.0, order.Legs[1].Quantity)
+		}
+	})
+
+	t.Run("Sized by buying power", func(t *testing.T) {
+		tmpl := PutCreditSpread(0.30, 5, 45, FractionOfBuyingPower(0.1))
+		balances := &AccountBalances{Margin: Margin{OptionBuyingPower: 10000}}
+		order, err := tmpl.Render(TemplateEnv{Symbol: "SPY", Now: now, Chains: chains, Balances: balances})
+		assert.NoError(t, err)
+		// Risk per spread is (5 - 1.40) * 100 = $360, so $1000 buys 2 spreads.
+		assert.Equal(t, 2.0, order.Legs[0].Quantity)
+	})
+
+	t.Run("Too small to size", func(t *testing.T) {
+		tmpl := PutCreditSpread(0.30, 5, 45, FractionOfBuyingPower(0.01))
+		balances := &AccountBalances{Margin: Margin{OptionBuyingPower: 10000}}
+		_, err := tmpl.Render(TemplateEnv{Symbol: "SPY", Now: now, Chains: chains, Balances: balances})
+		assert.Error(t, err)
+	})
+}
@@ -3,6 +3,8 @@ package tradier
 import (
 	"strconv"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // DateTime wraps time.Time and adds flexible implementations for unmarshaling
@@ -62,13 +64,42 @@ func (d *DateTime) UnmarshalJSON(b []byte) error {
 	return d.Set(s)
 }
 
+// MarshalJSON encodes the zero time as null so that it round-trips
+// through UnmarshalJSON unchanged.
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return []byte(strconv.Quote(d.Format(time.RFC3339Nano))), nil
+}
+
+func (d *DateTime) UnmarshalYAML(value *yaml.Node) error {
+	if value.Tag == "!!null" {
+		return nil
+	}
+
+	return d.Set(value.Value)
+}
+
+func (d DateTime) MarshalYAML() (interface{}, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+
+	return d.Format(time.RFC3339Nano), nil
+}
+
 func ParseTimeMs(tsMs string) (time.Time, error) {
 	msecs, err := strconv.ParseInt(tsMs, 10, 64)
 	if err != nil {
 		return time.Time{}, err
 	}
+	return timeFromMs(msecs), nil
+}
+
+func timeFromMs(msecs int64) time.Time {
 	secs := msecs / 1000
 	nsecs := 1000000 * (msecs % 1000)
-	t := time.Unix(secs, nsecs)
-	return t, nil
+	return time.Unix(secs, nsecs)
 }
@@ -34,7 +34,7 @@ func TestDateTime_Set(t *testing.T) {
 
 	t.Run("Parse seconds since epoc", func(t *testing.T) {
 		input := time.Now()
-		ms := fmt.Sprintf("%v%v", input.Unix(), input.Nanosecond()/1000000)
+		ms := fmt.Sprintf("%v%03d", input.Unix(), input.Nanosecond()/1000000)
 
 		value := DateTime{}
 		err := value.Set(ms)
@@ -0,0 +1,60 @@
+package tradier
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// RequestTimeouts are the deadlines of requests, by kind. A zero timeout
+// means no deadline other than that of the request's context.
+//
+// For example, quotes and the clock can be given a short deadline with
+// CategoryMarketData, while downloads of option chains and history get
+// longer to transfer.
+type RequestTimeouts struct {
+	// Deadline of requests that have no more specific timeout.
+	Default time.Duration
+	// Deadlines of the requests in each category, which take precedence
+	// over Default.
+	Categories map[EndpointCategory]time.Duration
+	// Deadline of requests to endpoints with large responses (option chains,
+	// price history, time and sales, account history and gain/loss), which
+	// takes precedence over the category's.
+	Download time.Duration
+}
+
+// Returns the deadline of requests to the endpoint. Streams are never given
+// a deadline.
+func (rt RequestTimeouts) timeout(category EndpointCategory, endpoint string) time.Duration {
+	if isStreamPath(endpoint) {
+		return 0
+	}
+	if rt.Download > 0 && isLargeResponsePath(endpoint) {
+		return rt.Download
+	}
+	if d, ok := rt.Categories[category]; ok {
+		return d
+	}
+	return rt.Default
+}
+
+// withTimeout returns ctx with the timeout, if positive.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// cancelBody cancels the context of a request once its response is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (cb *cancelBody) Close() error {
+	err := cb.ReadCloser.Close()
+	cb.cancel()
+	return err
+}
@@ -0,0 +1,88 @@
+package tradier
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Timeouts(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/markets/clock", "/v1/accounts/VA000/history":
+			time.Sleep(delay)
+			w.Write([]byte(`{"clock": {"state": "open"}, "history": "null"}`))
+		case "/v1/markets/events/session":
+			w.Write([]byte(`{"stream": {"url": "http://` + r.Host + `/v1/markets/events", "sessionid": "1"}}`))
+		case "/v1/markets/events":
+			w.(http.Flusher).Flush()
+			time.Sleep(delay)
+			w.Write([]byte(`{"type": "quote", "symbol": "SPY"}` + "\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	params := DefaultParams("secret-token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.RetryLimit = 0
+	params.Timeouts = RequestTimeouts{
+		Default:    delay / 4,
+		Categories: map[EndpointCategory]time.Duration{CategoryMarketData: delay / 4},
+		Download:   10 * delay,
+	}
+	client := NewClient(params)
+
+	t.Run("Category", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.GetMarketState()
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+		assert.True(t, time.Since(start) < delay)
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		_, err := client.GetAccountHistory(0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Streams", func(t *testing.T) {
+		stream, err := client.StreamMarketEvents([]string{"SPY"}, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer stream.Close()
+		line, err := bufio.NewReader(stream).ReadString('\n')
+		assert.NoError(t, err)
+		assert.Contains(t, line, "SPY")
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		params.Timeouts = RequestTimeouts{}
+		ctx, cancel := context.WithTimeout(context.Background(), delay/4)
+		defer cancel()
+		_, err := NewClient(params).getMarketState(ctx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	})
+}
+
+func TestRequestTimeouts_timeout(t *testing.T) {
+	timeouts := RequestTimeouts{
+		Default:    time.Second,
+		Categories: map[EndpointCategory]time.Duration{CategoryMarketData: 2 * time.Second},
+		Download:   time.Minute,
+	}
+	assert.Equal(t, time.Second, timeouts.timeout(CategoryStandard, "/v1/user/profile"))
+	assert.Equal(t, 2*time.Second, timeouts.timeout(CategoryMarketData, "/v1/markets/quotes"))
+	assert.Equal(t, time.Minute, timeouts.timeout(CategoryMarketData, "/v1/markets/options/chains"))
+	assert.Equal(t, time.Minute, timeouts.timeout(CategoryStandard, "/v1/accounts/{account}/history"))
+	assert.Zero(t, timeouts.timeout(CategoryMarketData, "/v1/markets/events"))
+}
@@ -0,0 +1,116 @@
+package tradier
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Tracer starts the spans of a client's requests and streams, with
+// attributes as alternating keys and values, as for Logger. Request spans are
+// named by method and endpoint, e.g. "GET /v1/accounts/{account}/orders", and
+// have the attributes:
+//
+//	http.method, tradier.endpoint, tradier.category, tradier.account,
+//	tradier.symbols (the number of symbols requested), tradier.attempts,
+//	http.status_code and tradier.rate_limit.available (if reported).
+//
+// Streams have a span from when they are opened until they end, named e.g.
+// "tradier.stream market", with tradier.account and tradier.symbols.
+//
+// An OpenTelemetry tracer can be adapted as shown in the README, so that
+// API calls are traced as children of the spans in the context of a request.
+type Tracer interface {
+	Start(ctx context.Context, name string, keyvals ...interface{}) (context.Context, Span)
+}
+
+// Span is an operation traced by a Tracer.
+type Span interface {
+	// SetAttributes adds attributes to the span.
+	SetAttributes(keyvals ...interface{})
+	// End ends the span, with the error the operation failed with, if any.
+	End(err error)
+}
+
+// Clients without a tracer do not trace.
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, keyvals ...interface{}) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(keyvals ...interface{}) {}
+func (noopSpan) End(err error)                        {}
+
+// endpointName returns the path of a request with the account number and
+// order ids replaced, e.g. "/v1/accounts/{account}/orders/{id}", so that
+// requests to the same endpoint share a span name.
+func endpointName(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if i > 0 && parts[i-1] == "accounts" && part != "events" {
+			parts[i] = "{account}"
+		} else if _, err := strconv.Atoi(part); err == nil {
+			parts[i] = "{id}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// requestSymbols returns the number of symbols requested, in the symbols
+// parameter of the URL or form.
+func requestSymbols(u *url.URL, form url.Values) int {
+	symbols := form.Get("symbols")
+	if symbols == "" && u != nil {
+		symbols = u.Query().Get("symbols")
+	}
+	if symbols == "" {
+		return 0
+	}
+	return strings.Count(symbols, ",") + 1
+}
+
+// startStreamSpan starts the span of a stream of the given kind.
+func (tc *Client) startStreamSpan(ctx context.Context, kind string, symbols []string) (context.Context, Span) {
+	return tc.tracer.Start(ctx, "tradier.stream "+kind,
+		"tradier.account", tc.account, "tradier.symbols", len(symbols))
+}
+
+// spanReader ends the span of a stream when the stream is closed, with the
+// error reading it failed with, if any.
+type spanReader struct {
+	io.ReadCloser
+	span Span
+
+	mu    sync.Mutex
+	err   error
+	ended bool
+}
+
+func (sr *spanReader) Read(p []byte) (int, error) {
+	n, err := sr.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		sr.mu.Lock()
+		if sr.err == nil {
+			sr.err = err
+		}
+		sr.mu.Unlock()
+	}
+	return n, err
+}
+
+func (sr *spanReader) Close() error {
+	err := sr.ReadCloser.Close()
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if !sr.ended {
+		sr.ended = true
+		sr.span.End(sr.err)
+	}
+	return err
+}
@@ -0,0 +1,149 @@
+package tradier
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTracer records the spans it starts.
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+type fakeSpanKey struct{}
+
+type fakeSpan struct {
+	tracer     *fakeTracer
+	name       string
+	parent     *fakeSpan
+	attributes map[string]interface{}
+	ended      bool
+	err        error
+}
+
+func (ft *fakeTracer) Start(ctx context.Context, name string, keyvals ...interface{}) (context.Context, Span) {
+	parent, _ := ctx.Value(fakeSpanKey{}).(*fakeSpan)
+	span := &fakeSpan{tracer: ft, name: name, parent: parent, attributes: make(map[string]interface{})}
+	span.SetAttributes(keyvals...)
+	ft.mu.Lock()
+	ft.spans = append(ft.spans, span)
+	ft.mu.Unlock()
+	return context.WithValue(ctx, fakeSpanKey{}, span), span
+}
+
+func (ft *fakeTracer) span(name string) *fakeSpan {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	for _, s := range ft.spans {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (fs *fakeSpan) SetAttributes(keyvals ...interface{}) {
+	fs.tracer.mu.Lock()
+	defer fs.tracer.mu.Unlock()
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		fs.attributes[keyvals[i].(string)] = keyvals[i+1]
+	}
+}
+
+func (fs *fakeSpan) End(err error) {
+	fs.tracer.mu.Lock()
+	defer fs.tracer.mu.Unlock()
+	fs.ended, fs.err = true, err
+}
+
+func TestClient_Tracer(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/markets/quotes":
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Header().Set(rateLimitAvailable, "118")
+			w.Write([]byte(`{"quotes": {"quote": [{"symbol": "SPY"}, {"symbol": "QQQ"}]}}`))
+		case "/v1/markets/events/session":
+			w.Write([]byte(`{"stream": {"url": "http://` + r.Host + `/v1/markets/events", "sessionid": "1"}}`))
+		case "/v1/markets/events":
+			w.Write([]byte(`{"type": "quote", "symbol": "SPY"}` + "\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tracer := &fakeTracer{}
+	params := DefaultParams("secret-token")
+	params.Endpoint = server.URL
+	params.Account = "VA000"
+	params.Backoff = &backoff.ZeroBackOff{}
+	params.Tracer = tracer
+	client := NewClient(params)
+
+	t.Run("Requests", func(t *testing.T) {
+		_, err := client.GetQuotes([]string{"SPY", "QQQ"})
+		assert.NoError(t, err)
+
+		span := tracer.span("GET /v1/markets/quotes")
+		if assert.NotNil(t, span) {
+			assert.True(t, span.ended)
+			assert.NoError(t, span.err)
+			assert.Equal(t, "VA000", span.attributes["tradier.account"])
+			assert.Equal(t, CategoryMarketData, span.attributes["tradier.category"])
+			assert.Equal(t, 2, span.attributes["tradier.symbols"])
+			assert.Equal(t, 2, span.attributes["tradier.attempts"])
+			assert.Equal(t, http.StatusOK, span.attributes["http.status_code"])
+			assert.Equal(t, 118, span.attributes["tradier.rate_limit.available"])
+		}
+
+		_, err = client.GetOrderStatus(42)
+		assert.Error(t, err)
+		span = tracer.span("GET /v1/accounts/{account}/orders/{id}")
+		if assert.NotNil(t, span) {
+			assert.Error(t, span.err)
+			assert.Equal(t, http.StatusNotFound, span.attributes["http.status_code"])
+		}
+	})
+
+	t.Run("Streams", func(t *testing.T) {
+		stream, err := client.StreamMarketEvents([]string{"SPY"}, nil)
+		assert.NoError(t, err)
+		body, err := ioutil.ReadAll(stream)
+		assert.NoError(t, err)
+		assert.True(t, strings.Contains(string(body), "SPY"))
+
+		span := tracer.span("tradier.stream market")
+		if assert.NotNil(t, span) {
+			assert.False(t, span.ended, "stream spans last until the stream is closed")
+			assert.Equal(t, 1, span.attributes["tradier.symbols"])
+			stream.Close()
+			assert.True(t, span.ended)
+		}
+
+		session := tracer.span("POST /v1/markets/events/session")
+		if assert.NotNil(t, session) {
+			assert.Equal(t, span, session.parent)
+		}
+	})
+}
+
+func Test_endpointName(t *testing.T) {
+	assert.Equal(t, "/v1/accounts/{account}/orders/{id}", endpointName("/v1/accounts/VA000/orders/42"))
+	assert.Equal(t, "/v1/accounts/events/session", endpointName("/v1/accounts/events/session"))
+	assert.Equal(t, "/v1/markets/quotes", endpointName("/v1/markets/quotes"))
+}
@@ -0,0 +1,63 @@
+package tradiertest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that, if set, makes AssertGolden
+// write the output to the golden files instead of comparing it:
+//
+//	UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// AssertGolden compares got, e.g. the output of an exporter, with the golden
+// file at path, and fails the test with the first differing line if they
+// differ. Golden files are conventionally kept under testdata.
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (set %s to create it): %v", UpdateGoldenEnv, err)
+	}
+	if diff := GoldenDiff(want, got); diff != "" {
+		t.Errorf("output differs from golden file %s (set %s to update it):\n%s", path, UpdateGoldenEnv, diff)
+	}
+}
+
+// GoldenDiff describes the first line where got differs from want, or
+// returns empty if they are equal.
+func GoldenDiff(want, got []byte) string {
+	if bytes.Equal(want, got) {
+		return ""
+	}
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; ; i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g || i >= len(wantLines) || i >= len(gotLines) {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s", i+1, w, g)
+		}
+	}
+}
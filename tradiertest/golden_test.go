@@ -0,0 +1,41 @@
+package tradiertest
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "report.csv")
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertGolden(t, path, []byte("symbol,net\nSPY,1.00\n"))
+
+	t.Setenv(UpdateGoldenEnv, "")
+	AssertGolden(t, path, []byte("symbol,net\nSPY,1.00\n"))
+
+	tb := &recordingTB{TB: t}
+	AssertGolden(tb, path, []byte("symbol,net\nSPY,2.00\n"))
+	if assert.Len(t, tb.errors, 1) {
+		assert.Contains(t, tb.errors[0], "line 2:\n- SPY,1.00\n+ SPY,2.00")
+	}
+}
+
+func TestGoldenDiff(t *testing.T) {
+	assert.Empty(t, GoldenDiff([]byte("a\nb\n"), []byte("a\nb\n")))
+	assert.Equal(t, "line 3:\n- \n+ c", GoldenDiff([]byte("a\nb\n"), []byte("a\nb\nc")))
+	assert.Equal(t, "line 2:\n- b\n+ ", GoldenDiff([]byte("a\nb"), []byte("a\n")))
+}
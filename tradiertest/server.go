@@ -0,0 +1,270 @@
+// Package tradiertest provides a fake Tradier API server for testing code
+// that uses the tradier package, including how it handles latency, failed
+// requests, rate limiting and malformed responses.
+package tradiertest
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gnagel/go-tradier"
+)
+
+// Malformed payload modes, for Server.Malformed.
+const (
+	// The response body is cut off partway through.
+	MalformedTruncated = "truncated"
+	// The response body is not JSON.
+	MalformedGarbage = "garbage"
+	// The response envelope is the string "null", as Tradier sends for
+	// empty results.
+	MalformedNull = "null"
+)
+
+// LatencyDistribution returns a latency to add to a response.
+type LatencyDistribution func(r *rand.Rand) time.Duration
+
+// FixedLatency delays every response by d.
+func FixedLatency(d time.Duration) LatencyDistribution {
+	return func(r *rand.Rand) time.Duration { return d }
+}
+
+// UniformLatency delays responses by between min and max.
+func UniformLatency(min, max time.Duration) LatencyDistribution {
+	return func(r *rand.Rand) time.Duration {
+		return min + time.Duration(r.Int63n(int64(max-min)+1))
+	}
+}
+
+// NormalLatency delays responses by a normally distributed latency, which
+// is never negative.
+func NormalLatency(mean, stddev time.Duration) LatencyDistribution {
+	return func(r *rand.Rand) time.Duration {
+		return time.Duration(math.Max(0, r.NormFloat64()*float64(stddev)+float64(mean)))
+	}
+}
+
+// Server is a fake Tradier API that serves the market clock, quotes, and the
+// balances, positions and orders of a single account. Its fields may be
+// changed while it runs, with Lock held.
+type Server struct {
+	*httptest.Server
+
+	sync.Mutex
+	Account   string
+	Clock     tradier.MarketStatus
+	Quotes    map[string]*tradier.Quote
+	Balances  tradier.AccountBalances
+	Positions []*tradier.Position
+	Orders    []*tradier.Order
+
+	// If set, each response is delayed by a latency from the distribution.
+	Latency LatencyDistribution
+	// Fraction of requests failing with a random 5xx status.
+	ErrorRate float64
+	// Fraction of requests failing with 429 and a Retry-After header.
+	RateLimitRate float64
+	RetryAfter    time.Duration
+	// Fraction of responses malformed in the Malformed mode.
+	MalformedRate float64
+	Malformed     string
+
+	// Requests received, by path.
+	requests map[string]int
+	rand     *rand.Rand
+	nextId   int
+}
+
+// NewServer starts a server with an open market and an empty account.
+// It should be closed when the test is done.
+func NewServer() *Server {
+	s := &Server{
+		Account:  "VA000001",
+		Clock:    tradier.MarketStatus{State: tradier.MarketOpen},
+		Quotes:   make(map[string]*tradier.Quote),
+		requests: make(map[string]int),
+		rand:     rand.New(rand.NewSource(1)),
+		nextId:   1,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Seed reseeds the random source of the injected failures and latencies.
+func (s *Server) Seed(seed int64) {
+	s.Lock()
+	s.rand = rand.New(rand.NewSource(seed))
+	s.Unlock()
+}
+
+// Params returns client parameters for the server's account, with trading
+// enabled.
+func (s *Server) Params() tradier.ClientParams {
+	params := tradier.DefaultParams("tradiertest-token")
+	params.Endpoint = s.URL
+	params.Account = s.Account
+	params.Environment = tradier.EnvironmentUnspecified
+	params.EnableTrading = true
+	return params
+}
+
+// NewClient returns a client of the server's account, as by Params.
+func (s *Server) NewClient() *tradier.Client {
+	return tradier.NewClient(s.Params())
+}
+
+// Requests returns the number of requests received for path, including
+// those that failed.
+func (s *Server) Requests(path string) int {
+	s.Lock()
+	defer s.Unlock()
+	return s.requests[path]
+}
+
+// Quote sets the quote of a symbol.
+func (s *Server) Quote(q *tradier.Quote) {
+	s.Lock()
+	s.Quotes[q.Symbol] = q
+	s.Unlock()
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	s.requests[r.URL.Path]++
+	var latency time.Duration
+	if s.Latency != nil {
+		latency = s.Latency(s.rand)
+	}
+	failure := s.rand.Float64()
+	status := http.StatusInternalServerError + s.rand.Intn(4)
+	malformed := s.rand.Float64() < s.MalformedRate
+	errorRate, rateLimitRate, retryAfter := s.ErrorRate, s.RateLimitRate, s.RetryAfter
+	s.Unlock()
+
+	time.Sleep(latency)
+	switch {
+	case failure < errorRate:
+		w.WriteHeader(status)
+		return
+	case failure < errorRate+rateLimitRate:
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	envelope, value, status := s.handle(r)
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+		return
+	}
+	body, err := json.Marshal(map[string]interface{}{envelope: value})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if malformed {
+		s.Lock()
+		mode := s.Malformed
+		s.Unlock()
+		switch mode {
+		case MalformedTruncated:
+			body = body[:len(body)/2]
+		case MalformedGarbage:
+			body = []byte("<html>Bad Gateway</html>")
+		case MalformedNull:
+			body = []byte(`{"` + envelope + `": "null"}`)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// Returns the envelope and value of the response to r, or an error status.
+func (s *Server) handle(r *http.Request) (string, interface{}, int) {
+	s.Lock()
+	defer s.Unlock()
+
+	accountPath := "/v1/accounts/" + s.Account
+	path := r.URL.Path
+	switch {
+	case path == "/v1/markets/clock":
+		return "clock", s.Clock, http.StatusOK
+	case path == "/v1/markets/quotes":
+		var quotes []*tradier.Quote
+		for _, symbol := range strings.Split(r.FormValue("symbols"), ",") {
+			if q := s.Quotes[symbol]; q != nil {
+				quotes = append(quotes, q)
+			}
+		}
+		return "quotes", map[string]interface{}{"quote": quotes}, http.StatusOK
+	case path == accountPath+"/balances":
+		return "balances", s.Balances, http.StatusOK
+	case path == accountPath+"/positions":
+		return "positions", map[string]interface{}{"position": s.Positions}, http.StatusOK
+	case path == accountPath+"/orders" && r.Method == http.MethodGet:
+		orders := make([]*tradier.Order, len(s.Orders))
+		for i, o := range s.Orders {
+			orders[i] = orderView(o, r)
+		}
+		return "orders", map[string]interface{}{"order": orders}, http.StatusOK
+	case path == accountPath+"/orders" && r.Method == http.MethodPost:
+		return "order", s.placeOrder(r), http.StatusOK
+	case strings.HasPrefix(path, accountPath+"/orders/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(path, accountPath+"/orders/"))
+		for _, o := range s.Orders {
+			if o.Id != id {
+				continue
+			}
+			if r.Method == http.MethodDelete {
+				o.Status = tradier.Canceled
+				return "order", map[string]interface{}{"id": id, "status": tradier.StatusOK}, http.StatusOK
+			}
+			return "order", orderView(o, r), http.StatusOK
+		}
+	}
+	return "", nil, http.StatusNotFound
+}
+
+// Returns a copy of the order as Tradier responds with it to r: without its
+// tag, unless the request includes tags.
+func orderView(o *tradier.Order, r *http.Request) *tradier.Order {
+	order := *o
+	if r.FormValue("includeTags") != "true" {
+		order.Tag = ""
+	}
+	return &order
+}
+
+func (s *Server) placeOrder(r *http.Request) interface{} {
+	if r.FormValue("preview") == "true" {
+		return map[string]interface{}{"status": tradier.StatusOK, "commission": 0}
+	}
+
+	quantity, _ := strconv.ParseFloat(r.FormValue("quantity"), 64)
+	price, _ := strconv.ParseFloat(r.FormValue("price"), 64)
+	order := &tradier.Order{
+		Id:           s.nextId,
+		Class:        r.FormValue("class"),
+		Symbol:       r.FormValue("symbol"),
+		OptionSymbol: r.FormValue("option_symbol"),
+		Side:         r.FormValue("side"),
+		Quantity:     quantity,
+		Type:         r.FormValue("type"),
+		Duration:     r.FormValue("duration"),
+		Price:        price,
+		Tag:          r.FormValue("tag"),
+		Status:       tradier.Open,
+		CreateDate:   tradier.DateTime{Time: time.Now()},
+	}
+	s.nextId++
+	s.Orders = append(s.Orders, order)
+	return map[string]interface{}{"id": order.Id, "status": tradier.StatusOK}
+}
@@ -0,0 +1,125 @@
+package tradiertest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/gnagel/go-tradier"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Quote(&tradier.Quote{Symbol: "SPY", Last: 380})
+	client := server.NewClient()
+
+	quotes, err := client.GetQuotes([]string{"SPY", "QQQ"})
+	assert.NoError(t, err)
+	if assert.Len(t, quotes, 1) {
+		assert.Equal(t, 380.0, quotes[0].Last)
+	}
+
+	result, err := client.PlaceOrder(tradier.Order{Class: tradier.Equity, Symbol: "SPY", Side: tradier.Buy,
+		Quantity: 10, Type: tradier.LimitOrder, Price: 379.5, Duration: tradier.Day})
+	if !assert.NoError(t, err) {
+		return
+	}
+	id := result.Id
+	orders, err := client.GetOpenOrders()
+	assert.NoError(t, err)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, id, orders[0].Id)
+		assert.Equal(t, 379.5, orders[0].Price)
+	}
+	assert.NoError(t, client.CancelOrder(id))
+	order, err := client.GetOrderStatus(id)
+	assert.NoError(t, err)
+	assert.Equal(t, tradier.Canceled, order.Status)
+}
+
+func TestServer_faults(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	params := server.Params()
+	params.Backoff = backoff.NewConstantBackOff(time.Millisecond)
+	params.RetryLimit = 0
+	client := tradier.NewClient(params)
+
+	t.Run("Errors", func(t *testing.T) {
+		server.Lock()
+		server.ErrorRate = 1
+		server.Unlock()
+		_, err := client.GetMarketState()
+		if assert.IsType(t, tradier.TradierError{}, err) {
+			assert.True(t, err.(tradier.TradierError).HttpStatusCode >= 500)
+		}
+	})
+
+	t.Run("Rate limited", func(t *testing.T) {
+		server.Lock()
+		server.ErrorRate, server.RateLimitRate = 0, 1
+		server.Unlock()
+		_, err := client.GetMarketState()
+		if assert.IsType(t, tradier.TradierError{}, err) {
+			assert.Equal(t, 429, err.(tradier.TradierError).HttpStatusCode)
+			assert.Equal(t, "0", err.(tradier.TradierError).Header.Get("Retry-After"))
+		}
+	})
+
+	t.Run("Retried", func(t *testing.T) {
+		server.Lock()
+		server.RateLimitRate, server.ErrorRate = 0, 0.5
+		server.Unlock()
+		server.Seed(2)
+		params.RetryLimit = 10
+		before := server.Requests("/v1/markets/clock")
+		_, err := tradier.NewClient(params).GetMarketState()
+		assert.NoError(t, err)
+		assert.Greater(t, server.Requests("/v1/markets/clock")-before, 0)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		server.Lock()
+		server.ErrorRate, server.MalformedRate = 0, 1
+		server.Unlock()
+		for _, mode := range []string{MalformedTruncated, MalformedGarbage} {
+			server.Lock()
+			server.Malformed = mode
+			server.Unlock()
+			_, err := client.GetAccountPositions()
+			assert.Error(t, err, mode)
+		}
+
+		server.Lock()
+		server.Malformed = MalformedNull
+		server.Positions = []*tradier.Position{{Symbol: "SPY", Quantity: 1}}
+		server.Unlock()
+		positions, err := client.GetAccountPositions()
+		assert.NoError(t, err)
+		assert.Empty(t, positions)
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		server.Lock()
+		server.MalformedRate = 0
+		server.Latency = UniformLatency(20*time.Millisecond, 30*time.Millisecond)
+		server.Unlock()
+		start := time.Now()
+		_, err := client.GetMarketState()
+		assert.NoError(t, err)
+		assert.True(t, time.Since(start) >= 20*time.Millisecond)
+	})
+}
+
+func TestLatencyDistributions(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Second, FixedLatency(time.Second)(server.rand))
+		d := UniformLatency(time.Millisecond, 2*time.Millisecond)(server.rand)
+		assert.True(t, d >= time.Millisecond && d <= 2*time.Millisecond)
+		assert.True(t, NormalLatency(time.Millisecond, 10*time.Millisecond)(server.rand) >= 0)
+	}
+}
@@ -0,0 +1,94 @@
+package tradier
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Account types.
+	CashAccount   = "cash"
+	MarginAccount = "margin"
+
+	// Account statuses.
+	AccountActive = "active"
+	AccountClosed = "closed"
+)
+
+// ErrNoMatchingAccount is returned by SelectMatchingAccount if none of
+// the user's active accounts are accepted.
+var ErrNoMatchingAccount = errors.New("no matching account")
+
+type UserAccount struct {
+	AccountNumber  string `json:"account_number"`
+	Classification string
+	Status         string
+	Type           string
+	DayTrader      bool        `json:"day_trader"`
+	OptionLevel    OptionLevel `json:"option_level"`
+	DateCreated    DateTime    `json:"date_created"`
+	LastUpdateDate DateTime    `json:"last_update_date"`
+}
+
+// If the user has a single account, then Tradier returns
+// an object rather than a list of objects.
+type UserAccounts []UserAccount
+
+func (ua *UserAccounts) UnmarshalJSON(data []byte) error {
+	accounts, err := decodeOneOrMany[UserAccount](data)
+	*ua = accounts
+	return err
+}
+
+type UserProfile struct {
+	Id      string
+	Name    string
+	Account UserAccounts
+}
+
+// ActiveAccounts returns the user's accounts that are active.
+func (up *UserProfile) ActiveAccounts() []UserAccount {
+	var active []UserAccount
+	for _, a := range up.Account {
+		if a.Status == AccountActive {
+			active = append(active, a)
+		}
+	}
+	return active
+}
+
+// Get the profile of the user, including their accounts.
+func (tc *Client) GetUserProfile() (*UserProfile, error) {
+	return tc.getUserProfile(context.Background())
+}
+
+func (tc *Client) getUserProfile(ctx context.Context) (*UserProfile, error) {
+	url := tc.endpoint + "/v1/user/profile"
+	var result struct {
+		Profile *UserProfile
+	}
+	err := tc.getJSONContext(ctx, url, &result)
+	return result.Profile, err
+}
+
+// SelectMatchingAccount selects the first of the user's active accounts
+// accepted by the given function, or the first active account if it is nil.
+// This allows the account to be chosen by its properties rather than hard-coded,
+// e.g. the margin account with option level 3.
+func (tc *Client) SelectMatchingAccount(accept func(account UserAccount) bool) (UserAccount, error) {
+	profile, err := tc.GetUserProfile()
+	if err != nil {
+		return UserAccount{}, err
+	} else if profile == nil {
+		return UserAccount{}, ErrNoMatchingAccount
+	}
+
+	for _, a := range profile.ActiveAccounts() {
+		if accept == nil || accept(a) {
+			tc.SelectAccount(a.AccountNumber)
+			return a, nil
+		}
+	}
+	return UserAccount{}, ErrNoMatchingAccount
+}
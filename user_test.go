@@ -0,0 +1,50 @@
+package tradier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SelectMatchingAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"profile": {"id": "id-test", "name": "Test", "account": [
+			{"account_number": "VA000", "classification": "individual", "day_trader": false, "option_level": 6,
+			 "status": "closed", "type": "margin", "date_created": "2016-08-01T21:08:55.000Z"},
+			{"account_number": "VA001", "classification": "individual", "day_trader": false, "option_level": 1,
+			 "status": "active", "type": "cash", "date_created": "2017-01-01T12:00:00.000Z"},
+			{"account_number": "VA002", "classification": "individual", "day_trader": true, "option_level": 3,
+			 "status": "active", "type": "margin", "last_update_date": "2021-01-04T00:00:00.000Z"}]}}`))
+	}))
+	defer server.Close()
+
+	params := DefaultParams("token")
+	params.Endpoint = server.URL
+	client := NewClient(params)
+
+	profile, err := client.GetUserProfile()
+	assert.NoError(t, err)
+	if assert.Len(t, profile.Account, 3) {
+		assert.Equal(t, OptionLevel(6), profile.Account[0].OptionLevel)
+		assert.Equal(t, time.Date(2016, time.August, 1, 21, 8, 55, 0, time.UTC), profile.Account[0].DateCreated.Time)
+		assert.True(t, profile.Account[2].DayTrader)
+	}
+	assert.Len(t, profile.ActiveAccounts(), 2)
+
+	account, err := client.SelectMatchingAccount(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "VA001", account.AccountNumber)
+
+	account, err = client.SelectMatchingAccount(func(a UserAccount) bool {
+		return a.Type == MarginAccount && a.OptionLevel >= 3
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "VA002", account.AccountNumber)
+	assert.Equal(t, "VA002", client.account)
+
+	_, err = client.SelectMatchingAccount(func(a UserAccount) bool { return a.OptionLevel > 6 })
+	assert.Equal(t, ErrNoMatchingAccount, err)
+}
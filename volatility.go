@@ -0,0 +1,110 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+)
+
+// RealizedVol returns the annualized close-to-close volatility of
+// the last window daily returns.
+func RealizedVol(bars []TimeSale, window int) (float64, error) {
+	if window < 2 || len(bars) < window+1 {
+		return 0, fmt.Errorf("need %d bars to compute %d day realized volatility, have %d", window+1, window, len(bars))
+	}
+
+	returns := make([]float64, 0, window)
+	for i := len(bars) - window; i < len(bars); i++ {
+		prev, cur := float64(bars[i-1].Close), float64(bars[i].Close)
+		if prev <= 0 || cur <= 0 {
+			return 0, fmt.Errorf("invalid close price at bar %d", i)
+		}
+		returns = append(returns, math.Log(cur/prev))
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	return math.Sqrt(variance * tradingDaysPerYear), nil
+}
+
+// ParkinsonVol returns the annualized Parkinson (high-low range) volatility
+// estimate of the last window bars.
+func ParkinsonVol(bars []TimeSale, window int) (float64, error) {
+	if window < 1 || len(bars) < window {
+		return 0, fmt.Errorf("need %d bars to compute %d day Parkinson volatility, have %d", window, window, len(bars))
+	}
+
+	var total float64
+	for i := len(bars) - window; i < len(bars); i++ {
+		high, low := float64(bars[i].High), float64(bars[i].Low)
+		if high <= 0 || low <= 0 {
+			return 0, fmt.Errorf("invalid high/low at bar %d", i)
+		}
+		hl := math.Log(high / low)
+		total += hl * hl
+	}
+
+	variance := total / (4 * math.Ln2 * float64(window))
+	return math.Sqrt(variance * tradingDaysPerYear), nil
+}
+
+// GarmanKlassVol returns the annualized Garman-Klass (OHLC) volatility
+// estimate of the last window bars.
+func GarmanKlassVol(bars []TimeSale, window int) (float64, error) {
+	if window < 1 || len(bars) < window {
+		return 0, fmt.Errorf("need %d bars to compute %d day Garman-Klass volatility, have %d", window, window, len(bars))
+	}
+
+	var total float64
+	for i := len(bars) - window; i < len(bars); i++ {
+		b := bars[i]
+		if b.Open <= 0 || b.High <= 0 || b.Low <= 0 || b.Close <= 0 {
+			return 0, fmt.Errorf("invalid OHLC at bar %d", i)
+		}
+		hl := math.Log(float64(b.High / b.Low))
+		co := math.Log(float64(b.Close / b.Open))
+		total += 0.5*hl*hl - (2*math.Ln2-1)*co*co
+	}
+
+	variance := total / float64(window)
+	return math.Sqrt(variance * tradingDaysPerYear), nil
+}
+
+// VolComparison compares an option's implied volatility with the
+// realized volatility of its underlying.
+type VolComparison struct {
+	Implied  float64
+	Realized float64
+	// Implied - Realized. Positive when options are rich relative to realized movement.
+	Spread float64
+	// Implied / Realized.
+	Ratio float64
+}
+
+// CompareVol compares the implied volatility against the close-to-close
+// realized volatility of the last window bars.
+func CompareVol(impliedVol float64, bars []TimeSale, window int) (VolComparison, error) {
+	rv, err := RealizedVol(bars, window)
+	if err != nil {
+		return VolComparison{}, err
+	}
+
+	vc := VolComparison{
+		Implied:  impliedVol,
+		Realized: rv,
+		Spread:   impliedVol - rv,
+	}
+	if rv > 0 {
+		vc.Ratio = impliedVol / rv
+	}
+	return vc, nil
+}
@@ -0,0 +1,46 @@
+package tradier
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealizedVol(t *testing.T) {
+	t.Run("Constant returns", func(t *testing.T) {
+		bars := []TimeSale{{Close: 100}, {Close: 101}, {Close: 102.01}, {Close: 103.0301}}
+		rv, err := RealizedVol(bars, 3)
+		assert.NoError(t, err)
+		assert.InDelta(t, 0, rv, 1e-9)
+	})
+
+	t.Run("Alternating returns", func(t *testing.T) {
+		bars := []TimeSale{{Close: 100}, {Close: 110}, {Close: 100}}
+		rv, err := RealizedVol(bars, 2)
+		assert.NoError(t, err)
+		r := math.Log(1.1)
+		assert.InDelta(t, math.Sqrt(2*r*r*252), rv, 1e-9)
+	})
+
+	t.Run("Not enough bars", func(t *testing.T) {
+		_, err := RealizedVol([]TimeSale{{Close: 100}}, 2)
+		assert.Error(t, err)
+	})
+}
+
+func TestRangeVol(t *testing.T) {
+	bars := []TimeSale{
+		{Open: 100, High: 102, Low: 98, Close: 100},
+		{Open: 100, High: 102, Low: 98, Close: 100},
+	}
+
+	hl := math.Log(102.0 / 98.0)
+	pk, err := ParkinsonVol(bars, 2)
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(hl*hl/(4*math.Ln2)*252), pk, 1e-9)
+
+	gk, err := GarmanKlassVol(bars, 2)
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(0.5*hl*hl*252), gk, 1e-9)
+}
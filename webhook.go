@@ -0,0 +1,119 @@
+package tradier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WebhookSignatureHeader is the header of a webhook that carries the
+// hex-encoded HMAC-SHA256 of its body, optionally prefixed by "sha256=".
+const WebhookSignatureHeader = "X-Tradier-Signature"
+
+// Webhook bodies larger than this are refused.
+const maxWebhookBodySize = 1 << 20
+
+// ErrInvalidWebhookSignature is passed to WebhookReceiver.Errors when a
+// webhook is refused because its signature does not match its body.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// SignWebhook returns the signature of a webhook body for WebhookSignatureHeader.
+func SignWebhook(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook returns true if signature is the signature of body with secret.
+func VerifyWebhook(secret, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(expected, mac.Sum(nil))
+}
+
+// WebhookReceiver is an http.Handler that receives order event webhooks, or
+// notifications relaying them, and delivers them as the same AccountEvents
+// sent by StreamAccountEvents. This allows order updates to be pushed to a
+// deployment without holding a stream open.
+//
+// The body of a webhook is an account event, or a list of them, in the form
+// sent by the account stream. Events other than order events are ignored.
+type WebhookReceiver struct {
+	// If not empty, webhooks must be signed with Secret in WebhookSignatureHeader.
+	Secret []byte
+	// Events is called with each order event received.
+	Events func(event *AccountEvent)
+	// If set, Errors is called with the reason each refused webhook was refused.
+	Errors func(err error)
+}
+
+func NewWebhookReceiver(secret []byte, events func(event *AccountEvent)) *WebhookReceiver {
+	return &WebhookReceiver{
+		Secret: secret,
+		Events: events,
+	}
+}
+
+// WebhookEvents delivers each order event received by the receiver on output.
+// The channel is never closed, since the receiver does not know when
+// webhooks stop arriving.
+func WebhookEvents(secret []byte, output chan<- *AccountEvent) *WebhookReceiver {
+	return NewWebhookReceiver(secret, func(event *AccountEvent) { output <- event })
+}
+
+func (wr *WebhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		wr.refuse(w, http.StatusMethodNotAllowed, errors.Errorf("webhook with method %v", r.Method))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize+1))
+	if err != nil {
+		wr.refuse(w, http.StatusBadRequest, err)
+		return
+	} else if len(body) > maxWebhookBodySize {
+		wr.refuse(w, http.StatusRequestEntityTooLarge,
+			errors.Errorf("webhook body exceeds %d bytes", maxWebhookBodySize))
+		return
+	}
+
+	if len(wr.Secret) > 0 && !VerifyWebhook(wr.Secret, body, r.Header.Get(WebhookSignatureHeader)) {
+		wr.refuse(w, http.StatusUnauthorized, ErrInvalidWebhookSignature)
+		return
+	}
+
+	events, err := decodeOneOrMany[*AccountEvent](body)
+	if err != nil {
+		wr.refuse(w, http.StatusBadRequest, errors.Wrap(err, "error decoding webhook"))
+		return
+	}
+
+	// The events are delivered before acknowledging the webhook, so that
+	// it is retried by the sender if the receiver fails meanwhile.
+	for _, event := range events {
+		if event == nil || event.Event != "order" {
+			continue
+		}
+		if wr.Events != nil {
+			wr.Events(event)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (wr *WebhookReceiver) refuse(w http.ResponseWriter, status int, err error) {
+	if wr.Errors != nil {
+		wr.Errors(err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
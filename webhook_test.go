@@ -0,0 +1,71 @@
+package tradier
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookReceiver(t *testing.T) {
+	secret := []byte("secret")
+	output := make(chan *AccountEvent, 10)
+	receiver := WebhookEvents(secret, output)
+	var errs []error
+	receiver.Errors = func(err error) { errs = append(errs, err) }
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	post := func(body, signature string) int {
+		req, _ := http.NewRequest("POST", server.URL, bytes.NewBufferString(body))
+		if signature != "" {
+			req.Header.Set(WebhookSignatureHeader, signature)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			return 0
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("Single event", func(t *testing.T) {
+		body := `{"id": 1, "event": "order", "status": "filled", "account": "VA000", "executed_quantity": 10, "avg_fill_price": 400.5}`
+		assert.Equal(t, http.StatusNoContent, post(body, SignWebhook(secret, []byte(body))))
+		event := <-output
+		assert.Equal(t, 1, event.Id)
+		assert.True(t, event.IsFill())
+		assert.Equal(t, 400.5, event.AverageFillPrice)
+	})
+
+	t.Run("List of events", func(t *testing.T) {
+		body := `[{"id": 2, "event": "order", "status": "open"}, {"event": "heartbeat"}, {"id": 3, "event": "order", "status": "canceled"}]`
+		assert.Equal(t, http.StatusNoContent, post(body, SignWebhook(secret, []byte(body))))
+		assert.Equal(t, 2, (<-output).Id)
+		assert.Equal(t, 3, (<-output).Id)
+		assert.Empty(t, output)
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		body := `{"id": 4, "event": "order", "status": "filled"}`
+		assert.Equal(t, http.StatusUnauthorized, post(body, SignWebhook([]byte("other"), []byte(body))))
+		assert.Equal(t, http.StatusUnauthorized, post(body, ""))
+		assert.Empty(t, output)
+		assert.Equal(t, ErrInvalidWebhookSignature, errs[len(errs)-1])
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		body := `{"id": "x"}`
+		assert.Equal(t, http.StatusBadRequest, post(body, SignWebhook(secret, []byte(body))))
+	})
+
+	t.Run("Method", func(t *testing.T) {
+		resp, err := http.Get(server.URL)
+		if assert.NoError(t, err) {
+			resp.Body.Close()
+			assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		}
+	})
+}
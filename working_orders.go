@@ -0,0 +1,144 @@
+package tradier
+
+import (
+	"fmt"
+	"math"
+)
+
+// SymbolExposure is the unfilled quantity of the working orders in a
+// symbol, the exposure that they would add to its position if they filled.
+type SymbolExposure struct {
+	Symbol string
+	// Working orders with legs in the symbol.
+	Orders []*Order
+	// Unfilled quantity of buy and sell orders, in shares or contracts.
+	PendingBuy  float64
+	PendingSell float64
+	// Value of the unfilled quantities at the orders' limit (or stop)
+	// prices, times the contract multiplier. Market orders have no value.
+	BuyNotional  float64
+	SellNotional float64
+}
+
+// NetPending returns the change in position if all of the orders filled.
+func (se *SymbolExposure) NetPending() float64 {
+	return se.PendingBuy - se.PendingSell
+}
+
+// GroupWorkingOrders returns the exposure of the working orders in each
+// symbol, keyed by the option symbol of option legs. Orders that are not
+// working are ignored. The legs of multileg and conditional orders are
+// counted separately, even if only some of them can fill.
+func GroupWorkingOrders(orders []*Order) map[string]*SymbolExposure {
+	exposures := make(map[string]*SymbolExposure)
+	var add func(order, leg *Order)
+	add = func(order, leg *Order) {
+		if len(leg.Legs) > 0 {
+			for i := range leg.Legs {
+				add(order, &leg.Legs[i])
+			}
+			return
+		}
+		if leg.Status != "" && !isOpenStatus(leg.Status) {
+			return
+		}
+
+		symbol := orderSymbol(leg)
+		se := exposures[symbol]
+		if se == nil {
+			se = &SymbolExposure{Symbol: symbol}
+			exposures[symbol] = se
+		}
+		if len(se.Orders) == 0 || se.Orders[len(se.Orders)-1] != order {
+			se.Orders = append(se.Orders, order)
+		}
+
+		price := leg.Price
+		if price == 0 {
+			price = leg.StopPrice
+		}
+		unfilled := unfilledQuantity(leg)
+		notional := unfilled * price * contractMultiplier(symbol)
+		if isBuySide(leg.Side) {
+			se.PendingBuy += unfilled
+			se.BuyNotional += notional
+		} else {
+			se.PendingSell += unfilled
+			se.SellNotional += notional
+		}
+	}
+
+	for _, o := range orders {
+		if isOpenStatus(o.Status) {
+			add(o, o)
+		}
+	}
+	return exposures
+}
+
+func unfilledQuantity(order *Order) float64 {
+	if order.RemainingQuantity > 0 {
+		return order.RemainingQuantity
+	}
+	return math.Max(0, order.Quantity-order.ExecutedQuantity)
+}
+
+// WorkingOrdersBySymbol returns the exposure of the working orders of the
+// selected account in each symbol, as by GroupWorkingOrders.
+func (tc *Client) WorkingOrdersBySymbol() (map[string]*SymbolExposure, error) {
+	orders, err := tc.GetOpenOrders()
+	if err != nil {
+		return nil, err
+	}
+	return GroupWorkingOrders(orders), nil
+}
+
+// ExposureSource lists the positions and orders of the selected account.
+// It is implemented by Client.
+type ExposureSource interface {
+	GetOpenOrders() ([]*Order, error)
+	GetAccountPositions() ([]*Position, error)
+}
+
+// LimitPositions rejects orders that could take the position in a symbol
+// beyond maxQuantity(symbol) shares or contracts, long or short, counting
+// the current position and the working orders as if they all filled.
+// Symbols with a limit of zero are not limited.
+func LimitPositions(account ExposureSource, maxQuantity func(symbol string) float64) RiskRule {
+	return func(order Order, preview *OrderPreview) error {
+		positions, err := account.GetAccountPositions()
+		if err != nil {
+			return err
+		}
+		orders, err := account.GetOpenOrders()
+		if err != nil {
+			return err
+		}
+
+		held := make(map[string]float64)
+		for _, p := range positions {
+			held[p.Symbol] += p.Quantity
+		}
+		working := GroupWorkingOrders(orders)
+		// Count the order being checked as another working order.
+		order.Status = Pending
+		for symbol, pending := range GroupWorkingOrders([]*Order{&order}) {
+			limit := maxQuantity(symbol)
+			if limit <= 0 {
+				continue
+			}
+			long, short := held[symbol]+pending.PendingBuy, held[symbol]-pending.PendingSell
+			if w := working[symbol]; w != nil {
+				long += w.PendingBuy
+				short -= w.PendingSell
+			}
+			if long > limit || -short > limit {
+				return ErrRiskRejected{
+					Rule:   "LimitPositions",
+					Reason: fmt.Sprintf("position in %v could reach %v with working orders, over the limit of %v", symbol, math.Max(long, -short), limit),
+				}
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+package tradier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeExposureSource struct {
+	orders    []*Order
+	positions []*Position
+}
+
+func (fs *fakeExposureSource) GetOpenOrders() ([]*Order, error)          { return fs.orders, nil }
+func (fs *fakeExposureSource) GetAccountPositions() ([]*Position, error) { return fs.positions, nil }
+
+func TestGroupWorkingOrders(t *testing.T) {
+	spread := &Order{Id: 3, Class: Multileg, Symbol: "SPY", Status: Open, Legs: []Order{
+		{Side: SellToOpen, Quantity: 2, OptionSymbol: "SPY210219P00400000"},
+		{Side: BuyToOpen, Quantity: 2, OptionSymbol: "SPY210219P00390000"},
+	}}
+	orders := []*Order{
+		{Id: 1, Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 100, ExecutedQuantity: 40, Price: 380, Status: PartiallyFilled},
+		{Id: 2, Class: Equity, Symbol: "SPY", Side: Sell, Quantity: 10, Status: Filled},
+		spread,
+		{Id: 4, Class: Option, Symbol: "SPY", OptionSymbol: "SPY210219P00400000", Side: SellToOpen, Quantity: 1, Price: 2.5, Status: Open},
+	}
+
+	exposures := GroupWorkingOrders(orders)
+	assert.Len(t, exposures, 3)
+	spy := exposures["SPY"]
+	assert.Equal(t, 60.0, spy.PendingBuy, "unfilled quantity")
+	assert.Equal(t, 0.0, spy.PendingSell, "filled orders are not working")
+	assert.Equal(t, 60*380.0, spy.BuyNotional)
+
+	put := exposures["SPY210219P00400000"]
+	assert.Equal(t, 3.0, put.PendingSell)
+	assert.Equal(t, 250.0, put.SellNotional, "option notional is per contract")
+	assert.Equal(t, -3.0, put.NetPending())
+	if assert.Len(t, put.Orders, 2) {
+		assert.Equal(t, spread, put.Orders[0])
+	}
+	assert.Equal(t, 2.0, exposures["SPY210219P00390000"].PendingBuy)
+}
+
+func TestLimitPositions(t *testing.T) {
+	account := &fakeExposureSource{
+		positions: []*Position{{Symbol: "SPY", Quantity: 50}},
+		orders:    []*Order{{Class: Equity, Symbol: "SPY", Side: Buy, Quantity: 30, Status: Open}},
+	}
+	rule := LimitPositions(account, func(symbol string) float64 {
+		if symbol == "SPY" {
+			return 100
+		}
+		return 0
+	})
+	buy := func(symbol string, quantity float64) Order {
+		return Order{Class: Equity, Symbol: symbol, Side: Buy, Quantity: quantity}
+	}
+
+	assert.NoError(t, rule(buy("SPY", 20), nil))
+	err := rule(buy("SPY", 21), nil)
+	if assert.IsType(t, ErrRiskRejected{}, err) {
+		assert.Equal(t, "LimitPositions", err.(ErrRiskRejected).Rule)
+	}
+	assert.NoError(t, rule(buy("AAPL", 1000), nil), "unlimited symbol")
+	assert.NoError(t, rule(Order{Class: Equity, Symbol: "SPY", Side: Sell, Quantity: 150}, nil))
+	assert.Error(t, rule(Order{Class: Equity, Symbol: "SPY", Side: SellShort, Quantity: 151}, nil))
+}